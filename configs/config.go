@@ -74,6 +74,15 @@ type EmailConfig struct {
 	SMTPPassword string `json:"-"` // No exponer en JSON
 	FromAddress  string `json:"from_address"`
 	Enabled      bool   `json:"enabled"`
+
+	// Recepción de correos de notificaciones bancarias
+	IngestDomain   string `json:"ingest_domain"`    // Dominio de los alias de ingesta (alias@dominio)
+	IngestToken    string `json:"-"`                // Token del webhook del proveedor de correo; vacío lo deshabilita
+	IngestSMTPAddr string `json:"ingest_smtp_addr"` // Dirección del servidor SMTP/LMTP de ingesta; vacío lo deshabilita
+	IngestLMTP     bool   `json:"ingest_lmtp"`      // Hablar LMTP en lugar de SMTP
+	IngestMaxSize  int64  `json:"ingest_max_size"`  // Tamaño máximo de un correo recibido
+
+	IngestMaxSessions int `json:"ingest_max_sessions"` // Conexiones SMTP/LMTP simultáneas
 }
 
 // ExternalConfig representa configuraciones de servicios externos
@@ -86,11 +95,20 @@ type ExternalConfig struct {
 
 // FeatureConfig representa configuraciones de características
 type FeatureConfig struct {
-	EnableSwagger       bool `json:"enable_swagger"`
-	EnableMetrics       bool `json:"enable_metrics"`
-	EnableProfiler      bool `json:"enable_profiler"`
-	EnableDebugRoutes   bool `json:"enable_debug_routes"`
-	EnableNotifications bool `json:"enable_notifications"`
+	EnableSwagger            bool `json:"enable_swagger"`
+	EnableMetrics            bool `json:"enable_metrics"`
+	EnableProfiler           bool `json:"enable_profiler"`
+	EnableDebugRoutes        bool `json:"enable_debug_routes"`
+	EnableNotifications      bool `json:"enable_notifications"`
+	PatternCacheSize         int  `json:"pattern_cache_size"`         // Conjuntos de patrones compilados en memoria
+	DedupWindowMinutes       int  `json:"dedup_window_minutes"`       // Ventana para considerar dos notificaciones el mismo evento
+	BatchWorkers             int  `json:"batch_workers"`              // Notificaciones de un lote procesadas en paralelo
+	PatternTimeBudgetMs      int  `json:"pattern_time_budget_ms"`     // Tiempo máximo para evaluar los patrones contra una notificación
+	RecurringIntervalMinutes int  `json:"recurring_interval_minutes"` // Cada cuánto se generan las ocurrencias vencidas de las transacciones recurrentes; 0 lo deshabilita
+	HoldExpiryHours          int  `json:"hold_expiry_hours"`          // Tiempo tras el cual vence una transacción pendiente sin confirmar y libera sus fondos; 0 lo deshabilita
+
+	PatternLibraryMaintainers []string `json:"pattern_library_maintainers"` // Emails autorizados a importar plantillas de patrones
+	AdminEmails               []string `json:"admin_emails"`                // Emails autorizados a las rutas de administración
 }
 
 // globalConfig almacena la configuración global
@@ -132,6 +150,14 @@ func Load() *Config {
 			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
 			FromAddress:  getEnv("SMTP_FROM", "noreply@fintech.com"),
 			Enabled:      getEnvAsBool("ENABLE_EMAIL_NOTIFICATIONS", false),
+
+			IngestDomain:   getEnv("INBOUND_EMAIL_DOMAIN", ""),
+			IngestToken:    getEnv("INBOUND_EMAIL_TOKEN", ""),
+			IngestSMTPAddr: getEnv("INBOUND_SMTP_ADDR", ""),
+			IngestLMTP:     getEnvAsBool("INBOUND_SMTP_LMTP", false),
+			IngestMaxSize:  getEnvAsInt64("INBOUND_EMAIL_MAX_SIZE", 10*1024*1024), // 10MB
+
+			IngestMaxSessions: getEnvAsInt("INBOUND_SMTP_MAX_SESSIONS", 20),
 		},
 		External: ExternalConfig{
 			PlaidClientID:    getEnv("PLAID_CLIENT_ID", ""),
@@ -140,11 +166,20 @@ func Load() *Config {
 			SentryDSN:        getEnv("SENTRY_DSN", ""),
 		},
 		Features: FeatureConfig{
-			EnableSwagger:       getEnvAsBool("ENABLE_SWAGGER", true),
-			EnableMetrics:       getEnvAsBool("ENABLE_METRICS", false),
-			EnableProfiler:      getEnvAsBool("ENABLE_PROFILER", false),
-			EnableDebugRoutes:   getEnvAsBool("ENABLE_DEBUG_ROUTES", true),
-			EnableNotifications: getEnvAsBool("ENABLE_NOTIFICATIONS", false),
+			EnableSwagger:            getEnvAsBool("ENABLE_SWAGGER", true),
+			EnableMetrics:            getEnvAsBool("ENABLE_METRICS", false),
+			EnableProfiler:           getEnvAsBool("ENABLE_PROFILER", false),
+			EnableDebugRoutes:        getEnvAsBool("ENABLE_DEBUG_ROUTES", true),
+			EnableNotifications:      getEnvAsBool("ENABLE_NOTIFICATIONS", false),
+			PatternCacheSize:         getEnvAsInt("PATTERN_CACHE_SIZE", 1000),
+			DedupWindowMinutes:       getEnvAsInt("NOTIFICATION_DEDUP_WINDOW_MINUTES", 30),
+			BatchWorkers:             getEnvAsInt("NOTIFICATION_BATCH_WORKERS", 4),
+			PatternTimeBudgetMs:      getEnvAsInt("PATTERN_TIME_BUDGET_MS", 250),
+			RecurringIntervalMinutes: getEnvAsInt("RECURRING_TRANSACTIONS_INTERVAL_MINUTES", 60),
+			HoldExpiryHours:          getEnvAsInt("PENDING_HOLD_EXPIRY_HOURS", 168),
+
+			PatternLibraryMaintainers: getEnvAsStringSlice("PATTERN_LIBRARY_MAINTAINERS", []string{}),
+			AdminEmails:               getEnvAsStringSlice("ADMIN_EMAILS", []string{}),
 		},
 	}
 
@@ -20,6 +20,8 @@ import (
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
 	"github.com/nick130920/fintech-backend/pkg/auth"
 	"github.com/nick130920/fintech-backend/pkg/database"
+	"github.com/nick130920/fintech-backend/pkg/inboundmail"
+	"github.com/nick130920/fintech-backend/pkg/patterncache"
 	"github.com/nick130920/fintech-backend/pkg/repository"
 )
 
@@ -56,11 +58,27 @@ func Run() {
 	jwtManager := auth.NewJWTManager(cfg.JWT.SecretKey, cfg.JWT.ExpiresIn)
 
 	// Inicializar dependencias
-	deps := initDependencies(db, jwtManager)
+	deps := initDependencies(cfg, db, jwtManager)
+
+	// Registrar en el libro contable el saldo de las cuentas creadas antes de que existiera
+	if created, err := deps.LedgerUC.BackfillOpeningEntries(); err != nil {
+		log.Printf("Warning: Failed to create ledger opening entries: %v", err)
+	} else if created > 0 {
+		log.Printf("Created %d ledger opening entries", created)
+	}
 
 	// Inicializar servidor HTTP
 	httpServer := initHTTPServer(cfg, deps)
 
+	// Servidor SMTP/LMTP de correos entrantes (opcional)
+	startInboundMailServer(cfg, deps)
+
+	// Generación periódica de las ocurrencias de transacciones recurrentes
+	startRecurringScheduler(cfg, deps)
+
+	// Vencimiento periódico de las transacciones pendientes sin confirmar
+	startHoldExpiryScheduler(cfg, deps)
+
 	// Ejecutar servidor
 	runServer(httpServer, cfg.Server.Port)
 }
@@ -76,6 +94,14 @@ type Dependencies struct {
 	IncomeUC                  *usecase.IncomeUseCase
 	BankAccountUC             *usecase.BankAccountUseCase
 	BankNotificationPatternUC *usecase.BankNotificationPatternUseCase
+	PatternPolicyUC           *usecase.PatternPolicyUseCase
+	NotificationInboxUC       *usecase.NotificationInboxUseCase
+	NotificationBatchUC       *usecase.NotificationBatchUseCase
+	PatternLibraryUC          *usecase.PatternLibraryUseCase
+	BalanceReconciliationUC   *usecase.BalanceReconciliationUseCase
+	EmailIngestionUC          *usecase.EmailIngestionUseCase
+	LedgerUC                  *usecase.LedgerUseCase
+	RecurringTransactionUC    *usecase.RecurringTransactionUseCase
 
 	// Repositories (necesarios para algunos handlers)
 	CategoryRepo repo.CategoryRepo
@@ -85,7 +111,7 @@ type Dependencies struct {
 }
 
 // initDependencies inicializa todas las dependencias usando inyección de dependencias
-func initDependencies(db *gorm.DB, jwtManager *auth.JWTManager) *Dependencies {
+func initDependencies(cfg *configs.Config, db *gorm.DB, jwtManager *auth.JWTManager) *Dependencies {
 	// Inicializar repositorios
 	userRepo := repository.NewUserPostgres(db)
 	accountRepo := repository.NewAccountPostgres(db)
@@ -94,6 +120,16 @@ func initDependencies(db *gorm.DB, jwtManager *auth.JWTManager) *Dependencies {
 	categoryRepo := repository.NewCategoryPostgres(db)
 	bankAccountRepo := repository.NewBankAccountPostgres(db)
 	bankNotificationPatternRepo := repository.NewBankNotificationPatternPostgres(db)
+	patternPolicyRepo := repository.NewPatternPolicyPostgres(db)
+	patternHistoryRepo := repository.NewPatternHistoryPostgres(db)
+	patternCache := patterncache.New(cfg.Features.PatternCacheSize)
+	notificationFingerprintRepo := repository.NewNotificationFingerprintPostgres(db)
+	notificationInboxRepo := repository.NewNotificationInboxPostgres(db)
+	patternTemplateRepo := repository.NewPatternTemplatePostgres(db)
+	balanceDiscrepancyRepo := repository.NewBalanceDiscrepancyPostgres(db)
+	emailIngestAliasRepo := repository.NewEmailIngestAliasPostgres(db)
+	ledgerRepo := repository.NewLedgerPostgres(db)
+	recurringTransactionRepo := repository.NewRecurringTransactionPostgres(db)
 
 	// Asegurar que existan las categorías por defecto
 	if err := categoryRepo.EnsureDefaultCategoriesExist(); err != nil {
@@ -105,14 +141,56 @@ func initDependencies(db *gorm.DB, jwtManager *auth.JWTManager) *Dependencies {
 	incomeRepo := repository.NewIncomePostgres(db)
 
 	// Inicializar casos de uso
+	patternPolicyUC := usecase.NewPatternPolicyUseCase(bankNotificationPatternRepo, patternPolicyRepo, patternHistoryRepo, patternCache)
+	notificationDedupUC := usecase.NewNotificationDedupUseCase(notificationFingerprintRepo, expenseRepo, time.Duration(cfg.Features.DedupWindowMinutes)*time.Minute)
 	userUC := usecase.NewUserUseCase(userRepo, jwtManager)
-	accountUC := usecase.NewAccountUseCase(accountRepo, userRepo)
-	transactionUC := usecase.NewTransactionUseCase(transactionRepo, accountRepo, userRepo)
+	accountUC := usecase.NewAccountUseCase(accountRepo, userRepo, ledgerRepo)
+	ledgerUC := usecase.NewLedgerUseCase(ledgerRepo, accountRepo)
+	transactionUC := usecase.NewTransactionUseCase(transactionRepo, accountRepo, userRepo, budgetRepo, categoryRepo, patternPolicyUC, time.Duration(cfg.Features.HoldExpiryHours)*time.Hour)
+	recurringTransactionUC := usecase.NewRecurringTransactionUseCase(recurringTransactionRepo, transactionRepo, categoryRepo, userRepo, transactionUC)
 	budgetUC := usecase.NewBudgetUseCase(budgetRepo, categoryRepo, expenseRepo, userRepo)
-	expenseUC := usecase.NewExpenseUseCase(expenseRepo, budgetRepo, categoryRepo, userRepo)
+	expenseUC := usecase.NewExpenseUseCase(expenseRepo, budgetRepo, categoryRepo, userRepo, patternPolicyUC)
 	incomeUC := usecase.NewIncomeUseCase(incomeRepo, userRepo)
 	bankAccountUC := usecase.NewBankAccountUseCase(bankAccountRepo, userRepo)
-	bankNotificationPatternUC := usecase.NewBankNotificationPatternUseCase(bankNotificationPatternRepo, bankAccountRepo, userRepo)
+	balanceReconciliationUC := usecase.NewBalanceReconciliationUseCase(balanceDiscrepancyRepo, bankAccountRepo, transactionRepo, expenseRepo)
+	bankNotificationPatternUC := usecase.NewBankNotificationPatternUseCase(
+		bankNotificationPatternRepo,
+		bankAccountRepo,
+		userRepo,
+		accountRepo,
+		transactionRepo,
+		transactionUC,
+		expenseRepo,
+		budgetRepo,
+		categoryRepo,
+		patternCache,
+		patternPolicyUC,
+		notificationDedupUC,
+		notificationInboxRepo,
+		balanceReconciliationUC,
+		time.Duration(cfg.Features.PatternTimeBudgetMs)*time.Millisecond,
+	)
+	notificationInboxUC := usecase.NewNotificationInboxUseCase(notificationInboxRepo, bankAccountRepo, bankNotificationPatternUC, transactionUC, expenseUC)
+	notificationBatchUC := usecase.NewNotificationBatchUseCase(bankNotificationPatternUC, cfg.Features.BatchWorkers)
+	patternLibraryUC := usecase.NewPatternLibraryUseCase(
+		patternTemplateRepo,
+		bankNotificationPatternRepo,
+		bankAccountRepo,
+		userRepo,
+		patternCache,
+		bankNotificationPatternUC,
+		cfg.Features.PatternLibraryMaintainers,
+	)
+	emailIngestionUC := usecase.NewEmailIngestionUseCase(
+		emailIngestAliasRepo,
+		bankAccountRepo,
+		accountRepo,
+		categoryRepo,
+		bankNotificationPatternUC,
+		cfg.Email.IngestDomain,
+		cfg.Email.IngestToken,
+		cfg.Email.IngestMaxSize,
+	)
 
 	return &Dependencies{
 		UserUC:                    userUC,
@@ -123,6 +201,14 @@ func initDependencies(db *gorm.DB, jwtManager *auth.JWTManager) *Dependencies {
 		IncomeUC:                  incomeUC,
 		BankAccountUC:             bankAccountUC,
 		BankNotificationPatternUC: bankNotificationPatternUC,
+		PatternPolicyUC:           patternPolicyUC,
+		NotificationInboxUC:       notificationInboxUC,
+		NotificationBatchUC:       notificationBatchUC,
+		PatternLibraryUC:          patternLibraryUC,
+		BalanceReconciliationUC:   balanceReconciliationUC,
+		EmailIngestionUC:          emailIngestionUC,
+		LedgerUC:                  ledgerUC,
+		RecurringTransactionUC:    recurringTransactionUC,
 		CategoryRepo:              categoryRepo,
 		JWTManager:                jwtManager,
 	}
@@ -150,7 +236,7 @@ func initHTTPServer(cfg *configs.Config, deps *Dependencies) *gin.Engine {
 	})
 
 	// Inicializar rutas API v1
-	v1.NewRouter(router, deps.UserUC, deps.AccountUC, deps.TransactionUC, deps.BudgetUC, deps.ExpenseUC, deps.IncomeUC, deps.BankAccountUC, deps.BankNotificationPatternUC, deps.CategoryRepo, deps.JWTManager)
+	v1.NewRouter(router, deps.UserUC, deps.AccountUC, deps.TransactionUC, deps.BudgetUC, deps.ExpenseUC, deps.IncomeUC, deps.BankAccountUC, deps.BankNotificationPatternUC, deps.PatternPolicyUC, deps.NotificationInboxUC, deps.NotificationBatchUC, deps.PatternLibraryUC, deps.BalanceReconciliationUC, deps.EmailIngestionUC, deps.LedgerUC, deps.RecurringTransactionUC, deps.CategoryRepo, deps.JWTManager, cfg.Features.AdminEmails)
 
 	// Documentación Swagger (solo en desarrollo)
 	if cfg.Features.EnableSwagger {
@@ -160,6 +246,78 @@ func initHTTPServer(cfg *configs.Config, deps *Dependencies) *gin.Engine {
 	return router
 }
 
+// startInboundMailServer inicia el servidor SMTP/LMTP que recibe los correos enviados a los
+// alias de ingesta, si se configuró su dirección
+func startInboundMailServer(cfg *configs.Config, deps *Dependencies) {
+	if cfg.Email.IngestSMTPAddr == "" {
+		return
+	}
+
+	server := &inboundmail.Server{
+		Addr:           cfg.Email.IngestSMTPAddr,
+		Domain:         cfg.Email.IngestDomain,
+		LMTP:           cfg.Email.IngestLMTP,
+		MaxMessageSize: cfg.Email.IngestMaxSize,
+		MaxSessions:    cfg.Email.IngestMaxSessions,
+		Backend:        deps.EmailIngestionUC,
+	}
+
+	go func() {
+		log.Printf("Inbound mail server starting on %s", cfg.Email.IngestSMTPAddr)
+		if err := server.ListenAndServe(); err != nil {
+			log.Printf("Warning: Inbound mail server stopped: %v", err)
+		}
+	}()
+}
+
+// startRecurringScheduler genera periódicamente las ocurrencias vencidas de las transacciones
+// recurrentes de todos los usuarios, si se configuró un intervalo
+func startRecurringScheduler(cfg *configs.Config, deps *Dependencies) {
+	if cfg.Features.RecurringIntervalMinutes <= 0 {
+		return
+	}
+
+	interval := time.Duration(cfg.Features.RecurringIntervalMinutes) * time.Minute
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			result, err := deps.RecurringTransactionUC.ProcessDue(time.Now())
+			if err != nil {
+				log.Printf("Warning: Failed to process recurring transactions: %v", err)
+			} else if result.Generated > 0 || len(result.Failures) > 0 {
+				log.Printf("Recurring transactions: %d generated, %d skipped, %d failed", result.Generated, result.Skipped, len(result.Failures))
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// startHoldExpiryScheduler vence periódicamente las transacciones pendientes que no se confirmaron
+// dentro de la ventana configurada, liberando sus fondos retenidos
+func startHoldExpiryScheduler(cfg *configs.Config, deps *Dependencies) {
+	if cfg.Features.HoldExpiryHours <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for {
+			expired, err := deps.TransactionUC.ExpirePending(time.Now())
+			if err != nil {
+				log.Printf("Warning: Failed to expire pending transactions: %v", err)
+			}
+			if expired > 0 {
+				log.Printf("Pending transactions: %d holds expired", expired)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
 // runServer ejecuta el servidor con graceful shutdown
 func runServer(router *gin.Engine, port string) {
 	server := router
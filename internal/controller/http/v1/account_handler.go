@@ -176,6 +176,13 @@ func (h *AccountHandler) UpdateAccount(c *gin.Context) {
 			})
 			return
 		}
+		if err.Error() == "cannot change credit account type with a non-zero balance" {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Cannot change account type",
+				Message: err.Error(),
+			})
+			return
+		}
 
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Failed to update account",
@@ -0,0 +1,207 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase"
+	"github.com/nick130920/fintech-backend/pkg/validator"
+)
+
+// BalanceReconciliationHandler maneja las peticiones HTTP de conciliación de saldos bancarios
+type BalanceReconciliationHandler struct {
+	reconciliationUC *usecase.BalanceReconciliationUseCase
+	validator        *validator.Validator
+}
+
+// NewBalanceReconciliationHandler crea una nueva instancia de BalanceReconciliationHandler
+func NewBalanceReconciliationHandler(reconciliationUC *usecase.BalanceReconciliationUseCase) *BalanceReconciliationHandler {
+	return &BalanceReconciliationHandler{
+		reconciliationUC: reconciliationUC,
+		validator:        validator.New(),
+	}
+}
+
+// ListDiscrepancies lista las diferencias de conciliación del usuario
+// @Summary Listar diferencias de conciliación
+// @Description Obtiene las diferencias entre el saldo reportado por el banco en las notificaciones y el saldo esperado según los movimientos registrados
+// @Tags balance-reconciliation
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Estado (open, resolved, dismissed)"
+// @Param bank_account_id query int false "ID de la cuenta bancaria"
+// @Param page query int false "Página (por defecto 1)"
+// @Param per_page query int false "Elementos por página (por defecto 20, máximo 100)"
+// @Success 200 {object} dto.PaginatedBalanceDiscrepancyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /balance-discrepancies [get]
+func (h *BalanceReconciliationHandler) ListDiscrepancies(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	var filter entity.BalanceDiscrepancyFilter
+	if status := c.Query("status"); status != "" {
+		discrepancyStatus := entity.BalanceDiscrepancyStatus(status)
+		filter.Status = &discrepancyStatus
+	}
+	if bankAccountIDStr := c.Query("bank_account_id"); bankAccountIDStr != "" {
+		bankAccountID, err := strconv.ParseUint(bankAccountIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid bank account ID",
+				Message: "Bank account ID must be a valid number",
+			})
+			return
+		}
+		id := uint(bankAccountID)
+		filter.BankAccountID = &id
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	response, err := h.reconciliationUC.ListDiscrepancies(userID.(uint), filter, page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDiscrepancy obtiene una diferencia de conciliación
+// @Summary Obtener diferencia de conciliación
+// @Description Obtiene el detalle de una diferencia de conciliación: saldo anterior, movimientos, saldo esperado y reportado
+// @Tags balance-reconciliation
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la diferencia"
+// @Success 200 {object} dto.BalanceDiscrepancyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /balance-discrepancies/{id} [get]
+func (h *BalanceReconciliationHandler) GetDiscrepancy(c *gin.Context) {
+	userID, discrepancyID, ok := h.parseDiscrepancyRequest(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.reconciliationUC.GetDiscrepancy(userID, discrepancyID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ResolveDiscrepancy cierra una diferencia de conciliación
+// @Summary Resolver diferencia de conciliación
+// @Description Marca una diferencia como resuelta (se corrigieron los movimientos) o descartada (se acepta la diferencia)
+// @Tags balance-reconciliation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la diferencia"
+// @Param resolution body dto.ResolveBalanceDiscrepancyRequest true "Resolución"
+// @Success 200 {object} dto.BalanceDiscrepancyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /balance-discrepancies/{id}/resolve [post]
+func (h *BalanceReconciliationHandler) ResolveDiscrepancy(c *gin.Context) {
+	userID, discrepancyID, ok := h.parseDiscrepancyRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ResolveBalanceDiscrepancyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.reconciliationUC.ResolveDiscrepancy(userID, discrepancyID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseDiscrepancyRequest obtiene el usuario autenticado y el ID de la diferencia
+func (h *BalanceReconciliationHandler) parseDiscrepancyRequest(c *gin.Context) (uint, uint, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return 0, 0, false
+	}
+
+	discrepancyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid discrepancy ID",
+			Message: "Discrepancy ID must be a valid number",
+		})
+		return 0, 0, false
+	}
+
+	return userID.(uint), uint(discrepancyID), true
+}
+
+// handleError traduce los errores de conciliación a respuestas HTTP
+func (h *BalanceReconciliationHandler) handleError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "balance discrepancy not found":
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Not found",
+			Message: err.Error(),
+		})
+		return
+	case "balance discrepancy is already resolved":
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Invalid discrepancy state",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "Internal server error",
+		Message: err.Error(),
+	})
+}
@@ -1,16 +1,23 @@
 package v1
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
 	"github.com/nick130920/fintech-backend/internal/usecase"
+	"github.com/nick130920/fintech-backend/pkg/extractor"
 	"github.com/nick130920/fintech-backend/pkg/validator"
 )
 
+// messageTooLongError es el error que retorna el caso de uso para una notificación más larga que
+// extractor.MaxMessageLength
+var messageTooLongError = fmt.Sprintf("notification message exceeds %d characters", extractor.MaxMessageLength)
+
 // BankNotificationPatternHandler maneja las peticiones HTTP relacionadas con patrones de notificación bancaria
 type BankNotificationPatternHandler struct {
 	patternUC *usecase.BankNotificationPatternUseCase
@@ -75,6 +82,13 @@ func (h *BankNotificationPatternHandler) CreatePattern(c *gin.Context) {
 			})
 			return
 		}
+		if strings.HasPrefix(err.Error(), "invalid regex patterns") {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Validation failed",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Internal server error",
 			Message: err.Error(),
@@ -282,6 +296,14 @@ func (h *BankNotificationPatternHandler) UpdatePattern(c *gin.Context) {
 			})
 			return
 		}
+		// Expresiones inválidas o que superan los límites de seguridad
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Validation failed",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Internal server error",
 			Message: err.Error(),
@@ -413,7 +435,7 @@ func (h *BankNotificationPatternHandler) SetPatternStatus(c *gin.Context) {
 
 // ProcessNotification procesa una notificación bancaria
 // @Summary Procesar notificación bancaria
-// @Description Procesa una notificación bancaria usando patrones de reconocimiento
+// @Description Procesa una notificación bancaria usando patrones de reconocimiento. En modo "transaction" o "expense" registra el movimiento resultante. Si se omite bank_account_id, la cuenta se identifica por los últimos dígitos de tarjeta del mensaje o por el único patrón que lo reconoce. Se aplica la configuración de notificaciones de la cuenta: la respuesta indica en decision y reason_code si la notificación se aceptó, se ignoró (cuenta inactiva, monto menor al mínimo) o quedó en espera (notificaciones deshabilitadas, remitente distinto al configurado)
 // @Tags notification-patterns
 // @Accept json
 // @Produce json
@@ -423,6 +445,7 @@ func (h *BankNotificationPatternHandler) SetPatternStatus(c *gin.Context) {
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /notification-patterns/process [post]
 func (h *BankNotificationPatternHandler) ProcessNotification(c *gin.Context) {
@@ -452,9 +475,241 @@ func (h *BankNotificationPatternHandler) ProcessNotification(c *gin.Context) {
 		return
 	}
 
-	response, err := h.patternUC.ProcessNotification(userID.(uint), req.BankAccountID, req.Channel, req.Message)
+	response, err := h.patternUC.ProcessNotification(userID.(uint), &req)
 	if err != nil {
-		if err.Error() == "unauthorized access to bank account" {
+		switch err.Error() {
+		case "unauthorized access to bank account", "account not found", "category not found":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not found",
+				Message: err.Error(),
+			})
+			return
+		case "no matching pattern for notification",
+			"amount could not be extracted from notification",
+			"account_id is required to create a transaction",
+			"category_id is required to create an expense",
+			"origin account is not active",
+			"no budget found for notification date",
+			"original transaction for reversal not found",
+			"original expense for reversal not found",
+			"bank account could not be resolved from notification",
+			"notification matches several bank accounts",
+			messageTooLongError:
+			c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "Notification could not be ingested",
+				Message: err.Error(),
+			})
+			return
+		}
+		// Errores con datos variables del mensaje o la categoría
+		if strings.HasPrefix(err.Error(), "notification kind ") ||
+			strings.HasPrefix(err.Error(), "invalid extracted amount: ") ||
+			strings.HasPrefix(err.Error(), "category not allocated in budget: ") {
+			c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "Notification could not be ingested",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// InducePattern genera un patrón a partir de mensajes de ejemplo
+// @Summary Generar patrón desde ejemplos
+// @Description Propone un patrón con regex y palabras clave a partir de mensajes de ejemplo con los campos marcados, y lo evalúa contra esos ejemplos. La propuesta no se guarda.
+// @Tags notification-patterns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param examples body dto.InducePatternRequest true "Mensajes de ejemplo con campos marcados"
+// @Success 200 {object} dto.InducedPatternResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-patterns/induce [post]
+func (h *BankNotificationPatternHandler) InducePattern(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	var req dto.InducePatternRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.patternUC.InducePattern(userID.(uint), &req)
+	if err != nil {
+		if err.Error() == "bank account not found" || err.Error() == "unauthorized access to bank account" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid examples") {
+			c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "Pattern could not be generated",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// TestPattern prueba un borrador de patrón contra mensajes de ejemplo
+// @Summary Probar borrador de patrón
+// @Description Aplica un patrón sin guardar a uno o varios mensajes y retorna los campos capturados, las palabras clave activadas y la confianza. No modifica estadísticas ni crea movimientos.
+// @Tags notification-patterns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param test body dto.TestPatternRequest true "Borrador del patrón y mensajes de prueba"
+// @Success 200 {object} dto.PatternTestResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-patterns/test [post]
+func (h *BankNotificationPatternHandler) TestPattern(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	var req dto.TestPatternRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.patternUC.TestPattern(userID.(uint), &req)
+	if err != nil {
+		if err.Error() == "bank account not found" || err.Error() == "unauthorized access to bank account" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		if err.Error() == "pattern is required" || strings.HasPrefix(err.Error(), "invalid regex patterns") {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Validation failed",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// TestSavedPattern prueba un patrón guardado contra mensajes de ejemplo
+// @Summary Probar patrón
+// @Description Aplica un patrón existente a uno o varios mensajes y retorna los campos capturados, las palabras clave activadas y la confianza. No modifica estadísticas ni crea movimientos.
+// @Tags notification-patterns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID del patrón"
+// @Param test body dto.TestPatternRequest true "Mensajes de prueba"
+// @Success 200 {object} dto.PatternTestResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-patterns/{id}/test [post]
+func (h *BankNotificationPatternHandler) TestSavedPattern(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	patternID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid pattern ID",
+			Message: "Pattern ID must be a valid number",
+		})
+		return
+	}
+
+	var req dto.TestPatternRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.patternUC.TestSavedPattern(userID.(uint), uint(patternID), &req)
+	if err != nil {
+		if err.Error() == "pattern not found" || err.Error() == "unauthorized access to pattern" {
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{
 				Error:   "Not found",
 				Message: err.Error(),
@@ -502,3 +757,25 @@ func (h *BankNotificationPatternHandler) GetPatternStatistics(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// GetPatternCacheStats obtiene estadísticas de la caché de patrones
+// @Summary Estadísticas de la caché de patrones
+// @Description Obtiene aciertos, fallos y ocupación de la caché de patrones compilados. La caché es global, por lo que solo está disponible para administradores
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.PatternCacheStatsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Router /admin/notification-patterns/cache/stats [get]
+func (h *BankNotificationPatternHandler) GetPatternCacheStats(c *gin.Context) {
+	if _, exists := c.Get("user_id"); !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.patternUC.GetPatternCacheStats())
+}
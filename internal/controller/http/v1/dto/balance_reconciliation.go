@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// ResolveBalanceDiscrepancyRequest representa la estructura para cerrar una diferencia de conciliación
+type ResolveBalanceDiscrepancyRequest struct {
+	Status entity.BalanceDiscrepancyStatus `json:"status" validate:"required,oneof=resolved dismissed"` // resolved: se corrigieron los movimientos; dismissed: se acepta la diferencia
+	Note   string                          `json:"note" validate:"omitempty,max=500"`
+}
+
+// BalanceDiscrepancyResponse representa una diferencia entre el saldo reportado por el banco y el esperado
+type BalanceDiscrepancyResponse struct {
+	ID                uint                            `json:"id"`
+	BankAccountID     uint                            `json:"bank_account_id"`
+	NotificationID    *uint                           `json:"notification_id"`
+	TransactionID     *uint                           `json:"transaction_id"`
+	ExpenseID         *uint                           `json:"expense_id"`
+	PreviousBalance   money.Amount                    `json:"previous_balance"`
+	PreviousBalanceAt time.Time                       `json:"previous_balance_at"`
+	MovementsTotal    money.Amount                    `json:"movements_total"`
+	ExpectedBalance   money.Amount                    `json:"expected_balance"`
+	ReportedBalance   money.Amount                    `json:"reported_balance"`
+	Difference        money.Amount                    `json:"difference"`
+	Currency          string                          `json:"currency"`
+	Status            entity.BalanceDiscrepancyStatus `json:"status"`
+	ResolutionNote    string                          `json:"resolution_note"`
+	ResolvedAt        *time.Time                      `json:"resolved_at"`
+	CreatedAt         time.Time                       `json:"created_at"`
+}
+
+// PaginatedBalanceDiscrepancyResponse representa una respuesta paginada de diferencias de conciliación
+type PaginatedBalanceDiscrepancyResponse struct {
+	Data       []*BalanceDiscrepancyResponse `json:"data"`
+	Total      int                           `json:"total"`
+	Page       int                           `json:"page"`
+	PerPage    int                           `json:"per_page"`
+	TotalPages int                           `json:"total_pages"`
+}
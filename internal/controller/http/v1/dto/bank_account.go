@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // CreateBankAccountRequest representa la estructura para crear una cuenta bancaria
@@ -21,22 +22,22 @@ type CreateBankAccountRequest struct {
 	IsNotificationEnabled bool                   `json:"is_notification_enabled"`
 	Currency              string                 `json:"currency" validate:"omitempty,len=3"`
 	NotificationPhone     string                 `json:"notification_phone" validate:"omitempty,min=10,max=15"`
-	NotificationEmail     string                 `json:"notification_email" validate:"omitempty,email"`
-	MinAmountToNotify     float64                `json:"min_amount_to_notify" validate:"omitempty,gte=0"`
+	NotificationEmail     string                 `json:"notification_email" validate:"omitempty,email"` // No se verifica en los correos recibidos por el alias de ingesta
+	MinAmountToNotify     money.Amount           `json:"min_amount_to_notify" validate:"omitempty,gte=0"`
 	Notes                 string                 `json:"notes" validate:"omitempty,max=1000"`
 }
 
 // UpdateBankAccountRequest representa la estructura para actualizar una cuenta bancaria
 type UpdateBankAccountRequest struct {
-	BankName              string   `json:"bank_name" validate:"omitempty,min=1,max=100"`
-	AccountAlias          string   `json:"account_alias" validate:"omitempty,min=1,max=100"`
-	Color                 string   `json:"color" validate:"omitempty,hexcolor"`
-	Icon                  string   `json:"icon" validate:"omitempty,max=50"`
-	IsNotificationEnabled *bool    `json:"is_notification_enabled"`
-	NotificationPhone     string   `json:"notification_phone" validate:"omitempty,min=10,max=15"`
-	NotificationEmail     string   `json:"notification_email" validate:"omitempty,email"`
-	MinAmountToNotify     *float64 `json:"min_amount_to_notify" validate:"omitempty,gte=0"`
-	Notes                 string   `json:"notes" validate:"omitempty,max=1000"`
+	BankName              string        `json:"bank_name" validate:"omitempty,min=1,max=100"`
+	AccountAlias          string        `json:"account_alias" validate:"omitempty,min=1,max=100"`
+	Color                 string        `json:"color" validate:"omitempty,hexcolor"`
+	Icon                  string        `json:"icon" validate:"omitempty,max=50"`
+	IsNotificationEnabled *bool         `json:"is_notification_enabled"`
+	NotificationPhone     string        `json:"notification_phone" validate:"omitempty,min=10,max=15"`
+	NotificationEmail     string        `json:"notification_email" validate:"omitempty,email"` // No se verifica en los correos recibidos por el alias de ingesta
+	MinAmountToNotify     *money.Amount `json:"min_amount_to_notify" validate:"omitempty,gte=0"`
+	Notes                 string        `json:"notes" validate:"omitempty,max=1000"`
 }
 
 // SetBankAccountActiveRequest representa la estructura para cambiar el estado activo
@@ -46,7 +47,7 @@ type SetBankAccountActiveRequest struct {
 
 // UpdateBankAccountBalanceRequest representa la estructura para actualizar el balance
 type UpdateBankAccountBalanceRequest struct {
-	Balance float64 `json:"balance" validate:"required"`
+	Balance money.Amount `json:"balance" validate:"required"`
 }
 
 // BankAccountResponse representa la respuesta de una cuenta bancaria
@@ -64,11 +65,11 @@ type BankAccountResponse struct {
 	IsActive              bool                   `json:"is_active"`
 	IsNotificationEnabled bool                   `json:"is_notification_enabled"`
 	Currency              string                 `json:"currency"`
-	LastBalance           float64                `json:"last_balance"`
+	LastBalance           money.Amount           `json:"last_balance"`
 	LastBalanceUpdate     time.Time              `json:"last_balance_update"`
 	NotificationPhone     string                 `json:"notification_phone"`
 	NotificationEmail     string                 `json:"notification_email"`
-	MinAmountToNotify     float64                `json:"min_amount_to_notify"`
+	MinAmountToNotify     money.Amount           `json:"min_amount_to_notify"`
 	Notes                 string                 `json:"notes"`
 	DisplayName           string                 `json:"display_name"`
 	CreatedAt             time.Time              `json:"created_at"`
@@ -87,7 +88,7 @@ type BankAccountSummaryResponse struct {
 	Icon              string                 `json:"icon"`
 	IsActive          bool                   `json:"is_active"`
 	Currency          string                 `json:"currency"`
-	LastBalance       float64                `json:"last_balance"`
+	LastBalance       money.Amount           `json:"last_balance"`
 	LastBalanceUpdate time.Time              `json:"last_balance_update"`
 	DisplayName       string                 `json:"display_name"`
 }
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // CreateBankNotificationPatternRequest representa la estructura para crear un patrón de notificación
@@ -14,12 +15,14 @@ type CreateBankNotificationPatternRequest struct {
 	Channel             entity.NotificationChannel `json:"channel" validate:"required,oneof=sms push email app"`
 	MessagePattern      string                     `json:"message_pattern" validate:"omitempty,max=2000"`
 	ExampleMessage      string                     `json:"example_message" validate:"omitempty,max=2000"`
-	KeywordsTrigger     []string                   `json:"keywords_trigger" validate:"omitempty"`
-	KeywordsExclude     []string                   `json:"keywords_exclude" validate:"omitempty"`
+	KeywordsTrigger     []string                   `json:"keywords_trigger" validate:"omitempty"` // Expresiones: palabra, "frase", "frase"~N, palabra~N, AND/OR/NOT
+	KeywordsExclude     []string                   `json:"keywords_exclude" validate:"omitempty"` // Misma sintaxis que keywords_trigger
 	AmountRegex         string                     `json:"amount_regex" validate:"omitempty,max=500"`
 	DateRegex           string                     `json:"date_regex" validate:"omitempty,max=500"`
 	DescriptionRegex    string                     `json:"description_regex" validate:"omitempty,max=500"`
 	MerchantRegex       string                     `json:"merchant_regex" validate:"omitempty,max=500"`
+	BalanceRegex        string                     `json:"balance_regex" validate:"omitempty,max=500"`                                                                     // Saldo disponible reportado, usado para conciliar la cuenta
+	TransactionKind     entity.TransactionKind     `json:"transaction_kind" validate:"omitempty,oneof=purchase withdrawal refund deposit transfer reversal authorization"` // Por defecto: purchase; el campo "type" extraído tiene prioridad
 	RequiresValidation  bool                       `json:"requires_validation"`
 	ConfidenceThreshold float64                    `json:"confidence_threshold" validate:"omitempty,gte=0,lte=1"`
 	AutoApprove         bool                       `json:"auto_approve"`
@@ -31,23 +34,25 @@ type CreateBankNotificationPatternRequest struct {
 
 // UpdateBankNotificationPatternRequest representa la estructura para actualizar un patrón
 type UpdateBankNotificationPatternRequest struct {
-	Name                *string                `json:"name" validate:"omitempty,min=1,max=100"`
-	Description         *string                `json:"description" validate:"omitempty,max=500"`
-	MessagePattern      *string                `json:"message_pattern" validate:"omitempty,max=2000"`
-	ExampleMessage      *string                `json:"example_message" validate:"omitempty,max=2000"`
-	KeywordsTrigger     []string               `json:"keywords_trigger"`
-	KeywordsExclude     []string               `json:"keywords_exclude"`
-	AmountRegex         *string                `json:"amount_regex" validate:"omitempty,max=500"`
-	DateRegex           *string                `json:"date_regex" validate:"omitempty,max=500"`
-	DescriptionRegex    *string                `json:"description_regex" validate:"omitempty,max=500"`
-	MerchantRegex       *string                `json:"merchant_regex" validate:"omitempty,max=500"`
-	RequiresValidation  *bool                  `json:"requires_validation"`
-	ConfidenceThreshold *float64               `json:"confidence_threshold" validate:"omitempty,gte=0,lte=1"`
-	AutoApprove         *bool                  `json:"auto_approve"`
-	Priority            *int                   `json:"priority" validate:"omitempty,gte=1"`
-	IsDefault           *bool                  `json:"is_default"`
-	Tags                []string               `json:"tags"`
-	Metadata            map[string]interface{} `json:"metadata"`
+	Name                *string                 `json:"name" validate:"omitempty,min=1,max=100"`
+	Description         *string                 `json:"description" validate:"omitempty,max=500"`
+	MessagePattern      *string                 `json:"message_pattern" validate:"omitempty,max=2000"`
+	ExampleMessage      *string                 `json:"example_message" validate:"omitempty,max=2000"`
+	KeywordsTrigger     []string                `json:"keywords_trigger"`
+	KeywordsExclude     []string                `json:"keywords_exclude"`
+	AmountRegex         *string                 `json:"amount_regex" validate:"omitempty,max=500"`
+	DateRegex           *string                 `json:"date_regex" validate:"omitempty,max=500"`
+	DescriptionRegex    *string                 `json:"description_regex" validate:"omitempty,max=500"`
+	MerchantRegex       *string                 `json:"merchant_regex" validate:"omitempty,max=500"`
+	BalanceRegex        *string                 `json:"balance_regex" validate:"omitempty,max=500"`
+	TransactionKind     *entity.TransactionKind `json:"transaction_kind" validate:"omitempty,oneof=purchase withdrawal refund deposit transfer reversal authorization"`
+	RequiresValidation  *bool                   `json:"requires_validation"`
+	ConfidenceThreshold *float64                `json:"confidence_threshold" validate:"omitempty,gte=0,lte=1"`
+	AutoApprove         *bool                   `json:"auto_approve"`
+	Priority            *int                    `json:"priority" validate:"omitempty,gte=1"`
+	IsDefault           *bool                   `json:"is_default"`
+	Tags                []string                `json:"tags"`
+	Metadata            map[string]interface{}  `json:"metadata"`
 }
 
 // SetPatternStatusRequest representa la estructura para cambiar el estado de un patrón
@@ -57,9 +62,57 @@ type SetPatternStatusRequest struct {
 
 // ProcessNotificationRequest representa la estructura para procesar una notificación
 type ProcessNotificationRequest struct {
-	BankAccountID uint                       `json:"bank_account_id" validate:"required"`
-	Channel       entity.NotificationChannel `json:"channel" validate:"required,oneof=sms push email app"`
-	Message       string                     `json:"message" validate:"required,min=1"`
+	BankAccountID uint                             `json:"bank_account_id"` // Si se omite, se identifica por los dígitos de tarjeta del mensaje
+	Channel       entity.NotificationChannel       `json:"channel" validate:"required,oneof=sms push email app"`
+	Message       string                           `json:"message" validate:"required,min=1"`                           // Hasta extractor.MaxMessageLength caracteres; lo valida el caso de uso
+	Mode          entity.NotificationIngestionMode `json:"mode" validate:"omitempty,oneof=preview transaction expense"` // Por defecto: preview
+	AccountID     *uint                            `json:"account_id"`                                                  // Cuenta destino (requerida en modo transaction)
+	CategoryID    *uint                            `json:"category_id"`                                                 // Categoría (requerida en modo expense)
+	ReceivedAt    *time.Time                       `json:"received_at"`                                                 // Hora de recepción en el dispositivo (por defecto: ahora)
+	Sender        string                           `json:"sender" validate:"max=255"`                                   // Teléfono o email remitente; se verifica contra la cuenta
+
+	// SkipSenderCheck omite la verificación del remitente para los canales que ya garantizan el
+	// origen (alias de ingesta de correos). Solo lo asigna el servidor; no se lee del JSON.
+	SkipSenderCheck bool `json:"-"`
+}
+
+// MaxBatchNotifications es el máximo de notificaciones aceptadas en un lote. Alcanza para sincronizar
+// el historial de SMS de una instalación nueva en una sola petición, sin chocar con el rate limit.
+const MaxBatchNotifications = 5000
+
+// BatchProcessNotificationRequest representa la estructura para procesar varias notificaciones en una sola petición
+type BatchProcessNotificationRequest struct {
+	Notifications []ProcessNotificationRequest `json:"notifications" validate:"required,min=1,dive"` // Máximo MaxBatchNotifications
+}
+
+// BatchNotificationStatus define el resultado de una notificación dentro de un lote
+type BatchNotificationStatus string
+
+const (
+	BatchNotificationStatusProcessed BatchNotificationStatus = "processed" // Procesada con un patrón
+	BatchNotificationStatusUnmatched BatchNotificationStatus = "unmatched" // Ningún patrón coincidió
+	BatchNotificationStatusDuplicate BatchNotificationStatus = "duplicate" // Repite un evento ya recibido
+	BatchNotificationStatusSkipped   BatchNotificationStatus = "skipped"   // Ignorada o en espera por la configuración de la cuenta
+	BatchNotificationStatusFailed    BatchNotificationStatus = "failed"    // Error al procesarla
+)
+
+// BatchNotificationResult representa el resultado de una notificación del lote
+type BatchNotificationResult struct {
+	Index  int                            `json:"index"` // Posición en la petición
+	Status BatchNotificationStatus        `json:"status"`
+	Result *ProcessedNotificationResponse `json:"result,omitempty"`
+	Error  string                         `json:"error,omitempty"`
+}
+
+// BatchProcessNotificationResponse representa el resultado de procesar un lote de notificaciones
+type BatchProcessNotificationResponse struct {
+	Total      int                       `json:"total"`
+	Processed  int                       `json:"processed"`
+	Unmatched  int                       `json:"unmatched"`
+	Duplicates int                       `json:"duplicates"`
+	Skipped    int                       `json:"skipped"`
+	Failed     int                       `json:"failed"`
+	Results    []BatchNotificationResult `json:"results"` // En el mismo orden de la petición
 }
 
 // BankNotificationPatternResponse representa la respuesta de un patrón de notificación
@@ -78,17 +131,23 @@ type BankNotificationPatternResponse struct {
 	DateRegex           string                           `json:"date_regex"`
 	DescriptionRegex    string                           `json:"description_regex"`
 	MerchantRegex       string                           `json:"merchant_regex"`
+	BalanceRegex        string                           `json:"balance_regex"`
+	TransactionKind     entity.TransactionKind           `json:"transaction_kind"`
 	RequiresValidation  bool                             `json:"requires_validation"`
 	ConfidenceThreshold float64                          `json:"confidence_threshold"`
 	AutoApprove         bool                             `json:"auto_approve"`
 	MatchCount          int                              `json:"match_count"`
 	SuccessCount        int                              `json:"success_count"`
+	FailureCount        int                              `json:"failure_count"`
 	SuccessRate         float64                          `json:"success_rate"`
 	LastMatchedAt       *time.Time                       `json:"last_matched_at"`
 	Priority            int                              `json:"priority"`
 	IsDefault           bool                             `json:"is_default"`
 	Tags                []string                         `json:"tags"`
 	Metadata            map[string]interface{}           `json:"metadata"`
+	TemplateID          *uint                            `json:"template_id,omitempty"`      // Plantilla de la biblioteca a la que está suscrito
+	TemplateVersion     int                              `json:"template_version,omitempty"` // Versión de la plantilla aplicada
+	Overrides           []string                         `json:"overrides,omitempty"`        // Campos ajustados localmente
 	CreatedAt           time.Time                        `json:"created_at"`
 	UpdatedAt           time.Time                        `json:"updated_at"`
 }
@@ -96,6 +155,7 @@ type BankNotificationPatternResponse struct {
 // ProcessedNotificationResponse representa la respuesta de procesamiento de una notificación
 type ProcessedNotificationResponse struct {
 	BankAccountID      uint                       `json:"bank_account_id"`
+	RoutedBy           string                     `json:"routed_by,omitempty"` // Cómo se identificó la cuenta si no se indicó (card_digits, pattern)
 	Channel            entity.NotificationChannel `json:"channel"`
 	Message            string                     `json:"message"`
 	Processed          bool                       `json:"processed"`
@@ -104,6 +164,54 @@ type ProcessedNotificationResponse struct {
 	Confidence         float64                    `json:"confidence"`
 	RequiresValidation bool                       `json:"requires_validation"`
 	ExtractedData      map[string]interface{}     `json:"extracted_data"`
+	Candidates         []PatternCandidateResponse `json:"candidates"` // Patrones evaluados, de mayor a menor puntaje
+
+	// Valores normalizados según la moneda de la cuenta y el locale del usuario
+	Amount          money.Amount `json:"amount,omitempty"`
+	Currency        string       `json:"currency,omitempty"`
+	TransactionDate *time.Time   `json:"transaction_date,omitempty"`
+
+	// Clase de movimiento (extraída del mensaje o declarada en el patrón) y tipo de transacción resultante
+	TransactionKind entity.TransactionKind `json:"transaction_kind,omitempty"`
+	TransactionType entity.TransactionType `json:"transaction_type,omitempty"`
+	ReportedBalance *money.Amount          `json:"reported_balance,omitempty"` // Saldo disponible reportado por el banco
+
+	// Conciliación del saldo reportado (solo en modos transaction y expense)
+	BalanceDiscrepancyID *uint `json:"balance_discrepancy_id,omitempty"`
+
+	// Decisión según la configuración de notificaciones de la cuenta y su motivo
+	Decision   entity.NotificationDecision `json:"decision"`
+	ReasonCode entity.NotificationReason   `json:"reason_code"`
+
+	// Resultado de la ingesta (solo en modos transaction y expense)
+	Mode                  entity.NotificationIngestionMode `json:"mode"`
+	TransactionID         *uint                            `json:"transaction_id,omitempty"`
+	ReversedTransactionID *uint                            `json:"reversed_transaction_id,omitempty"` // Transacción anulada o compensada por un reverso
+	ExpenseID             *uint                            `json:"expense_id,omitempty"`
+	ReversedExpenseID     *uint                            `json:"reversed_expense_id,omitempty"` // Gasto cancelado por un reverso o devolución
+	ValidationStatus      entity.ValidationStatus          `json:"validation_status,omitempty"`
+
+	// Bandeja y deduplicación entre canales
+	InboxItemID    *uint `json:"inbox_item_id,omitempty"`   // Entrada de la notificación en la bandeja
+	NotificationID *uint `json:"notification_id,omitempty"` // Huella registrada para esta notificación
+	Duplicate      bool  `json:"duplicate"`                 // Si repite un evento ya recibido
+	DuplicateOfID  *uint `json:"duplicate_of_id,omitempty"` // Notificación original
+}
+
+// PatternCandidateResponse representa un patrón evaluado contra una notificación y su puntaje
+type PatternCandidateResponse struct {
+	Rank              int                              `json:"rank"`
+	PatternID         uint                             `json:"pattern_id"`
+	PatternName       string                           `json:"pattern_name"`
+	Status            entity.NotificationPatternStatus `json:"status"`
+	Score             float64                          `json:"score"`          // Puntaje combinado (0-1)
+	Confidence        float64                          `json:"confidence"`     // Proporción de campos extraídos
+	KeywordScore      float64                          `json:"keyword_score"`  // Palabras de activación presentes
+	HistoryScore      float64                          `json:"history_score"`  // Tasa de éxito histórica
+	PriorityScore     float64                          `json:"priority_score"` // Prioridad configurada
+	TriggeredKeywords []string                         `json:"triggered_keywords"`
+	IsDefault         bool                             `json:"is_default"`
+	Selected          bool                             `json:"selected"`
 }
 
 // PatternStatisticsResponse representa estadísticas de patrones
@@ -113,9 +221,21 @@ type PatternStatisticsResponse struct {
 	LearningPatterns   int     `json:"learning_patterns"`
 	TotalMatches       int     `json:"total_matches"`
 	TotalSuccesses     int     `json:"total_successes"`
+	TotalFailures      int     `json:"total_failures"`
 	OverallSuccessRate float64 `json:"overall_success_rate"`
 }
 
+// PatternCacheStatsResponse representa las estadísticas de la caché de patrones compilados
+type PatternCacheStatsResponse struct {
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	Evictions     uint64  `json:"evictions"`
+	Invalidations uint64  `json:"invalidations"`
+	Entries       int     `json:"entries"`
+	Capacity      int     `json:"capacity"`
+	HitRate       float64 `json:"hit_rate"`
+}
+
 // PaginatedBankNotificationPatternResponse representa una respuesta paginada de patrones
 type PaginatedBankNotificationPatternResponse struct {
 	Data       []*BankNotificationPatternResponse `json:"data"`
@@ -124,3 +244,86 @@ type PaginatedBankNotificationPatternResponse struct {
 	PerPage    int                                `json:"per_page"`
 	TotalPages int                                `json:"total_pages"`
 }
+
+// NotificationExampleSpan representa un campo marcado por el usuario en un mensaje de ejemplo
+type NotificationExampleSpan struct {
+	Field string `json:"field" validate:"required,max=50"`  // Nombre del campo (amount, merchant, date...)
+	Value string `json:"value" validate:"required,max=500"` // Texto exacto del campo dentro del mensaje
+}
+
+// NotificationExample representa un mensaje de ejemplo con sus campos marcados
+type NotificationExample struct {
+	Message string                    `json:"message" validate:"required,min=1,max=2000"`
+	Spans   []NotificationExampleSpan `json:"spans" validate:"required,min=1,dive"`
+}
+
+// InducePatternRequest representa la estructura para generar un patrón a partir de ejemplos
+type InducePatternRequest struct {
+	BankAccountID uint                       `json:"bank_account_id" validate:"required"`
+	Channel       entity.NotificationChannel `json:"channel" validate:"required,oneof=sms push email app"`
+	Name          string                     `json:"name" validate:"omitempty,max=100"`
+	Examples      []NotificationExample      `json:"examples" validate:"required,min=1,max=20,dive"`
+}
+
+// InducedPatternResponse representa el patrón propuesto junto con su evaluación sobre los ejemplos
+type InducedPatternResponse struct {
+	Pattern  CreateBankNotificationPatternRequest `json:"pattern"` // Propuesta lista para crear el patrón
+	Score    InductionScoreResponse               `json:"score"`
+	Examples []InductionExampleResult             `json:"examples"`
+}
+
+// InductionScoreResponse representa el resultado de aplicar el patrón propuesto a los ejemplos
+type InductionScoreResponse struct {
+	TotalExamples   int     `json:"total_examples"`
+	MatchedExamples int     `json:"matched_examples"` // Ejemplos en los que todos los campos coinciden
+	TotalFields     int     `json:"total_fields"`
+	CorrectFields   int     `json:"correct_fields"`
+	Score           float64 `json:"score"` // Proporción de campos extraídos correctamente (0-1)
+}
+
+// InductionExampleResult representa el resultado del patrón propuesto sobre un ejemplo
+type InductionExampleResult struct {
+	Index            int                    `json:"index"`
+	Matched          bool                   `json:"matched"`
+	KeywordsMatched  bool                   `json:"keywords_matched"`
+	Confidence       float64                `json:"confidence"`
+	ExtractedData    map[string]interface{} `json:"extracted_data"`
+	MismatchedFields []string               `json:"mismatched_fields,omitempty"`
+}
+
+// TestPatternRequest representa la estructura para probar un patrón contra mensajes de ejemplo.
+// En /notification-patterns/test se prueba el borrador en Pattern; en /:id/test el patrón guardado.
+type TestPatternRequest struct {
+	Pattern  *CreateBankNotificationPatternRequest `json:"pattern"`
+	Messages []string                              `json:"messages" validate:"required,min=1,max=50,dive,required,max=2000"`
+}
+
+// PatternTestResponse representa el resultado de probar un patrón
+type PatternTestResponse struct {
+	PatternID       *uint               `json:"pattern_id,omitempty"` // Solo para patrones guardados
+	TotalMessages   int                 `json:"total_messages"`
+	MatchedMessages int                 `json:"matched_messages"` // Mensajes que pasan las palabras clave y extraen algún campo
+	Results         []PatternTestResult `json:"results"`
+}
+
+// PatternTestResult representa el resultado de aplicar un patrón a un mensaje
+type PatternTestResult struct {
+	Index              int                     `json:"index"`
+	Message            string                  `json:"message"`
+	KeywordsMatched    bool                    `json:"keywords_matched"`
+	TriggeredKeywords  []string                `json:"triggered_keywords"`
+	ExcludedKeywords   []string                `json:"excluded_keywords"`
+	Captures           []FieldCaptureResponse  `json:"captures"`
+	MissingFields      []string                `json:"missing_fields"`
+	ExtractedData      map[string]interface{}  `json:"extracted_data"`
+	Confidence         float64                 `json:"confidence"`
+	RequiresValidation bool                    `json:"requires_validation"`
+	ValidationStatus   entity.ValidationStatus `json:"validation_status"`
+}
+
+// FieldCaptureResponse representa el valor capturado de un campo y la regex que lo capturó
+type FieldCaptureResponse struct {
+	Field  string `json:"field"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // message_pattern o field_regex
+}
@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+)
+
+// UpdateEmailIngestAliasRequest representa la estructura para configurar el alias de ingesta de correos.
+// Los correos recibidos por el alias no se comparan con el email de notificaciones de la cuenta:
+// su remitente no está autenticado y el alias, secreto, ya identifica el origen.
+type UpdateEmailIngestAliasRequest struct {
+	BankAccountID *uint                            `json:"bank_account_id"` // 0 para identificar la cuenta por el mensaje
+	Mode          entity.NotificationIngestionMode `json:"mode" validate:"omitempty,oneof=preview transaction expense"`
+	AccountID     *uint                            `json:"account_id"`  // Cuenta destino (requerida en modo transaction)
+	CategoryID    *uint                            `json:"category_id"` // Categoría (requerida en modo expense)
+	IsActive      *bool                            `json:"is_active"`
+}
+
+// EmailIngestAliasResponse representa el alias de ingesta de correos del usuario
+type EmailIngestAliasResponse struct {
+	ID             uint                             `json:"id"`
+	Alias          string                           `json:"alias"`
+	Address        string                           `json:"address"` // Dirección a la que reenviar los correos del banco
+	BankAccountID  *uint                            `json:"bank_account_id"`
+	IsActive       bool                             `json:"is_active"`
+	Mode           entity.NotificationIngestionMode `json:"mode"`
+	AccountID      *uint                            `json:"account_id"`
+	CategoryID     *uint                            `json:"category_id"`
+	ReceivedCount  int                              `json:"received_count"`
+	LastReceivedAt *time.Time                       `json:"last_received_at"`
+	CreatedAt      time.Time                        `json:"created_at"`
+	UpdatedAt      time.Time                        `json:"updated_at"`
+}
+
+// InboundEmailStatus define el resultado de un correo recibido para uno de sus destinatarios
+type InboundEmailStatus string
+
+const (
+	InboundEmailStatusProcessed InboundEmailStatus = "processed" // Entró al flujo de notificaciones
+	InboundEmailStatusUnknown   InboundEmailStatus = "unknown"   // El destinatario no es un alias de ingesta activo
+	InboundEmailStatusFailed    InboundEmailStatus = "failed"    // Error al procesarlo
+)
+
+// InboundEmailRecipientResult representa el resultado de un correo para uno de sus destinatarios
+type InboundEmailRecipientResult struct {
+	Recipient string                         `json:"recipient"`
+	Status    InboundEmailStatus             `json:"status"`
+	Result    *ProcessedNotificationResponse `json:"result,omitempty"`
+	Error     string                         `json:"error,omitempty"`
+}
+
+// InboundEmailResponse representa el resultado de recibir un correo
+type InboundEmailResponse struct {
+	From       string                        `json:"from"`
+	Subject    string                        `json:"subject"`
+	Recipients []InboundEmailRecipientResult `json:"recipients"`
+}
@@ -1,11 +1,14 @@
 package dto
 
-import "github.com/nick130920/fintech-backend/internal/entity"
+import (
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
 
 // CreateExpenseRequest representa la estructura para crear un gasto
 type CreateExpenseRequest struct {
 	CategoryID  uint                 `json:"category_id" validate:"required"`
-	Amount      float64              `json:"amount" validate:"required,gt=0"`
+	Amount      money.Amount         `json:"amount" validate:"required,gt=0"`
 	Description string               `json:"description" validate:"required,min=1,max=500"`
 	Date        string               `json:"date" validate:"required"` // Formato: 2006-01-02 o 2006-01-02T15:04:05Z
 	Location    string               `json:"location" validate:"max=200"`
@@ -18,21 +21,21 @@ type CreateExpenseRequest struct {
 
 // UpdateExpenseRequest representa la estructura para actualizar un gasto
 type UpdateExpenseRequest struct {
-	CategoryID  *uint    `json:"category_id"`
-	Amount      *float64 `json:"amount" validate:"omitempty,gt=0"`
-	Description string   `json:"description" validate:"omitempty,min=1,max=500"`
-	Date        string   `json:"date"` // Formato: 2006-01-02 o 2006-01-02T15:04:05Z
-	Location    string   `json:"location" validate:"max=200"`
-	Merchant    string   `json:"merchant" validate:"max=100"`
-	Tags        []string `json:"tags"`
-	Notes       string   `json:"notes" validate:"max=1000"`
-	ReceiptURL  string   `json:"receipt_url" validate:"omitempty,url"`
+	CategoryID  *uint         `json:"category_id"`
+	Amount      *money.Amount `json:"amount" validate:"omitempty,gt=0"`
+	Description string        `json:"description" validate:"omitempty,min=1,max=500"`
+	Date        string        `json:"date"` // Formato: 2006-01-02 o 2006-01-02T15:04:05Z
+	Location    string        `json:"location" validate:"max=200"`
+	Merchant    string        `json:"merchant" validate:"max=100"`
+	Tags        []string      `json:"tags"`
+	Notes       string        `json:"notes" validate:"max=1000"`
+	ReceiptURL  string        `json:"receipt_url" validate:"omitempty,url"`
 }
 
 // ExpenseSummaryResponse representa un resumen de gasto
 type ExpenseSummaryResponse struct {
 	ID              uint                    `json:"id"`
-	Amount          float64                 `json:"amount"`
+	Amount          money.Amount            `json:"amount"`
 	FormattedAmount string                  `json:"formatted_amount"`
 	Description     string                  `json:"description"`
 	Date            string                  `json:"date"`     // ISO format
@@ -64,15 +67,15 @@ type ExpenseDetailResponse struct {
 
 // BudgetImpactInfo representa el impacto en el presupuesto
 type BudgetImpactInfo struct {
-	AllocationID            uint    `json:"allocation_id"`
-	PreviousSpent           float64 `json:"previous_spent"`
-	NewSpent                float64 `json:"new_spent"`
-	RemainingBudget         float64 `json:"remaining_budget"`
-	PreviousProgressPercent float64 `json:"previous_progress_percent"`
-	NewProgressPercent      float64 `json:"new_progress_percent"`
-	ExceededBudget          bool    `json:"exceeded_budget"`
-	ExceededDailyLimit      bool    `json:"exceeded_daily_limit"`
-	NewDailyLimit           float64 `json:"new_daily_limit"`
+	AllocationID            uint         `json:"allocation_id"`
+	PreviousSpent           money.Amount `json:"previous_spent"`
+	NewSpent                money.Amount `json:"new_spent"`
+	RemainingBudget         money.Amount `json:"remaining_budget"`
+	PreviousProgressPercent float64      `json:"previous_progress_percent"`
+	NewProgressPercent      float64      `json:"new_progress_percent"`
+	ExceededBudget          bool         `json:"exceeded_budget"`
+	ExceededDailyLimit      bool         `json:"exceeded_daily_limit"`
+	NewDailyLimit           money.Amount `json:"new_daily_limit"`
 }
 
 // CreateExpenseResponse representa la respuesta al crear un gasto
@@ -84,14 +87,14 @@ type CreateExpenseResponse struct {
 
 // ExpenseAlert representa una alerta generada por un gasto
 type ExpenseAlert struct {
-	Type            string  `json:"type"` // "warning", "danger", "over_budget", "daily_limit"
-	Title           string  `json:"title"`
-	Message         string  `json:"message"`
-	CategoryName    string  `json:"category_name"`
-	SpentAmount     float64 `json:"spent_amount"`
-	BudgetAmount    float64 `json:"budget_amount"`
-	ProgressPercent float64 `json:"progress_percent"`
-	Severity        string  `json:"severity"` // "low", "medium", "high"
+	Type            string       `json:"type"` // "warning", "danger", "over_budget", "daily_limit"
+	Title           string       `json:"title"`
+	Message         string       `json:"message"`
+	CategoryName    string       `json:"category_name"`
+	SpentAmount     money.Amount `json:"spent_amount"`
+	BudgetAmount    money.Amount `json:"budget_amount"`
+	ProgressPercent float64      `json:"progress_percent"`
+	Severity        string       `json:"severity"` // "low", "medium", "high"
 }
 
 // ExpenseSuggestion representa una sugerencia para el usuario
@@ -106,9 +109,9 @@ type ExpenseSuggestion struct {
 // ExpenseStatsResponse representa estadísticas de gastos
 type ExpenseStatsResponse struct {
 	Period        string                 `json:"period"` // "today", "week", "month"
-	TotalAmount   float64                `json:"total_amount"`
+	TotalAmount   money.Amount           `json:"total_amount"`
 	TotalCount    int                    `json:"total_count"`
-	AverageAmount float64                `json:"average_amount"`
+	AverageAmount money.Amount           `json:"average_amount"`
 	ByCategory    []CategoryExpenseStats `json:"by_category"`
 	BySource      []SourceExpenseStats   `json:"by_source"`
 	TopMerchants  []MerchantExpenseStats `json:"top_merchants"`
@@ -118,41 +121,41 @@ type ExpenseStatsResponse struct {
 // CategoryExpenseStats representa estadísticas por categoría
 type CategoryExpenseStats struct {
 	Category      CategorySummaryResponse `json:"category"`
-	TotalAmount   float64                 `json:"total_amount"`
+	TotalAmount   money.Amount            `json:"total_amount"`
 	Count         int                     `json:"count"`
-	AverageAmount float64                 `json:"average_amount"`
+	AverageAmount money.Amount            `json:"average_amount"`
 	Percentage    float64                 `json:"percentage"` // Del total
 }
 
 // SourceExpenseStats representa estadísticas por fuente
 type SourceExpenseStats struct {
 	Source      entity.ExpenseSource `json:"source"`
-	TotalAmount float64              `json:"total_amount"`
+	TotalAmount money.Amount         `json:"total_amount"`
 	Count       int                  `json:"count"`
 	Percentage  float64              `json:"percentage"`
 }
 
 // MerchantExpenseStats representa estadísticas por comercio
 type MerchantExpenseStats struct {
-	Merchant      string  `json:"merchant"`
-	TotalAmount   float64 `json:"total_amount"`
-	Count         int     `json:"count"`
-	AverageAmount float64 `json:"average_amount"`
+	Merchant      string       `json:"merchant"`
+	TotalAmount   money.Amount `json:"total_amount"`
+	Count         int          `json:"count"`
+	AverageAmount money.Amount `json:"average_amount"`
 }
 
 // DailyExpenseStats representa estadísticas diarias
 type DailyExpenseStats struct {
-	Date        string  `json:"date"` // YYYY-MM-DD
-	TotalAmount float64 `json:"total_amount"`
-	Count       int     `json:"count"`
+	Date        string       `json:"date"` // YYYY-MM-DD
+	TotalAmount money.Amount `json:"total_amount"`
+	Count       int          `json:"count"`
 }
 
 // QuickAddExpenseRequest representa una estructura simplificada para agregar gastos rápidos
 type QuickAddExpenseRequest struct {
-	CategoryID  uint    `json:"category_id" validate:"required"`
-	Amount      float64 `json:"amount" validate:"required,gt=0"`
-	Description string  `json:"description" validate:"required,min=1,max=100"`
-	Merchant    string  `json:"merchant" validate:"max=50"`
+	CategoryID  uint         `json:"category_id" validate:"required"`
+	Amount      money.Amount `json:"amount" validate:"required,gt=0"`
+	Description string       `json:"description" validate:"required,min=1,max=100"`
+	Merchant    string       `json:"merchant" validate:"max=50"`
 }
 
 // ExpenseConfirmationRequest para confirmar gastos automáticos pendientes
@@ -1,15 +1,17 @@
 package dto
 
+import "github.com/nick130920/fintech-backend/pkg/money"
+
 // CreateIncomeRequest representa la petición para crear un ingreso
 type CreateIncomeRequest struct {
-	Amount      float64 `json:"amount" validate:"required,gt=0"`
-	Description string  `json:"description" validate:"required,max=255"`
-	Source      string  `json:"source" validate:"required,oneof=salary freelance investment business rental bonus gift other"`
-	Date        string  `json:"date" validate:"required"`
-	Notes       string  `json:"notes,omitempty"`
-	TaxDeducted float64 `json:"tax_deducted,omitempty" validate:"gte=0"`
+	Amount      money.Amount `json:"amount" validate:"required,gt=0"`
+	Description string       `json:"description" validate:"required,max=255"`
+	Source      string       `json:"source" validate:"required,oneof=salary freelance investment business rental bonus gift other"`
+	Date        string       `json:"date" validate:"required"`
+	Notes       string       `json:"notes,omitempty"`
+	TaxDeducted money.Amount `json:"tax_deducted,omitempty" validate:"gte=0"`
 
-	// Campos para ingresos recurrentes
+	// Campos para ingresos recurrentes (obsoletos: usar /api/v1/recurring-transactions)
 	IsRecurring bool    `json:"is_recurring"`
 	Frequency   *string `json:"frequency,omitempty" validate:"omitempty,oneof=weekly biweekly monthly quarterly yearly"`
 	EndDate     *string `json:"end_date,omitempty"`
@@ -17,14 +19,14 @@ type CreateIncomeRequest struct {
 
 // UpdateIncomeRequest representa la petición para actualizar un ingreso
 type UpdateIncomeRequest struct {
-	Amount      *float64 `json:"amount,omitempty" validate:"omitempty,gt=0"`
-	Description string   `json:"description,omitempty" validate:"omitempty,max=255"`
-	Source      string   `json:"source,omitempty" validate:"omitempty,oneof=salary freelance investment business rental bonus gift other"`
-	Date        string   `json:"date,omitempty"`
-	Notes       string   `json:"notes,omitempty"`
-	TaxDeducted *float64 `json:"tax_deducted,omitempty" validate:"omitempty,gte=0"`
+	Amount      *money.Amount `json:"amount,omitempty" validate:"omitempty,gt=0"`
+	Description string        `json:"description,omitempty" validate:"omitempty,max=255"`
+	Source      string        `json:"source,omitempty" validate:"omitempty,oneof=salary freelance investment business rental bonus gift other"`
+	Date        string        `json:"date,omitempty"`
+	Notes       string        `json:"notes,omitempty"`
+	TaxDeducted *money.Amount `json:"tax_deducted,omitempty" validate:"omitempty,gte=0"`
 
-	// Campos para ingresos recurrentes
+	// Campos para ingresos recurrentes (obsoletos: usar /api/v1/recurring-transactions)
 	IsRecurring *bool   `json:"is_recurring,omitempty"`
 	Frequency   *string `json:"frequency,omitempty" validate:"omitempty,oneof=weekly biweekly monthly quarterly yearly"`
 	EndDate     *string `json:"end_date,omitempty"`
@@ -32,20 +34,20 @@ type UpdateIncomeRequest struct {
 
 // IncomeResponse representa la respuesta de un ingreso
 type IncomeResponse struct {
-	ID                 uint    `json:"id"`
-	Amount             float64 `json:"amount"`
-	FormattedAmount    string  `json:"formatted_amount"`
-	NetAmount          float64 `json:"net_amount"`
-	FormattedNetAmount string  `json:"formatted_net_amount"`
-	Description        string  `json:"description"`
-	Source             string  `json:"source"`
-	SourceDisplayName  string  `json:"source_display_name"`
-	Date               string  `json:"date"`
-	Notes              string  `json:"notes"`
-	Currency           string  `json:"currency"`
-	TaxDeducted        float64 `json:"tax_deducted"`
+	ID                 uint         `json:"id"`
+	Amount             money.Amount `json:"amount"`
+	FormattedAmount    string       `json:"formatted_amount"`
+	NetAmount          money.Amount `json:"net_amount"`
+	FormattedNetAmount string       `json:"formatted_net_amount"`
+	Description        string       `json:"description"`
+	Source             string       `json:"source"`
+	SourceDisplayName  string       `json:"source_display_name"`
+	Date               string       `json:"date"`
+	Notes              string       `json:"notes"`
+	Currency           string       `json:"currency"`
+	TaxDeducted        money.Amount `json:"tax_deducted"`
 
-	// Campos para ingresos recurrentes
+	// Campos para ingresos recurrentes (obsoletos: usar /api/v1/recurring-transactions)
 	IsRecurring          bool    `json:"is_recurring"`
 	Frequency            *string `json:"frequency,omitempty"`
 	FrequencyDisplayName *string `json:"frequency_display_name,omitempty"`
@@ -63,23 +65,23 @@ type IncomeResponse struct {
 
 // IncomeSummaryResponse representa un resumen de ingresos
 type IncomeSummaryResponse struct {
-	ID                uint    `json:"id"`
-	Amount            float64 `json:"amount"`
-	FormattedAmount   string  `json:"formatted_amount"`
-	Description       string  `json:"description"`
-	Source            string  `json:"source"`
-	SourceDisplayName string  `json:"source_display_name"`
-	Date              string  `json:"date"`
-	Currency          string  `json:"currency"`
-	IsRecurring       bool    `json:"is_recurring"`
-	CreatedAt         string  `json:"created_at"`
+	ID                uint         `json:"id"`
+	Amount            money.Amount `json:"amount"`
+	FormattedAmount   string       `json:"formatted_amount"`
+	Description       string       `json:"description"`
+	Source            string       `json:"source"`
+	SourceDisplayName string       `json:"source_display_name"`
+	Date              string       `json:"date"`
+	Currency          string       `json:"currency"`
+	IsRecurring       bool         `json:"is_recurring"`
+	CreatedAt         string       `json:"created_at"`
 }
 
 // IncomeStatsResponse representa estadísticas de ingresos
 type IncomeStatsResponse struct {
-	TotalIncome             float64                  `json:"total_income"`
+	TotalIncome             money.Amount             `json:"total_income"`
 	FormattedTotalIncome    string                   `json:"formatted_total_income"`
-	MonthlyAverage          float64                  `json:"monthly_average"`
+	MonthlyAverage          money.Amount             `json:"monthly_average"`
 	FormattedMonthlyAverage string                   `json:"formatted_monthly_average"`
 	IncomeBySource          []IncomeBySourceResponse `json:"income_by_source"`
 	MonthlyIncome           []MonthlyIncomeResponse  `json:"monthly_income"`
@@ -90,22 +92,22 @@ type IncomeStatsResponse struct {
 
 // IncomeBySourceResponse representa ingresos agrupados por fuente
 type IncomeBySourceResponse struct {
-	Source            string  `json:"source"`
-	SourceDisplayName string  `json:"source_display_name"`
-	TotalAmount       float64 `json:"total_amount"`
-	FormattedAmount   string  `json:"formatted_amount"`
-	Count             int     `json:"count"`
-	Percentage        float64 `json:"percentage"`
+	Source            string       `json:"source"`
+	SourceDisplayName string       `json:"source_display_name"`
+	TotalAmount       money.Amount `json:"total_amount"`
+	FormattedAmount   string       `json:"formatted_amount"`
+	Count             int          `json:"count"`
+	Percentage        float64      `json:"percentage"`
 }
 
 // MonthlyIncomeResponse representa ingresos agrupados por mes
 type MonthlyIncomeResponse struct {
-	Year            int     `json:"year"`
-	Month           int     `json:"month"`
-	MonthName       string  `json:"month_name"`
-	TotalAmount     float64 `json:"total_amount"`
-	FormattedAmount string  `json:"formatted_amount"`
-	Count           int     `json:"count"`
+	Year            int          `json:"year"`
+	Month           int          `json:"month"`
+	MonthName       string       `json:"month_name"`
+	TotalAmount     money.Amount `json:"total_amount"`
+	FormattedAmount string       `json:"formatted_amount"`
+	Count           int          `json:"count"`
 }
 
 // RecurringIncomeProcessResponse respuesta para procesamiento de ingresos recurrentes
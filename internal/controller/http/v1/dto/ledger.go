@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// LedgerPostingResponse representa una línea de un asiento del libro contable
+type LedgerPostingResponse struct {
+	AccountID *uint                       `json:"account_id"`
+	Nominal   entity.LedgerNominalAccount `json:"nominal,omitempty"`
+	Amount    money.Amount                `json:"amount"` // Positivo: entra a la cuenta; negativo: sale
+}
+
+// LedgerEntryResponse representa un asiento del libro contable
+type LedgerEntryResponse struct {
+	ID            uint                    `json:"id"`
+	Kind          entity.LedgerEntryKind  `json:"kind"`
+	TransactionID *uint                   `json:"transaction_id"`
+	ExpenseID     *uint                   `json:"expense_id"`
+	IncomeID      *uint                   `json:"income_id"`
+	ReversalOfID  *uint                   `json:"reversal_of_id"`
+	Description   string                  `json:"description"`
+	EntryDate     time.Time               `json:"entry_date"`
+	Postings      []LedgerPostingResponse `json:"postings"`
+	CreatedAt     time.Time               `json:"created_at"`
+}
+
+// PaginatedLedgerEntryResponse representa una respuesta paginada de asientos del libro contable
+type PaginatedLedgerEntryResponse struct {
+	Data       []*LedgerEntryResponse `json:"data"`
+	Total      int                    `json:"total"`
+	Page       int                    `json:"page"`
+	PerPage    int                    `json:"per_page"`
+	TotalPages int                    `json:"total_pages"`
+}
+
+// LedgerAccountCheck compara el balance guardado de una cuenta con el que resulta del libro contable
+type LedgerAccountCheck struct {
+	AccountID     uint         `json:"account_id"`
+	Name          string       `json:"name"`
+	Balance       money.Amount `json:"balance"`        // Balance guardado en la cuenta
+	LedgerBalance money.Amount `json:"ledger_balance"` // Balance según las líneas del libro contable
+	Difference    money.Amount `json:"difference"`     // Guardado menos libro contable
+}
+
+// LedgerIntegrityResponse representa el resultado de verificar el libro contable del usuario
+type LedgerIntegrityResponse struct {
+	Balanced           bool                     `json:"balanced"`   // Todos los asientos suman cero
+	Consistent         bool                     `json:"consistent"` // Todas las cuentas coinciden con el libro contable
+	EntriesChecked     int64                    `json:"entries_checked"`
+	UnbalancedEntries  []entity.LedgerImbalance `json:"unbalanced_entries"`
+	AccountsChecked    int                      `json:"accounts_checked"`
+	MismatchedAccounts []LedgerAccountCheck     `json:"mismatched_accounts"`
+	CheckedAt          time.Time                `json:"checked_at"`
+}
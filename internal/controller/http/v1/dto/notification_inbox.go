@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// ReviewNotificationInboxRequest representa los parámetros de ingesta al reprocesar o aprobar una notificación.
+// Los campos omitidos conservan los valores con los que se recibió la notificación.
+type ReviewNotificationInboxRequest struct {
+	BankAccountID *uint                            `json:"bank_account_id"` // Asigna la cuenta bancaria de una notificación que no se pudo enrutar
+	Mode          entity.NotificationIngestionMode `json:"mode" validate:"omitempty,oneof=preview transaction expense"`
+	AccountID     *uint                            `json:"account_id"`
+	CategoryID    *uint                            `json:"category_id"`
+}
+
+// NotificationInboxItemResponse representa una notificación de la bandeja
+type NotificationInboxItemResponse struct {
+	ID              uint                             `json:"id"`
+	BankAccountID   *uint                            `json:"bank_account_id"`
+	Channel         entity.NotificationChannel       `json:"channel"`
+	Message         string                           `json:"message"`
+	Sender          string                           `json:"sender,omitempty"`
+	ReceivedAt      time.Time                        `json:"received_at"`
+	Mode            entity.NotificationIngestionMode `json:"mode"`
+	AccountID       *uint                            `json:"account_id"`
+	CategoryID      *uint                            `json:"category_id"`
+	Status          entity.NotificationInboxStatus   `json:"status"`
+	StatusReason    string                           `json:"status_reason"`
+	ReasonCode      entity.NotificationReason        `json:"reason_code,omitempty"`
+	PatternID       *uint                            `json:"pattern_id"`
+	Confidence      float64                          `json:"confidence"`
+	ExtractedData   map[string]interface{}           `json:"extracted_data"`
+	Amount          money.Amount                     `json:"amount"`
+	Currency        string                           `json:"currency"`
+	TransactionDate *time.Time                       `json:"transaction_date"`
+	Attempts        int                              `json:"attempts"`
+	LastProcessedAt *time.Time                       `json:"last_processed_at"`
+	TransactionID   *uint                            `json:"transaction_id"`
+	ExpenseID       *uint                            `json:"expense_id"`
+	UpdatedAt       time.Time                        `json:"updated_at"`
+}
+
+// PaginatedNotificationInboxResponse representa una respuesta paginada de la bandeja de notificaciones
+type PaginatedNotificationInboxResponse struct {
+	Data       []*NotificationInboxItemResponse `json:"data"`
+	Total      int                              `json:"total"`
+	Page       int                              `json:"page"`
+	PerPage    int                              `json:"per_page"`
+	TotalPages int                              `json:"total_pages"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+)
+
+// PatternTemplateSpec representa una plantilla de la biblioteca en el formato de exportación e importación (JSON o YAML)
+type PatternTemplateSpec struct {
+	Slug                string                     `json:"slug" yaml:"slug" validate:"required,min=1,max=100"`
+	BankCode            string                     `json:"bank_code" yaml:"bank_code" validate:"required,max=10"`
+	BankName            string                     `json:"bank_name" yaml:"bank_name" validate:"omitempty,max=100"`
+	Channel             entity.NotificationChannel `json:"channel" yaml:"channel" validate:"required,oneof=sms push email app"`
+	Version             int                        `json:"version" yaml:"version" validate:"omitempty,gte=1"` // Si se omite al importar, se incrementa la versión actual
+	Name                string                     `json:"name" yaml:"name" validate:"required,min=1,max=100"`
+	Description         string                     `json:"description" yaml:"description,omitempty" validate:"omitempty,max=500"`
+	MessagePattern      string                     `json:"message_pattern" yaml:"message_pattern,omitempty" validate:"omitempty,max=2000"`
+	ExampleMessage      string                     `json:"example_message" yaml:"example_message,omitempty" validate:"omitempty,max=2000"`
+	KeywordsTrigger     []string                   `json:"keywords_trigger" yaml:"keywords_trigger,omitempty"`
+	KeywordsExclude     []string                   `json:"keywords_exclude" yaml:"keywords_exclude,omitempty"`
+	AmountRegex         string                     `json:"amount_regex" yaml:"amount_regex,omitempty" validate:"omitempty,max=500"`
+	DateRegex           string                     `json:"date_regex" yaml:"date_regex,omitempty" validate:"omitempty,max=500"`
+	DescriptionRegex    string                     `json:"description_regex" yaml:"description_regex,omitempty" validate:"omitempty,max=500"`
+	MerchantRegex       string                     `json:"merchant_regex" yaml:"merchant_regex,omitempty" validate:"omitempty,max=500"`
+	BalanceRegex        string                     `json:"balance_regex" yaml:"balance_regex,omitempty" validate:"omitempty,max=500"`
+	TransactionKind     entity.TransactionKind     `json:"transaction_kind" yaml:"transaction_kind,omitempty" validate:"omitempty,oneof=purchase withdrawal refund deposit transfer reversal authorization"`
+	RequiresValidation  bool                       `json:"requires_validation" yaml:"requires_validation"`
+	ConfidenceThreshold float64                    `json:"confidence_threshold" yaml:"confidence_threshold" validate:"omitempty,gte=0,lte=1"`
+	AutoApprove         bool                       `json:"auto_approve" yaml:"auto_approve"`
+	Priority            int                        `json:"priority" yaml:"priority,omitempty" validate:"omitempty,gte=1"`
+	Tags                []string                   `json:"tags" yaml:"tags,omitempty"`
+}
+
+// PatternLibraryDocument representa un conjunto de plantillas exportadas o a importar
+type PatternLibraryDocument struct {
+	Templates []PatternTemplateSpec `json:"templates" yaml:"templates" validate:"required,min=1,max=500,dive"`
+}
+
+// PatternTemplateResponse representa una plantilla de la biblioteca
+type PatternTemplateResponse struct {
+	ID uint `json:"id"`
+	PatternTemplateSpec
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SubscribePatternTemplateRequest representa la estructura para suscribir una cuenta bancaria a una plantilla
+type SubscribePatternTemplateRequest struct {
+	BankAccountID uint `json:"bank_account_id" validate:"required"`
+}
+
+// ImportPatternLibraryResponse representa el resultado de importar plantillas
+type ImportPatternLibraryResponse struct {
+	Created        int                       `json:"created"`
+	Updated        int                       `json:"updated"`
+	Unchanged      int                       `json:"unchanged"`
+	Skipped        []SkippedTemplateResponse `json:"skipped"`
+	SyncedPatterns int                       `json:"synced_patterns"` // Patrones suscritos actualizados a la nueva versión
+}
+
+// SkippedTemplateResponse representa una plantilla no importada
+type SkippedTemplateResponse struct {
+	Slug   string `json:"slug"`
+	Reason string `json:"reason"`
+}
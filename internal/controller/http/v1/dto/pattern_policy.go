@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+)
+
+// UpdatePatternPolicyRequest representa la estructura para actualizar la política de patrones del usuario
+type UpdatePatternPolicyRequest struct {
+	Enabled               *bool    `json:"enabled"`
+	PromoteMinConfirmed   *int     `json:"promote_min_confirmed" validate:"omitempty,gte=1,lte=1000"`
+	PromoteMinSuccessRate *float64 `json:"promote_min_success_rate" validate:"omitempty,gte=0,lte=100"`
+	DemoteMinSamples      *int     `json:"demote_min_samples" validate:"omitempty,gte=1,lte=1000"`
+	DemoteBelowRate       *float64 `json:"demote_below_rate" validate:"omitempty,gte=0,lte=100"`
+	DisableBelowRate      *float64 `json:"disable_below_rate" validate:"omitempty,gte=0,lte=100"`
+}
+
+// PatternPolicyResponse representa la política de promoción y degradación de patrones
+type PatternPolicyResponse struct {
+	Enabled               bool    `json:"enabled"`
+	PromoteMinConfirmed   int     `json:"promote_min_confirmed"`
+	PromoteMinSuccessRate float64 `json:"promote_min_success_rate"`
+	DemoteMinSamples      int     `json:"demote_min_samples"`
+	DemoteBelowRate       float64 `json:"demote_below_rate"`
+	DisableBelowRate      float64 `json:"disable_below_rate"`
+	IsDefault             bool    `json:"is_default"` // true si el usuario aún no personalizó su política
+}
+
+// PatternHistoryResponse representa un cambio de estado de un patrón
+type PatternHistoryResponse struct {
+	ID           uint                             `json:"id"`
+	PatternID    uint                             `json:"pattern_id"`
+	FromStatus   entity.NotificationPatternStatus `json:"from_status"`
+	ToStatus     entity.NotificationPatternStatus `json:"to_status"`
+	Reason       entity.PatternTransitionReason   `json:"reason"`
+	Details      string                           `json:"details"`
+	MatchCount   int                              `json:"match_count"`
+	SuccessCount int                              `json:"success_count"`
+	FailureCount int                              `json:"failure_count"`
+	SuccessRate  float64                          `json:"success_rate"`
+	CreatedAt    time.Time                        `json:"created_at"`
+}
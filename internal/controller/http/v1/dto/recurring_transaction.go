@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// CreateRecurringTransactionRequest representa la estructura para programar una transacción recurrente
+type CreateRecurringTransactionRequest struct {
+	AccountID   uint                   `json:"account_id" validate:"required"`
+	ToAccountID *uint                  `json:"to_account_id"`
+	Type        entity.TransactionType `json:"type" validate:"required,oneof=income expense transfer"`
+	Amount      money.Amount           `json:"amount" validate:"required,gt=0"`
+	Description string                 `json:"description" validate:"required,min=1,max=500"`
+	CategoryID  *uint                  `json:"category_id"`
+	Notes       string                 `json:"notes" validate:"max=1000"`
+
+	// Programación: ej. mensual el día 5, el último día hábil del mes (day_of_month -1 con
+	// business_day previous) o cada 2 semanas (weekly con interval 2)
+	Frequency   entity.RecurrenceFrequency   `json:"frequency" validate:"required,oneof=daily weekly monthly yearly"`
+	Interval    int                          `json:"interval" validate:"omitempty,min=1,max=365"`
+	DayOfMonth  int                          `json:"day_of_month" validate:"omitempty,min=-1,max=31"`
+	BusinessDay entity.BusinessDayAdjustment `json:"business_day" validate:"omitempty,oneof=none previous next"`
+	StartDate   string                       `json:"start_date" validate:"required"` // YYYY-MM-DD
+
+	// Condiciones de fin (opcionales)
+	EndDate        string `json:"end_date"` // YYYY-MM-DD
+	MaxOccurrences *int   `json:"max_occurrences" validate:"omitempty,min=1"`
+}
+
+// UpdateRecurringTransactionRequest representa la estructura para actualizar una transacción
+// recurrente. Los cambios aplican a las ocurrencias que aún no se generaron; para cambiar la
+// programación se crea una nueva recurrencia.
+type UpdateRecurringTransactionRequest struct {
+	Amount         *money.Amount                     `json:"amount" validate:"omitempty,gt=0"`
+	Description    string                            `json:"description" validate:"omitempty,min=1,max=500"`
+	CategoryID     *uint                             `json:"category_id"`
+	Notes          string                            `json:"notes" validate:"max=1000"`
+	EndDate        string                            `json:"end_date"` // YYYY-MM-DD
+	MaxOccurrences *int                              `json:"max_occurrences" validate:"omitempty,min=1"`
+	Status         entity.RecurringTransactionStatus `json:"status" validate:"omitempty,oneof=active paused"`
+}
+
+// SetRecurrenceExceptionRequest representa la estructura para omitir o modificar una sola ocurrencia
+type SetRecurrenceExceptionRequest struct {
+	Action          entity.RecurrenceExceptionAction `json:"action" validate:"required,oneof=skip modify"`
+	Amount          *money.Amount                    `json:"amount" validate:"omitempty,gt=0"`
+	Description     string                           `json:"description" validate:"max=500"`
+	Notes           string                           `json:"notes" validate:"max=1000"`
+	TransactionDate string                           `json:"transaction_date"` // YYYY-MM-DD; mueve la ocurrencia
+}
+
+// RecurringProcessResponse representa el resultado de generar las ocurrencias pendientes
+type RecurringProcessResponse struct {
+	RecurringChecked int                       `json:"recurring_checked"`
+	Generated        int                       `json:"generated"`
+	Skipped          int                       `json:"skipped"`
+	TransactionIDs   []uint                    `json:"transaction_ids"`
+	Failures         []RecurringProcessFailure `json:"failures"`
+	ProcessedAt      time.Time                 `json:"processed_at"`
+}
+
+// RecurringProcessFailure representa una ocurrencia que no se pudo generar; se reintenta en la
+// siguiente ejecución
+type RecurringProcessFailure struct {
+	RecurringID    uint      `json:"recurring_id"`
+	OccurrenceDate time.Time `json:"occurrence_date"`
+	Error          string    `json:"error"`
+}
@@ -1,13 +1,16 @@
 package dto
 
-import "github.com/nick130920/fintech-backend/internal/entity"
+import (
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
 
 // CreateTransactionRequest representa la estructura para crear una transacción
 type CreateTransactionRequest struct {
 	AccountID       uint                   `json:"account_id" validate:"required"`
 	ToAccountID     *uint                  `json:"to_account_id"`
 	Type            entity.TransactionType `json:"type" validate:"required,oneof=income expense transfer"`
-	Amount          float64                `json:"amount" validate:"required,gt=0"`
+	Amount          money.Amount           `json:"amount" validate:"required,gt=0"`
 	Description     string                 `json:"description" validate:"required,min=1,max=500"`
 	CategoryID      *uint                  `json:"category_id"`
 	Tags            []string               `json:"tags"`
@@ -16,6 +19,13 @@ type CreateTransactionRequest struct {
 	Reference       string                 `json:"reference" validate:"max=100"`
 	Notes           string                 `json:"notes" validate:"max=1000"`
 	Currency        string                 `json:"currency" validate:"omitempty,len=3"`
+
+	// Registra la transacción como autorización pendiente (ej: una retención de tarjeta): reduce el
+	// balance disponible pero no el contable hasta que se confirma o vence
+	Hold bool `json:"hold"`
+
+	// Líneas para dividir la transacción entre categorías; deben sumar el monto
+	Splits []TransactionSplitRequest `json:"splits" validate:"omitempty,dive"`
 }
 
 // UpdateTransactionRequest representa la estructura para actualizar una transacción
@@ -29,3 +39,25 @@ type UpdateTransactionRequest struct {
 	Notes           string                   `json:"notes" validate:"max=1000"`
 	Status          entity.TransactionStatus `json:"status" validate:"omitempty,oneof=pending completed cancelled"`
 }
+
+// ClearTransactionRequest representa la estructura para confirmar una transacción pendiente. Si no
+// se indica el monto final se confirma por el monto autorizado.
+type ClearTransactionRequest struct {
+	Amount *money.Amount `json:"amount" validate:"omitempty,gt=0"`
+}
+
+// TransactionSplitRequest representa una línea de una transacción dividida. Si no se indica la
+// asignación de presupuesto de un gasto, se usa la de su categoría en el presupuesto del mes.
+type TransactionSplitRequest struct {
+	CategoryID   *uint        `json:"category_id"`
+	AllocationID *uint        `json:"allocation_id"`
+	Amount       money.Amount `json:"amount" validate:"required,gt=0"`
+	Notes        string       `json:"notes" validate:"max=500"`
+}
+
+// UpdateTransactionSplitsRequest representa la estructura para reemplazar las líneas de una
+// transacción. Una lista vacía deja la transacción sin dividir en la categoría indicada.
+type UpdateTransactionSplitsRequest struct {
+	Splits     []TransactionSplitRequest `json:"splits" validate:"dive"`
+	CategoryID *uint                     `json:"category_id"` // Requerida al quitar la división de una transacción
+}
@@ -1,6 +1,9 @@
 package dto
 
-import "github.com/nick130920/fintech-backend/internal/entity"
+import (
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
 
 // CreateUserRequest representa la estructura para registro de usuario
 type CreateUserRequest struct {
@@ -76,12 +79,12 @@ type UserPublicResponse struct {
 
 // UserStatsResponse representa estadísticas del usuario
 type UserStatsResponse struct {
-	TotalBudgets        int     `json:"total_budgets"`
-	CurrentMonthBudget  float64 `json:"current_month_budget"`
-	TotalSpentThisMonth float64 `json:"total_spent_this_month"`
-	TotalCategories     int     `json:"total_categories"`
-	TotalExpenses       int     `json:"total_expenses"`
-	AccountCreatedDays  int     `json:"account_created_days"`
+	TotalBudgets        int          `json:"total_budgets"`
+	CurrentMonthBudget  money.Amount `json:"current_month_budget"`
+	TotalSpentThisMonth money.Amount `json:"total_spent_this_month"`
+	TotalCategories     int          `json:"total_categories"`
+	TotalExpenses       int          `json:"total_expenses"`
+	AccountCreatedDays  int          `json:"account_created_days"`
 }
 
 // UserProfileResponse representa el perfil completo del usuario
@@ -0,0 +1,260 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/usecase"
+	"github.com/nick130920/fintech-backend/pkg/validator"
+)
+
+// inboundEmailTokenHeader es la cabecera con la que el proveedor de correo se autentica en el webhook
+const inboundEmailTokenHeader = "X-Inbound-Token"
+
+// EmailIngestionHandler maneja las peticiones HTTP de ingesta de correos de notificaciones bancarias
+type EmailIngestionHandler struct {
+	emailIngestionUC *usecase.EmailIngestionUseCase
+	validator        *validator.Validator
+}
+
+// NewEmailIngestionHandler crea una nueva instancia de EmailIngestionHandler
+func NewEmailIngestionHandler(emailIngestionUC *usecase.EmailIngestionUseCase) *EmailIngestionHandler {
+	return &EmailIngestionHandler{
+		emailIngestionUC: emailIngestionUC,
+		validator:        validator.New(),
+	}
+}
+
+// ReceiveEmail recibe un correo del proveedor de correo entrante
+// @Summary Recibir correo entrante
+// @Description Recibe un correo RFC 822/MIME enviado al alias de ingesta de un usuario y lo procesa como notificación del canal email. El cuerpo puede ser el mensaje crudo (message/rfc822) o un formulario multipart con el mensaje en el campo "email" o "body-mime". Los destinatarios del sobre se indican con el parámetro "recipient"; si se omiten, se usan los de las cabeceras. El remitente del correo no está autenticado y no se compara con el email de notificaciones de la cuenta: el alias secreto identifica el origen. Requiere la cabecera X-Inbound-Token.
+// @Tags email-ingestion
+// @Accept message/rfc822,multipart/form-data
+// @Produce json
+// @Param X-Inbound-Token header string true "Token del webhook de correo entrante"
+// @Param recipient query []string false "Destinatarios del sobre"
+// @Success 200 {object} dto.InboundEmailResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /inbound-email [post]
+func (h *EmailIngestionHandler) ReceiveEmail(c *gin.Context) {
+	if !h.emailIngestionUC.WebhookEnabled() {
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+			Error:   "Service unavailable",
+			Message: "Inbound email is not configured",
+		})
+		return
+	}
+	if !h.emailIngestionUC.VerifyToken(c.GetHeader(inboundEmailTokenHeader)) {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Invalid inbound email token",
+		})
+		return
+	}
+
+	raw, recipients, err := readInboundEmail(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.emailIngestionUC.ReceiveEmail(raw, recipients)
+	if err != nil {
+		switch {
+		case err.Error() == "email exceeds maximum size":
+			c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+				Error:   "Email too large",
+				Message: err.Error(),
+			})
+		case strings.HasPrefix(err.Error(), "invalid email"):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid email",
+				Message: err.Error(),
+			})
+		case err.Error() == "no ingest alias among recipients":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not found",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Internal server error",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAlias obtiene el alias de ingesta de correos del usuario
+// @Summary Obtener alias de ingesta de correos
+// @Description Obtiene la dirección personal a la que reenviar los correos de notificaciones del banco. Se crea al consultarla por primera vez.
+// @Tags email-ingestion
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.EmailIngestAliasResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /email-ingest/alias [get]
+func (h *EmailIngestionHandler) GetAlias(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.emailIngestionUC.GetAlias(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateAlias configura el alias de ingesta de correos del usuario
+// @Summary Configurar alias de ingesta de correos
+// @Description Define la cuenta bancaria de los correos recibidos (0 para identificarla por el mensaje), el modo de ingesta y si el alias está activo
+// @Tags email-ingestion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param alias body dto.UpdateEmailIngestAliasRequest true "Configuración del alias"
+// @Success 200 {object} dto.EmailIngestAliasResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /email-ingest/alias [put]
+func (h *EmailIngestionHandler) UpdateAlias(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	var req dto.UpdateEmailIngestAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.emailIngestionUC.UpdateAlias(userID.(uint), &req)
+	if err != nil {
+		if err.Error() == "bank account not found" || err.Error() == "account not found" || err.Error() == "category not found" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RotateAlias reemplaza el alias de ingesta de correos del usuario
+// @Summary Cambiar alias de ingesta de correos
+// @Description Genera una nueva dirección de ingesta; la anterior deja de recibir correos
+// @Tags email-ingestion
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.EmailIngestAliasResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /email-ingest/alias/rotate [post]
+func (h *EmailIngestionHandler) RotateAlias(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.emailIngestionUC.RotateAlias(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// readInboundEmail obtiene el mensaje crudo y los destinatarios del sobre de la petición del proveedor
+func readInboundEmail(c *gin.Context) ([]byte, []string, error) {
+	recipients := splitRecipients(c.QueryArray("recipient"))
+
+	if !strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		raw, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		return raw, recipients, nil
+	}
+
+	for _, field := range []string{"email", "body-mime"} {
+		if raw := c.PostForm(field); raw != "" {
+			recipients = append(recipients, splitRecipients(c.PostFormArray("recipient"))...)
+			return []byte(raw), recipients, nil
+		}
+	}
+
+	return nil, nil, errors.New("email field is required")
+}
+
+// splitRecipients separa destinatarios enviados como lista o separados por comas
+func splitRecipients(values []string) []string {
+	var recipients []string
+	for _, value := range values {
+		for _, recipient := range strings.Split(value, ",") {
+			if recipient = strings.TrimSpace(recipient); recipient != "" {
+				recipients = append(recipients, recipient)
+			}
+		}
+	}
+	return recipients
+}
@@ -118,7 +118,7 @@ func (h *ExpenseHandler) CreateExpense(c *gin.Context) {
 		return
 	}
 
-	log.Printf("✅ Gasto creado exitosamente: ID=%d, Monto=%.2f", expense.ID, req.Amount)
+	log.Printf("✅ Gasto creado exitosamente: ID=%d, Monto=%s", expense.ID, req.Amount)
 
 	c.JSON(http.StatusCreated, dto.Response{
 		Code:    "SUCCESS",
@@ -400,3 +400,120 @@ func (h *ExpenseHandler) DeleteExpense(c *gin.Context) {
 		Data:    nil,
 	})
 }
+
+// ConfirmExpense godoc
+// @Summary      Confirmar gasto
+// @Description  Confirma un gasto pendiente generado desde una notificación
+// @Tags         expenses
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path uint true "ID del gasto"
+// @Success      200 {object} dto.Response{data=dto.ExpenseSummaryResponse}
+// @Failure      400 {object} dto.ErrorResponse
+// @Failure      401 {object} dto.ErrorResponse
+// @Failure      404 {object} dto.ErrorResponse
+// @Failure      500 {object} dto.ErrorResponse
+// @Router       /api/v1/expenses/{id}/confirm [post]
+func (h *ExpenseHandler) ConfirmExpense(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+
+	// Obtener ID del gasto
+	expenseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Code:    "INVALID_ID",
+			Message: "ID de gasto inválido",
+		})
+		return
+	}
+
+	expense, err := h.expenseUC.ConfirmExpense(userID, uint(expenseID))
+	if err != nil {
+		switch err.Error() {
+		case "expense not found":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Code:    "EXPENSE_NOT_FOUND",
+				Message: "Gasto no encontrado",
+			})
+		case "expense is not pending":
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Code:    "EXPENSE_NOT_PENDING",
+				Message: "Solo se pueden confirmar gastos pendientes",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: "Error interno del servidor",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Code:    "SUCCESS",
+		Message: "Gasto confirmado exitosamente",
+		Data:    expense,
+	})
+}
+
+// RejectExpense godoc
+// @Summary      Rechazar gasto
+// @Description  Cancela un gasto generado desde una notificación y registra el rechazo en el patrón
+// @Tags         expenses
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path uint true "ID del gasto"
+// @Success      200 {object} dto.Response{data=dto.ExpenseSummaryResponse}
+// @Failure      400 {object} dto.ErrorResponse
+// @Failure      401 {object} dto.ErrorResponse
+// @Failure      404 {object} dto.ErrorResponse
+// @Failure      500 {object} dto.ErrorResponse
+// @Router       /api/v1/expenses/{id}/reject [post]
+func (h *ExpenseHandler) RejectExpense(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+
+	// Obtener ID del gasto
+	expenseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Code:    "INVALID_ID",
+			Message: "ID de gasto inválido",
+		})
+		return
+	}
+
+	expense, err := h.expenseUC.RejectExpense(userID, uint(expenseID))
+	if err != nil {
+		switch err.Error() {
+		case "expense not found":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Code:    "EXPENSE_NOT_FOUND",
+				Message: "Gasto no encontrado",
+			})
+		case "expense cannot be cancelled":
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Code:    "EXPENSE_READONLY",
+				Message: "Este gasto no puede ser rechazado",
+			})
+		case "expense was not generated from a notification":
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Code:    "EXPENSE_NOT_FROM_NOTIFICATION",
+				Message: "Solo se pueden rechazar gastos generados desde notificaciones",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: "Error interno del servidor",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Code:    "SUCCESS",
+		Message: "Gasto rechazado exitosamente",
+		Data:    expense,
+	})
+}
@@ -51,7 +51,7 @@ func (h *IncomeHandler) CreateIncome(c *gin.Context) {
 		return
 	}
 
-	log.Printf("[DEBUG] 📥 CreateIncome Request: UserID=%d, Monto=%.2f, Fuente=%s", userID, req.Amount, req.Source)
+	log.Printf("[DEBUG] 📥 CreateIncome Request: UserID=%d, Monto=%s, Fuente=%s", userID, req.Amount, req.Source)
 
 	income, err := h.incomeUC.CreateIncome(userID, &req)
 	if err != nil {
@@ -84,7 +84,7 @@ func (h *IncomeHandler) CreateIncome(c *gin.Context) {
 		return
 	}
 
-	log.Printf("[INFO] ✅ Ingreso creado exitosamente: ID=%d, UserID=%d, Monto=%.2f", income.ID, userID, income.Amount)
+	log.Printf("[INFO] ✅ Ingreso creado exitosamente: ID=%d, UserID=%d, Monto=%s", income.ID, userID, income.Amount)
 
 	c.JSON(http.StatusCreated, dto.Response{
 		Code:    "SUCCESS",
@@ -435,7 +435,7 @@ func (h *IncomeHandler) GetRecentIncomes(c *gin.Context) {
 
 // ProcessRecurringIncomes godoc
 // @Summary Procesar ingresos recurrentes
-// @Description Procesa y genera ingresos recurrentes pendientes
+// @Description Procesa y genera ingresos recurrentes pendientes. Obsoleto: las recurrencias se programan en /api/v1/recurring-transactions y se procesan con POST /api/v1/recurring-transactions/process
 // @Tags incomes
 // @Accept json
 // @Produce json
@@ -443,10 +443,15 @@ func (h *IncomeHandler) GetRecentIncomes(c *gin.Context) {
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Security Bearer
+// @Deprecated
 // @Router /api/v1/incomes/process-recurring [post]
 func (h *IncomeHandler) ProcessRecurringIncomes(c *gin.Context) {
 	userID := getUserID(c)
 
+	// Indicar a los clientes el endpoint que lo reemplaza
+	c.Header("Deprecation", "true")
+	c.Header("Link", `</api/v1/recurring-transactions/process>; rel="successor-version"`)
+
 	result, err := h.incomeUC.ProcessRecurringIncomes(userID)
 	if err != nil {
 		log.Printf("❌ Error al procesar ingresos recurrentes: %v | UserID: %d", err, userID)
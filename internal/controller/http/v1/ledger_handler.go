@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase"
+)
+
+// LedgerHandler maneja las peticiones HTTP del libro contable
+type LedgerHandler struct {
+	ledgerUC *usecase.LedgerUseCase
+}
+
+// NewLedgerHandler crea una nueva instancia de LedgerHandler
+func NewLedgerHandler(ledgerUC *usecase.LedgerUseCase) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerUC: ledgerUC,
+	}
+}
+
+// ListEntries lista los asientos del libro contable del usuario
+// @Summary Listar asientos del libro contable
+// @Description Obtiene los asientos inmutables del libro contable con sus líneas. Cada transacción, transferencia, gasto, ingreso y saldo inicial genera un asiento cuyas líneas suman cero; las modificaciones, cancelaciones y eliminaciones generan un asiento de reverso.
+// @Tags ledger
+// @Produce json
+// @Security BearerAuth
+// @Param account_id query int false "ID de la cuenta"
+// @Param transaction_id query int false "ID de la transacción"
+// @Param kind query string false "Tipo de asiento (opening, transaction, transfer, expense, income, reversal)"
+// @Param page query int false "Página (por defecto 1)"
+// @Param per_page query int false "Elementos por página (por defecto 50, máximo 200)"
+// @Success 200 {object} dto.PaginatedLedgerEntryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /ledger/entries [get]
+func (h *LedgerHandler) ListEntries(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	var filter entity.LedgerEntryFilter
+	if accountIDStr := c.Query("account_id"); accountIDStr != "" {
+		accountID, err := strconv.ParseUint(accountIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid account ID",
+				Message: "Account ID must be a valid number",
+			})
+			return
+		}
+		id := uint(accountID)
+		filter.AccountID = &id
+	}
+	if transactionIDStr := c.Query("transaction_id"); transactionIDStr != "" {
+		transactionID, err := strconv.ParseUint(transactionIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid transaction ID",
+				Message: "Transaction ID must be a valid number",
+			})
+			return
+		}
+		id := uint(transactionID)
+		filter.TransactionID = &id
+	}
+	if kind := c.Query("kind"); kind != "" {
+		entryKind := entity.LedgerEntryKind(kind)
+		filter.Kind = &entryKind
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "50"))
+
+	response, err := h.ledgerUC.ListEntries(userID.(uint), filter, page, perPage)
+	if err != nil {
+		if err.Error() == "account not found" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CheckIntegrity verifica el libro contable del usuario
+// @Summary Verificar integridad del libro contable
+// @Description Comprueba que cada asiento del libro contable sume cero y que el balance de cada cuenta coincida con la suma de sus líneas
+// @Tags ledger
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.LedgerIntegrityResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /ledger/integrity [get]
+func (h *LedgerHandler) CheckIntegrity(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.ledgerUC.CheckIntegrity(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
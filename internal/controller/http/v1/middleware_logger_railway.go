@@ -73,7 +73,7 @@ func RailwayLoggerMiddleware() gin.HandlerFunc {
 
 // logRequestResult registra el resultado del request de forma compacta
 func logRequestResult(requestID, method, path string, status int, latency time.Duration, 
-	ip, userID string, hasAuth bool, responseSize int, errors []error) {
+	ip, userID string, hasAuth bool, responseSize int, errors []*gin.Error) {
 	
 	// Emoji y color según status
 	emoji, level := getStatusEmoji(status)
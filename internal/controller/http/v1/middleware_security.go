@@ -155,7 +155,7 @@ func ValidateContentTypeMiddleware() gin.HandlerFunc {
 		if method == "POST" || method == "PUT" || method == "PATCH" {
 			contentType := c.Request.Header.Get("Content-Type")
 
-			// Permitir application/json y multipart/form-data
+			// Permitir JSON, formularios, YAML (importación de plantillas) y correos entrantes
 			if contentType == "" {
 				AbortWithAppError(c, apperrors.ErrInvalidRequest.WithDetails("Content-Type header requerido"))
 				return
@@ -283,6 +283,10 @@ func isValidContentType(contentType string) bool {
 		"application/json",
 		"multipart/form-data",
 		"application/x-www-form-urlencoded",
+		"application/yaml",
+		"application/x-yaml",
+		"text/yaml",
+		"message/rfc822", // Correo entrante crudo
 	}
 
 	for _, valid := range validTypes {
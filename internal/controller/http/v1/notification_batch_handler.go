@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/usecase"
+	"github.com/nick130920/fintech-backend/pkg/validator"
+)
+
+// NotificationBatchHandler maneja las peticiones HTTP de procesamiento de notificaciones por lotes
+type NotificationBatchHandler struct {
+	batchUC   *usecase.NotificationBatchUseCase
+	validator *validator.Validator
+}
+
+// NewNotificationBatchHandler crea una nueva instancia de NotificationBatchHandler
+func NewNotificationBatchHandler(batchUC *usecase.NotificationBatchUseCase) *NotificationBatchHandler {
+	return &NotificationBatchHandler{
+		batchUC:   batchUC,
+		validator: validator.New(),
+	}
+}
+
+// ProcessBatch procesa un lote de notificaciones bancarias
+// @Summary Procesar lote de notificaciones
+// @Description Procesa hasta 5000 notificaciones en paralelo (las de una misma cuenta bancaria, una a la vez), cada una con su canal, cuenta bancaria y hora de recepción. Retorna el resultado de cada notificación (en el orden recibido) y los totales por estado. Un error en una notificación no detiene el lote.
+// @Tags notification-patterns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param batch body dto.BatchProcessNotificationRequest true "Notificaciones a procesar"
+// @Success 200 {object} dto.BatchProcessNotificationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /notification-patterns/process/batch [post]
+func (h *NotificationBatchHandler) ProcessBatch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	var req dto.BatchProcessNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if len(req.Notifications) > dto.MaxBatchNotifications {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: fmt.Sprintf("a batch accepts at most %d notifications", dto.MaxBatchNotifications),
+		})
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response := h.batchUC.ProcessBatch(userID.(uint), &req)
+
+	c.JSON(http.StatusOK, response)
+}
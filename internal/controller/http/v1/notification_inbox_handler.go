@@ -0,0 +1,313 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase"
+	"github.com/nick130920/fintech-backend/pkg/validator"
+)
+
+// NotificationInboxHandler maneja las peticiones HTTP de la bandeja de notificaciones recibidas
+type NotificationInboxHandler struct {
+	inboxUC   *usecase.NotificationInboxUseCase
+	validator *validator.Validator
+}
+
+// NewNotificationInboxHandler crea una nueva instancia de NotificationInboxHandler
+func NewNotificationInboxHandler(inboxUC *usecase.NotificationInboxUseCase) *NotificationInboxHandler {
+	return &NotificationInboxHandler{
+		inboxUC:   inboxUC,
+		validator: validator.New(),
+	}
+}
+
+// ListItems lista las notificaciones recibidas por el usuario
+// @Summary Listar bandeja de notificaciones
+// @Description Obtiene las notificaciones recibidas con su estado de procesamiento, de la más reciente a la más antigua
+// @Tags notification-inbox
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Estado (unrouted, unmatched, parsed, pending_review, converted, ignored, parked)"
+// @Param bank_account_id query int false "ID de la cuenta bancaria"
+// @Param channel query string false "Canal (sms, push, email, app)"
+// @Param page query int false "Página (por defecto 1)"
+// @Param per_page query int false "Elementos por página (por defecto 20, máximo 100)"
+// @Success 200 {object} dto.PaginatedNotificationInboxResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-inbox [get]
+func (h *NotificationInboxHandler) ListItems(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	var filter entity.NotificationInboxFilter
+	if status := c.Query("status"); status != "" {
+		inboxStatus := entity.NotificationInboxStatus(status)
+		filter.Status = &inboxStatus
+	}
+	if channel := c.Query("channel"); channel != "" {
+		notificationChannel := entity.NotificationChannel(channel)
+		filter.Channel = &notificationChannel
+	}
+	if bankAccountIDStr := c.Query("bank_account_id"); bankAccountIDStr != "" {
+		bankAccountID, err := strconv.ParseUint(bankAccountIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid bank account ID",
+				Message: "Bank account ID must be a valid number",
+			})
+			return
+		}
+		id := uint(bankAccountID)
+		filter.BankAccountID = &id
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	response, err := h.inboxUC.ListItems(userID.(uint), filter, page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetItem obtiene una notificación de la bandeja
+// @Summary Obtener notificación de la bandeja
+// @Description Obtiene una notificación recibida con el resultado de su procesamiento
+// @Tags notification-inbox
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la notificación"
+// @Success 200 {object} dto.NotificationInboxItemResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-inbox/{id} [get]
+func (h *NotificationInboxHandler) GetItem(c *gin.Context) {
+	userID, itemID, ok := h.parseItemRequest(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.inboxUC.GetItem(userID, itemID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RetryItem vuelve a procesar una notificación con los patrones actuales
+// @Summary Reprocesar notificación
+// @Description Aplica nuevamente los patrones actuales a una notificación que no generó movimiento. Los parámetros enviados reemplazan a los originales; bank_account_id asigna la cuenta de una notificación que no se pudo enrutar.
+// @Tags notification-inbox
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la notificación"
+// @Param review body dto.ReviewNotificationInboxRequest false "Parámetros de ingesta"
+// @Success 200 {object} dto.NotificationInboxItemResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-inbox/{id}/retry [post]
+func (h *NotificationInboxHandler) RetryItem(c *gin.Context) {
+	userID, itemID, ok := h.parseItemRequest(c)
+	if !ok {
+		return
+	}
+
+	req, ok := h.bindReviewRequest(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.inboxUC.RetryItem(userID, itemID, req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ApproveItem convierte una notificación en transacción o gasto
+// @Summary Aprobar notificación
+// @Description Aprueba el movimiento pendiente de una notificación o lo crea a partir de los datos extraídos
+// @Tags notification-inbox
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la notificación"
+// @Param review body dto.ReviewNotificationInboxRequest false "Parámetros de ingesta"
+// @Success 200 {object} dto.NotificationInboxItemResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-inbox/{id}/approve [post]
+func (h *NotificationInboxHandler) ApproveItem(c *gin.Context) {
+	userID, itemID, ok := h.parseItemRequest(c)
+	if !ok {
+		return
+	}
+
+	req, ok := h.bindReviewRequest(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.inboxUC.ApproveItem(userID, itemID, req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RejectItem descarta una notificación
+// @Summary Rechazar notificación
+// @Description Descarta una notificación y rechaza su movimiento pendiente, si lo tiene
+// @Tags notification-inbox
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la notificación"
+// @Success 200 {object} dto.NotificationInboxItemResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-inbox/{id}/reject [post]
+func (h *NotificationInboxHandler) RejectItem(c *gin.Context) {
+	userID, itemID, ok := h.parseItemRequest(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.inboxUC.RejectItem(userID, itemID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseItemRequest obtiene el usuario autenticado y el ID de la notificación
+func (h *NotificationInboxHandler) parseItemRequest(c *gin.Context) (uint, uint, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return 0, 0, false
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid notification ID",
+			Message: "Notification ID must be a valid number",
+		})
+		return 0, 0, false
+	}
+
+	return userID.(uint), uint(itemID), true
+}
+
+// bindReviewRequest lee los parámetros de ingesta opcionales del cuerpo de la petición
+func (h *NotificationInboxHandler) bindReviewRequest(c *gin.Context) (*dto.ReviewNotificationInboxRequest, bool) {
+	var req dto.ReviewNotificationInboxRequest
+	if c.Request.ContentLength == 0 {
+		return &req, true
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	return &req, true
+}
+
+// handleError traduce los errores de la bandeja a respuestas HTTP
+func (h *NotificationInboxHandler) handleError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "notification not found", "unauthorized access to bank account", "transaction not found", "expense not found":
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Not found",
+			Message: err.Error(),
+		})
+		return
+	case "notification was already converted",
+		"notification cannot be approved",
+		"notification cannot be rejected",
+		"transaction cannot be approved",
+		"transaction cannot be rejected",
+		"expense is not pending",
+		"expense cannot be cancelled":
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Invalid notification state",
+			Message: err.Error(),
+		})
+		return
+	case "mode is required to approve a notification", "bank account is required to process the notification":
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err.Error() == "notification has no extracted data" || strings.HasPrefix(err.Error(), "notification could not be converted") {
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error:   "Notification could not be converted",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "Internal server error",
+		Message: err.Error(),
+	})
+}
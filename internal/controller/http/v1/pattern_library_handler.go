@@ -0,0 +1,397 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase"
+	"github.com/nick130920/fintech-backend/pkg/validator"
+)
+
+// PatternLibraryHandler maneja las peticiones HTTP de la biblioteca de plantillas de patrones
+type PatternLibraryHandler struct {
+	libraryUC *usecase.PatternLibraryUseCase
+	validator *validator.Validator
+}
+
+// NewPatternLibraryHandler crea una nueva instancia de PatternLibraryHandler
+func NewPatternLibraryHandler(libraryUC *usecase.PatternLibraryUseCase) *PatternLibraryHandler {
+	return &PatternLibraryHandler{
+		libraryUC: libraryUC,
+		validator: validator.New(),
+	}
+}
+
+// ListTemplates lista las plantillas de la biblioteca
+// @Summary Listar plantillas de la biblioteca
+// @Description Obtiene las plantillas de patrones del sistema, opcionalmente filtradas por código de banco y canal
+// @Tags pattern-library
+// @Produce json
+// @Security BearerAuth
+// @Param bank_code query string false "Código del banco"
+// @Param channel query string false "Canal (sms, push, email, app)"
+// @Success 200 {array} dto.PatternTemplateResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pattern-library [get]
+func (h *PatternLibraryHandler) ListTemplates(c *gin.Context) {
+	if _, exists := c.Get("user_id"); !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	templates, err := h.libraryUC.ListTemplates(templateFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate obtiene una plantilla de la biblioteca
+// @Summary Obtener plantilla de la biblioteca
+// @Description Obtiene una plantilla de patrón del sistema por ID
+// @Tags pattern-library
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la plantilla"
+// @Success 200 {object} dto.PatternTemplateResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pattern-library/{id} [get]
+func (h *PatternLibraryHandler) GetTemplate(c *gin.Context) {
+	if _, exists := c.Get("user_id"); !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.libraryUC.GetTemplate(templateID)
+	if err != nil {
+		if err.Error() == "template not found" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// Subscribe suscribe una cuenta bancaria a una plantilla
+// @Summary Suscribir cuenta bancaria a plantilla
+// @Description Crea un patrón para la cuenta bancaria a partir de la plantilla. El patrón recibe las nuevas versiones de la plantilla, salvo en los campos que el usuario modifique.
+// @Tags pattern-library
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la plantilla"
+// @Param subscription body dto.SubscribePatternTemplateRequest true "Cuenta bancaria a suscribir"
+// @Success 201 {object} dto.BankNotificationPatternResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pattern-library/{id}/subscribe [post]
+func (h *PatternLibraryHandler) Subscribe(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	templateID, ok := parseTemplateID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SubscribePatternTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	pattern, err := h.libraryUC.Subscribe(userID.(uint), templateID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "template not found", "unauthorized access to bank account":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not found",
+				Message: err.Error(),
+			})
+			return
+		case "bank account is already subscribed to template":
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Already subscribed",
+				Message: err.Error(),
+			})
+			return
+		case "template does not match bank account bank":
+			c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "Template not applicable",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, pattern)
+}
+
+// Unsubscribe desvincula un patrón de su plantilla
+// @Summary Desvincular patrón de su plantilla
+// @Description El patrón se conserva como propio de la cuenta bancaria y deja de recibir nuevas versiones de la plantilla
+// @Tags notification-patterns
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID del patrón"
+// @Success 200 {object} dto.BankNotificationPatternResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-patterns/{id}/template [delete]
+func (h *PatternLibraryHandler) Unsubscribe(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	patternID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid pattern ID",
+			Message: "Pattern ID must be a valid number",
+		})
+		return
+	}
+
+	pattern, err := h.libraryUC.Unsubscribe(userID.(uint), uint(patternID))
+	if err != nil {
+		switch err.Error() {
+		case "pattern not found", "unauthorized access to pattern":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not found",
+				Message: err.Error(),
+			})
+			return
+		case "pattern is not subscribed to a template":
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Not subscribed",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pattern)
+}
+
+// Export exporta las plantillas de la biblioteca
+// @Summary Exportar biblioteca de plantillas
+// @Description Exporta las plantillas del sistema en JSON o YAML, en el mismo formato que acepta la importación
+// @Tags pattern-library
+// @Produce json
+// @Produce x-yaml
+// @Security BearerAuth
+// @Param format query string false "Formato (json, yaml)" default(json)
+// @Param bank_code query string false "Código del banco"
+// @Param channel query string false "Canal (sms, push, email, app)"
+// @Success 200 {object} dto.PatternLibraryDocument
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pattern-library/export [get]
+func (h *PatternLibraryHandler) Export(c *gin.Context) {
+	if _, exists := c.Get("user_id"); !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "yaml" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid format",
+			Message: "Format must be json or yaml",
+		})
+		return
+	}
+
+	document, err := h.libraryUC.Export(templateFilter(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=pattern-library."+format)
+	if format == "yaml" {
+		c.YAML(http.StatusOK, document)
+		return
+	}
+	c.JSON(http.StatusOK, document)
+}
+
+// Import importa plantillas a la biblioteca
+// @Summary Importar plantillas a la biblioteca
+// @Description Crea o actualiza plantillas del sistema desde un documento JSON o YAML (según el Content-Type). Las plantillas se identifican por slug; si el contenido cambió, su versión aumenta y los patrones suscritos se actualizan conservando sus ajustes locales. Solo para mantenedores de la biblioteca.
+// @Tags pattern-library
+// @Accept json
+// @Accept x-yaml
+// @Produce json
+// @Security BearerAuth
+// @Param document body dto.PatternLibraryDocument true "Plantillas a importar"
+// @Success 200 {object} dto.ImportPatternLibraryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pattern-library/import [post]
+func (h *PatternLibraryHandler) Import(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	var document dto.PatternLibraryDocument
+	var err error
+	if strings.Contains(c.ContentType(), "yaml") {
+		err = c.ShouldBindYAML(&document)
+	} else {
+		err = c.ShouldBindJSON(&document)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Validate(document); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.libraryUC.Import(userID.(uint), &document)
+	if err != nil {
+		if err.Error() == "only pattern library maintainers can import templates" {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{
+				Error:   "Forbidden",
+				Message: err.Error(),
+			})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid template") {
+			c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "Invalid template",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseTemplateID obtiene el ID de la plantilla de la ruta
+func parseTemplateID(c *gin.Context) (uint, bool) {
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid template ID",
+			Message: "Template ID must be a valid number",
+		})
+		return 0, false
+	}
+	return uint(templateID), true
+}
+
+// templateFilter construye el filtro de la biblioteca a partir de los query params
+func templateFilter(c *gin.Context) entity.PatternTemplateFilter {
+	filter := entity.PatternTemplateFilter{
+		BankCode: c.Query("bank_code"),
+	}
+	if channel := c.Query("channel"); channel != "" {
+		notificationChannel := entity.NotificationChannel(channel)
+		filter.Channel = &notificationChannel
+	}
+	return filter
+}
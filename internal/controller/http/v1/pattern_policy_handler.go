@@ -0,0 +1,169 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/usecase"
+	"github.com/nick130920/fintech-backend/pkg/validator"
+)
+
+// PatternPolicyHandler maneja las peticiones HTTP relacionadas con la política de ciclo de vida de patrones
+type PatternPolicyHandler struct {
+	policyUC  *usecase.PatternPolicyUseCase
+	validator *validator.Validator
+}
+
+// NewPatternPolicyHandler crea una nueva instancia de PatternPolicyHandler
+func NewPatternPolicyHandler(policyUC *usecase.PatternPolicyUseCase) *PatternPolicyHandler {
+	return &PatternPolicyHandler{
+		policyUC:  policyUC,
+		validator: validator.New(),
+	}
+}
+
+// GetPolicy obtiene la política de promoción y degradación de patrones del usuario
+// @Summary Obtener política de patrones
+// @Description Obtiene los umbrales con los que se promueven o degradan los patrones del usuario
+// @Tags notification-patterns
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.PatternPolicyResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-patterns/policy [get]
+func (h *PatternPolicyHandler) GetPolicy(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.policyUC.GetPolicy(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdatePolicy actualiza la política de promoción y degradación de patrones del usuario
+// @Summary Actualizar política de patrones
+// @Description Actualiza los umbrales de promoción y degradación automática de patrones
+// @Tags notification-patterns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param policy body dto.UpdatePatternPolicyRequest true "Umbrales de la política"
+// @Success 200 {object} dto.PatternPolicyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-patterns/policy [put]
+func (h *PatternPolicyHandler) UpdatePolicy(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	var req dto.UpdatePatternPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.policyUC.UpdatePolicy(userID.(uint), &req)
+	if err != nil {
+		if err.Error() == "disable_below_rate cannot be greater than demote_below_rate" ||
+			err.Error() == "demote_below_rate cannot be greater than promote_min_success_rate" {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Validation failed",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPatternHistory obtiene el historial de cambios de estado de un patrón
+// @Summary Historial de estados del patrón
+// @Description Obtiene las promociones, degradaciones y cambios manuales de estado de un patrón
+// @Tags notification-patterns
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID del patrón"
+// @Success 200 {array} dto.PatternHistoryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notification-patterns/{id}/history [get]
+func (h *PatternPolicyHandler) GetPatternHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	patternID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid pattern ID",
+			Message: "Pattern ID must be a valid number",
+		})
+		return
+	}
+
+	response, err := h.policyUC.GetPatternHistory(userID.(uint), uint(patternID))
+	if err != nil {
+		if err.Error() == "pattern not found" || err.Error() == "unauthorized access to pattern" {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,395 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase"
+	"github.com/nick130920/fintech-backend/pkg/validator"
+)
+
+// RecurringTransactionHandler maneja las peticiones HTTP de transacciones recurrentes
+type RecurringTransactionHandler struct {
+	recurringUC *usecase.RecurringTransactionUseCase
+	validator   *validator.Validator
+}
+
+// NewRecurringTransactionHandler crea una nueva instancia de RecurringTransactionHandler
+func NewRecurringTransactionHandler(recurringUC *usecase.RecurringTransactionUseCase) *RecurringTransactionHandler {
+	return &RecurringTransactionHandler{
+		recurringUC: recurringUC,
+		validator:   validator.New(),
+	}
+}
+
+// CreateRecurring programa una transacción recurrente
+// @Summary Crear transacción recurrente
+// @Description Programa un gasto, ingreso o transferencia que se repite (renta, suscripciones, salario). La programación admite frecuencia diaria, semanal, mensual o anual con intervalo (weekly + interval 2 = cada 2 semanas), día del mes (-1 = último día), ajuste a día hábil (previous/next) y fin por fecha o número de ocurrencias. Las ocurrencias ya vencidas se generan al crearla.
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateRecurringTransactionRequest true "Transacción recurrente"
+// @Success 201 {object} entity.RecurringTransaction
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /recurring-transactions [post]
+func (h *RecurringTransactionHandler) CreateRecurring(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	var req dto.CreateRecurringTransactionRequest
+	if !h.bindAndValidate(c, &req) {
+		return
+	}
+
+	recurring, err := h.recurringUC.Create(userID.(uint), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, recurring)
+}
+
+// ListRecurring lista las transacciones recurrentes del usuario
+// @Summary Listar transacciones recurrentes
+// @Description Obtiene las transacciones recurrentes del usuario con su próxima ocurrencia y sus excepciones
+// @Tags recurring-transactions
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Estado (active, paused, ended)"
+// @Success 200 {array} entity.RecurringTransaction
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /recurring-transactions [get]
+func (h *RecurringTransactionHandler) ListRecurring(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	var status *entity.RecurringTransactionStatus
+	if statusStr := c.Query("status"); statusStr != "" {
+		recurringStatus := entity.RecurringTransactionStatus(statusStr)
+		status = &recurringStatus
+	}
+
+	recurrings, err := h.recurringUC.List(userID.(uint), status)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recurrings)
+}
+
+// GetRecurring obtiene una transacción recurrente
+// @Summary Obtener transacción recurrente
+// @Tags recurring-transactions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la transacción recurrente"
+// @Success 200 {object} entity.RecurringTransaction
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /recurring-transactions/{id} [get]
+func (h *RecurringTransactionHandler) GetRecurring(c *gin.Context) {
+	userID, recurringID, ok := h.parseRecurringRequest(c)
+	if !ok {
+		return
+	}
+
+	recurring, err := h.recurringUC.Get(userID, recurringID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recurring)
+}
+
+// UpdateRecurring actualiza una transacción recurrente
+// @Summary Actualizar transacción recurrente
+// @Description Actualiza el monto, la descripción, la categoría o las condiciones de fin de las ocurrencias que aún no se generaron, o pausa y reanuda la recurrencia. Al reanudarla no se generan las ocurrencias que cayeron mientras estuvo pausada.
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la transacción recurrente"
+// @Param request body dto.UpdateRecurringTransactionRequest true "Cambios"
+// @Success 200 {object} entity.RecurringTransaction
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /recurring-transactions/{id} [put]
+func (h *RecurringTransactionHandler) UpdateRecurring(c *gin.Context) {
+	userID, recurringID, ok := h.parseRecurringRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateRecurringTransactionRequest
+	if !h.bindAndValidate(c, &req) {
+		return
+	}
+
+	recurring, err := h.recurringUC.Update(userID, recurringID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recurring)
+}
+
+// DeleteRecurring elimina una transacción recurrente
+// @Summary Eliminar transacción recurrente
+// @Description Elimina la recurrencia; las transacciones ya generadas se conservan
+// @Tags recurring-transactions
+// @Security BearerAuth
+// @Param id path int true "ID de la transacción recurrente"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /recurring-transactions/{id} [delete]
+func (h *RecurringTransactionHandler) DeleteRecurring(c *gin.Context) {
+	userID, recurringID, ok := h.parseRecurringRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.recurringUC.Delete(userID, recurringID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetOccurrences obtiene las próximas ocurrencias de una transacción recurrente
+// @Summary Próximas ocurrencias
+// @Description Obtiene las próximas ocurrencias por generar con las excepciones aplicadas (omitidas o modificadas)
+// @Tags recurring-transactions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la transacción recurrente"
+// @Param count query int false "Número de ocurrencias (por defecto 12, máximo 100)"
+// @Success 200 {array} entity.RecurrenceOccurrence
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /recurring-transactions/{id}/occurrences [get]
+func (h *RecurringTransactionHandler) GetOccurrences(c *gin.Context) {
+	userID, recurringID, ok := h.parseRecurringRequest(c)
+	if !ok {
+		return
+	}
+
+	count, _ := strconv.Atoi(c.DefaultQuery("count", "12"))
+
+	occurrences, err := h.recurringUC.Occurrences(userID, recurringID, count)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, occurrences)
+}
+
+// SetOccurrenceException omite o modifica una ocurrencia
+// @Summary Omitir o modificar una ocurrencia
+// @Description Omite una sola ocurrencia o cambia su monto, descripción, notas o fecha, sin afectar al resto de la programación. Solo aplica a ocurrencias que aún no se generaron; una ocurrencia movida debe quedar entre la anterior y la siguiente.
+// @Tags recurring-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la transacción recurrente"
+// @Param date path string true "Fecha programada de la ocurrencia (YYYY-MM-DD)"
+// @Param request body dto.SetRecurrenceExceptionRequest true "Excepción"
+// @Success 200 {object} entity.RecurrenceOccurrence
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /recurring-transactions/{id}/occurrences/{date} [put]
+func (h *RecurringTransactionHandler) SetOccurrenceException(c *gin.Context) {
+	userID, recurringID, ok := h.parseRecurringRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SetRecurrenceExceptionRequest
+	if !h.bindAndValidate(c, &req) {
+		return
+	}
+
+	occurrence, err := h.recurringUC.SetException(userID, recurringID, c.Param("date"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, occurrence)
+}
+
+// DeleteOccurrenceException devuelve una ocurrencia a su programación
+// @Summary Quitar excepción de una ocurrencia
+// @Description Elimina la omisión o modificación de una ocurrencia, que vuelve a generarse según la programación
+// @Tags recurring-transactions
+// @Security BearerAuth
+// @Param id path int true "ID de la transacción recurrente"
+// @Param date path string true "Fecha programada de la ocurrencia (YYYY-MM-DD)"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /recurring-transactions/{id}/occurrences/{date} [delete]
+func (h *RecurringTransactionHandler) DeleteOccurrenceException(c *gin.Context) {
+	userID, recurringID, ok := h.parseRecurringRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.recurringUC.DeleteException(userID, recurringID, c.Param("date")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ProcessRecurring genera las ocurrencias vencidas del usuario
+// @Summary Generar ocurrencias vencidas
+// @Description Genera como transacciones las ocurrencias vencidas de las transacciones recurrentes del usuario. El proceso también se ejecuta periódicamente y es idempotente: una ocurrencia nunca se genera dos veces. Las que fallan (ej: fondos insuficientes) se reintentan en la siguiente ejecución.
+// @Tags recurring-transactions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.RecurringProcessResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /recurring-transactions/process [post]
+func (h *RecurringTransactionHandler) ProcessRecurring(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	result, err := h.recurringUC.Process(userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// bindAndValidate lee y valida el cuerpo de la petición
+func (h *RecurringTransactionHandler) bindAndValidate(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return false
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+// parseRecurringRequest obtiene el usuario autenticado y el ID de la transacción recurrente
+func (h *RecurringTransactionHandler) parseRecurringRequest(c *gin.Context) (uint, uint, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User ID not found in context",
+		})
+		return 0, 0, false
+	}
+
+	recurringID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid recurring transaction ID",
+			Message: "Recurring transaction ID must be a valid number",
+		})
+		return 0, 0, false
+	}
+
+	return userID.(uint), uint(recurringID), true
+}
+
+// handleError traduce los errores de transacciones recurrentes a respuestas HTTP
+func (h *RecurringTransactionHandler) handleError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "recurring transaction not found", "recurring occurrence not found", "recurrence exception not found":
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Not found",
+			Message: err.Error(),
+		})
+		return
+	case "recurring occurrence already generated", "recurring transaction has ended":
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Invalid recurrence state",
+			Message: err.Error(),
+		})
+		return
+	case "user not found", "user account is not active", "account not found", "destination account not found",
+		"to_account_id is required for transfers", "cannot transfer to the same account",
+		"currency mismatch between accounts", "category not found":
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid recurring transaction",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if strings.HasPrefix(err.Error(), "invalid recurrence") {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid recurrence",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "Internal server error",
+		Message: err.Error(),
+	})
+}
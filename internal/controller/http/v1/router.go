@@ -21,8 +21,17 @@ func NewRouter(
 	incomeUC *usecase.IncomeUseCase,
 	bankAccountUC *usecase.BankAccountUseCase,
 	bankNotificationPatternUC *usecase.BankNotificationPatternUseCase,
+	patternPolicyUC *usecase.PatternPolicyUseCase,
+	notificationInboxUC *usecase.NotificationInboxUseCase,
+	notificationBatchUC *usecase.NotificationBatchUseCase,
+	patternLibraryUC *usecase.PatternLibraryUseCase,
+	balanceReconciliationUC *usecase.BalanceReconciliationUseCase,
+	emailIngestionUC *usecase.EmailIngestionUseCase,
+	ledgerUC *usecase.LedgerUseCase,
+	recurringTransactionUC *usecase.RecurringTransactionUseCase,
 	categoryRepo repo.CategoryRepo,
 	jwtManager *auth.JWTManager,
+	adminEmails []string,
 ) {
 	// Configurar middlewares globales de seguridad
 	setupGlobalMiddlewares(router)
@@ -42,6 +51,14 @@ func NewRouter(
 	incomeHandler := NewIncomeHandler(incomeUC)
 	bankAccountHandler := NewBankAccountHandler(bankAccountUC)
 	bankNotificationPatternHandler := NewBankNotificationPatternHandler(bankNotificationPatternUC)
+	patternPolicyHandler := NewPatternPolicyHandler(patternPolicyUC)
+	notificationInboxHandler := NewNotificationInboxHandler(notificationInboxUC)
+	notificationBatchHandler := NewNotificationBatchHandler(notificationBatchUC)
+	patternLibraryHandler := NewPatternLibraryHandler(patternLibraryUC)
+	balanceReconciliationHandler := NewBalanceReconciliationHandler(balanceReconciliationUC)
+	emailIngestionHandler := NewEmailIngestionHandler(emailIngestionUC)
+	ledgerHandler := NewLedgerHandler(ledgerUC)
+	recurringTransactionHandler := NewRecurringTransactionHandler(recurringTransactionUC)
 	categoryHandler := NewCategoryHandler(categoryRepo)
 
 	// Middleware de autenticación
@@ -63,6 +80,9 @@ func NewRouter(
 		}
 	}
 
+	// Correo entrante del proveedor de correo (autenticado con su propio token)
+	v1.POST("/inbound-email", emailIngestionHandler.ReceiveEmail)
+
 	// Rutas protegidas (requieren autenticación)
 	protectedGroup := v1.Group("/")
 	protectedGroup.Use(authMiddleware.RequireAuth())
@@ -95,8 +115,13 @@ func NewRouter(
 			transactionsGroup.PUT("/:id", transactionHandler.UpdateTransaction)
 			transactionsGroup.DELETE("/:id", transactionHandler.DeleteTransaction)
 			transactionsGroup.POST("/:id/cancel", transactionHandler.CancelTransaction)
+			transactionsGroup.POST("/:id/clear", transactionHandler.ClearTransaction)
+			transactionsGroup.POST("/:id/approve", transactionHandler.ApproveTransaction)
+			transactionsGroup.POST("/:id/reject", transactionHandler.RejectTransaction)
+			transactionsGroup.PUT("/:id/splits", transactionHandler.UpdateTransactionSplits)
 			transactionsGroup.GET("/recent", transactionHandler.GetRecentTransactions)
 			transactionsGroup.GET("/totals", transactionHandler.GetTotalsByType)
+			transactionsGroup.GET("/categories", transactionHandler.GetCategoryTotals)
 		}
 
 		// Rutas de presupuestos
@@ -131,6 +156,8 @@ func NewRouter(
 			expensesGroup.GET("/by-category", expenseHandler.GetExpensesByCategory)
 			expensesGroup.PUT("/:id", expenseHandler.UpdateExpense)
 			expensesGroup.DELETE("/:id", expenseHandler.DeleteExpense)
+			expensesGroup.POST("/:id/confirm", expenseHandler.ConfirmExpense)
+			expensesGroup.POST("/:id/reject", expenseHandler.RejectExpense)
 		}
 
 		// Rutas de ingresos
@@ -177,14 +204,91 @@ func NewRouter(
 			// Otras rutas
 			notificationPatternsGroup.GET("/statistics", bankNotificationPatternHandler.GetPatternStatistics)
 			notificationPatternsGroup.POST("/process", bankNotificationPatternHandler.ProcessNotification)
+			notificationPatternsGroup.POST("/process/batch", notificationBatchHandler.ProcessBatch)
+			notificationPatternsGroup.POST("/induce", bankNotificationPatternHandler.InducePattern)
+			notificationPatternsGroup.POST("/test", bankNotificationPatternHandler.TestPattern)
+			notificationPatternsGroup.GET("/policy", patternPolicyHandler.GetPolicy)
+			notificationPatternsGroup.PUT("/policy", patternPolicyHandler.UpdatePolicy)
 			notificationPatternsGroup.GET("/:id", bankNotificationPatternHandler.GetPattern)
 			notificationPatternsGroup.PUT("/:id", bankNotificationPatternHandler.UpdatePattern)
 			notificationPatternsGroup.DELETE("/:id", bankNotificationPatternHandler.DeletePattern)
 			notificationPatternsGroup.PATCH("/:id/status", bankNotificationPatternHandler.SetPatternStatus)
+			notificationPatternsGroup.GET("/:id/history", patternPolicyHandler.GetPatternHistory)
+			notificationPatternsGroup.POST("/:id/test", bankNotificationPatternHandler.TestSavedPattern)
+			notificationPatternsGroup.DELETE("/:id/template", patternLibraryHandler.Unsubscribe)
 
 			// Rutas de patrones por cuenta bancaria (usando ruta alternativa)
 			notificationPatternsGroup.GET("/bank-account/:bank_account_id", bankNotificationPatternHandler.GetBankAccountPatterns)
 		}
+
+		// Rutas de la biblioteca de plantillas de patrones
+		patternLibraryGroup := protectedGroup.Group("/pattern-library")
+		{
+			patternLibraryGroup.GET("", patternLibraryHandler.ListTemplates)
+			patternLibraryGroup.GET("/", patternLibraryHandler.ListTemplates)
+			patternLibraryGroup.GET("/export", patternLibraryHandler.Export)
+			patternLibraryGroup.POST("/import", patternLibraryHandler.Import)
+			patternLibraryGroup.GET("/:id", patternLibraryHandler.GetTemplate)
+			patternLibraryGroup.POST("/:id/subscribe", patternLibraryHandler.Subscribe)
+		}
+
+		// Rutas de la bandeja de notificaciones recibidas
+		notificationInboxGroup := protectedGroup.Group("/notification-inbox")
+		{
+			notificationInboxGroup.GET("", notificationInboxHandler.ListItems)
+			notificationInboxGroup.GET("/", notificationInboxHandler.ListItems)
+			notificationInboxGroup.GET("/:id", notificationInboxHandler.GetItem)
+			notificationInboxGroup.POST("/:id/retry", notificationInboxHandler.RetryItem)
+			notificationInboxGroup.POST("/:id/approve", notificationInboxHandler.ApproveItem)
+			notificationInboxGroup.POST("/:id/reject", notificationInboxHandler.RejectItem)
+		}
+
+		// Rutas de conciliación de saldos reportados por notificaciones
+		balanceDiscrepanciesGroup := protectedGroup.Group("/balance-discrepancies")
+		{
+			balanceDiscrepanciesGroup.GET("", balanceReconciliationHandler.ListDiscrepancies)
+			balanceDiscrepanciesGroup.GET("/", balanceReconciliationHandler.ListDiscrepancies)
+			balanceDiscrepanciesGroup.GET("/:id", balanceReconciliationHandler.GetDiscrepancy)
+			balanceDiscrepanciesGroup.POST("/:id/resolve", balanceReconciliationHandler.ResolveDiscrepancy)
+		}
+
+		// Rutas del alias de ingesta de correos
+		emailIngestGroup := protectedGroup.Group("/email-ingest")
+		{
+			emailIngestGroup.GET("/alias", emailIngestionHandler.GetAlias)
+			emailIngestGroup.PUT("/alias", emailIngestionHandler.UpdateAlias)
+			emailIngestGroup.POST("/alias/rotate", emailIngestionHandler.RotateAlias)
+		}
+
+		// Rutas del libro contable
+		ledgerGroup := protectedGroup.Group("/ledger")
+		{
+			ledgerGroup.GET("/entries", ledgerHandler.ListEntries)
+			ledgerGroup.GET("/integrity", ledgerHandler.CheckIntegrity)
+		}
+
+		// Rutas de transacciones recurrentes
+		recurringGroup := protectedGroup.Group("/recurring-transactions")
+		{
+			recurringGroup.GET("", recurringTransactionHandler.ListRecurring)
+			recurringGroup.GET("/", recurringTransactionHandler.ListRecurring)
+			recurringGroup.POST("", recurringTransactionHandler.CreateRecurring)
+			recurringGroup.POST("/", recurringTransactionHandler.CreateRecurring)
+			recurringGroup.POST("/process", recurringTransactionHandler.ProcessRecurring)
+			recurringGroup.GET("/:id", recurringTransactionHandler.GetRecurring)
+			recurringGroup.PUT("/:id", recurringTransactionHandler.UpdateRecurring)
+			recurringGroup.DELETE("/:id", recurringTransactionHandler.DeleteRecurring)
+			recurringGroup.GET("/:id/occurrences", recurringTransactionHandler.GetOccurrences)
+			recurringGroup.PUT("/:id/occurrences/:date", recurringTransactionHandler.SetOccurrenceException)
+			recurringGroup.DELETE("/:id/occurrences/:date", recurringTransactionHandler.DeleteOccurrenceException)
+		}
+
+		// Rutas de administración (estado global del servicio)
+		adminGroup := protectedGroup.Group("/admin")
+		adminGroup.Use(authMiddleware.RequireAdmin(adminEmails))
+		{
+			adminGroup.GET("/notification-patterns/cache/stats", bankNotificationPatternHandler.GetPatternCacheStats)
+		}
 	}
 }
 
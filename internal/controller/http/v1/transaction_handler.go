@@ -3,6 +3,7 @@ package v1
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -99,6 +100,14 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 			return
 		}
 
+		if strings.HasPrefix(err.Error(), "invalid split") {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid split",
+				Message: err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Failed to create transaction",
 			Message: err.Error(),
@@ -198,6 +207,22 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 			return
 		}
 
+		if strings.HasPrefix(err.Error(), "invalid split") {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid split",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if err.Error() == "invalid status transition" {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Failed to update transaction",
+				Message: err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Failed to update transaction",
 			Message: err.Error(),
@@ -208,6 +233,88 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedTransaction)
 }
 
+// UpdateTransactionSplits divide una transacción en líneas por categoría
+// @Summary Dividir una transacción por categorías
+// @Description Reemplaza las líneas de una transacción (ej: un ticket de supermercado con comida, hogar y farmacia). Las líneas deben ser al menos dos y sumar el monto de la transacción; cada línea suma al presupuesto y a los reportes de su categoría. Una lista vacía deja la transacción sin dividir.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la transacción"
+// @Param request body dto.UpdateTransactionSplitsRequest true "Líneas de la transacción"
+// @Success 200 {object} entity.Transaction
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /transactions/{id}/splits [put]
+func (h *TransactionHandler) UpdateTransactionSplits(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Valid authentication required",
+		})
+		return
+	}
+
+	transactionIDStr := c.Param("id")
+	transactionID, err := strconv.ParseUint(transactionIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid transaction ID",
+			Message: "Transaction ID must be a valid number",
+		})
+		return
+	}
+
+	var req dto.UpdateTransactionSplitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	transaction, err := h.transactionUC.UpdateSplits(userID, uint(transactionID), &req)
+	if err != nil {
+		switch {
+		case err.Error() == "transaction not found":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Transaction not found",
+				Message: "Transaction not found",
+			})
+		case err.Error() == "transaction cannot be modified":
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Failed to split transaction",
+				Message: err.Error(),
+			})
+		case strings.HasPrefix(err.Error(), "invalid split"):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid split",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to split transaction",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
 // DeleteTransaction elimina una transacción
 func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
 	userID, exists := GetUserIDFromContext(c)
@@ -285,6 +392,207 @@ func (h *TransactionHandler) CancelTransaction(c *gin.Context) {
 	})
 }
 
+// ClearTransaction confirma una transacción pendiente
+// @Summary Confirmar una transacción pendiente
+// @Description Confirma una transacción pendiente (autorización): libera los fondos retenidos y registra el movimiento en el balance contable. El monto final puede diferir del autorizado (ej: propinas o retenciones de combustible); si no se indica se confirma por el monto autorizado.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la transacción"
+// @Param request body dto.ClearTransactionRequest false "Monto final"
+// @Success 200 {object} entity.Transaction
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /transactions/{id}/clear [post]
+func (h *TransactionHandler) ClearTransaction(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Valid authentication required",
+		})
+		return
+	}
+
+	transactionIDStr := c.Param("id")
+	transactionID, err := strconv.ParseUint(transactionIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid transaction ID",
+			Message: "Transaction ID must be a valid number",
+		})
+		return
+	}
+
+	// El cuerpo es opcional: sin monto se confirma por el monto autorizado
+	var req dto.ClearTransactionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid request format",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	transaction, err := h.transactionUC.Clear(userID, uint(transactionID), &req)
+	if err != nil {
+		switch {
+		case err.Error() == "transaction not found":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Transaction not found",
+				Message: "Transaction not found",
+			})
+		case err.Error() == "transaction is not pending", err.Error() == "transaction amount must be positive":
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Failed to clear transaction",
+				Message: err.Error(),
+			})
+		case strings.HasPrefix(err.Error(), "invalid split"):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid split",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to clear transaction",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// ApproveTransaction confirma una transacción generada desde una notificación
+// @Summary Aprobar una transacción generada desde una notificación
+// @Description Marca la transacción como validada por el usuario y registra el acierto en el patrón que la generó. Aprobar una transacción ya aprobada no tiene efecto.
+// @Tags transactions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la transacción"
+// @Success 200 {object} entity.Transaction
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /transactions/{id}/approve [post]
+func (h *TransactionHandler) ApproveTransaction(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Valid authentication required",
+		})
+		return
+	}
+
+	transactionIDStr := c.Param("id")
+	transactionID, err := strconv.ParseUint(transactionIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid transaction ID",
+			Message: "Transaction ID must be a valid number",
+		})
+		return
+	}
+
+	transaction, err := h.transactionUC.Approve(userID, uint(transactionID))
+	if err != nil {
+		switch err.Error() {
+		case "transaction not found":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Transaction not found",
+				Message: "Transaction not found",
+			})
+		case "transaction cannot be approved":
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Failed to approve transaction",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to approve transaction",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// RejectTransaction rechaza una transacción generada desde una notificación
+// @Summary Rechazar una transacción generada desde una notificación
+// @Description Cancela la transacción revirtiendo su efecto en el balance y registra el fallo en el patrón que la generó.
+// @Tags transactions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID de la transacción"
+// @Success 200 {object} entity.Transaction
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /transactions/{id}/reject [post]
+func (h *TransactionHandler) RejectTransaction(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Valid authentication required",
+		})
+		return
+	}
+
+	transactionIDStr := c.Param("id")
+	transactionID, err := strconv.ParseUint(transactionIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid transaction ID",
+			Message: "Transaction ID must be a valid number",
+		})
+		return
+	}
+
+	transaction, err := h.transactionUC.Reject(userID, uint(transactionID))
+	if err != nil {
+		switch err.Error() {
+		case "transaction not found":
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Transaction not found",
+				Message: "Transaction not found",
+			})
+		case "transaction cannot be rejected":
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Failed to reject transaction",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to reject transaction",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
 // GetRecentTransactions obtiene transacciones recientes
 func (h *TransactionHandler) GetRecentTransactions(c *gin.Context) {
 	userID, exists := GetUserIDFromContext(c)
@@ -351,6 +659,54 @@ func (h *TransactionHandler) GetTotalsByType(c *gin.Context) {
 	c.JSON(http.StatusOK, totals)
 }
 
+// GetCategoryTotals obtiene totales por categoría
+// @Summary Totales por categoría
+// @Description Obtiene el número de transacciones completadas y el total por categoría y tipo. Las transacciones divididas suman a la categoría de cada una de sus líneas; las transferencias no se incluyen.
+// @Tags transactions
+// @Produce json
+// @Security BearerAuth
+// @Param from_date query string false "Fecha inicial (YYYY-MM-DD)"
+// @Param to_date query string false "Fecha final (YYYY-MM-DD)"
+// @Success 200 {array} entity.TransactionCategoryTotal
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /transactions/categories [get]
+func (h *TransactionHandler) GetCategoryTotals(c *gin.Context) {
+	userID, exists := GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Valid authentication required",
+		})
+		return
+	}
+
+	var fromDate, toDate *time.Time
+
+	if fromStr := c.Query("from_date"); fromStr != "" {
+		if from, err := time.Parse("2006-01-02", fromStr); err == nil {
+			fromDate = &from
+		}
+	}
+
+	if toStr := c.Query("to_date"); toStr != "" {
+		if to, err := time.Parse("2006-01-02", toStr); err == nil {
+			toDate = &to
+		}
+	}
+
+	totals, err := h.transactionUC.GetCategoryTotals(userID, fromDate, toDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to get category totals",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, totals)
+}
+
 // buildFilterFromQuery construye un filtro desde los query parameters
 func (h *TransactionHandler) buildFilterFromQuery(c *gin.Context) *entity.TransactionFilter {
 	filter := &entity.TransactionFilter{
@@ -366,6 +722,14 @@ func (h *TransactionHandler) buildFilterFromQuery(c *gin.Context) *entity.Transa
 		}
 	}
 
+	// Category ID (incluye transacciones divididas con una línea en la categoría)
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		if categoryID, err := strconv.ParseUint(categoryIDStr, 10, 32); err == nil {
+			categoryIDUint := uint(categoryID)
+			filter.CategoryID = &categoryIDUint
+		}
+	}
+
 	// Type
 	if typeStr := c.Query("type"); typeStr != "" {
 		transType := entity.TransactionType(typeStr)
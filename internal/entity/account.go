@@ -1,9 +1,12 @@
 package entity
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // AccountType define los tipos de cuenta disponibles
@@ -32,10 +35,14 @@ type Account struct {
 	Description string      `json:"description" validate:"max=500"`
 	Type        AccountType `json:"type" gorm:"not null" validate:"required,oneof=checking savings credit investment cash"`
 
-	// Información financiera
-	Balance        float64 `json:"balance" gorm:"default:0;type:decimal(15,2)"`
-	InitialBalance float64 `json:"initial_balance" gorm:"default:0;type:decimal(15,2)"`
-	CreditLimit    float64 `json:"credit_limit" gorm:"default:0;type:decimal(15,2)"` // Para tarjetas de crédito
+	// Información financiera. Balance es la proyección de las líneas del libro contable sobre la
+	// cuenta (balance contable); solo lo actualiza el repositorio al registrar un asiento.
+	// HeldAmount son los fondos retenidos por transacciones pendientes (autorizaciones): reducen el
+	// balance disponible pero no el contable hasta que la transacción se confirma.
+	Balance        money.Amount `json:"balance" gorm:"default:0;type:decimal(15,2)"`
+	HeldAmount     money.Amount `json:"held_amount" gorm:"default:0;type:decimal(15,2)"`
+	InitialBalance money.Amount `json:"initial_balance" gorm:"default:0;type:decimal(15,2)"`
+	CreditLimit    money.Amount `json:"credit_limit" gorm:"default:0;type:decimal(15,2)"` // Para tarjetas de crédito
 
 	// Información bancaria (opcional)
 	BankName      string `json:"bank_name" validate:"max=100"`
@@ -48,27 +55,44 @@ type Account struct {
 	Icon     string `json:"icon" validate:"max=50"`
 
 	// Configuración de alertas
-	LowBalanceAlert bool    `json:"low_balance_alert" gorm:"default:false"`
-	LowBalanceLimit float64 `json:"low_balance_limit" gorm:"default:0;type:decimal(15,2)"`
+	LowBalanceAlert bool         `json:"low_balance_alert" gorm:"default:false"`
+	LowBalanceLimit money.Amount `json:"low_balance_limit" gorm:"default:0;type:decimal(15,2)"`
+}
+
+// MarshalJSON serializa la cuenta incluyendo sus balances contable y disponible
+func (a Account) MarshalJSON() ([]byte, error) {
+	type account Account
+	return json.Marshal(struct {
+		account
+		LedgerBalance    money.Amount `json:"ledger_balance"`
+		AvailableBalance money.Amount `json:"available_balance"`
+	}{
+		account:          account(a),
+		LedgerBalance:    a.Balance,
+		AvailableBalance: a.GetAvailableBalance(),
+	})
 }
 
 // ToSummary convierte una Account a AccountSummary
 func (a *Account) ToSummary() AccountSummary {
 	summary := AccountSummary{
-		ID:       a.ID,
-		Name:     a.Name,
-		Type:     a.Type,
-		Balance:  a.Balance,
-		Currency: a.Currency,
-		Color:    a.Color,
-		Icon:     a.Icon,
-		IsActive: a.IsActive,
-		BankName: a.BankName,
+		ID:               a.ID,
+		Name:             a.Name,
+		Type:             a.Type,
+		Balance:          a.Balance,
+		LedgerBalance:    a.Balance,
+		AvailableBalance: a.GetAvailableBalance(),
+		HeldAmount:       a.HeldAmount,
+		Currency:         a.Currency,
+		Color:            a.Color,
+		Icon:             a.Icon,
+		IsActive:         a.IsActive,
+		BankName:         a.BankName,
 	}
 
 	// Calcular crédito disponible para tarjetas de crédito
 	if a.Type == AccountTypeCredit {
-		summary.AvailableCredit = a.CreditLimit - a.Balance
+		summary.AvailableCredit = summary.AvailableBalance
 	}
 
 	return summary
@@ -87,16 +111,17 @@ func (a *Account) IsCredit() bool {
 	return a.Type == AccountTypeCredit
 }
 
-// GetAvailableBalance retorna el balance disponible considerando límites de crédito
-func (a *Account) GetAvailableBalance() float64 {
+// GetAvailableBalance retorna el balance disponible considerando límites de crédito y los fondos
+// retenidos por transacciones pendientes
+func (a *Account) GetAvailableBalance() money.Amount {
 	if a.IsCredit() {
-		return a.CreditLimit - a.Balance
+		return a.CreditLimit - a.Balance - a.HeldAmount
 	}
-	return a.Balance
+	return a.Balance - a.HeldAmount
 }
 
 // CanDebit verifica si se puede debitar un monto de la cuenta
-func (a *Account) CanDebit(amount float64) bool {
+func (a *Account) CanDebit(amount money.Amount) bool {
 	if !a.IsActive {
 		return false
 	}
@@ -106,7 +131,7 @@ func (a *Account) CanDebit(amount float64) bool {
 }
 
 // Debit debita un monto de la cuenta
-func (a *Account) Debit(amount float64) bool {
+func (a *Account) Debit(amount money.Amount) bool {
 	if !a.CanDebit(amount) {
 		return false
 	}
@@ -121,7 +146,7 @@ func (a *Account) Debit(amount float64) bool {
 }
 
 // Credit acredita un monto a la cuenta
-func (a *Account) Credit(amount float64) {
+func (a *Account) Credit(amount money.Amount) {
 	if a.IsCredit() {
 		a.Balance -= amount // Para crédito, disminuir deuda
 		if a.Balance < 0 {
@@ -132,29 +157,43 @@ func (a *Account) Credit(amount float64) {
 	}
 }
 
+// LedgerAmount convierte un cambio de balance de la cuenta al monto de su línea en el libro contable.
+// En las tarjetas de crédito el balance es la deuda, que aumenta con los créditos.
+func (a *Account) LedgerAmount(balanceChange money.Amount) money.Amount {
+	if a.IsCredit() {
+		return -balanceChange
+	}
+	return balanceChange
+}
+
+// BalanceFromLedger calcula el balance de la cuenta a partir de la suma de sus líneas en el libro contable
+func (a *Account) BalanceFromLedger(net money.Amount) money.Amount {
+	return a.LedgerAmount(net)
+}
+
 // ShouldAlert verifica si debe activarse una alerta de balance bajo
 func (a *Account) ShouldAlert() bool {
 	if !a.LowBalanceAlert || !a.IsActive {
 		return false
 	}
 
-	if a.IsCredit() {
-		return a.GetAvailableBalance() <= a.LowBalanceLimit
-	}
-
-	return a.Balance <= a.LowBalanceLimit
+	return a.GetAvailableBalance() <= a.LowBalanceLimit
 }
 
-// AccountSummary representa un resumen de la cuenta
+// AccountSummary representa un resumen de la cuenta. Balance se conserva por compatibilidad y es
+// igual a LedgerBalance.
 type AccountSummary struct {
-	ID              uint        `json:"id"`
-	Name            string      `json:"name"`
-	Type            AccountType `json:"type"`
-	Balance         float64     `json:"balance"`
-	Currency        string      `json:"currency"`
-	Color           string      `json:"color"`
-	Icon            string      `json:"icon"`
-	IsActive        bool        `json:"is_active"`
-	BankName        string      `json:"bank_name"`
-	AvailableCredit float64     `json:"available_credit,omitempty"` // Para tarjetas de crédito
+	ID               uint         `json:"id"`
+	Name             string       `json:"name"`
+	Type             AccountType  `json:"type"`
+	Balance          money.Amount `json:"balance"`
+	LedgerBalance    money.Amount `json:"ledger_balance"`    // Balance contable (transacciones confirmadas)
+	AvailableBalance money.Amount `json:"available_balance"` // Balance contable menos fondos retenidos
+	HeldAmount       money.Amount `json:"held_amount"`       // Fondos retenidos por transacciones pendientes
+	Currency         string       `json:"currency"`
+	Color            string       `json:"color"`
+	Icon             string       `json:"icon"`
+	IsActive         bool         `json:"is_active"`
+	BankName         string       `json:"bank_name"`
+	AvailableCredit  money.Amount `json:"available_credit,omitempty"` // Para tarjetas de crédito
 }
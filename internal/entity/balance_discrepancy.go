@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// BalanceDiscrepancyStatus define el estado de una diferencia de conciliación
+type BalanceDiscrepancyStatus string
+
+const (
+	BalanceDiscrepancyStatusOpen      BalanceDiscrepancyStatus = "open"      // Pendiente de revisión
+	BalanceDiscrepancyStatusResolved  BalanceDiscrepancyStatus = "resolved"  // El usuario corrigió los movimientos
+	BalanceDiscrepancyStatusDismissed BalanceDiscrepancyStatus = "dismissed" // El usuario aceptó la diferencia
+)
+
+// BalanceDiscrepancy registra una diferencia entre el saldo reportado por el banco en una notificación
+// y el saldo esperado según el saldo anterior y los movimientos registrados desde entonces
+type BalanceDiscrepancy struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relaciones
+	UserID         uint  `json:"user_id" gorm:"not null;index"`
+	BankAccountID  uint  `json:"bank_account_id" gorm:"not null;index"`
+	NotificationID *uint `json:"notification_id" gorm:"index"` // Huella de la notificación que reportó el saldo
+	TransactionID  *uint `json:"transaction_id"`               // Movimiento creado por esa notificación
+	ExpenseID      *uint `json:"expense_id"`
+
+	// Conciliación
+	PreviousBalance   money.Amount `json:"previous_balance" gorm:"type:decimal(15,2)"` // Último saldo conocido antes de la notificación
+	PreviousBalanceAt time.Time    `json:"previous_balance_at"`
+	MovementsTotal    money.Amount `json:"movements_total" gorm:"type:decimal(15,2)"`  // Neto de movimientos registrados desde el saldo anterior
+	ExpectedBalance   money.Amount `json:"expected_balance" gorm:"type:decimal(15,2)"` // Saldo anterior más movimientos
+	ReportedBalance   money.Amount `json:"reported_balance" gorm:"type:decimal(15,2)"` // Saldo reportado por el banco
+	Difference        money.Amount `json:"difference" gorm:"type:decimal(15,2)"`       // Reportado menos esperado
+	Currency          string       `json:"currency" gorm:"size:3"`
+
+	// Resolución
+	Status         BalanceDiscrepancyStatus `json:"status" gorm:"not null;default:'open';index"`
+	ResolutionNote string                   `json:"resolution_note" validate:"max=500"`
+	ResolvedAt     *time.Time               `json:"resolved_at"`
+}
+
+// NewBalanceDiscrepancy calcula la diferencia entre el saldo reportado y el esperado
+func NewBalanceDiscrepancy(bankAccount *BankAccount, movementsTotal, reportedBalance money.Amount) *BalanceDiscrepancy {
+	expected := bankAccount.LastBalance + movementsTotal
+
+	return &BalanceDiscrepancy{
+		UserID:            bankAccount.UserID,
+		BankAccountID:     bankAccount.ID,
+		PreviousBalance:   bankAccount.LastBalance,
+		PreviousBalanceAt: bankAccount.LastBalanceUpdate,
+		MovementsTotal:    movementsTotal,
+		ExpectedBalance:   expected,
+		ReportedBalance:   reportedBalance,
+		Difference:        reportedBalance - expected,
+		Currency:          bankAccount.Currency,
+		Status:            BalanceDiscrepancyStatusOpen,
+	}
+}
+
+// Exceeds verifica si la diferencia supera la tolerancia permitida
+func (bd *BalanceDiscrepancy) Exceeds(tolerance money.Amount) bool {
+	return bd.Difference.Abs() > tolerance
+}
+
+// IsOpen verifica si la diferencia sigue pendiente de revisión
+func (bd *BalanceDiscrepancy) IsOpen() bool {
+	return bd.Status == BalanceDiscrepancyStatusOpen
+}
+
+// Resolve cierra la diferencia con el estado y la nota indicados
+func (bd *BalanceDiscrepancy) Resolve(status BalanceDiscrepancyStatus, note string) {
+	now := time.Now()
+	bd.Status = status
+	bd.ResolutionNote = note
+	bd.ResolvedAt = &now
+}
+
+// BalanceDiscrepancyFilter representa filtros para las diferencias de conciliación
+type BalanceDiscrepancyFilter struct {
+	BankAccountID *uint                     `json:"bank_account_id"`
+	Status        *BalanceDiscrepancyStatus `json:"status"`
+	Limit         int                       `json:"limit"`
+	Offset        int                       `json:"offset"`
+}
@@ -1,9 +1,13 @@
 package entity
 
 import (
+	"strings"
 	"time"
+	"unicode"
 
 	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // BankAccountType define los tipos de cuenta bancaria
@@ -17,6 +21,39 @@ const (
 	BankAccountTypeInvestment BankAccountType = "investment" // Cuenta de inversión
 )
 
+// NotificationDecision define qué se hace con una notificación según la configuración de la cuenta
+type NotificationDecision string
+
+const (
+	NotificationDecisionAccepted NotificationDecision = "accepted" // Se procesa normalmente
+	NotificationDecisionIgnored  NotificationDecision = "ignored"  // Se descarta sin crear movimiento
+	NotificationDecisionParked   NotificationDecision = "parked"   // Queda en espera hasta que el usuario la apruebe
+)
+
+// NotificationReason explica la decisión tomada sobre una notificación
+type NotificationReason string
+
+const (
+	NotificationReasonAccepted              NotificationReason = "accepted"               // Cumple la configuración de la cuenta
+	NotificationReasonSenderUnverified      NotificationReason = "sender_unverified"      // La cuenta espera un remitente y la notificación no lo indica
+	NotificationReasonAccountInactive       NotificationReason = "account_inactive"       // La cuenta bancaria está inactiva
+	NotificationReasonNotificationsDisabled NotificationReason = "notifications_disabled" // La cuenta tiene las notificaciones deshabilitadas
+	NotificationReasonBelowMinAmount        NotificationReason = "below_min_amount"       // Monto menor a MinAmountToNotify
+	NotificationReasonSenderMismatch        NotificationReason = "sender_mismatch"        // El remitente no es el teléfono o email configurado
+)
+
+// Decision retorna la decisión correspondiente al motivo
+func (r NotificationReason) Decision() NotificationDecision {
+	switch r {
+	case NotificationReasonAccountInactive, NotificationReasonBelowMinAmount:
+		return NotificationDecisionIgnored
+	case NotificationReasonNotificationsDisabled, NotificationReasonSenderMismatch, NotificationReasonSenderUnverified:
+		return NotificationDecisionParked
+	default:
+		return NotificationDecisionAccepted
+	}
+}
+
 // BankAccount representa una cuenta bancaria real asociada a un usuario
 type BankAccount struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
@@ -48,14 +85,14 @@ type BankAccount struct {
 	IsNotificationEnabled bool `json:"is_notification_enabled" gorm:"default:true"` // Si acepta notificaciones de esta cuenta
 
 	// Información adicional
-	Currency          string    `json:"currency" gorm:"default:'MXN'" validate:"len=3"`
-	LastBalance       float64   `json:"last_balance" gorm:"type:decimal(15,2)"` // Último balance conocido
-	LastBalanceUpdate time.Time `json:"last_balance_update"`                    // Fecha de última actualización de balance
+	Currency          string       `json:"currency" gorm:"default:'MXN'" validate:"len=3"`
+	LastBalance       money.Amount `json:"last_balance" gorm:"type:decimal(15,2)"` // Último balance conocido
+	LastBalanceUpdate time.Time    `json:"last_balance_update"`                    // Fecha de última actualización de balance
 
 	// Configuración de notificaciones
-	NotificationPhone string  `json:"notification_phone" validate:"omitempty,min=10,max=15"`    // Teléfono para SMS
-	NotificationEmail string  `json:"notification_email" validate:"omitempty,email"`            // Email para notificaciones
-	MinAmountToNotify float64 `json:"min_amount_to_notify" gorm:"default:0;type:decimal(15,2)"` // Monto mínimo para notificar
+	NotificationPhone string       `json:"notification_phone" validate:"omitempty,min=10,max=15"`    // Teléfono para SMS
+	NotificationEmail string       `json:"notification_email" validate:"omitempty,email"`            // Email para notificaciones
+	MinAmountToNotify money.Amount `json:"min_amount_to_notify" gorm:"default:0;type:decimal(15,2)"` // Monto mínimo para notificar
 
 	// Metadatos
 	Notes        string `json:"notes" validate:"max=1000"`        // Notas adicionales
@@ -76,6 +113,26 @@ func (ba *BankAccount) GetMaskedAccountNumber() string {
 	return "****"
 }
 
+// GetLastFour retorna los últimos cuatro dígitos visibles de la máscara de la cuenta
+func (ba *BankAccount) GetLastFour() string {
+	var digits []rune
+	for _, r := range ba.AccountNumberMask {
+		if unicode.IsDigit(r) {
+			digits = append(digits, r)
+		}
+	}
+	if len(digits) > 4 {
+		digits = digits[len(digits)-4:]
+	}
+	return string(digits)
+}
+
+// MatchesLastFour verifica si los dígitos de una notificación corresponden a la cuenta
+func (ba *BankAccount) MatchesLastFour(lastFour string) bool {
+	visible := ba.GetLastFour()
+	return len(visible) == 4 && visible == lastFour
+}
+
 // IsCredit verifica si la cuenta es de tipo crédito
 func (ba *BankAccount) IsCredit() bool {
 	return ba.Type == BankAccountTypeCredit
@@ -92,15 +149,93 @@ func (ba *BankAccount) CanReceiveNotifications() bool {
 }
 
 // ShouldNotifyAmount verifica si un monto debe generar notificación
-func (ba *BankAccount) ShouldNotifyAmount(amount float64) bool {
+func (ba *BankAccount) ShouldNotifyAmount(amount money.Amount) bool {
 	if !ba.CanReceiveNotifications() {
 		return false
 	}
 	return amount >= ba.MinAmountToNotify
 }
 
+// CheckNotification decide si una notificación recibida por el canal y remitente indicados se procesa.
+// El monto se verifica aparte, una vez extraído del mensaje (ShouldNotifyAmount).
+func (ba *BankAccount) CheckNotification(channel NotificationChannel, sender string) NotificationReason {
+	if reason := ba.CheckNotificationStatus(); reason != NotificationReasonAccepted {
+		return reason
+	}
+
+	// Solo los SMS y los correos tienen un remitente configurable
+	var expected string
+	var matches func(expected, sender string) bool
+	switch channel {
+	case NotificationChannelSMS:
+		expected, matches = ba.NotificationPhone, samePhone
+	case NotificationChannelEmail:
+		expected, matches = ba.NotificationEmail, sameEmail
+	default:
+		return NotificationReasonAccepted
+	}
+
+	if strings.TrimSpace(expected) == "" {
+		return NotificationReasonAccepted
+	}
+	// Omitir el remitente no evita la verificación: la notificación queda en espera
+	if strings.TrimSpace(sender) == "" {
+		return NotificationReasonSenderUnverified
+	}
+	if !matches(expected, sender) {
+		return NotificationReasonSenderMismatch
+	}
+	return NotificationReasonAccepted
+}
+
+// CheckNotificationStatus verifica solo el estado de la cuenta y de sus notificaciones, sin el remitente.
+// Se usa con los canales que ya garantizan el origen, como el alias de ingesta de correos.
+func (ba *BankAccount) CheckNotificationStatus() NotificationReason {
+	if !ba.IsActive {
+		return NotificationReasonAccountInactive
+	}
+	if !ba.IsNotificationEnabled {
+		return NotificationReasonNotificationsDisabled
+	}
+	return NotificationReasonAccepted
+}
+
+// samePhone compara dos teléfonos ignorando el formato. Los números de diez o más dígitos se comparan
+// por sus últimos diez (sin prefijo de país); los más cortos (ej: códigos cortos de SMS) deben ser
+// iguales completos, para que un remitente como "33" no coincida con cualquier número que termine en 33.
+func samePhone(a, b string) bool {
+	da, db := phoneDigits(a), phoneDigits(b)
+	if da == "" || db == "" {
+		return false
+	}
+	return da == db
+}
+
+// phoneDigits retorna hasta los últimos diez dígitos de un teléfono
+func phoneDigits(phone string) string {
+	var digits []rune
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			digits = append(digits, r)
+		}
+	}
+	if len(digits) > 10 {
+		digits = digits[len(digits)-10:]
+	}
+	return string(digits)
+}
+
+// sameEmail compara dos direcciones de correo sin distinguir mayúsculas. El remitente puede
+// venir con nombre ("Banco <alertas@banco.com>").
+func sameEmail(expected, sender string) bool {
+	if start, end := strings.LastIndex(sender, "<"), strings.LastIndex(sender, ">"); start >= 0 && end > start {
+		sender = sender[start+1 : end]
+	}
+	return strings.EqualFold(strings.TrimSpace(expected), strings.TrimSpace(sender))
+}
+
 // UpdateBalance actualiza el balance y la fecha de actualización
-func (ba *BankAccount) UpdateBalance(newBalance float64) {
+func (ba *BankAccount) UpdateBalance(newBalance money.Amount) {
 	ba.LastBalance = newBalance
 	ba.LastBalanceUpdate = time.Now()
 }
@@ -127,7 +262,7 @@ type BankAccountSummary struct {
 	Icon              string          `json:"icon"`
 	IsActive          bool            `json:"is_active"`
 	Currency          string          `json:"currency"`
-	LastBalance       float64         `json:"last_balance"`
+	LastBalance       money.Amount    `json:"last_balance"`
 	LastBalanceUpdate time.Time       `json:"last_balance_update"`
 	DisplayName       string          `json:"display_name"`
 }
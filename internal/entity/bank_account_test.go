@@ -0,0 +1,84 @@
+package entity
+
+import "testing"
+
+func TestSamePhone(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"5512345678", "5512345678", true},
+		{"+52 55 1234 5678", "5512345678", true},
+		{"(55) 1234-5678", "+525512345678", true},
+		{"33333", "33333", true},
+		{"5512345633", "33", false},
+		{"33", "5512345633", false},
+		{"12345678", "5512345678", false},
+		{"5512345678", "5512345679", false},
+		{"", "5512345678", false},
+	}
+
+	for _, tt := range tests {
+		if got := samePhone(tt.a, tt.b); got != tt.want {
+			t.Errorf("samePhone(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheckNotificationSender(t *testing.T) {
+	account := &BankAccount{
+		IsActive:              true,
+		IsNotificationEnabled: true,
+		NotificationPhone:     "5512345678",
+		NotificationEmail:     "alertas@banco.com",
+	}
+
+	tests := []struct {
+		name    string
+		channel NotificationChannel
+		sender  string
+		want    NotificationReason
+	}{
+		{"matching phone", NotificationChannelSMS, "+52 55 1234 5678", NotificationReasonAccepted},
+		{"phone suffix", NotificationChannelSMS, "78", NotificationReasonSenderMismatch},
+		{"missing phone", NotificationChannelSMS, "", NotificationReasonSenderUnverified},
+		{"matching email", NotificationChannelEmail, "Banco <Alertas@Banco.com>", NotificationReasonAccepted},
+		{"other email", NotificationChannelEmail, "otro@banco.com", NotificationReasonSenderMismatch},
+		{"missing email", NotificationChannelEmail, " ", NotificationReasonSenderUnverified},
+		{"push has no sender", NotificationChannelPush, "", NotificationReasonAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := account.CheckNotification(tt.channel, tt.sender); got != tt.want {
+				t.Fatalf("CheckNotification = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := NotificationReasonSenderUnverified.Decision(); got != NotificationDecisionParked {
+		t.Fatalf("sender_unverified decision = %q, want parked", got)
+	}
+
+	// Los canales que garantizan el origen solo verifican el estado de la cuenta
+	if got := account.CheckNotificationStatus(); got != NotificationReasonAccepted {
+		t.Fatalf("CheckNotificationStatus = %q, want accepted", got)
+	}
+
+	account.NotificationPhone = ""
+	if got := account.CheckNotification(NotificationChannelSMS, ""); got != NotificationReasonAccepted {
+		t.Fatalf("CheckNotification without configured phone = %q, want accepted", got)
+	}
+}
+
+func TestCheckNotificationStatus(t *testing.T) {
+	account := &BankAccount{IsActive: true, NotificationEmail: "alertas@banco.com"}
+	if got := account.CheckNotificationStatus(); got != NotificationReasonNotificationsDisabled {
+		t.Fatalf("CheckNotificationStatus with notifications disabled = %q, want notifications_disabled", got)
+	}
+
+	account.IsActive = false
+	if got := account.CheckNotificationStatus(); got != NotificationReasonAccountInactive {
+		t.Fatalf("CheckNotificationStatus on inactive account = %q, want account_inactive", got)
+	}
+}
@@ -2,9 +2,13 @@ package entity
 
 import (
 	"encoding/json"
+	"sort"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/pkg/keywords"
 )
 
 // NotificationChannel define los canales de notificación
@@ -26,6 +30,15 @@ const (
 	NotificationPatternStatusLearning NotificationPatternStatus = "learning" // En aprendizaje
 )
 
+// NotificationIngestionMode define qué se hace con una notificación procesada
+type NotificationIngestionMode string
+
+const (
+	NotificationIngestionModePreview     NotificationIngestionMode = "preview"     // Solo extraer datos
+	NotificationIngestionModeTransaction NotificationIngestionMode = "transaction" // Crear una transacción
+	NotificationIngestionModeExpense     NotificationIngestionMode = "expense"     // Crear un gasto en el presupuesto
+)
+
 // BankNotificationPattern representa un patrón de notificación bancaria
 type BankNotificationPattern struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
@@ -44,7 +57,7 @@ type BankNotificationPattern struct {
 	Status      NotificationPatternStatus `json:"status" gorm:"default:'active'" validate:"oneof=active inactive learning"`
 
 	// Patrón de mensaje
-	MessagePattern  string `json:"message_pattern" gorm:"type:text" validate:"max=2000"` // Patrón regex o template
+	MessagePattern  string `json:"message_pattern" gorm:"type:text" validate:"max=2000"` // Regex con grupos nombrados o template ("Compra por {amount} en {merchant}")
 	ExampleMessage  string `json:"example_message" gorm:"type:text" validate:"max=2000"` // Ejemplo de mensaje
 	KeywordsTrigger string `json:"keywords_trigger" validate:"max=1000"`                 // Palabras clave (JSON array)
 	KeywordsExclude string `json:"keywords_exclude" validate:"max=1000"`                 // Palabras a excluir (JSON array)
@@ -54,6 +67,10 @@ type BankNotificationPattern struct {
 	DateRegex        string `json:"date_regex" validate:"max=500"`        // Regex para extraer fecha
 	DescriptionRegex string `json:"description_regex" validate:"max=500"` // Regex para extraer descripción
 	MerchantRegex    string `json:"merchant_regex" validate:"max=500"`    // Regex para extraer comercio
+	BalanceRegex     string `json:"balance_regex" validate:"max=500"`     // Regex para extraer el saldo disponible reportado
+
+	// Clase de movimiento declarada; el campo "type" extraído del mensaje tiene prioridad
+	TransactionKind TransactionKind `json:"transaction_kind" gorm:"default:'purchase'" validate:"omitempty,oneof=purchase withdrawal refund deposit transfer reversal authorization"`
 
 	// Configuración de validación
 	RequiresValidation  bool    `json:"requires_validation" gorm:"default:true"`                   // Si requiere validación manual
@@ -63,6 +80,7 @@ type BankNotificationPattern struct {
 	// Estadísticas de uso
 	MatchCount    int        `json:"match_count" gorm:"default:0"`                    // Número de coincidencias
 	SuccessCount  int        `json:"success_count" gorm:"default:0"`                  // Número de éxitos
+	FailureCount  int        `json:"failure_count" gorm:"default:0"`                  // Número de rechazos del usuario
 	SuccessRate   float64    `json:"success_rate" gorm:"default:0;type:decimal(5,2)"` // Tasa de éxito (%)
 	LastMatchedAt *time.Time `json:"last_matched_at"`                                 // Última vez que coincidió
 
@@ -71,6 +89,11 @@ type BankNotificationPattern struct {
 	IsDefault bool   `json:"is_default" gorm:"default:false"` // Si es el patrón por defecto para el banco
 	Tags      string `json:"tags" validate:"max:500"`         // Tags adicionales (JSON array)
 	Metadata  string `json:"metadata" gorm:"type:text"`       // Metadatos adicionales (JSON)
+
+	// Suscripción a la biblioteca de plantillas
+	TemplateID      *uint  `json:"template_id" gorm:"index"` // Plantilla de origen (nil si el patrón es propio)
+	TemplateVersion int    `json:"template_version"`         // Versión de la plantilla aplicada
+	Overrides       string `json:"overrides"`                // Campos ajustados localmente (JSON array)
 }
 
 // GetKeywordsTrigger convierte el campo KeywordsTrigger (JSON string) a slice de strings
@@ -193,6 +216,59 @@ func (bnp *BankNotificationPattern) SetMetadata(metadata map[string]interface{})
 	return nil
 }
 
+// GetOverrides retorna los campos de la plantilla ajustados localmente
+func (bnp *BankNotificationPattern) GetOverrides() []string {
+	return decodeStringList(bnp.Overrides)
+}
+
+// IsSubscribed verifica si el patrón proviene de una plantilla de la biblioteca
+func (bnp *BankNotificationPattern) IsSubscribed() bool {
+	return bnp.TemplateID != nil
+}
+
+// MarkOverrides registra como ajustes locales los campos de la plantilla que cambiaron
+// respecto a la versión anterior del patrón
+func (bnp *BankNotificationPattern) MarkOverrides(before *BankNotificationPattern) error {
+	overrides := bnp.GetOverrides()
+	seen := make(map[string]bool)
+	for _, field := range overrides {
+		seen[field] = true
+	}
+
+	changes := map[string]bool{
+		TemplateFieldName:                bnp.Name != before.Name,
+		TemplateFieldDescription:         bnp.Description != before.Description,
+		TemplateFieldMessagePattern:      bnp.MessagePattern != before.MessagePattern,
+		TemplateFieldExampleMessage:      bnp.ExampleMessage != before.ExampleMessage,
+		TemplateFieldKeywordsTrigger:     bnp.KeywordsTrigger != before.KeywordsTrigger,
+		TemplateFieldKeywordsExclude:     bnp.KeywordsExclude != before.KeywordsExclude,
+		TemplateFieldAmountRegex:         bnp.AmountRegex != before.AmountRegex,
+		TemplateFieldDateRegex:           bnp.DateRegex != before.DateRegex,
+		TemplateFieldDescriptionRegex:    bnp.DescriptionRegex != before.DescriptionRegex,
+		TemplateFieldMerchantRegex:       bnp.MerchantRegex != before.MerchantRegex,
+		TemplateFieldBalanceRegex:        bnp.BalanceRegex != before.BalanceRegex,
+		TemplateFieldTransactionKind:     bnp.TransactionKind != before.TransactionKind,
+		TemplateFieldRequiresValidation:  bnp.RequiresValidation != before.RequiresValidation,
+		TemplateFieldConfidenceThreshold: bnp.ConfidenceThreshold != before.ConfidenceThreshold,
+		TemplateFieldAutoApprove:         bnp.AutoApprove != before.AutoApprove,
+		TemplateFieldPriority:            bnp.Priority != before.Priority,
+		TemplateFieldTags:                bnp.Tags != before.Tags,
+	}
+	for field, changed := range changes {
+		if changed && !seen[field] {
+			overrides = append(overrides, field)
+		}
+	}
+	sort.Strings(overrides)
+
+	encoded, err := encodeStringList(overrides)
+	if err != nil {
+		return err
+	}
+	bnp.Overrides = encoded
+	return nil
+}
+
 // IsActive verifica si el patrón está activo
 func (bnp *BankNotificationPattern) IsActive() bool {
 	return bnp.Status == NotificationPatternStatusActive
@@ -203,6 +279,71 @@ func (bnp *BankNotificationPattern) IsLearning() bool {
 	return bnp.Status == NotificationPatternStatusLearning
 }
 
+// MatchesKeywords verifica si un mensaje cumple alguna palabra clave de
+// activación (o no hay ninguna definida) y ninguna palabra de exclusión
+func (bnp *BankNotificationPattern) MatchesKeywords(message string) bool {
+	triggers := bnp.TriggerExpressions()
+	triggered, excluded := MatchKeywordExpressions(triggers, bnp.ExcludeExpressions(), message)
+	return KeywordsAccepted(triggers, triggered, excluded)
+}
+
+// MatchedKeywords retorna las palabras clave de activación y de exclusión que cumple el mensaje
+func (bnp *BankNotificationPattern) MatchedKeywords(message string) (triggered, excluded []string) {
+	return MatchKeywordExpressions(bnp.TriggerExpressions(), bnp.ExcludeExpressions(), message)
+}
+
+// TriggerExpressions compila las palabras clave de activación
+func (bnp *BankNotificationPattern) TriggerExpressions() []*keywords.Expression {
+	return compileKeywords(bnp.GetKeywordsTrigger())
+}
+
+// ExcludeExpressions compila las palabras clave de exclusión
+func (bnp *BankNotificationPattern) ExcludeExpressions() []*keywords.Expression {
+	return compileKeywords(bnp.GetKeywordsExclude())
+}
+
+// MatchKeywordExpressions evalúa las palabras clave de activación y exclusión sobre el mensaje
+// normalizado (sin mayúsculas ni acentos) y retorna las que coinciden
+func MatchKeywordExpressions(triggers, excludes []*keywords.Expression, message string) (triggered, excluded []string) {
+	if len(triggers) == 0 && len(excludes) == 0 {
+		return nil, nil
+	}
+
+	text := keywords.NewText(message)
+	return keywords.MatchAll(triggers, text), keywords.MatchAll(excludes, text)
+}
+
+// KeywordsAccepted indica si el resultado de las palabras clave permite evaluar el patrón:
+// ninguna exclusión y alguna activación (o ninguna definida)
+func KeywordsAccepted(triggers []*keywords.Expression, triggered, excluded []string) bool {
+	if len(excluded) > 0 {
+		return false
+	}
+	return len(triggers) == 0 || len(triggered) > 0
+}
+
+// compileKeywords compila las palabras clave guardadas. Las expresiones inválidas (anteriores a
+// la validación) se buscan como texto literal.
+func compileKeywords(list []string) []*keywords.Expression {
+	expressions := make([]*keywords.Expression, 0, len(list))
+	for _, keyword := range list {
+		if strings.TrimSpace(keyword) == "" {
+			continue
+		}
+		expression, err := keywords.Compile(keyword)
+		if err != nil {
+			expression = keywords.Literal(keyword)
+		}
+		expressions = append(expressions, expression)
+	}
+	return expressions
+}
+
+// NeedsValidation indica si un movimiento extraído con esta confianza debe revisarse manualmente
+func (bnp *BankNotificationPattern) NeedsValidation(confidence float64) bool {
+	return bnp.RequiresValidation || confidence < bnp.ConfidenceThreshold
+}
+
 // CanAutoApprove verifica si puede auto-aprobar basado en la confianza
 func (bnp *BankNotificationPattern) CanAutoApprove(confidence float64) bool {
 	return bnp.AutoApprove && confidence >= bnp.ConfidenceThreshold
@@ -215,13 +356,64 @@ func (bnp *BankNotificationPattern) RecordMatch(success bool) {
 		bnp.SuccessCount++
 	}
 
-	// Calcular tasa de éxito
+	bnp.calculateSuccessRate()
+
+	now := time.Now()
+	bnp.LastMatchedAt = &now
+}
+
+// RecordFeedback registra la validación del usuario sobre un movimiento generado
+// por el patrón. wasSuccess indica si el movimiento ya se había contado como
+// éxito (auto-aprobado o confirmado antes), en cuyo caso un rechazo lo revierte.
+func (bnp *BankNotificationPattern) RecordFeedback(success, wasSuccess bool) {
+	switch {
+	case success && !wasSuccess:
+		bnp.SuccessCount++
+	case !success:
+		bnp.FailureCount++
+		if wasSuccess && bnp.SuccessCount > 0 {
+			bnp.SuccessCount--
+		}
+	}
+
+	bnp.calculateSuccessRate()
+}
+
+// ResolvedSuccessRate retorna la tasa de éxito (%) sobre los movimientos ya validados por el usuario
+func (bnp *BankNotificationPattern) ResolvedSuccessRate() float64 {
+	resolved := bnp.SuccessCount + bnp.FailureCount
+	if resolved == 0 {
+		return 0
+	}
+	return float64(bnp.SuccessCount) / float64(resolved) * 100
+}
+
+// calculateSuccessRate recalcula la tasa de éxito sobre el total de coincidencias
+func (bnp *BankNotificationPattern) calculateSuccessRate() {
 	if bnp.MatchCount > 0 {
 		bnp.SuccessRate = float64(bnp.SuccessCount) / float64(bnp.MatchCount) * 100
 	}
+}
 
-	now := time.Now()
-	bnp.LastMatchedAt = &now
+// GetValidationStatus determina el estado de validación de una transacción creada con este patrón.
+// Los patrones en aprendizaje nunca auto-aprueban: sus movimientos siempre requieren revisión.
+func (bnp *BankNotificationPattern) GetValidationStatus(confidence float64) ValidationStatus {
+	if !bnp.IsLearning() && bnp.CanAutoApprove(confidence) {
+		return ValidationStatusAuto
+	}
+	return ValidationStatusPending
+}
+
+// ResolveTransactionKind determina la clase de movimiento de una notificación: primero el tipo
+// extraído del mensaje y, si no se reconoce, la clase declarada en el patrón (compra por defecto)
+func (bnp *BankNotificationPattern) ResolveTransactionKind(extractedType string) TransactionKind {
+	if kind, ok := ClassifyTransactionKind(extractedType); ok {
+		return kind
+	}
+	if bnp.TransactionKind.IsValid() {
+		return bnp.TransactionKind
+	}
+	return TransactionKindPurchase
 }
 
 // GetDisplayName retorna el nombre de visualización del patrón
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // Budget representa el presupuesto mensual de un usuario
@@ -21,9 +23,9 @@ type Budget struct {
 	Month int `json:"month" gorm:"not null;index"` // 1-12
 
 	// Montos
-	TotalAmount     float64 `json:"total_amount" gorm:"not null;type:decimal(15,2)" validate:"required,gt=0"`
-	SpentAmount     float64 `json:"spent_amount" gorm:"default:0;type:decimal(15,2)"`
-	RemainingAmount float64 `json:"remaining_amount" gorm:"type:decimal(15,2)"`
+	TotalAmount     money.Amount `json:"total_amount" gorm:"not null;type:decimal(15,2)" validate:"required,gt=0"`
+	SpentAmount     money.Amount `json:"spent_amount" gorm:"default:0;type:decimal(15,2)"`
+	RemainingAmount money.Amount `json:"remaining_amount" gorm:"type:decimal(15,2)"`
 
 	// Estado
 	IsActive bool `json:"is_active" gorm:"default:true"`
@@ -48,14 +50,14 @@ type BudgetAllocation struct {
 	CategoryID uint `json:"category_id" gorm:"not null;index"`
 
 	// Montos asignados
-	AllocatedAmount float64 `json:"allocated_amount" gorm:"not null;type:decimal(15,2)" validate:"required,gte=0"`
-	SpentAmount     float64 `json:"spent_amount" gorm:"default:0;type:decimal(15,2)"`
-	RemainingAmount float64 `json:"remaining_amount" gorm:"type:decimal(15,2)"`
+	AllocatedAmount money.Amount `json:"allocated_amount" gorm:"not null;type:decimal(15,2)" validate:"required,gte=0"`
+	SpentAmount     money.Amount `json:"spent_amount" gorm:"default:0;type:decimal(15,2)"`
+	RemainingAmount money.Amount `json:"remaining_amount" gorm:"type:decimal(15,2)"`
 
 	// Configuración de límites diarios
-	DailyLimit        float64    `json:"daily_limit" gorm:"type:decimal(15,2)"`         // Límite diario calculado
-	CurrentDailyLimit float64    `json:"current_daily_limit" gorm:"type:decimal(15,2)"` // Límite actual con rollover
-	LastCalculatedAt  *time.Time `json:"last_calculated_at"`                            // Última vez que se calculó
+	DailyLimit        money.Amount `json:"daily_limit" gorm:"type:decimal(15,2)"`         // Límite diario calculado
+	CurrentDailyLimit money.Amount `json:"current_daily_limit" gorm:"type:decimal(15,2)"` // Límite actual con rollover
+	LastCalculatedAt  *time.Time   `json:"last_calculated_at"`                            // Última vez que se calculó
 
 	// Alertas
 	AlertThreshold float64 `json:"alert_threshold" gorm:"default:0.8;type:decimal(3,2)"` // % para alertar (ej: 0.8 = 80%)
@@ -92,7 +94,7 @@ func (b *Budget) GetProgressPercentage() float64 {
 	if b.TotalAmount == 0 {
 		return 0
 	}
-	return (b.SpentAmount / b.TotalAmount) * 100
+	return money.Ratio(b.SpentAmount, b.TotalAmount) * 100
 }
 
 // GetRemainingDays retorna los días restantes del mes
@@ -126,7 +128,7 @@ func (ba *BudgetAllocation) GetProgressPercentage() float64 {
 	if ba.AllocatedAmount == 0 {
 		return 0
 	}
-	return (ba.SpentAmount / ba.AllocatedAmount) * 100
+	return money.Ratio(ba.SpentAmount, ba.AllocatedAmount) * 100
 }
 
 // IsOverBudgetCheck verifica si se ha excedido el presupuesto
@@ -152,8 +154,8 @@ func (ba *BudgetAllocation) CalculateDailyLimit(remainingDays int) {
 		return
 	}
 
-	// Límite base = monto restante / días restantes
-	ba.DailyLimit = ba.RemainingAmount / float64(remainingDays)
+	// Límite base = monto restante / días restantes, con redondeo bancario
+	ba.DailyLimit = ba.RemainingAmount.Div(int64(remainingDays), money.RoundHalfEven)
 
 	// Si es negativo (ya se excedió), poner en 0
 	if ba.DailyLimit < 0 {
@@ -167,7 +169,7 @@ func (ba *BudgetAllocation) CalculateDailyLimit(remainingDays int) {
 }
 
 // AddRollover agrega saldo no gastado al límite del día siguiente
-func (ba *BudgetAllocation) AddRollover(unspentAmount float64) {
+func (ba *BudgetAllocation) AddRollover(unspentAmount money.Amount) {
 	ba.CurrentDailyLimit += unspentAmount
 }
 
@@ -179,14 +181,14 @@ func (ba *BudgetAllocation) ResetDailyLimit() {
 }
 
 // CanSpend verifica si se puede gastar una cantidad
-func (ba *BudgetAllocation) CanSpend(amount float64) bool {
+func (ba *BudgetAllocation) CanSpend(amount money.Amount) bool {
 	return ba.CurrentDailyLimit >= amount
 }
 
 // GetAllocationPercentage retorna el porcentaje de asignación del presupuesto total
-func (ba *BudgetAllocation) GetAllocationPercentage(totalBudget float64) float64 {
+func (ba *BudgetAllocation) GetAllocationPercentage(totalBudget money.Amount) float64 {
 	if totalBudget == 0 {
 		return 0
 	}
-	return (ba.AllocatedAmount / totalBudget) * 100
+	return money.Ratio(ba.AllocatedAmount, totalBudget) * 100
 }
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EmailIngestAlias es la dirección de correo personal a la que el usuario reenvía las
+// notificaciones de su banco (<alias>@<dominio de ingesta>). Los correos recibidos en ella
+// entran al mismo flujo que las notificaciones enviadas por la app, con canal email.
+type EmailIngestAlias struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relaciones
+	UserID        uint  `json:"user_id" gorm:"not null;uniqueIndex"`
+	BankAccountID *uint `json:"bank_account_id"` // Cuenta de los correos; si es nil se identifica por el mensaje
+
+	// Dirección
+	Alias    string `json:"alias" gorm:"not null;uniqueIndex;size:64"` // Parte local de la dirección
+	IsActive bool   `json:"is_active" gorm:"default:true"`
+
+	// Parámetros de ingesta de los correos recibidos
+	Mode       NotificationIngestionMode `json:"mode" gorm:"not null;default:'preview'"`
+	AccountID  *uint                     `json:"account_id"`
+	CategoryID *uint                     `json:"category_id"`
+
+	// Estadísticas
+	ReceivedCount  int        `json:"received_count" gorm:"default:0"`
+	LastReceivedAt *time.Time `json:"last_received_at"`
+}
+
+// Address retorna la dirección completa del alias en el dominio de ingesta
+func (a *EmailIngestAlias) Address(domain string) string {
+	if domain == "" {
+		return a.Alias
+	}
+	return a.Alias + "@" + domain
+}
+
+// RecordReceived registra la recepción de un correo
+func (a *EmailIngestAlias) RecordReceived() {
+	now := time.Now()
+	a.ReceivedCount++
+	a.LastReceivedAt = &now
+}
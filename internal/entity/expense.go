@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // ExpenseSource define la fuente del gasto
@@ -42,21 +44,23 @@ type Expense struct {
 	AllocationID uint `json:"allocation_id" gorm:"not null;index"`
 
 	// Información del gasto
-	Amount      float64       `json:"amount" gorm:"not null;type:decimal(15,2)" validate:"required,gt=0"`
+	Amount      money.Amount  `json:"amount" gorm:"not null;type:decimal(15,2)" validate:"required,gt=0"`
 	Description string        `json:"description" gorm:"not null" validate:"required,min=1,max=500"`
 	Date        time.Time     `json:"date" gorm:"not null;index"`
 	Source      ExpenseSource `json:"source" gorm:"not null" validate:"required"`
 	Status      ExpenseStatus `json:"status" gorm:"default:'confirmed'" validate:"oneof=pending confirmed cancelled"`
 
 	// Ubicación y contexto
-	Location  string `json:"location" validate:"max=200"`
-	Merchant  string `json:"merchant" validate:"max=100"`  // Comercio donde se realizó el gasto
-	Reference string `json:"reference" validate:"max=100"` // Referencia bancaria o número de transacción
+	Location    string `json:"location" validate:"max=200"`
+	Merchant    string `json:"merchant" validate:"max=100"`  // Comercio donde se realizó el gasto
+	MerchantKey string `json:"-" gorm:"index"`               // Comercio normalizado para detectar notificaciones repetidas
+	Reference   string `json:"reference" validate:"max=100"` // Referencia bancaria o número de transacción
 
 	// Metadatos para captura automática
-	RawData    string  `json:"raw_data"`   // Datos originales (SMS, JSON, etc.)
-	Confidence float64 `json:"confidence"` // Confianza en la clasificación automática (0-1)
-	Tags       string  `json:"tags"`       // JSON array de tags
+	RawData    string  `json:"raw_data"`                // Datos originales (SMS, JSON, etc.)
+	Confidence float64 `json:"confidence"`              // Confianza en la clasificación automática (0-1)
+	PatternID  *uint   `json:"pattern_id" gorm:"index"` // ID del patrón que procesó la notificación
+	Tags       string  `json:"tags"`                    // JSON array de tags
 
 	// Información adicional
 	Notes        string  `json:"notes" validate:"max=1000"`
@@ -75,6 +79,13 @@ type Expense struct {
 	Allocation BudgetAllocation `json:"allocation" gorm:"foreignKey:AllocationID"`
 }
 
+// BeforeSave se ejecuta antes de crear o actualizar el gasto
+func (e *Expense) BeforeSave(tx *gorm.DB) error {
+	// Normalizar el comercio igual que las huellas de notificación
+	e.MerchantKey = normalizeFingerprintText(e.Merchant)
+	return nil
+}
+
 // GetTags convierte el campo Tags (JSON string) a slice de strings
 func (e *Expense) GetTags() []string {
 	if e.Tags == "" {
@@ -139,6 +150,11 @@ func (e *Expense) IsAutomatic() bool {
 		e.Source == ExpenseSourceNotification
 }
 
+// IsFromNotification verifica si el gasto lo generó un patrón a partir de una notificación bancaria
+func (e *Expense) IsFromNotification() bool {
+	return e.PatternID != nil
+}
+
 // IsManual verifica si el gasto fue ingresado manualmente
 func (e *Expense) IsManual() bool {
 	return e.Source == ExpenseSourceManual || e.Source == ExpenseSourceWhatsApp
@@ -159,13 +175,18 @@ func (e *Expense) GetFormattedAmount() string {
 	return FormatCurrency(e.Amount, e.Currency)
 }
 
+// Money retorna el monto con su moneda
+func (e *Expense) Money() money.Money {
+	return money.New(e.Amount, e.Currency)
+}
+
 // GetAmountInBaseCurrency retorna el monto en la moneda base
-func (e *Expense) GetAmountInBaseCurrency(baseCurrency string) float64 {
+func (e *Expense) GetAmountInBaseCurrency(baseCurrency string) money.Amount {
 	if e.Currency == baseCurrency {
 		return e.Amount
 	}
 
-	return e.Amount * e.ExchangeRate
+	return e.Amount.Mul(e.ExchangeRate, money.RoundHalfUp)
 }
 
 // ShouldTriggerAlert verifica si debe disparar una alerta
@@ -176,7 +197,7 @@ func (e *Expense) ShouldTriggerAlert(allocation *BudgetAllocation) bool {
 
 	// Verificar si con este gasto se excede el umbral de alerta
 	newSpent := allocation.SpentAmount + e.Amount
-	percentage := newSpent / allocation.AllocatedAmount
+	percentage := money.Ratio(newSpent, allocation.AllocatedAmount)
 
 	return percentage >= allocation.AlertThreshold
 }
@@ -225,7 +246,7 @@ func (e *Expense) GetTimeAgo() string {
 // ExpenseSummary representa un resumen de gasto para listas
 type ExpenseSummary struct {
 	ID           uint          `json:"id"`
-	Amount       float64       `json:"amount"`
+	Amount       money.Amount  `json:"amount"`
 	Description  string        `json:"description"`
 	Date         time.Time     `json:"date"`
 	CategoryName string        `json:"category_name"`
@@ -243,8 +264,8 @@ type ExpenseFilter struct {
 	Status     *ExpenseStatus `json:"status"`
 	FromDate   *time.Time     `json:"from_date"`
 	ToDate     *time.Time     `json:"to_date"`
-	MinAmount  *float64       `json:"min_amount"`
-	MaxAmount  *float64       `json:"max_amount"`
+	MinAmount  *money.Amount  `json:"min_amount"`
+	MaxAmount  *money.Amount  `json:"max_amount"`
 	Search     string         `json:"search"` // Búsqueda en descripción
 	Merchant   string         `json:"merchant"`
 	Limit      int            `json:"limit"`
@@ -254,14 +275,14 @@ type ExpenseFilter struct {
 }
 
 // FormatCurrency formatea una cantidad con la moneda especificada
-func FormatCurrency(amount float64, currency string) string {
+func FormatCurrency(amount money.Amount, currency string) string {
 	// TODO: Implementar formateo según la moneda
 	switch currency {
 	case "MXN":
-		return fmt.Sprintf("$%.2f MXN", amount)
+		return fmt.Sprintf("$%s MXN", amount)
 	case "USD":
-		return fmt.Sprintf("$%.2f USD", amount)
+		return fmt.Sprintf("$%s USD", amount)
 	default:
-		return fmt.Sprintf("%.2f %s", amount, currency)
+		return fmt.Sprintf("%s %s", amount, currency)
 	}
 }
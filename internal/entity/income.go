@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // IncomeSource define los tipos de fuente de ingreso
@@ -36,14 +38,17 @@ const (
 type Income struct {
 	gorm.Model
 	UserID      uint         `json:"user_id" gorm:"not null;index"`
-	Amount      float64      `json:"amount" gorm:"not null" validate:"gt=0"`
+	Amount      money.Amount `json:"amount" gorm:"not null" validate:"gt=0"`
 	Description string       `json:"description" gorm:"not null" validate:"required,max=255"`
 	Source      IncomeSource `json:"source" gorm:"not null" validate:"required"`
 	Date        time.Time    `json:"date" gorm:"not null"`
 	Notes       string       `json:"notes,omitempty" gorm:"type:text"`
 	Currency    string       `json:"currency" gorm:"size:3;default:'USD'" validate:"len=3"`
 
-	// Campos para ingresos recurrentes
+	// Campos para ingresos recurrentes.
+	//
+	// Deprecated: las recurrencias se programan con RecurringTransaction, que genera ingresos, gastos y
+	// transferencias en una cuenta. Se conservan para los ingresos recurrentes ya registrados.
 	IsRecurring    bool             `json:"is_recurring" gorm:"default:false"`
 	Frequency      *IncomeFrequency `json:"frequency,omitempty" gorm:"type:varchar(20)"`
 	NextDate       *time.Time       `json:"next_date,omitempty"`
@@ -51,8 +56,8 @@ type Income struct {
 	RecurringUntil *time.Time       `json:"recurring_until,omitempty"`
 
 	// Metadatos
-	TaxDeducted float64 `json:"tax_deducted" gorm:"default:0"`
-	NetAmount   float64 `json:"net_amount" gorm:"default:0"` // amount - tax_deducted
+	TaxDeducted money.Amount `json:"tax_deducted" gorm:"default:0"`
+	NetAmount   money.Amount `json:"net_amount" gorm:"default:0"` // amount - tax_deducted
 
 	// Relaciones
 	User User `json:"-" gorm:"foreignKey:UserID"`
@@ -0,0 +1,274 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// LedgerEntryKind define el origen de un asiento del libro contable
+type LedgerEntryKind string
+
+const (
+	LedgerEntryKindOpening     LedgerEntryKind = "opening"     // Saldo inicial de una cuenta
+	LedgerEntryKindTransaction LedgerEntryKind = "transaction" // Ingreso, gasto o transferencia registrada como Transaction
+	LedgerEntryKindTransfer    LedgerEntryKind = "transfer"    // Traspaso directo entre cuentas del usuario
+	LedgerEntryKindReversal    LedgerEntryKind = "reversal"    // Anulación de un asiento anterior
+	LedgerEntryKindExpense     LedgerEntryKind = "expense"     // Gasto de presupuesto (Expense)
+	LedgerEntryKindIncome      LedgerEntryKind = "income"      // Ingreso registrado (Income)
+)
+
+// LedgerNominalAccount identifica la contrapartida de un movimiento que no es una cuenta del usuario
+type LedgerNominalAccount string
+
+const (
+	LedgerNominalIncome         LedgerNominalAccount = "income"          // Origen de los ingresos
+	LedgerNominalExpense        LedgerNominalAccount = "expense"         // Destino de los gastos
+	LedgerNominalOpeningBalance LedgerNominalAccount = "opening_balance" // Patrimonio inicial de las cuentas
+	LedgerNominalExternal       LedgerNominalAccount = "external"        // Transferencias a cuentas de terceros
+	LedgerNominalUnassigned     LedgerNominalAccount = "unassigned"      // Fondos de gastos e ingresos que no indican cuenta
+)
+
+// LedgerEntry es un asiento inmutable del libro contable. Sus líneas suman cero: el dinero que
+// entra a una cuenta sale de otra o de una cuenta nominal. Los asientos no se modifican ni se
+// eliminan; para deshacer uno se registra su reverso.
+type LedgerEntry struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relaciones
+	UserID        uint  `json:"user_id" gorm:"not null;index"`
+	TransactionID *uint `json:"transaction_id" gorm:"index"` // Transacción que originó el asiento
+	ReversalOfID  *uint `json:"reversal_of_id" gorm:"index"` // Asiento que este anula
+	ExpenseID     *uint `json:"expense_id" gorm:"index"`     // Gasto que originó el asiento
+	IncomeID      *uint `json:"income_id" gorm:"index"`      // Ingreso que originó el asiento
+
+	// Información del asiento
+	Kind        LedgerEntryKind `json:"kind" gorm:"not null;index"`
+	Description string          `json:"description"`
+	EntryDate   time.Time       `json:"entry_date" gorm:"not null;index"`
+
+	Postings []LedgerPosting `json:"postings" gorm:"foreignKey:EntryID"`
+}
+
+// LedgerPosting es una línea de un asiento. El monto es positivo en el débito (entra dinero a la
+// cuenta) y negativo en el crédito (sale dinero). La línea afecta una cuenta del usuario o una
+// cuenta nominal, nunca ambas.
+type LedgerPosting struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	EntryID   uint                 `json:"entry_id" gorm:"not null;index"`
+	AccountID *uint                `json:"account_id" gorm:"index"`
+	Nominal   LedgerNominalAccount `json:"nominal,omitempty" gorm:"size:32"`
+	Amount    money.Amount         `json:"amount" gorm:"not null;type:decimal(15,2)"`
+}
+
+// NewTransactionLedgerEntry construye el asiento de una transacción: los ingresos entran a la cuenta
+// desde la cuenta nominal de ingresos, los gastos salen hacia la de gastos y las transferencias pasan
+// de la cuenta origen a la destino, o a cuentas de terceros si no tiene destino
+func NewTransactionLedgerEntry(transaction *Transaction) *LedgerEntry {
+	entry := &LedgerEntry{
+		UserID:        transaction.UserID,
+		TransactionID: &transaction.ID,
+		Kind:          LedgerEntryKindTransaction,
+		Description:   transaction.Description,
+		EntryDate:     transaction.TransactionDate,
+	}
+
+	accountID := transaction.AccountID
+	switch transaction.Type {
+	case TransactionTypeIncome:
+		entry.addAccount(accountID, transaction.Amount)
+		entry.addNominal(LedgerNominalIncome, -transaction.Amount)
+	case TransactionTypeExpense:
+		entry.addAccount(accountID, -transaction.Amount)
+		entry.addNominal(LedgerNominalExpense, transaction.Amount)
+	case TransactionTypeTransfer:
+		entry.addAccount(accountID, -transaction.Amount)
+		if transaction.ToAccountID != nil {
+			entry.addAccount(*transaction.ToAccountID, transaction.Amount)
+		} else {
+			entry.addNominal(LedgerNominalExternal, transaction.Amount)
+		}
+	}
+
+	return entry
+}
+
+// NewTransferLedgerEntry construye el asiento de un traspaso directo entre dos cuentas del usuario
+func NewTransferLedgerEntry(userID, fromAccountID, toAccountID uint, amount money.Amount, description string) *LedgerEntry {
+	entry := &LedgerEntry{
+		UserID:      userID,
+		Kind:        LedgerEntryKindTransfer,
+		Description: description,
+		EntryDate:   time.Now(),
+	}
+	entry.addAccount(fromAccountID, -amount)
+	entry.addAccount(toAccountID, amount)
+	return entry
+}
+
+// NewExpenseLedgerEntry construye el asiento de un gasto de presupuesto. Los gastos no indican
+// cuenta, por lo que salen de la cuenta nominal de fondos sin asignar hacia la de gastos.
+func NewExpenseLedgerEntry(expense *Expense) *LedgerEntry {
+	entry := &LedgerEntry{
+		UserID:      expense.UserID,
+		ExpenseID:   &expense.ID,
+		Kind:        LedgerEntryKindExpense,
+		Description: expense.Description,
+		EntryDate:   expense.Date,
+	}
+	entry.addNominal(LedgerNominalUnassigned, -expense.Amount)
+	entry.addNominal(LedgerNominalExpense, expense.Amount)
+	return entry
+}
+
+// NewIncomeLedgerEntry construye el asiento de un ingreso registrado: entra a la cuenta nominal de
+// fondos sin asignar desde la de ingresos
+func NewIncomeLedgerEntry(income *Income) *LedgerEntry {
+	entry := &LedgerEntry{
+		UserID:      income.UserID,
+		IncomeID:    &income.ID,
+		Kind:        LedgerEntryKindIncome,
+		Description: income.Description,
+		EntryDate:   income.Date,
+	}
+	entry.addNominal(LedgerNominalUnassigned, income.Amount)
+	entry.addNominal(LedgerNominalIncome, -income.Amount)
+	return entry
+}
+
+// NewOpeningLedgerEntry construye el asiento que abre una cuenta con su balance actual contra el
+// patrimonio inicial. Retorna nil si la cuenta no tiene saldo.
+func NewOpeningLedgerEntry(account *Account) *LedgerEntry {
+	amount := account.LedgerAmount(account.Balance)
+	if amount.IsZero() {
+		return nil
+	}
+
+	entry := &LedgerEntry{
+		UserID:      account.UserID,
+		Kind:        LedgerEntryKindOpening,
+		Description: "Saldo inicial: " + account.Name,
+		EntryDate:   account.CreatedAt,
+	}
+	if entry.EntryDate.IsZero() {
+		entry.EntryDate = time.Now()
+	}
+	entry.addAccount(account.ID, amount)
+	entry.addNominal(LedgerNominalOpeningBalance, -amount)
+	return entry
+}
+
+// Reversal construye el asiento que anula este, con las mismas líneas en sentido contrario
+func (e *LedgerEntry) Reversal(description string) *LedgerEntry {
+	reversal := &LedgerEntry{
+		UserID:        e.UserID,
+		TransactionID: e.TransactionID,
+		ExpenseID:     e.ExpenseID,
+		IncomeID:      e.IncomeID,
+		Kind:          LedgerEntryKindReversal,
+		Description:   description,
+		EntryDate:     time.Now(),
+	}
+	if e.ID != 0 {
+		reversal.ReversalOfID = &e.ID
+	}
+
+	for _, posting := range e.Postings {
+		reversal.Postings = append(reversal.Postings, LedgerPosting{
+			AccountID: posting.AccountID,
+			Nominal:   posting.Nominal,
+			Amount:    -posting.Amount,
+		})
+	}
+	return reversal
+}
+
+// Total retorna la suma de las líneas del asiento
+func (e *LedgerEntry) Total() money.Amount {
+	total := money.Zero
+	for _, posting := range e.Postings {
+		total += posting.Amount
+	}
+	return total
+}
+
+// Matches verifica si el asiento registra las mismas líneas en la misma fecha que otro. La fecha se
+// compara con la precisión de microsegundos de la base de datos.
+func (e *LedgerEntry) Matches(other *LedgerEntry) bool {
+	if !e.EntryDate.Truncate(time.Microsecond).Equal(other.EntryDate.Truncate(time.Microsecond)) ||
+		len(e.Postings) != len(other.Postings) {
+		return false
+	}
+
+	type line struct {
+		accountID uint
+		nominal   LedgerNominalAccount
+		amount    money.Amount
+	}
+	key := func(posting LedgerPosting) line {
+		l := line{nominal: posting.Nominal, amount: posting.Amount}
+		if posting.AccountID != nil {
+			l.accountID = *posting.AccountID
+		}
+		return l
+	}
+
+	pending := make(map[line]int, len(e.Postings))
+	for _, posting := range e.Postings {
+		pending[key(posting)]++
+	}
+	for _, posting := range other.Postings {
+		k := key(posting)
+		if pending[k] == 0 {
+			return false
+		}
+		pending[k]--
+	}
+	return true
+}
+
+// IsBalanced verifica que el asiento tenga al menos dos líneas válidas y que sumen cero
+func (e *LedgerEntry) IsBalanced() bool {
+	if len(e.Postings) < 2 {
+		return false
+	}
+	for _, posting := range e.Postings {
+		if (posting.AccountID == nil) == (posting.Nominal == "") {
+			return false
+		}
+	}
+	return e.Total().IsZero()
+}
+
+// addAccount agrega una línea sobre una cuenta del usuario
+func (e *LedgerEntry) addAccount(accountID uint, amount money.Amount) {
+	e.Postings = append(e.Postings, LedgerPosting{AccountID: &accountID, Amount: amount})
+}
+
+// addNominal agrega una línea sobre una cuenta nominal
+func (e *LedgerEntry) addNominal(nominal LedgerNominalAccount, amount money.Amount) {
+	e.Postings = append(e.Postings, LedgerPosting{Nominal: nominal, Amount: amount})
+}
+
+// LedgerEntryFilter representa filtros para los asientos del libro contable
+type LedgerEntryFilter struct {
+	AccountID     *uint            `json:"account_id"`
+	TransactionID *uint            `json:"transaction_id"`
+	Kind          *LedgerEntryKind `json:"kind"`
+	Limit         int              `json:"limit"`
+	Offset        int              `json:"offset"`
+}
+
+// LedgerImbalance describe un asiento cuyas líneas no suman cero
+type LedgerImbalance struct {
+	EntryID       uint            `json:"entry_id"`
+	Kind          LedgerEntryKind `json:"kind"`
+	TransactionID *uint           `json:"transaction_id"`
+	ExpenseID     *uint           `json:"expense_id"`
+	IncomeID      *uint           `json:"income_id"`
+	PostingCount  int             `json:"posting_count"`
+	Total         money.Amount    `json:"total"`
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+func TestExpenseAndIncomeLedgerEntries(t *testing.T) {
+	date := time.Date(2026, 3, 5, 14, 32, 0, 0, time.UTC)
+
+	expense := NewExpenseLedgerEntry(&Expense{ID: 1, UserID: 7, Amount: money.FromUnits(250), Date: date})
+	if !expense.IsBalanced() || *expense.ExpenseID != 1 || expense.Kind != LedgerEntryKindExpense {
+		t.Fatalf("expense entry = %+v", expense)
+	}
+
+	income := NewIncomeLedgerEntry(&Income{UserID: 7, Amount: money.FromUnits(1000), Date: date})
+	if !income.IsBalanced() || income.Kind != LedgerEntryKindIncome {
+		t.Fatalf("income entry = %+v", income)
+	}
+
+	reversal := expense.Reversal("Anulación")
+	if !reversal.IsBalanced() || reversal.ExpenseID != expense.ExpenseID || reversal.Total() != money.Zero {
+		t.Fatalf("reversal = %+v", reversal)
+	}
+}
+
+func TestLedgerEntryMatches(t *testing.T) {
+	date := time.Date(2026, 3, 5, 14, 32, 0, 123456789, time.UTC)
+	base := NewExpenseLedgerEntry(&Expense{ID: 1, Amount: money.FromUnits(250), Date: date})
+
+	// Las líneas leídas de la base de datos pueden venir en otro orden y la fecha con microsegundos
+	stored := NewExpenseLedgerEntry(&Expense{ID: 1, Amount: money.FromUnits(250), Date: date.Truncate(time.Microsecond).In(time.FixedZone("CST", -6*3600))})
+	stored.Postings[0], stored.Postings[1] = stored.Postings[1], stored.Postings[0]
+	stored.Description = "Otra descripción"
+
+	tests := []struct {
+		name  string
+		other *LedgerEntry
+		want  bool
+	}{
+		{"same postings and date", stored, true},
+		{"other amount", NewExpenseLedgerEntry(&Expense{ID: 1, Amount: money.FromUnits(300), Date: date}), false},
+		{"other date", NewExpenseLedgerEntry(&Expense{ID: 1, Amount: money.FromUnits(250), Date: date.AddDate(0, 0, 1)}), false},
+		{"other nominal", NewIncomeLedgerEntry(&Income{Amount: money.FromUnits(250), Date: date}), false},
+		{"reversal", base.Reversal("Anulación"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base.Matches(tt.other); got != tt.want {
+				t.Fatalf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
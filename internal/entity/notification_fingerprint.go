@@ -0,0 +1,143 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// NotificationFingerprint identifica un evento bancario recibido por notificación. El mismo evento
+// suele llegar por varios canales (SMS, push, email); las notificaciones repetidas se enlazan
+// a la primera en lugar de crear movimientos duplicados.
+type NotificationFingerprint struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relaciones
+	UserID        uint `json:"user_id" gorm:"not null;index"`
+	BankAccountID uint `json:"bank_account_id" gorm:"not null;index:idx_fingerprint_lookup,priority:1"`
+
+	// Datos normalizados del evento
+	Fingerprint string              `json:"fingerprint" gorm:"not null;index;size:64"` // Hash de cuenta, monto, comercio, ventana de tiempo y referencia
+	Channel     NotificationChannel `json:"channel" gorm:"not null"`
+	Amount      money.Amount        `json:"amount" gorm:"not null;type:decimal(15,2);index:idx_fingerprint_lookup,priority:2"`
+	Currency    string              `json:"currency" gorm:"size:3"`
+	Merchant    string              `json:"merchant"`  // Comercio normalizado
+	Reference   string              `json:"reference"` // Número de referencia o autorización
+	Kind        TransactionKind     `json:"kind"`      // Clase de movimiento (vacío en huellas anteriores equivale a compra)
+	EventAt     time.Time           `json:"event_at" gorm:"not null;index:idx_fingerprint_lookup,priority:3"`
+
+	// Resultado: movimiento creado por la notificación original
+	TransactionID *uint `json:"transaction_id" gorm:"index"`
+	ExpenseID     *uint `json:"expense_id" gorm:"index"`
+	DuplicateOfID *uint `json:"duplicate_of_id" gorm:"index"` // Notificación original si esta es un duplicado
+}
+
+// NewNotificationFingerprint crea la huella de una notificación. Si la fecha del movimiento no
+// incluye hora, se usa la hora de recepción para ubicar el evento en el tiempo.
+func NewNotificationFingerprint(
+	userID, bankAccountID uint,
+	channel NotificationChannel,
+	kind TransactionKind,
+	amount money.Amount,
+	currency, merchant, reference string,
+	occurredAt, receivedAt time.Time,
+	window time.Duration,
+) *NotificationFingerprint {
+	eventAt := occurredAt
+	if occurredAt.Hour() == 0 && occurredAt.Minute() == 0 && occurredAt.Second() == 0 {
+		eventAt = receivedAt
+	}
+
+	fp := &NotificationFingerprint{
+		UserID:        userID,
+		BankAccountID: bankAccountID,
+		Channel:       channel,
+		Amount:        amount,
+		Currency:      currency,
+		Merchant:      normalizeFingerprintText(merchant),
+		Reference:     normalizeFingerprintText(reference),
+		Kind:          kind,
+		EventAt:       eventAt,
+	}
+	fp.Fingerprint = fp.hash(window)
+
+	return fp
+}
+
+// Matches indica si otra notificación corresponde al mismo evento bancario: misma cuenta y monto,
+// y clase de movimiento, dentro de la ventana de tiempo, con la misma referencia (si ambas la tienen)
+// y un comercio compatible. Sin referencias, solo se enlazan notificaciones de distintos canales: el
+// mismo canal no repite un evento, por lo que dos avisos iguales por él son dos movimientos.
+// Un reverso nunca es duplicado de la compra que anula.
+func (nf *NotificationFingerprint) Matches(other *NotificationFingerprint, window time.Duration) bool {
+	if nf.BankAccountID != other.BankAccountID || nf.Amount != other.Amount {
+		return false
+	}
+	if nf.resolvedKind() != other.resolvedKind() {
+		return false
+	}
+	if nf.Currency != "" && other.Currency != "" && nf.Currency != other.Currency {
+		return false
+	}
+
+	diff := nf.EventAt.Sub(other.EventAt)
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > window {
+		return false
+	}
+
+	if nf.Reference != "" && other.Reference != "" {
+		return nf.Reference == other.Reference
+	}
+	if nf.Channel == other.Channel {
+		return false
+	}
+
+	// Algunos canales truncan el nombre del comercio: basta con que uno contenga al otro
+	if nf.Merchant != "" && other.Merchant != "" {
+		return strings.Contains(nf.Merchant, other.Merchant) || strings.Contains(other.Merchant, nf.Merchant)
+	}
+
+	return true
+}
+
+// IsDuplicate verifica si la notificación fue enlazada a una anterior
+func (nf *NotificationFingerprint) IsDuplicate() bool {
+	return nf.DuplicateOfID != nil
+}
+
+// hash calcula la huella exacta del evento agrupando la hora en ventanas fijas
+func (nf *NotificationFingerprint) hash(window time.Duration) string {
+	bucket := int64(0)
+	if window > 0 {
+		bucket = nf.EventAt.Unix() / int64(window.Seconds())
+	}
+
+	key := fmt.Sprintf("%d|%s|%s|%s|%d|%s", nf.BankAccountID, nf.Amount, nf.Merchant, nf.Reference, bucket, nf.resolvedKind())
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// resolvedKind retorna la clase de movimiento de la huella; las anteriores a la clasificación son compras
+func (nf *NotificationFingerprint) resolvedKind() TransactionKind {
+	if nf.Kind == "" {
+		return TransactionKindPurchase
+	}
+	return nf.Kind
+}
+
+// normalizeFingerprintText deja solo letras y dígitos en minúscula separados por un espacio
+func normalizeFingerprintText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
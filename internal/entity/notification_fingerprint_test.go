@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+func TestNotificationFingerprintMatches(t *testing.T) {
+	at := time.Date(2026, 3, 5, 14, 32, 0, 0, time.UTC)
+	window := 30 * time.Minute
+	fingerprint := func(channel NotificationChannel, kind TransactionKind, merchant, reference string, eventAt time.Time) *NotificationFingerprint {
+		return NewNotificationFingerprint(1, 10, channel, kind, money.FromUnits(250), "MXN", merchant, reference, eventAt, eventAt, window)
+	}
+	sms := fingerprint(NotificationChannelSMS, TransactionKindPurchase, "OXXO Centro", "", at)
+
+	tests := []struct {
+		name  string
+		other *NotificationFingerprint
+		want  bool
+	}{
+		{"other channel", fingerprint(NotificationChannelPush, TransactionKindPurchase, "oxxo centro", "", at.Add(5*time.Minute)), true},
+		{"other channel with truncated merchant", fingerprint(NotificationChannelEmail, TransactionKindPurchase, "OXXO", "", at), true},
+		{"same channel without references", fingerprint(NotificationChannelSMS, TransactionKindPurchase, "OXXO Centro", "", at.Add(time.Minute)), false},
+		{"other merchant", fingerprint(NotificationChannelPush, TransactionKindPurchase, "Walmart", "", at), false},
+		{"outside the window", fingerprint(NotificationChannelPush, TransactionKindPurchase, "OXXO Centro", "", at.Add(time.Hour)), false},
+		{"reversal of the purchase", fingerprint(NotificationChannelPush, TransactionKindReversal, "OXXO Centro", "", at), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sms.Matches(tt.other, window); got != tt.want {
+				t.Fatalf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Con referencias en ambas, deciden ellas aunque sea el mismo canal
+	first := fingerprint(NotificationChannelSMS, TransactionKindPurchase, "OXXO", "AUT-123", at)
+	if !first.Matches(fingerprint(NotificationChannelSMS, TransactionKindPurchase, "OXXO", "aut 123", at), window) {
+		t.Fatal("expected notifications with the same reference to match")
+	}
+	if first.Matches(fingerprint(NotificationChannelPush, TransactionKindPurchase, "OXXO", "AUT-456", at), window) {
+		t.Fatal("expected notifications with other references not to match")
+	}
+}
@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// NotificationInboxStatus define el estado de procesamiento de una notificación recibida
+type NotificationInboxStatus string
+
+const (
+	NotificationInboxStatusUnrouted      NotificationInboxStatus = "unrouted"       // No se pudo identificar la cuenta bancaria; el usuario debe asignarla
+	NotificationInboxStatusUnmatched     NotificationInboxStatus = "unmatched"      // Ningún patrón coincidió
+	NotificationInboxStatusParsed        NotificationInboxStatus = "parsed"         // Datos extraídos, sin movimiento creado
+	NotificationInboxStatusPendingReview NotificationInboxStatus = "pending_review" // Movimiento creado pendiente de revisión
+	NotificationInboxStatusConverted     NotificationInboxStatus = "converted"      // Convertida en transacción o gasto
+	NotificationInboxStatusIgnored       NotificationInboxStatus = "ignored"        // Descartada por el usuario, duplicada o por la configuración de la cuenta
+	NotificationInboxStatusParked        NotificationInboxStatus = "parked"         // En espera por la configuración de la cuenta; el usuario puede aprobarla
+)
+
+// NotificationInboxItem almacena cada notificación recibida con el resultado de su procesamiento,
+// para poder revisarla o reprocesarla más tarde (por ejemplo, después de corregir un patrón)
+type NotificationInboxItem struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relaciones
+	UserID        uint  `json:"user_id" gorm:"not null;index"`
+	BankAccountID *uint `json:"bank_account_id" gorm:"index"` // Nil mientras la notificación no se asigne a una cuenta
+
+	// Notificación original
+	Channel    NotificationChannel `json:"channel" gorm:"not null"`
+	Message    string              `json:"message" gorm:"type:text;not null"`
+	Sender     string              `json:"sender"` // Teléfono o email remitente
+	ReceivedAt time.Time           `json:"received_at" gorm:"not null;index"`
+
+	// El canal ya garantiza el origen (alias de ingesta de correos): no se verifica el remitente
+	SkipSenderCheck bool `json:"skip_sender_check" gorm:"default:false"`
+
+	// Parámetros de ingesta solicitados (se reutilizan al reprocesar o aprobar)
+	Mode       NotificationIngestionMode `json:"mode" gorm:"not null"`
+	AccountID  *uint                     `json:"account_id"`
+	CategoryID *uint                     `json:"category_id"`
+
+	// Resultado del procesamiento
+	Status          NotificationInboxStatus `json:"status" gorm:"not null;index"`
+	StatusReason    string                  `json:"status_reason"` // Error de procesamiento o motivo del estado
+	ReasonCode      NotificationReason      `json:"reason_code"`   // Decisión según la configuración de notificaciones de la cuenta
+	PatternID       *uint                   `json:"pattern_id" gorm:"index"`
+	Confidence      float64                 `json:"confidence" gorm:"type:decimal(3,2)"`
+	ExtractedData   string                  `json:"extracted_data" gorm:"type:text"` // Datos extraídos (JSON)
+	Amount          money.Amount            `json:"amount" gorm:"type:decimal(15,2)"`
+	Currency        string                  `json:"currency"`
+	TransactionDate *time.Time              `json:"transaction_date"`
+	Attempts        int                     `json:"attempts" gorm:"default:0"`
+	LastProcessedAt *time.Time              `json:"last_processed_at"`
+
+	// Movimiento generado
+	TransactionID  *uint `json:"transaction_id" gorm:"index"`
+	ExpenseID      *uint `json:"expense_id" gorm:"index"`
+	NotificationID *uint `json:"notification_id"` // Huella usada para deduplicar
+}
+
+// GetExtractedData convierte el campo ExtractedData (JSON string) a map
+func (ni *NotificationInboxItem) GetExtractedData() map[string]interface{} {
+	if ni.ExtractedData == "" {
+		return map[string]interface{}{}
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(ni.ExtractedData), &data); err != nil {
+		return map[string]interface{}{}
+	}
+
+	return data
+}
+
+// SetExtractedData convierte un map a JSON string para ExtractedData
+func (ni *NotificationInboxItem) SetExtractedData(data map[string]interface{}) error {
+	if len(data) == 0 {
+		ni.ExtractedData = ""
+		return nil
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	ni.ExtractedData = string(jsonData)
+	return nil
+}
+
+// IsRouted verifica si la notificación tiene una cuenta bancaria asignada
+func (ni *NotificationInboxItem) IsRouted() bool {
+	return ni.BankAccountID != nil
+}
+
+// HasMovement verifica si la notificación ya generó una transacción o gasto
+func (ni *NotificationInboxItem) HasMovement() bool {
+	return ni.TransactionID != nil || ni.ExpenseID != nil
+}
+
+// CanReprocess verifica si la notificación puede volver a procesarse.
+// Las notificaciones que ya generaron un movimiento no se reprocesan para no duplicarlo.
+func (ni *NotificationInboxItem) CanReprocess() bool {
+	return !ni.HasMovement() && ni.Status != NotificationInboxStatusConverted
+}
+
+// NotificationInboxFilter representa filtros para la bandeja de notificaciones
+type NotificationInboxFilter struct {
+	BankAccountID *uint                    `json:"bank_account_id"`
+	Channel       *NotificationChannel     `json:"channel"`
+	Status        *NotificationInboxStatus `json:"status"`
+	Limit         int                      `json:"limit"`
+	Offset        int                      `json:"offset"`
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+)
+
+// PatternTransitionReason define el motivo de un cambio de estado de un patrón
+type PatternTransitionReason string
+
+const (
+	PatternTransitionReasonPromoted PatternTransitionReason = "promoted" // Promovido por alcanzar los umbrales de éxito
+	PatternTransitionReasonDemoted  PatternTransitionReason = "demoted"  // Devuelto a aprendizaje por baja tasa de éxito
+	PatternTransitionReasonDisabled PatternTransitionReason = "disabled" // Desactivado por tasa de éxito crítica
+	PatternTransitionReasonManual   PatternTransitionReason = "manual"   // Cambio manual del usuario
+)
+
+// PatternHistory registra un cambio de estado de un patrón de notificación
+type PatternHistory struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+
+	// Relaciones
+	UserID    uint `json:"user_id" gorm:"not null;index"`
+	PatternID uint `json:"pattern_id" gorm:"not null;index"`
+
+	// Transición
+	FromStatus NotificationPatternStatus `json:"from_status" gorm:"not null"`
+	ToStatus   NotificationPatternStatus `json:"to_status" gorm:"not null"`
+	Reason     PatternTransitionReason   `json:"reason" gorm:"not null"`
+	Details    string                    `json:"details"` // Explicación legible del cambio
+
+	// Estadísticas del patrón al momento del cambio
+	MatchCount   int     `json:"match_count"`
+	SuccessCount int     `json:"success_count"`
+	FailureCount int     `json:"failure_count"`
+	SuccessRate  float64 `json:"success_rate" gorm:"type:decimal(5,2)"` // Tasa sobre movimientos validados (%)
+}
+
+// NewPatternHistory crea el registro de una transición con las estadísticas actuales del patrón
+func NewPatternHistory(pattern *BankNotificationPattern, toStatus NotificationPatternStatus, reason PatternTransitionReason, details string) *PatternHistory {
+	return &PatternHistory{
+		UserID:       pattern.UserID,
+		PatternID:    pattern.ID,
+		FromStatus:   pattern.Status,
+		ToStatus:     toStatus,
+		Reason:       reason,
+		Details:      details,
+		MatchCount:   pattern.MatchCount,
+		SuccessCount: pattern.SuccessCount,
+		FailureCount: pattern.FailureCount,
+		SuccessRate:  pattern.ResolvedSuccessRate(),
+	}
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"time"
+)
+
+// Valores por defecto de la política de ciclo de vida de patrones
+const (
+	DefaultPromoteMinConfirmed   = 5    // Confirmaciones mínimas para promover un patrón en aprendizaje
+	DefaultPromoteMinSuccessRate = 80.0 // Tasa de éxito mínima (%) para promover
+	DefaultDemoteMinSamples      = 10   // Validaciones mínimas antes de evaluar una degradación
+	DefaultDemoteBelowRate       = 60.0 // Tasa de éxito (%) por debajo de la cual un patrón activo vuelve a aprendizaje
+	DefaultDisableBelowRate      = 30.0 // Tasa de éxito (%) por debajo de la cual un patrón se desactiva
+)
+
+// PatternPolicy define los umbrales con los que se promueven o degradan los patrones de un usuario.
+// Los valores por defecto se asignan en NewDefaultPatternPolicy y no en la base de datos, para
+// que GORM no reemplace valores cero explícitos (ej: desactivar la política) al crear el registro.
+type PatternPolicy struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relaciones
+	UserID uint `json:"user_id" gorm:"not null;uniqueIndex"`
+
+	// Estado de la política
+	Enabled bool `json:"enabled" gorm:"not null"` // Si se aplican transiciones automáticas
+
+	// Promoción learning → active
+	PromoteMinConfirmed   int     `json:"promote_min_confirmed" gorm:"not null"`                      // Confirmaciones mínimas
+	PromoteMinSuccessRate float64 `json:"promote_min_success_rate" gorm:"not null;type:decimal(5,2)"` // Tasa mínima (%)
+
+	// Degradación active → learning / inactive
+	DemoteMinSamples int     `json:"demote_min_samples" gorm:"not null"`                   // Validaciones mínimas antes de degradar
+	DemoteBelowRate  float64 `json:"demote_below_rate" gorm:"not null;type:decimal(5,2)"`  // Tasa (%) para volver a aprendizaje
+	DisableBelowRate float64 `json:"disable_below_rate" gorm:"not null;type:decimal(5,2)"` // Tasa (%) para desactivar
+}
+
+// NewDefaultPatternPolicy crea la política por defecto de un usuario
+func NewDefaultPatternPolicy(userID uint) *PatternPolicy {
+	return &PatternPolicy{
+		UserID:                userID,
+		Enabled:               true,
+		PromoteMinConfirmed:   DefaultPromoteMinConfirmed,
+		PromoteMinSuccessRate: DefaultPromoteMinSuccessRate,
+		DemoteMinSamples:      DefaultDemoteMinSamples,
+		DemoteBelowRate:       DefaultDemoteBelowRate,
+		DisableBelowRate:      DefaultDisableBelowRate,
+	}
+}
+
+// Evaluate determina si un patrón debe cambiar de estado según sus estadísticas.
+// Retorna el nuevo estado y el motivo, o false si no corresponde ninguna transición.
+// Las tasas se calculan sobre movimientos validados (confirmados o rechazados),
+// para que las notificaciones aún pendientes de revisión no penalicen al patrón.
+func (pp *PatternPolicy) Evaluate(pattern *BankNotificationPattern) (NotificationPatternStatus, PatternTransitionReason, bool) {
+	if !pp.Enabled {
+		return "", "", false
+	}
+
+	samples := pattern.SuccessCount + pattern.FailureCount
+	rate := pattern.ResolvedSuccessRate()
+
+	switch pattern.Status {
+	case NotificationPatternStatusLearning:
+		if pattern.SuccessCount >= pp.PromoteMinConfirmed && rate >= pp.PromoteMinSuccessRate {
+			return NotificationPatternStatusActive, PatternTransitionReasonPromoted, true
+		}
+	case NotificationPatternStatusActive:
+		if samples < pp.DemoteMinSamples {
+			return "", "", false
+		}
+		if rate < pp.DisableBelowRate {
+			return NotificationPatternStatusInactive, PatternTransitionReasonDisabled, true
+		}
+		if rate < pp.DemoteBelowRate {
+			return NotificationPatternStatusLearning, PatternTransitionReasonDemoted, true
+		}
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,194 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Campos de un patrón que provienen de su plantilla. Si el usuario modifica alguno en su copia,
+// queda registrado como ajuste local y las nuevas versiones de la plantilla no lo sobrescriben.
+const (
+	TemplateFieldName                = "name"
+	TemplateFieldDescription         = "description"
+	TemplateFieldMessagePattern      = "message_pattern"
+	TemplateFieldExampleMessage      = "example_message"
+	TemplateFieldKeywordsTrigger     = "keywords_trigger"
+	TemplateFieldKeywordsExclude     = "keywords_exclude"
+	TemplateFieldAmountRegex         = "amount_regex"
+	TemplateFieldDateRegex           = "date_regex"
+	TemplateFieldDescriptionRegex    = "description_regex"
+	TemplateFieldMerchantRegex       = "merchant_regex"
+	TemplateFieldBalanceRegex        = "balance_regex"
+	TemplateFieldTransactionKind     = "transaction_kind"
+	TemplateFieldRequiresValidation  = "requires_validation"
+	TemplateFieldConfidenceThreshold = "confidence_threshold"
+	TemplateFieldAutoApprove         = "auto_approve"
+	TemplateFieldPriority            = "priority"
+	TemplateFieldTags                = "tags"
+)
+
+// PatternTemplate representa una plantilla de la biblioteca de patrones por banco y canal.
+// Las cuentas bancarias se suscriben a una plantilla y reciben una copia (BankNotificationPattern)
+// que se actualiza cuando cambia la versión de la plantilla.
+type PatternTemplate struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relación con usuario (null para plantillas del sistema)
+	UserID *uint `json:"user_id" gorm:"index"`
+
+	// Identificación
+	Slug     string              `json:"slug" gorm:"not null;uniqueIndex;size:100"` // Identificador estable usado al exportar e importar
+	BankCode string              `json:"bank_code" gorm:"not null;index;size:10"`   // Código del banco (BankAccount.BankCode)
+	BankName string              `json:"bank_name"`
+	Channel  NotificationChannel `json:"channel" gorm:"not null;index"`
+	Version  int                 `json:"version" gorm:"not null;default:1"`
+
+	// Contenido del patrón
+	Name                string          `json:"name" gorm:"not null"`
+	Description         string          `json:"description"`
+	MessagePattern      string          `json:"message_pattern" gorm:"type:text"`
+	ExampleMessage      string          `json:"example_message" gorm:"type:text"`
+	KeywordsTrigger     string          `json:"keywords_trigger"` // Palabras clave (JSON array)
+	KeywordsExclude     string          `json:"keywords_exclude"` // Palabras a excluir (JSON array)
+	AmountRegex         string          `json:"amount_regex"`
+	DateRegex           string          `json:"date_regex"`
+	DescriptionRegex    string          `json:"description_regex"`
+	MerchantRegex       string          `json:"merchant_regex"`
+	BalanceRegex        string          `json:"balance_regex"`
+	TransactionKind     TransactionKind `json:"transaction_kind"`
+	RequiresValidation  bool            `json:"requires_validation"`
+	ConfidenceThreshold float64         `json:"confidence_threshold" gorm:"type:decimal(3,2)"`
+	AutoApprove         bool            `json:"auto_approve"`
+	Priority            int             `json:"priority"`
+	Tags                string          `json:"tags"` // Tags adicionales (JSON array)
+}
+
+// IsSystem verifica si la plantilla pertenece a la biblioteca del sistema
+func (pt *PatternTemplate) IsSystem() bool {
+	return pt.UserID == nil
+}
+
+// GetKeywordsTrigger convierte el campo KeywordsTrigger (JSON string) a slice de strings
+func (pt *PatternTemplate) GetKeywordsTrigger() []string {
+	return decodeStringList(pt.KeywordsTrigger)
+}
+
+// GetKeywordsExclude convierte el campo KeywordsExclude (JSON string) a slice de strings
+func (pt *PatternTemplate) GetKeywordsExclude() []string {
+	return decodeStringList(pt.KeywordsExclude)
+}
+
+// GetTags convierte el campo Tags (JSON string) a slice de strings
+func (pt *PatternTemplate) GetTags() []string {
+	return decodeStringList(pt.Tags)
+}
+
+// SetLists guarda como JSON las palabras clave y los tags de la plantilla
+func (pt *PatternTemplate) SetLists(keywordsTrigger, keywordsExclude, tags []string) error {
+	var err error
+	if pt.KeywordsTrigger, err = encodeStringList(keywordsTrigger); err != nil {
+		return err
+	}
+	if pt.KeywordsExclude, err = encodeStringList(keywordsExclude); err != nil {
+		return err
+	}
+	if pt.Tags, err = encodeStringList(tags); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SameContent verifica si otra plantilla tiene el mismo contenido de patrón
+func (pt *PatternTemplate) SameContent(other *PatternTemplate) bool {
+	return pt.Name == other.Name &&
+		pt.Description == other.Description &&
+		pt.MessagePattern == other.MessagePattern &&
+		pt.ExampleMessage == other.ExampleMessage &&
+		pt.KeywordsTrigger == other.KeywordsTrigger &&
+		pt.KeywordsExclude == other.KeywordsExclude &&
+		pt.AmountRegex == other.AmountRegex &&
+		pt.DateRegex == other.DateRegex &&
+		pt.DescriptionRegex == other.DescriptionRegex &&
+		pt.MerchantRegex == other.MerchantRegex &&
+		pt.BalanceRegex == other.BalanceRegex &&
+		pt.TransactionKind == other.TransactionKind &&
+		pt.RequiresValidation == other.RequiresValidation &&
+		pt.ConfidenceThreshold == other.ConfidenceThreshold &&
+		pt.AutoApprove == other.AutoApprove &&
+		pt.Priority == other.Priority &&
+		pt.Tags == other.Tags
+}
+
+// ApplyTo copia el contenido de la plantilla al patrón, excepto los campos ajustados localmente
+func (pt *PatternTemplate) ApplyTo(pattern *BankNotificationPattern) {
+	overrides := make(map[string]bool)
+	for _, field := range pattern.GetOverrides() {
+		overrides[field] = true
+	}
+
+	apply := func(field string, set func()) {
+		if !overrides[field] {
+			set()
+		}
+	}
+
+	apply(TemplateFieldName, func() { pattern.Name = pt.Name })
+	apply(TemplateFieldDescription, func() { pattern.Description = pt.Description })
+	apply(TemplateFieldMessagePattern, func() { pattern.MessagePattern = pt.MessagePattern })
+	apply(TemplateFieldExampleMessage, func() { pattern.ExampleMessage = pt.ExampleMessage })
+	apply(TemplateFieldKeywordsTrigger, func() { pattern.KeywordsTrigger = pt.KeywordsTrigger })
+	apply(TemplateFieldKeywordsExclude, func() { pattern.KeywordsExclude = pt.KeywordsExclude })
+	apply(TemplateFieldAmountRegex, func() { pattern.AmountRegex = pt.AmountRegex })
+	apply(TemplateFieldDateRegex, func() { pattern.DateRegex = pt.DateRegex })
+	apply(TemplateFieldDescriptionRegex, func() { pattern.DescriptionRegex = pt.DescriptionRegex })
+	apply(TemplateFieldMerchantRegex, func() { pattern.MerchantRegex = pt.MerchantRegex })
+	apply(TemplateFieldBalanceRegex, func() { pattern.BalanceRegex = pt.BalanceRegex })
+	apply(TemplateFieldTransactionKind, func() { pattern.TransactionKind = pt.TransactionKind })
+	apply(TemplateFieldRequiresValidation, func() { pattern.RequiresValidation = pt.RequiresValidation })
+	apply(TemplateFieldConfidenceThreshold, func() { pattern.ConfidenceThreshold = pt.ConfidenceThreshold })
+	apply(TemplateFieldAutoApprove, func() { pattern.AutoApprove = pt.AutoApprove })
+	apply(TemplateFieldPriority, func() { pattern.Priority = pt.Priority })
+	apply(TemplateFieldTags, func() { pattern.Tags = pt.Tags })
+
+	pattern.TemplateID = &pt.ID
+	pattern.TemplateVersion = pt.Version
+}
+
+// PatternTemplateFilter representa filtros para la biblioteca de plantillas
+type PatternTemplateFilter struct {
+	BankCode string               `json:"bank_code"`
+	Channel  *NotificationChannel `json:"channel"`
+}
+
+// decodeStringList convierte un JSON array a slice de strings
+func decodeStringList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+
+	var list []string
+	if err := json.Unmarshal([]byte(value), &list); err != nil {
+		return []string{}
+	}
+
+	return list
+}
+
+// encodeStringList convierte un slice de strings a JSON array (vacío si no hay elementos)
+func encodeStringList(list []string) (string, error) {
+	if len(list) == 0 {
+		return "", nil
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
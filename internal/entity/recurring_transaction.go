@@ -0,0 +1,369 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// RecurrenceFrequency define la unidad de repetición de una transacción recurrente (FREQ de RRULE)
+type RecurrenceFrequency string
+
+const (
+	RecurrenceFrequencyDaily   RecurrenceFrequency = "daily"   // Diaria
+	RecurrenceFrequencyWeekly  RecurrenceFrequency = "weekly"  // Semanal (el día de la semana de la fecha de inicio)
+	RecurrenceFrequencyMonthly RecurrenceFrequency = "monthly" // Mensual
+	RecurrenceFrequencyYearly  RecurrenceFrequency = "yearly"  // Anual
+)
+
+// BusinessDayAdjustment define cómo se mueve una ocurrencia que cae en fin de semana
+type BusinessDayAdjustment string
+
+const (
+	BusinessDayNone     BusinessDayAdjustment = "none"     // Se mantiene la fecha
+	BusinessDayPrevious BusinessDayAdjustment = "previous" // Se adelanta al viernes anterior
+	BusinessDayNext     BusinessDayAdjustment = "next"     // Se pospone al lunes siguiente
+)
+
+// RecurringTransactionStatus define el estado de una transacción recurrente
+type RecurringTransactionStatus string
+
+const (
+	RecurringTransactionStatusActive RecurringTransactionStatus = "active" // Genera sus ocurrencias
+	RecurringTransactionStatusPaused RecurringTransactionStatus = "paused" // Pausada por el usuario
+	RecurringTransactionStatusEnded  RecurringTransactionStatus = "ended"  // Alcanzó su fecha o número de ocurrencias
+)
+
+// LastDayOfMonth indica en DayOfMonth el último día de cada mes (BYMONTHDAY=-1 de RRULE)
+const LastDayOfMonth = -1
+
+// maxOccurrenceScan limita la búsqueda de una fecha entre las ocurrencias de una programación
+const maxOccurrenceScan = 10000
+
+// RecurringTransaction es la programación de un gasto, ingreso o transferencia que se repite (renta,
+// suscripciones, salario). Sus ocurrencias se generan como transacciones con RecurringID y
+// OccurrenceDate, que las identifican para no generarlas dos veces.
+type RecurringTransaction struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relaciones
+	UserID      uint  `json:"user_id" gorm:"not null;index"`
+	AccountID   uint  `json:"account_id" gorm:"not null;index"`
+	ToAccountID *uint `json:"to_account_id"` // Cuenta destino (para transferencias)
+	CategoryID  *uint `json:"category_id"`
+
+	// Plantilla de las transacciones generadas
+	Type         TransactionType `json:"type" gorm:"not null" validate:"required,oneof=income expense transfer"`
+	Amount       money.Amount    `json:"amount" gorm:"not null;type:decimal(15,2)" validate:"required,gt=0"`
+	Currency     string          `json:"currency" gorm:"size:3" validate:"len=3"`
+	Description  string          `json:"description" gorm:"not null" validate:"required,min=1,max=500"`
+	CategoryName string          `json:"category_name" validate:"max=100"` // Desnormalizado para performance
+	Notes        string          `json:"notes" validate:"max=1000"`
+
+	// Programación (subconjunto de RRULE)
+	Frequency   RecurrenceFrequency   `json:"frequency" gorm:"not null" validate:"required,oneof=daily weekly monthly yearly"`
+	Interval    int                   `json:"interval" gorm:"not null;default:1" validate:"min=1,max=365"` // Cada cuántas unidades se repite (2 + weekly = quincenal)
+	DayOfMonth  int                   `json:"day_of_month" gorm:"default:0"`                               // 1-31 o -1 (último día) para monthly/yearly; 0 usa el día de StartDate
+	BusinessDay BusinessDayAdjustment `json:"business_day" gorm:"not null;default:'none'"`
+	StartDate   time.Time             `json:"start_date" gorm:"not null"`
+
+	// Condiciones de fin (opcionales)
+	EndDate        *time.Time `json:"end_date"`
+	MaxOccurrences *int       `json:"max_occurrences"`
+
+	// Estado de la generación de ocurrencias
+	Status         RecurringTransactionStatus `json:"status" gorm:"not null;default:'active';index"`
+	NextIndex      int                        `json:"-" gorm:"not null;default:0"` // Índice de la próxima ocurrencia por generar
+	NextOccurrence *time.Time                 `json:"next_occurrence" gorm:"index"`
+	LastOccurrence *time.Time                 `json:"last_occurrence"`
+	GeneratedCount int                        `json:"generated_count" gorm:"default:0"`
+
+	// Ocurrencias omitidas o modificadas
+	Exceptions []RecurrenceException `json:"exceptions,omitempty" gorm:"foreignKey:RecurringID"`
+}
+
+// RecurrenceExceptionAction define qué se hace con una ocurrencia puntual
+type RecurrenceExceptionAction string
+
+const (
+	RecurrenceExceptionSkip   RecurrenceExceptionAction = "skip"   // No se genera
+	RecurrenceExceptionModify RecurrenceExceptionAction = "modify" // Se genera con otros valores
+)
+
+// RecurrenceException omite o modifica una sola ocurrencia de una transacción recurrente
+type RecurrenceException struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	RecurringID    uint                      `json:"recurring_id" gorm:"not null;uniqueIndex:idx_recurrence_exception_occurrence"`
+	OccurrenceDate time.Time                 `json:"occurrence_date" gorm:"not null;uniqueIndex:idx_recurrence_exception_occurrence"` // Fecha programada de la ocurrencia
+	Action         RecurrenceExceptionAction `json:"action" gorm:"not null" validate:"required,oneof=skip modify"`
+
+	// Valores de la ocurrencia modificada (vacío mantiene el de la programación)
+	Amount          *money.Amount `json:"amount,omitempty" gorm:"type:decimal(15,2)"`
+	Description     string        `json:"description,omitempty" validate:"max=500"`
+	Notes           string        `json:"notes,omitempty" validate:"max=1000"`
+	TransactionDate *time.Time    `json:"transaction_date,omitempty"` // Fecha a la que se mueve la ocurrencia
+}
+
+// RecurrenceOccurrenceStatus define el estado de una ocurrencia por generar
+type RecurrenceOccurrenceStatus string
+
+const (
+	RecurrenceOccurrenceScheduled RecurrenceOccurrenceStatus = "scheduled" // Se generará según la programación
+	RecurrenceOccurrenceSkipped   RecurrenceOccurrenceStatus = "skipped"   // Omitida por el usuario
+	RecurrenceOccurrenceModified  RecurrenceOccurrenceStatus = "modified"  // Modificada por el usuario
+)
+
+// RecurrenceOccurrence es una ocurrencia de la programación con sus excepciones aplicadas
+type RecurrenceOccurrence struct {
+	Date            time.Time                  `json:"date"`             // Fecha programada
+	TransactionDate time.Time                  `json:"transaction_date"` // Fecha de la transacción generada
+	Amount          money.Amount               `json:"amount"`
+	Description     string                     `json:"description"`
+	Notes           string                     `json:"notes"`
+	Status          RecurrenceOccurrenceStatus `json:"status"`
+}
+
+// DateOf retorna el día de una fecha a medianoche UTC, la forma en que se guardan las fechas de
+// las ocurrencias
+func DateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// IsActive verifica si la transacción recurrente genera ocurrencias
+func (r *RecurringTransaction) IsActive() bool {
+	return r.Status == RecurringTransactionStatusActive
+}
+
+// IsPaused verifica si la transacción recurrente está pausada
+func (r *RecurringTransaction) IsPaused() bool {
+	return r.Status == RecurringTransactionStatusPaused
+}
+
+// OccurrenceDate calcula la fecha de la n-ésima ocurrencia (desde 0). Se calcula desde StartDate y
+// no desde la ocurrencia anterior, para que el día 31 no se corra a 28 después de febrero.
+func (r *RecurringTransaction) OccurrenceDate(n int) time.Time {
+	// Si el día del mes pedido es anterior a la fecha de inicio, la primera ocurrencia es la del
+	// período siguiente
+	start := DateOf(r.StartDate)
+	if r.scheduledDate(0).Before(start) {
+		n++
+	}
+
+	// Adelantar al día hábil anterior no puede llevar la primera ocurrencia antes del inicio
+	date := r.adjustBusinessDay(r.scheduledDate(n))
+	if date.Before(start) {
+		return start
+	}
+	return date
+}
+
+// Occurrence retorna la n-ésima ocurrencia con su excepción aplicada
+func (r *RecurringTransaction) Occurrence(n int) RecurrenceOccurrence {
+	date := r.OccurrenceDate(n)
+	occurrence := RecurrenceOccurrence{
+		Date:            date,
+		TransactionDate: date,
+		Amount:          r.Amount,
+		Description:     r.Description,
+		Notes:           r.Notes,
+		Status:          RecurrenceOccurrenceScheduled,
+	}
+
+	exception := r.ExceptionFor(date)
+	if exception == nil {
+		return occurrence
+	}
+
+	if exception.Action == RecurrenceExceptionSkip {
+		occurrence.Status = RecurrenceOccurrenceSkipped
+		return occurrence
+	}
+
+	occurrence.Status = RecurrenceOccurrenceModified
+	if exception.Amount != nil {
+		occurrence.Amount = *exception.Amount
+	}
+	if exception.Description != "" {
+		occurrence.Description = exception.Description
+	}
+	if exception.Notes != "" {
+		occurrence.Notes = exception.Notes
+	}
+	if exception.TransactionDate != nil {
+		occurrence.TransactionDate = DateOf(*exception.TransactionDate)
+	}
+	return occurrence
+}
+
+// OccurrenceIndex busca la ocurrencia programada en una fecha. Retorna false si la fecha no es
+// una ocurrencia de la programación.
+func (r *RecurringTransaction) OccurrenceIndex(date time.Time) (int, bool) {
+	date = DateOf(date)
+	for n := 0; n < maxOccurrenceScan; n++ {
+		occurrenceDate := r.OccurrenceDate(n)
+		if !r.withinEnd(n, occurrenceDate) || occurrenceDate.After(date) {
+			return 0, false
+		}
+		if occurrenceDate.Equal(date) {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// HasOccurrence verifica si la n-ésima ocurrencia cumple las condiciones de fin
+func (r *RecurringTransaction) HasOccurrence(n int) bool {
+	return n >= 0 && r.withinEnd(n, r.OccurrenceDate(n))
+}
+
+// ExceptionFor retorna la excepción de la ocurrencia programada en una fecha, si existe
+func (r *RecurringTransaction) ExceptionFor(date time.Time) *RecurrenceException {
+	for i := range r.Exceptions {
+		if DateOf(r.Exceptions[i].OccurrenceDate).Equal(DateOf(date)) {
+			return &r.Exceptions[i]
+		}
+	}
+	return nil
+}
+
+// ScheduleNext calcula la próxima ocurrencia por generar, o termina la recurrencia si ya no quedan
+func (r *RecurringTransaction) ScheduleNext() {
+	date := r.OccurrenceDate(r.NextIndex)
+	if !r.withinEnd(r.NextIndex, date) {
+		r.NextOccurrence = nil
+		r.Status = RecurringTransactionStatusEnded
+		return
+	}
+
+	r.NextOccurrence = &date
+	if r.Status == RecurringTransactionStatusEnded {
+		r.Status = RecurringTransactionStatusActive
+	}
+}
+
+// Advance registra la ocurrencia actual como procesada y pasa a la siguiente
+func (r *RecurringTransaction) Advance(generated bool) {
+	if r.NextOccurrence != nil {
+		last := *r.NextOccurrence
+		r.LastOccurrence = &last
+	}
+	if generated {
+		r.GeneratedCount++
+	}
+	r.NextIndex++
+	r.ScheduleNext()
+}
+
+// SkipUntil pasa por alto las ocurrencias anteriores a una fecha sin generarlas (ej: las que
+// cayeron mientras la recurrencia estaba pausada)
+func (r *RecurringTransaction) SkipUntil(date time.Time) {
+	date = DateOf(date)
+	for r.NextOccurrence != nil && r.NextOccurrence.Before(date) {
+		r.NextIndex++
+		r.ScheduleNext()
+	}
+}
+
+// NewTransaction crea la transacción de una ocurrencia
+func (r *RecurringTransaction) NewTransaction(occurrence RecurrenceOccurrence) *Transaction {
+	recurringID := r.ID
+	occurrenceDate := occurrence.Date
+	transaction := &Transaction{
+		UserID:          r.UserID,
+		AccountID:       r.AccountID,
+		ToAccountID:     r.ToAccountID,
+		Type:            r.Type,
+		Status:          TransactionStatusCompleted,
+		Amount:          occurrence.Amount,
+		Description:     occurrence.Description,
+		TransactionDate: occurrence.TransactionDate,
+		Notes:           occurrence.Notes,
+		Recurring:       true,
+		RecurringID:     &recurringID,
+		OccurrenceDate:  &occurrenceDate,
+		Currency:        r.Currency,
+		ExchangeRate:    1.0,
+		Source:          TransactionSourceRecurring,
+	}
+
+	if r.Type != TransactionTypeTransfer {
+		transaction.ToAccountID = nil
+		transaction.CategoryID = r.CategoryID
+		transaction.CategoryName = r.CategoryName
+	}
+
+	return transaction
+}
+
+// scheduledDate calcula la n-ésima fecha de la programación antes de ajustar por día hábil
+func (r *RecurringTransaction) scheduledDate(n int) time.Time {
+	start := DateOf(r.StartDate)
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch r.Frequency {
+	case RecurrenceFrequencyWeekly:
+		return start.AddDate(0, 0, 7*n*interval)
+	case RecurrenceFrequencyMonthly:
+		return dayInMonth(start.Year(), start.Month()+time.Month(n*interval), r.dayOfMonth())
+	case RecurrenceFrequencyYearly:
+		return dayInMonth(start.Year()+n*interval, start.Month(), r.dayOfMonth())
+	default:
+		return start.AddDate(0, 0, n*interval)
+	}
+}
+
+// dayOfMonth retorna el día del mes de las programaciones mensuales y anuales
+func (r *RecurringTransaction) dayOfMonth() int {
+	if r.DayOfMonth != 0 {
+		return r.DayOfMonth
+	}
+	return r.StartDate.Day()
+}
+
+// adjustBusinessDay mueve una fecha que cae en fin de semana según el ajuste configurado
+func (r *RecurringTransaction) adjustBusinessDay(date time.Time) time.Time {
+	switch r.BusinessDay {
+	case BusinessDayPrevious:
+		for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			date = date.AddDate(0, 0, -1)
+		}
+	case BusinessDayNext:
+		for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			date = date.AddDate(0, 0, 1)
+		}
+	}
+	return date
+}
+
+// withinEnd verifica si la n-ésima ocurrencia, en la fecha indicada, cumple las condiciones de fin
+func (r *RecurringTransaction) withinEnd(n int, date time.Time) bool {
+	if r.MaxOccurrences != nil && n >= *r.MaxOccurrences {
+		return false
+	}
+	if r.EndDate != nil && date.After(DateOf(*r.EndDate)) {
+		return false
+	}
+	return true
+}
+
+// dayInMonth retorna el día indicado de un mes (el mes puede desbordar el año); los días que el
+// mes no tiene y LastDayOfMonth se convierten en su último día
+func dayInMonth(year int, month time.Month, day int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	if day == LastDayOfMonth || day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.UTC)
+}
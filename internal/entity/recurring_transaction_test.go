@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecurringTransactionOccurrenceDate(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		recurring RecurringTransaction
+		n         int
+		want      time.Time
+	}{
+		{
+			name:      "day 31 after february",
+			recurring: RecurringTransaction{Frequency: RecurrenceFrequencyMonthly, StartDate: date(2026, 1, 31)},
+			n:         2,
+			want:      date(2026, 3, 31),
+		},
+		{
+			name:      "day of month before the start",
+			recurring: RecurringTransaction{Frequency: RecurrenceFrequencyMonthly, DayOfMonth: 5, StartDate: date(2026, 3, 10)},
+			n:         0,
+			want:      date(2026, 4, 5),
+		},
+		{
+			name:      "biweekly",
+			recurring: RecurringTransaction{Frequency: RecurrenceFrequencyWeekly, Interval: 2, StartDate: date(2026, 3, 6)},
+			n:         3,
+			want:      date(2026, 4, 17),
+		},
+		{
+			name:      "previous business day",
+			recurring: RecurringTransaction{Frequency: RecurrenceFrequencyMonthly, BusinessDay: BusinessDayPrevious, StartDate: date(2026, 2, 1)},
+			n:         1,
+			want:      date(2026, 2, 27), // 1 de marzo es domingo
+		},
+		{
+			name:      "previous business day before the start",
+			recurring: RecurringTransaction{Frequency: RecurrenceFrequencyMonthly, BusinessDay: BusinessDayPrevious, StartDate: date(2026, 3, 1)},
+			n:         0,
+			want:      date(2026, 3, 1),
+		},
+		{
+			name:      "previous business day after the first occurrence",
+			recurring: RecurringTransaction{Frequency: RecurrenceFrequencyMonthly, BusinessDay: BusinessDayPrevious, StartDate: date(2026, 3, 1)},
+			n:         2,
+			want:      date(2026, 5, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.recurring.OccurrenceDate(tt.n); !got.Equal(tt.want) {
+				t.Fatalf("OccurrenceDate(%d) = %s, want %s", tt.n, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
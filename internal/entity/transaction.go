@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // TransactionType define los tipos de transacción
@@ -33,6 +35,7 @@ const (
 	TransactionSourceManual       TransactionSource = "manual"       // Ingresada manualmente
 	TransactionSourceIntegration  TransactionSource = "integration"  // Desde integración bancaria
 	TransactionSourceImport       TransactionSource = "import"       // Importada desde archivo
+	TransactionSourceRecurring    TransactionSource = "recurring"    // Generada por una transacción recurrente
 )
 
 // ValidationStatus define el estado de validación de la transacción
@@ -63,7 +66,7 @@ type Transaction struct {
 	// Información de la transacción
 	Type        TransactionType   `json:"type" gorm:"not null" validate:"required,oneof=income expense transfer"`
 	Status      TransactionStatus `json:"status" gorm:"default:'completed'" validate:"oneof=pending completed cancelled"`
-	Amount      float64           `json:"amount" gorm:"not null;type:decimal(15,2)" validate:"required,gt=0"`
+	Amount      money.Amount      `json:"amount" gorm:"not null;type:decimal(15,2)" validate:"required,gt=0"`
 	Description string            `json:"description" gorm:"not null" validate:"required,min=1,max=500"`
 
 	// Categorización
@@ -76,25 +79,43 @@ type Transaction struct {
 	Location        string    `json:"location" validate:"max=200"`
 
 	// Información adicional
-	Reference   string `json:"reference" validate:"max=100"`   // Número de referencia
-	Notes       string `json:"notes" validate:"max=1000"`      // Notas adicionales
-	Recurring   bool   `json:"recurring" gorm:"default:false"` // Si es una transacción recurrente
-	RecurringID *uint  `json:"recurring_id" gorm:"index"`      // ID del patrón recurrente
+	Reference string `json:"reference" validate:"max=100"`   // Número de referencia
+	Notes     string `json:"notes" validate:"max=1000"`      // Notas adicionales
+	Recurring bool   `json:"recurring" gorm:"default:false"` // Si es una transacción recurrente
+
+	// Transacción recurrente que generó la transacción. Con la fecha programada de la ocurrencia
+	// la identifican para no generarla dos veces aunque se mueva de fecha o se elimine.
+	RecurringID    *uint      `json:"recurring_id" gorm:"index;uniqueIndex:idx_transactions_recurring_occurrence"`
+	OccurrenceDate *time.Time `json:"occurrence_date,omitempty" gorm:"uniqueIndex:idx_transactions_recurring_occurrence"`
+
+	// Autorización (ej: una retención de tarjeta): mientras está pendiente retiene HoldAmount en la
+	// cuenta, sin registrar asiento contable, hasta que se confirma o vence. HoldAmount se conserva
+	// al confirmarla, cuando el monto final puede diferir (propinas, combustible). Las transacciones
+	// pendientes que no son autorizaciones (ej: en espera de revisión) se registran en el libro: el
+	// banco ya aplicó el movimiento y solo falta que el usuario confirme los datos extraídos.
+	IsAuthorization bool         `json:"is_authorization" gorm:"default:false;index"`
+	HoldAmount      money.Amount `json:"hold_amount" gorm:"default:0;type:decimal(15,2)"`
+	ClearedAt       *time.Time   `json:"cleared_at,omitempty"`
+	ExpiredAt       *time.Time   `json:"expired_at,omitempty"`
 
 	// Moneda (normalmente heredada de la cuenta)
 	Currency     string  `json:"currency" gorm:"default:'MXN'" validate:"len=3"`
 	ExchangeRate float64 `json:"exchange_rate" gorm:"default:1;type:decimal(10,6)"` // Para conversiones
 
 	// Origen y validación de la transacción
-	Source           TransactionSource `json:"source" gorm:"default:'manual'" validate:"oneof=notification manual integration import"`
+	Source           TransactionSource `json:"source" gorm:"default:'manual'" validate:"oneof=notification manual integration import recurring"`
 	ValidationStatus ValidationStatus  `json:"validation_status" gorm:"default:'auto'" validate:"oneof=auto pending_review manual_validated rejected"`
 	RawNotification  string            `json:"raw_notification" gorm:"type:text"`                // Notificación original (para transacciones desde notificación)
 	AIConfidence     float64           `json:"ai_confidence" gorm:"default:0;type:decimal(3,2)"` // Confianza del AI (0-1)
 	PatternID        *uint             `json:"pattern_id" gorm:"index"`                          // ID del patrón que procesó la notificación
+	ReversalOfID     *uint             `json:"reversal_of_id" gorm:"index"`                      // Transacción que este movimiento compensa (reversos bancarios)
 
 	// Metadatos
 	ImportedFrom string `json:"imported_from" validate:"max=100"` // Fuente de importación
 	ExternalID   string `json:"external_id" validate:"max=100"`   // ID externo
+
+	// Líneas de la transacción dividida entre categorías (vacío si no está dividida)
+	Splits []TransactionSplit `json:"splits,omitempty" gorm:"foreignKey:TransactionID"`
 }
 
 // GetTags convierte el campo Tags (JSON string) a slice de strings
@@ -158,7 +179,7 @@ func (t *Transaction) IsCancelled() bool {
 }
 
 // GetSignedAmount retorna el monto con signo apropiado para balances
-func (t *Transaction) GetSignedAmount() float64 {
+func (t *Transaction) GetSignedAmount() money.Amount {
 	switch t.Type {
 	case TransactionTypeIncome:
 		return t.Amount
@@ -173,7 +194,7 @@ func (t *Transaction) GetSignedAmount() float64 {
 }
 
 // GetSignedAmountForAccount retorna el monto con signo apropiado para una cuenta específica
-func (t *Transaction) GetSignedAmountForAccount(accountID uint) float64 {
+func (t *Transaction) GetSignedAmountForAccount(accountID uint) money.Amount {
 	switch t.Type {
 	case TransactionTypeIncome:
 		return t.Amount
@@ -213,14 +234,56 @@ func (t *Transaction) Cancel() {
 	}
 }
 
+// PlaceHold registra la transacción como autorización pendiente que retiene su monto
+func (t *Transaction) PlaceHold() {
+	t.Status = TransactionStatusPending
+	t.IsAuthorization = true
+	t.HoldAmount = t.Amount
+}
+
+// IsHold verifica si la transacción es una autorización pendiente, que todavía no tiene asiento en
+// el libro contable
+func (t *Transaction) IsHold() bool {
+	return t.IsPending() && t.IsAuthorization
+}
+
+// HoldsFunds verifica si la autorización retiene fondos de la cuenta origen. Los ingresos pendientes
+// no retienen fondos: solo aumentan el balance al confirmarse.
+func (t *Transaction) HoldsFunds() bool {
+	return t.IsHold() && (t.IsExpense() || t.IsTransfer())
+}
+
+// Clear confirma una transacción pendiente con su monto final
+func (t *Transaction) Clear(amount money.Amount, at time.Time) {
+	if !t.IsPending() {
+		return
+	}
+	t.Status = TransactionStatusCompleted
+	t.Amount = amount
+	t.ClearedAt = &at
+}
+
+// Expire cancela una autorización pendiente que no se confirmó a tiempo
+func (t *Transaction) Expire(at time.Time) {
+	if t.IsHold() {
+		t.Status = TransactionStatusCancelled
+		t.ExpiredAt = &at
+	}
+}
+
+// Money retorna el monto con su moneda
+func (t *Transaction) Money() money.Money {
+	return money.New(t.Amount, t.Currency)
+}
+
 // GetAmountInBaseCurrency retorna el monto en la moneda base usando el tipo de cambio
-func (t *Transaction) GetAmountInBaseCurrency(baseCurrency string) float64 {
+func (t *Transaction) GetAmountInBaseCurrency(baseCurrency string) money.Amount {
 	if t.Currency == baseCurrency {
 		return t.Amount
 	}
 
 	// Si no es la moneda base, aplicar tipo de cambio
-	return t.Amount * t.ExchangeRate
+	return t.Amount.Mul(t.ExchangeRate, money.RoundHalfUp)
 }
 
 // IsFromNotification verifica si la transacción proviene de una notificación
@@ -300,7 +363,7 @@ type TransactionSummary struct {
 	ID               uint              `json:"id"`
 	Type             TransactionType   `json:"type"`
 	Status           TransactionStatus `json:"status"`
-	Amount           float64           `json:"amount"`
+	Amount           money.Amount      `json:"amount"`
 	Description      string            `json:"description"`
 	CategoryName     string            `json:"category_name"`
 	TransactionDate  time.Time         `json:"transaction_date"`
@@ -312,6 +375,7 @@ type TransactionSummary struct {
 	ValidationStatus ValidationStatus  `json:"validation_status"`
 	AIConfidence     float64           `json:"ai_confidence"`
 	NeedsReview      bool              `json:"needs_review"`
+	IsSplit          bool              `json:"is_split"` // Dividida entre varias categorías
 	CreatedAt        time.Time         `json:"created_at"`
 }
 
@@ -326,8 +390,8 @@ type TransactionFilter struct {
 	CategoryID       *uint              `json:"category_id"`
 	FromDate         *time.Time         `json:"from_date"`
 	ToDate           *time.Time         `json:"to_date"`
-	MinAmount        *float64           `json:"min_amount"`
-	MaxAmount        *float64           `json:"max_amount"`
+	MinAmount        *money.Amount      `json:"min_amount"`
+	MaxAmount        *money.Amount      `json:"max_amount"`
 	MinConfidence    *float64           `json:"min_confidence"`
 	MaxConfidence    *float64           `json:"max_confidence"`
 	NeedsReview      *bool              `json:"needs_review"`
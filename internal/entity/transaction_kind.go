@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/nick130920/fintech-backend/pkg/keywords"
+)
+
+// TransactionKind define la clase de movimiento que reporta una notificación bancaria
+type TransactionKind string
+
+const (
+	TransactionKindPurchase   TransactionKind = "purchase"   // Compra con tarjeta o pago a comercio
+	TransactionKindWithdrawal TransactionKind = "withdrawal" // Retiro de efectivo
+	TransactionKindRefund     TransactionKind = "refund"     // Devolución de un comercio
+	TransactionKindDeposit    TransactionKind = "deposit"    // Depósito, abono o transferencia recibida
+	TransactionKindTransfer   TransactionKind = "transfer"   // Transferencia enviada
+	TransactionKindReversal   TransactionKind = "reversal"   // Reverso o anulación de un movimiento anterior
+
+	TransactionKindAuthorization TransactionKind = "authorization" // Autorización o retención de tarjeta pendiente de confirmar
+)
+
+// TransactionKinds lista las clases de movimiento válidas
+var TransactionKinds = []TransactionKind{
+	TransactionKindPurchase,
+	TransactionKindWithdrawal,
+	TransactionKindRefund,
+	TransactionKindDeposit,
+	TransactionKindTransfer,
+	TransactionKindReversal,
+	TransactionKindAuthorization,
+}
+
+// transactionKindVocabulary asocia prefijos de palabras (sin acentos) con la clase de movimiento.
+// El orden importa: "reverso de compra" es un reverso y "devolución de compra" una devolución.
+var transactionKindVocabulary = []struct {
+	kind     TransactionKind
+	prefixes []string
+}{
+	{TransactionKindReversal, []string{"revers", "anulaci", "anulad", "contracargo", "chargeback", "void"}},
+	{TransactionKindRefund, []string{"devoluci", "devuelt", "reembols", "refund"}},
+	{TransactionKindAuthorization, []string{"preautoriz", "preauth", "retenci", "retenid"}},
+	{TransactionKindWithdrawal, []string{"retiro", "retirast", "withdraw", "cajero", "atm"}},
+	{TransactionKindTransfer, []string{"transfer", "spei", "envio", "enviast", "sent"}},
+	{TransactionKindDeposit, []string{"deposit", "abono", "abonad", "nomina", "recibist", "recibid", "ingreso"}},
+	{TransactionKindPurchase, []string{"compra", "pago", "pagast", "cargo", "consumo", "purchase", "payment"}},
+}
+
+// incomingTransferPrefixes identifican una transferencia recibida, que se registra como depósito
+var incomingTransferPrefixes = []string{"recibist", "recibid", "received", "incoming", "entrante"}
+
+// IsValid verifica si la clase de movimiento es conocida
+func (k TransactionKind) IsValid() bool {
+	for _, kind := range TransactionKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReversal verifica si el movimiento anula uno anterior en lugar de crear uno nuevo
+func (k TransactionKind) IsReversal() bool {
+	return k == TransactionKindReversal
+}
+
+// IsAuthorization verifica si el movimiento es una autorización que retiene fondos sin registrarse
+// en el libro hasta que el banco la confirma
+func (k TransactionKind) IsAuthorization() bool {
+	return k == TransactionKindAuthorization
+}
+
+// TransactionType retorna el tipo de transacción con el que se registra la clase de movimiento.
+// Los reversos no tienen tipo propio: toman el opuesto del movimiento que anulan.
+func (k TransactionKind) TransactionType() TransactionType {
+	switch k {
+	case TransactionKindRefund, TransactionKindDeposit:
+		return TransactionTypeIncome
+	case TransactionKindTransfer:
+		return TransactionTypeTransfer
+	default:
+		return TransactionTypeExpense
+	}
+}
+
+// IsSpending verifica si el movimiento puede registrarse como gasto del presupuesto
+func (k TransactionKind) IsSpending() bool {
+	return k == TransactionKindPurchase || k == TransactionKindWithdrawal
+}
+
+// ClassifyTransactionKind interpreta el tipo de movimiento extraído de una notificación
+// ("Compra", "Retiro en cajero", "Reverso de compra"...). Retorna false si no se reconoce.
+func ClassifyTransactionKind(text string) (TransactionKind, bool) {
+	normalized := strings.TrimSpace(strings.ToLower(text))
+	if kind := TransactionKind(normalized); kind.IsValid() {
+		return kind, true
+	}
+
+	words := strings.Fields(keywords.Normalize(text))
+	for _, entry := range transactionKindVocabulary {
+		if !hasWordPrefix(words, entry.prefixes) {
+			continue
+		}
+		if entry.kind == TransactionKindTransfer && hasWordPrefix(words, incomingTransferPrefixes) {
+			return TransactionKindDeposit, true
+		}
+		return entry.kind, true
+	}
+
+	return "", false
+}
+
+// hasWordPrefix verifica si alguna palabra empieza con alguno de los prefijos
+func hasWordPrefix(words, prefixes []string) bool {
+	for _, word := range words {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(word, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// TransactionSplit es una línea de una transacción dividida entre varias categorías (ej: un ticket
+// de supermercado con comida, hogar y farmacia). Las líneas de una transacción suman su monto.
+type TransactionSplit struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relaciones
+	TransactionID uint  `json:"transaction_id" gorm:"not null;index"`
+	CategoryID    *uint `json:"category_id" gorm:"index"`
+	AllocationID  *uint `json:"allocation_id" gorm:"index"` // Asignación del presupuesto a la que suma el gasto
+
+	// Información de la línea
+	CategoryName string       `json:"category_name" validate:"max=100"` // Desnormalizado para performance
+	Amount       money.Amount `json:"amount" gorm:"not null;type:decimal(15,2)" validate:"required,gt=0"`
+	Notes        string       `json:"notes" validate:"max=500"`
+}
+
+// SplitsTotal retorna la suma de las líneas de la transacción
+func (t *Transaction) SplitsTotal() money.Amount {
+	total := money.Zero
+	for _, split := range t.Splits {
+		total += split.Amount
+	}
+	return total
+}
+
+// IsSplit verifica si la transacción está dividida en líneas
+func (t *Transaction) IsSplit() bool {
+	return len(t.Splits) > 0
+}
+
+// SplitAllocationIDs retorna las asignaciones de presupuesto a las que suman las líneas, sin repetir
+func (t *Transaction) SplitAllocationIDs() []uint {
+	seen := make(map[uint]bool)
+	var ids []uint
+	for _, split := range t.Splits {
+		if split.AllocationID != nil && !seen[*split.AllocationID] {
+			seen[*split.AllocationID] = true
+			ids = append(ids, *split.AllocationID)
+		}
+	}
+	return ids
+}
+
+// TransactionCategoryTotal representa el total de una categoría en un período. Las transacciones
+// divididas suman a la categoría de cada línea.
+type TransactionCategoryTotal struct {
+	CategoryID   *uint           `json:"category_id"`
+	CategoryName string          `json:"category_name"`
+	Type         TransactionType `json:"type"`
+	Count        int64           `json:"count"`
+	Total        money.Amount    `json:"total"`
+}
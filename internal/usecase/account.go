@@ -6,19 +6,22 @@ import (
 	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // AccountUseCase contiene la lógica de negocio para cuentas
 type AccountUseCase struct {
 	accountRepo repo.AccountRepo
 	userRepo    repo.UserRepo
+	ledgerRepo  repo.LedgerRepo
 }
 
 // NewAccountUseCase crea una nueva instancia de AccountUseCase
-func NewAccountUseCase(accountRepo repo.AccountRepo, userRepo repo.UserRepo) *AccountUseCase {
+func NewAccountUseCase(accountRepo repo.AccountRepo, userRepo repo.UserRepo, ledgerRepo repo.LedgerRepo) *AccountUseCase {
 	return &AccountUseCase{
 		accountRepo: accountRepo,
 		userRepo:    userRepo,
+		ledgerRepo:  ledgerRepo,
 	}
 }
 
@@ -41,7 +44,7 @@ func (uc *AccountUseCase) Create(userID uint, req *dto.CreateAccountRequest) (*e
 		Description:    req.Description,
 		Type:           req.Type,
 		InitialBalance: req.InitialBalance,
-		Balance:        req.InitialBalance, // El balance inicial es igual al balance actual
+		Balance:        req.InitialBalance, // El repositorio registra el asiento de apertura por este saldo
 		CreditLimit:    req.CreditLimit,
 		BankName:       req.BankName,
 		AccountNumber:  req.AccountNumber,
@@ -111,6 +114,12 @@ func (uc *AccountUseCase) Update(userID, accountID uint, req *dto.UpdateAccountR
 		account.Description = req.Description
 	}
 	if req.Type != "" {
+		// El balance de una tarjeta de crédito es deuda: cambiar de o hacia crédito invertiría
+		// el sentido de las líneas ya registradas en el libro contable
+		isCredit := req.Type == entity.AccountTypeCredit
+		if isCredit != account.IsCredit() && account.Balance != 0 {
+			return nil, errors.New("cannot change credit account type with a non-zero balance")
+		}
 		account.Type = req.Type
 	}
 	if req.CreditLimit >= 0 {
@@ -193,7 +202,7 @@ func (uc *AccountUseCase) GetAccountsByType(userID uint, accountType entity.Acco
 }
 
 // GetTotalBalance obtiene el balance total de todas las cuentas activas
-func (uc *AccountUseCase) GetTotalBalance(userID uint) (float64, error) {
+func (uc *AccountUseCase) GetTotalBalance(userID uint) (money.Amount, error) {
 	// Verificar que el usuario existe
 	if _, err := uc.userRepo.GetByID(userID); err != nil {
 		return 0, errors.New("user not found")
@@ -203,7 +212,7 @@ func (uc *AccountUseCase) GetTotalBalance(userID uint) (float64, error) {
 }
 
 // TransferBalance transfiere dinero entre cuentas del mismo usuario
-func (uc *AccountUseCase) TransferBalance(userID uint, fromAccountID, toAccountID uint, amount float64) error {
+func (uc *AccountUseCase) TransferBalance(userID uint, fromAccountID, toAccountID uint, amount money.Amount) error {
 	// Obtener cuentas
 	fromAccount, err := uc.GetByID(userID, fromAccountID)
 	if err != nil {
@@ -220,31 +229,19 @@ func (uc *AccountUseCase) TransferBalance(userID uint, fromAccountID, toAccountI
 		return errors.New("both accounts must be active")
 	}
 
-	if amount <= 0 {
-		return errors.New("transfer amount must be positive")
-	}
-
-	if !fromAccount.CanDebit(amount) {
-		return errors.New("insufficient funds")
-	}
-
-	// Realizar transferencia
-	fromAccount.Debit(amount)
-	toAccount.Credit(amount)
-
-	// Guardar cambios
-	if err := uc.accountRepo.Update(fromAccount); err != nil {
-		return err
+	if fromAccount.ID == toAccount.ID {
+		return errors.New("cannot transfer to the same account")
 	}
 
-	if err := uc.accountRepo.Update(toAccount); err != nil {
-		// Revertir cambios en caso de error
-		fromAccount.Credit(amount)
-		uc.accountRepo.Update(fromAccount)
-		return err
+	if amount <= 0 {
+		return errors.New("transfer amount must be positive")
 	}
 
-	return nil
+	// Registrar el traspaso como un único asiento: los fondos se verifican y ambas cuentas cambian
+	// en la misma transacción de DB
+	entry := entity.NewTransferLedgerEntry(userID, fromAccount.ID, toAccount.ID, amount,
+		"Transferencia de "+fromAccount.Name+" a "+toAccount.Name)
+	return uc.ledgerRepo.PostTransfer(entry, fromAccount.ID, toAccount.ID, amount)
 }
 
 // validateAccountRules valida las reglas de negocio para cuentas
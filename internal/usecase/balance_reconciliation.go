@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// balanceReconciliationTolerance es la diferencia máxima entre el saldo reportado y el esperado
+// que se considera redondeo y no se registra como diferencia (un centavo)
+const balanceReconciliationTolerance = money.Amount(1)
+
+// Paginación por defecto de las diferencias de conciliación
+const (
+	defaultDiscrepancyPerPage = 20
+	maxDiscrepancyPerPage     = 100
+)
+
+// BalanceReconciliationUseCase concilia el saldo reportado por el banco en las notificaciones con
+// los movimientos registrados, y gestiona las diferencias encontradas
+type BalanceReconciliationUseCase struct {
+	discrepancyRepo repo.BalanceDiscrepancyRepo
+	bankAccountRepo repo.BankAccountRepo
+	transactionRepo repo.TransactionRepo
+	expenseRepo     repo.ExpenseRepo
+}
+
+// NewBalanceReconciliationUseCase crea una nueva instancia de BalanceReconciliationUseCase
+func NewBalanceReconciliationUseCase(
+	discrepancyRepo repo.BalanceDiscrepancyRepo,
+	bankAccountRepo repo.BankAccountRepo,
+	transactionRepo repo.TransactionRepo,
+	expenseRepo repo.ExpenseRepo,
+) *BalanceReconciliationUseCase {
+	return &BalanceReconciliationUseCase{
+		discrepancyRepo: discrepancyRepo,
+		bankAccountRepo: bankAccountRepo,
+		transactionRepo: transactionRepo,
+		expenseRepo:     expenseRepo,
+	}
+}
+
+// Reconcile compara el saldo reportado por una notificación con el último saldo conocido más los
+// movimientos registrados desde entonces, y guarda el saldo reportado como nuevo saldo de la cuenta.
+// Retorna la diferencia registrada, o nil si el saldo cuadra o la cuenta no tenía saldo anterior.
+// Un saldo reportado en otra moneda que la de la cuenta no se compara ni se guarda.
+func (uc *BalanceReconciliationUseCase) Reconcile(
+	bankAccount *entity.BankAccount,
+	reportedBalance money.Amount,
+	currency string,
+	fingerprint *entity.NotificationFingerprint,
+) (*entity.BalanceDiscrepancy, error) {
+	if currency != "" && !strings.EqualFold(currency, bankAccount.Currency) {
+		return nil, fmt.Errorf("reported balance currency %s does not match bank account currency %s", currency, bankAccount.Currency)
+	}
+
+	var discrepancy *entity.BalanceDiscrepancy
+
+	// Sin saldo anterior no hay con qué comparar: el reportado es el punto de partida
+	if !bankAccount.LastBalanceUpdate.IsZero() {
+		since := bankAccount.LastBalanceUpdate
+
+		transactionsNet, err := uc.transactionRepo.GetNetAmountByBankAccount(bankAccount.ID, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate bank account movements: %w", err)
+		}
+		expensesTotal, err := uc.expenseRepo.CalculateNotificationTotalByBankAccount(bankAccount.ID, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate bank account expenses: %w", err)
+		}
+
+		candidate := entity.NewBalanceDiscrepancy(bankAccount, transactionsNet-expensesTotal, reportedBalance)
+		if candidate.Exceeds(balanceReconciliationTolerance) {
+			if fingerprint != nil && fingerprint.ID != 0 {
+				candidate.NotificationID = &fingerprint.ID
+				candidate.TransactionID = fingerprint.TransactionID
+				candidate.ExpenseID = fingerprint.ExpenseID
+			}
+			if err := uc.discrepancyRepo.Create(candidate); err != nil {
+				return nil, err
+			}
+			discrepancy = candidate
+		}
+	}
+
+	if err := uc.bankAccountRepo.UpdateBalance(bankAccount.ID, reportedBalance); err != nil {
+		return discrepancy, fmt.Errorf("failed to update bank account balance: %w", err)
+	}
+	bankAccount.UpdateBalance(reportedBalance)
+
+	return discrepancy, nil
+}
+
+// ListDiscrepancies obtiene las diferencias de conciliación del usuario con filtros y paginación
+func (uc *BalanceReconciliationUseCase) ListDiscrepancies(userID uint, filter entity.BalanceDiscrepancyFilter, page, perPage int) (*dto.PaginatedBalanceDiscrepancyResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = defaultDiscrepancyPerPage
+	}
+	if perPage > maxDiscrepancyPerPage {
+		perPage = maxDiscrepancyPerPage
+	}
+	filter.Limit = perPage
+	filter.Offset = (page - 1) * perPage
+
+	discrepancies, total, err := uc.discrepancyRepo.GetWithFilters(userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance discrepancies: %w", err)
+	}
+
+	data := make([]*dto.BalanceDiscrepancyResponse, len(discrepancies))
+	for i, discrepancy := range discrepancies {
+		data[i] = uc.toDTO(discrepancy)
+	}
+
+	return &dto.PaginatedBalanceDiscrepancyResponse{
+		Data:       data,
+		Total:      int(total),
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: int((total + int64(perPage) - 1) / int64(perPage)),
+	}, nil
+}
+
+// GetDiscrepancy obtiene una diferencia de conciliación del usuario
+func (uc *BalanceReconciliationUseCase) GetDiscrepancy(userID, discrepancyID uint) (*dto.BalanceDiscrepancyResponse, error) {
+	discrepancy, err := uc.getDiscrepancy(userID, discrepancyID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.toDTO(discrepancy), nil
+}
+
+// ResolveDiscrepancy cierra una diferencia de conciliación pendiente
+func (uc *BalanceReconciliationUseCase) ResolveDiscrepancy(userID, discrepancyID uint, req *dto.ResolveBalanceDiscrepancyRequest) (*dto.BalanceDiscrepancyResponse, error) {
+	discrepancy, err := uc.getDiscrepancy(userID, discrepancyID)
+	if err != nil {
+		return nil, err
+	}
+	if !discrepancy.IsOpen() {
+		return nil, errors.New("balance discrepancy is already resolved")
+	}
+
+	discrepancy.Resolve(req.Status, req.Note)
+	if err := uc.discrepancyRepo.Update(discrepancy); err != nil {
+		return nil, err
+	}
+
+	return uc.toDTO(discrepancy), nil
+}
+
+// getDiscrepancy obtiene una diferencia verificando que pertenece al usuario
+func (uc *BalanceReconciliationUseCase) getDiscrepancy(userID, discrepancyID uint) (*entity.BalanceDiscrepancy, error) {
+	discrepancy, err := uc.discrepancyRepo.GetByID(discrepancyID)
+	if err != nil {
+		return nil, err
+	}
+	if discrepancy == nil || discrepancy.UserID != userID {
+		return nil, errors.New("balance discrepancy not found")
+	}
+	return discrepancy, nil
+}
+
+// toDTO convierte una diferencia de conciliación a DTO de respuesta
+func (uc *BalanceReconciliationUseCase) toDTO(discrepancy *entity.BalanceDiscrepancy) *dto.BalanceDiscrepancyResponse {
+	return &dto.BalanceDiscrepancyResponse{
+		ID:                discrepancy.ID,
+		BankAccountID:     discrepancy.BankAccountID,
+		NotificationID:    discrepancy.NotificationID,
+		TransactionID:     discrepancy.TransactionID,
+		ExpenseID:         discrepancy.ExpenseID,
+		PreviousBalance:   discrepancy.PreviousBalance,
+		PreviousBalanceAt: discrepancy.PreviousBalanceAt,
+		MovementsTotal:    discrepancy.MovementsTotal,
+		ExpectedBalance:   discrepancy.ExpectedBalance,
+		ReportedBalance:   discrepancy.ReportedBalance,
+		Difference:        discrepancy.Difference,
+		Currency:          discrepancy.Currency,
+		Status:            discrepancy.Status,
+		ResolutionNote:    discrepancy.ResolutionNote,
+		ResolvedAt:        discrepancy.ResolvedAt,
+		CreatedAt:         discrepancy.CreatedAt,
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // BankAccountUseCase contiene la lógica de negocio para cuentas bancarias
@@ -230,8 +231,9 @@ func (uc *BankAccountUseCase) SetBankAccountActive(userID, bankAccountID uint, a
 	return nil
 }
 
-// UpdateBankAccountBalance actualiza el balance de una cuenta bancaria
-func (uc *BankAccountUseCase) UpdateBankAccountBalance(userID, bankAccountID uint, balance float64) error {
+// UpdateBankAccountBalance actualiza manualmente el balance de una cuenta bancaria. El nuevo
+// balance pasa a ser el punto de partida de la conciliación con las notificaciones siguientes.
+func (uc *BankAccountUseCase) UpdateBankAccountBalance(userID, bankAccountID uint, balance money.Amount) error {
 	// Verificar que la cuenta existe y pertenece al usuario
 	bankAccount, err := uc.bankAccountRepo.GetByID(bankAccountID)
 	if err != nil {
@@ -3,19 +3,36 @@ package usecase
 import (
 	"errors"
 	"fmt"
-	"regexp"
+	"log"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/extractor"
+	"github.com/nick130920/fintech-backend/pkg/keywords"
+	"github.com/nick130920/fintech-backend/pkg/patterncache"
 )
 
 // BankNotificationPatternUseCase contiene la lógica de negocio para patrones de notificación bancaria
 type BankNotificationPatternUseCase struct {
-	patternRepo     repo.BankNotificationPatternRepo
-	bankAccountRepo repo.BankAccountRepo
-	userRepo        repo.UserRepo
+	patternRepo      repo.BankNotificationPatternRepo
+	bankAccountRepo  repo.BankAccountRepo
+	userRepo         repo.UserRepo
+	accountRepo      repo.AccountRepo
+	transactionRepo  repo.TransactionRepo
+	transactionUC    *TransactionUseCase
+	expenseRepo      repo.ExpenseRepo
+	budgetRepo       repo.BudgetRepo
+	categoryRepo     repo.CategoryRepo
+	patternCache     *patterncache.Cache
+	patternPolicyUC  *PatternPolicyUseCase
+	dedupUC          *NotificationDedupUseCase
+	inboxRepo        repo.NotificationInboxRepo
+	reconciliationUC *BalanceReconciliationUseCase
+	evaluationBudget time.Duration // Tiempo máximo para evaluar los patrones contra un mensaje
 }
 
 // NewBankNotificationPatternUseCase crea una nueva instancia de BankNotificationPatternUseCase
@@ -23,11 +40,35 @@ func NewBankNotificationPatternUseCase(
 	patternRepo repo.BankNotificationPatternRepo,
 	bankAccountRepo repo.BankAccountRepo,
 	userRepo repo.UserRepo,
+	accountRepo repo.AccountRepo,
+	transactionRepo repo.TransactionRepo,
+	transactionUC *TransactionUseCase,
+	expenseRepo repo.ExpenseRepo,
+	budgetRepo repo.BudgetRepo,
+	categoryRepo repo.CategoryRepo,
+	patternCache *patterncache.Cache,
+	patternPolicyUC *PatternPolicyUseCase,
+	dedupUC *NotificationDedupUseCase,
+	inboxRepo repo.NotificationInboxRepo,
+	reconciliationUC *BalanceReconciliationUseCase,
+	evaluationBudget time.Duration,
 ) *BankNotificationPatternUseCase {
 	return &BankNotificationPatternUseCase{
-		patternRepo:     patternRepo,
-		bankAccountRepo: bankAccountRepo,
-		userRepo:        userRepo,
+		patternRepo:      patternRepo,
+		bankAccountRepo:  bankAccountRepo,
+		userRepo:         userRepo,
+		accountRepo:      accountRepo,
+		transactionRepo:  transactionRepo,
+		transactionUC:    transactionUC,
+		expenseRepo:      expenseRepo,
+		budgetRepo:       budgetRepo,
+		categoryRepo:     categoryRepo,
+		patternCache:     patternCache,
+		patternPolicyUC:  patternPolicyUC,
+		dedupUC:          dedupUC,
+		inboxRepo:        inboxRepo,
+		reconciliationUC: reconciliationUC,
+		evaluationBudget: evaluationBudget,
 	}
 }
 
@@ -51,46 +92,9 @@ func (uc *BankNotificationPatternUseCase) CreatePattern(userID uint, req *dto.Cr
 	}
 
 	// Crear la entidad patrón
-	pattern := &entity.BankNotificationPattern{
-		UserID:              userID,
-		BankAccountID:       req.BankAccountID,
-		Name:                req.Name,
-		Description:         req.Description,
-		Channel:             req.Channel,
-		Status:              entity.NotificationPatternStatusActive,
-		MessagePattern:      req.MessagePattern,
-		ExampleMessage:      req.ExampleMessage,
-		AmountRegex:         req.AmountRegex,
-		DateRegex:           req.DateRegex,
-		DescriptionRegex:    req.DescriptionRegex,
-		MerchantRegex:       req.MerchantRegex,
-		RequiresValidation:  req.RequiresValidation,
-		ConfidenceThreshold: req.ConfidenceThreshold,
-		AutoApprove:         req.AutoApprove,
-		Priority:            req.Priority,
-		IsDefault:           req.IsDefault,
-	}
-
-	// Establecer palabras clave
-	if len(req.KeywordsTrigger) > 0 {
-		if err := pattern.SetKeywordsTrigger(req.KeywordsTrigger); err != nil {
-			return nil, fmt.Errorf("failed to set trigger keywords: %w", err)
-		}
-	}
-	if len(req.KeywordsExclude) > 0 {
-		if err := pattern.SetKeywordsExclude(req.KeywordsExclude); err != nil {
-			return nil, fmt.Errorf("failed to set exclude keywords: %w", err)
-		}
-	}
-	if len(req.Tags) > 0 {
-		if err := pattern.SetTags(req.Tags); err != nil {
-			return nil, fmt.Errorf("failed to set tags: %w", err)
-		}
-	}
-	if req.Metadata != nil {
-		if err := pattern.SetMetadata(req.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to set metadata: %w", err)
-		}
+	pattern, err := uc.buildPattern(userID, req)
+	if err != nil {
+		return nil, err
 	}
 
 	// Si se está marcando como por defecto, desactivar otros patrones por defecto
@@ -104,6 +108,7 @@ func (uc *BankNotificationPatternUseCase) CreatePattern(userID uint, req *dto.Cr
 	if err := uc.patternRepo.Create(pattern); err != nil {
 		return nil, fmt.Errorf("failed to create pattern: %w", err)
 	}
+	uc.patternCache.InvalidateBankAccount(pattern.BankAccountID)
 
 	response := uc.toDTO(pattern)
 	return response, nil
@@ -186,6 +191,7 @@ func (uc *BankNotificationPatternUseCase) UpdatePattern(userID, patternID uint,
 	if pattern.UserID != userID {
 		return nil, errors.New("unauthorized access to pattern")
 	}
+	before := *pattern
 
 	// Actualizar campos
 	if req.Name != nil && *req.Name != "" {
@@ -195,6 +201,9 @@ func (uc *BankNotificationPatternUseCase) UpdatePattern(userID, patternID uint,
 		pattern.Description = *req.Description
 	}
 	if req.MessagePattern != nil && *req.MessagePattern != "" {
+		if _, err := extractor.Compile(*req.MessagePattern); err != nil {
+			return nil, fmt.Errorf("invalid message pattern: %w", err)
+		}
 		pattern.MessagePattern = *req.MessagePattern
 	}
 	if req.ExampleMessage != nil && *req.ExampleMessage != "" {
@@ -202,29 +211,38 @@ func (uc *BankNotificationPatternUseCase) UpdatePattern(userID, patternID uint,
 	}
 	if req.AmountRegex != nil && *req.AmountRegex != "" {
 		// Validar regex
-		if _, err := regexp.Compile(*req.AmountRegex); err != nil {
+		if _, err := extractor.CompileField(*req.AmountRegex); err != nil {
 			return nil, fmt.Errorf("invalid amount regex: %w", err)
 		}
 		pattern.AmountRegex = *req.AmountRegex
 	}
 	if req.DateRegex != nil && *req.DateRegex != "" {
-		if _, err := regexp.Compile(*req.DateRegex); err != nil {
+		if _, err := extractor.CompileField(*req.DateRegex); err != nil {
 			return nil, fmt.Errorf("invalid date regex: %w", err)
 		}
 		pattern.DateRegex = *req.DateRegex
 	}
 	if req.DescriptionRegex != nil && *req.DescriptionRegex != "" {
-		if _, err := regexp.Compile(*req.DescriptionRegex); err != nil {
+		if _, err := extractor.CompileField(*req.DescriptionRegex); err != nil {
 			return nil, fmt.Errorf("invalid description regex: %w", err)
 		}
 		pattern.DescriptionRegex = *req.DescriptionRegex
 	}
 	if req.MerchantRegex != nil && *req.MerchantRegex != "" {
-		if _, err := regexp.Compile(*req.MerchantRegex); err != nil {
+		if _, err := extractor.CompileField(*req.MerchantRegex); err != nil {
 			return nil, fmt.Errorf("invalid merchant regex: %w", err)
 		}
 		pattern.MerchantRegex = *req.MerchantRegex
 	}
+	if req.BalanceRegex != nil && *req.BalanceRegex != "" {
+		if _, err := extractor.CompileField(*req.BalanceRegex); err != nil {
+			return nil, fmt.Errorf("invalid balance regex: %w", err)
+		}
+		pattern.BalanceRegex = *req.BalanceRegex
+	}
+	if req.TransactionKind != nil {
+		pattern.TransactionKind = *req.TransactionKind
+	}
 	if req.RequiresValidation != nil {
 		pattern.RequiresValidation = *req.RequiresValidation
 	}
@@ -248,6 +266,9 @@ func (uc *BankNotificationPatternUseCase) UpdatePattern(userID, patternID uint,
 	}
 
 	// Actualizar palabras clave si se proporcionan
+	if err := validateKeywordExpressions(req.KeywordsTrigger, req.KeywordsExclude); err != nil {
+		return nil, err
+	}
 	if req.KeywordsTrigger != nil {
 		if err := pattern.SetKeywordsTrigger(req.KeywordsTrigger); err != nil {
 			return nil, fmt.Errorf("failed to set trigger keywords: %w", err)
@@ -269,10 +290,18 @@ func (uc *BankNotificationPatternUseCase) UpdatePattern(userID, patternID uint,
 		}
 	}
 
+	// Los cambios a un patrón suscrito se conservan al recibir nuevas versiones de la plantilla
+	if pattern.IsSubscribed() {
+		if err := pattern.MarkOverrides(&before); err != nil {
+			return nil, fmt.Errorf("failed to set overrides: %w", err)
+		}
+	}
+
 	// Guardar cambios
 	if err := uc.patternRepo.Update(pattern); err != nil {
 		return nil, fmt.Errorf("failed to update pattern: %w", err)
 	}
+	uc.patternCache.InvalidateBankAccount(pattern.BankAccountID)
 
 	response := uc.toDTO(pattern)
 	return response, nil
@@ -296,6 +325,7 @@ func (uc *BankNotificationPatternUseCase) DeletePattern(userID, patternID uint)
 	if err := uc.patternRepo.Delete(patternID); err != nil {
 		return fmt.Errorf("failed to delete pattern: %w", err)
 	}
+	uc.patternCache.InvalidateBankAccount(pattern.BankAccountID)
 
 	return nil
 }
@@ -314,50 +344,114 @@ func (uc *BankNotificationPatternUseCase) SetPatternStatus(userID, patternID uin
 		return errors.New("unauthorized access to pattern")
 	}
 
-	// Cambiar el estado
-	if err := uc.patternRepo.SetStatus(patternID, status); err != nil {
+	// Cambiar el estado y registrarlo en el historial del patrón
+	if err := uc.patternPolicyUC.RecordManualTransition(pattern, status); err != nil {
 		return fmt.Errorf("failed to set pattern status: %w", err)
 	}
 
 	return nil
 }
 
-// ProcessNotification procesa una notificación bancaria usando patrones
-func (uc *BankNotificationPatternUseCase) ProcessNotification(userID, bankAccountID uint, channel entity.NotificationChannel, message string) (*dto.ProcessedNotificationResponse, error) {
-	// Verificar que la cuenta bancaria pertenece al usuario
-	bankAccount, err := uc.bankAccountRepo.GetByID(bankAccountID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get bank account: %w", err)
-	}
-	if bankAccount == nil || bankAccount.UserID != userID {
-		return nil, errors.New("unauthorized access to bank account")
-	}
+// ProcessNotification procesa una notificación bancaria usando patrones.
+// En modo preview solo extrae datos; en modos transaction y expense además
+// persiste el movimiento resultante. Toda notificación se guarda en la bandeja
+// con el resultado del procesamiento, incluso si falla. Si no se indica la
+// cuenta bancaria, se identifica a partir del mensaje.
+func (uc *BankNotificationPatternUseCase) ProcessNotification(userID uint, req *dto.ProcessNotificationRequest) (*dto.ProcessedNotificationResponse, error) {
+	var bankAccount *entity.BankAccount
+	var route string
+	var err error
 
-	// Obtener patrones que podrían coincidir
-	matchingPatterns, err := uc.patternRepo.GetMatchingPatterns(bankAccountID, channel, message)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get matching patterns: %w", err)
+	// Los mensajes muy largos no son notificaciones bancarias y encarecen la evaluación de patrones
+	if utf8.RuneCountInString(req.Message) > extractor.MaxMessageLength {
+		return nil, fmt.Errorf("notification message exceeds %d characters", extractor.MaxMessageLength)
 	}
 
-	// Si no hay patrones coincidentes, usar el patrón por defecto
-	if len(matchingPatterns) == 0 {
-		defaultPattern, err := uc.patternRepo.GetDefaultPattern(bankAccountID, channel)
+	if req.BankAccountID != 0 {
+		// Verificar que la cuenta bancaria pertenece al usuario
+		bankAccount, err = uc.bankAccountRepo.GetByID(req.BankAccountID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get default pattern: %w", err)
+			return nil, fmt.Errorf("failed to get bank account: %w", err)
 		}
-		if defaultPattern != nil {
-			matchingPatterns = []*entity.BankNotificationPattern{defaultPattern}
+		if bankAccount == nil || bankAccount.UserID != userID {
+			return nil, errors.New("unauthorized access to bank account")
 		}
 	}
 
-	// Procesar con el primer patrón coincidente (mayor prioridad)
+	// Guardar la notificación antes de enrutarla y procesarla para no perderla si algo falla
+	item := newInboxItem(userID, req)
+	if err := uc.inboxRepo.Create(item); err != nil {
+		return nil, fmt.Errorf("failed to store notification: %w", err)
+	}
+
+	if bankAccount == nil {
+		if bankAccount, route, err = uc.routeNotification(userID, req); err != nil {
+			// La notificación queda en la bandeja para que el usuario le asigne la cuenta
+			applyRoutingError(item, err)
+			if updateErr := uc.inboxRepo.Update(item); updateErr != nil {
+				log.Printf("Warning: Failed to update notification inbox item %d: %v", item.ID, updateErr)
+			}
+			return nil, err
+		}
+		req.BankAccountID = bankAccount.ID
+		item.BankAccountID = &bankAccount.ID
+	}
+
+	response, processErr := uc.processNotification(userID, bankAccount, req, true)
+	if response != nil {
+		response.RoutedBy = route
+	}
+
+	applyInboxResult(item, response, processErr)
+	if err := uc.inboxRepo.Update(item); err != nil {
+		log.Printf("Warning: Failed to update notification inbox item %d: %v", item.ID, err)
+	}
+
+	if processErr != nil {
+		return nil, processErr
+	}
+
+	response.InboxItemID = &item.ID
+	return response, nil
+}
+
+// processNotification aplica los patrones a la notificación y, según el modo, crea el movimiento.
+// Salvo errores internos, retorna la respuesta parcial junto con el error de procesamiento
+// para que quede registrada en la bandeja. Con enforceSettings, las notificaciones que no
+// cumplen la configuración de la cuenta se ignoran o quedan en espera sin crear movimiento;
+// la aprobación del usuario desde la bandeja la omite.
+func (uc *BankNotificationPatternUseCase) processNotification(
+	userID uint,
+	bankAccount *entity.BankAccount,
+	req *dto.ProcessNotificationRequest,
+	enforceSettings bool,
+) (*dto.ProcessedNotificationResponse, error) {
+	bankAccountID := bankAccount.ID
+	channel := req.Channel
+	message := req.Message
+
+	mode := req.Mode
+	if mode == "" {
+		mode = entity.NotificationIngestionModePreview
+	}
+
+	// Obtener patrones compilados de la cuenta y canal (desde caché si es posible)
+	patternSet, err := uc.getPatternSet(bankAccountID, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Evaluar todos los patrones candidatos; gana el de mayor puntaje
+	ranked := uc.rankPatterns(patternSet, message)
+
 	var bestPattern *entity.BankNotificationPattern
 	var confidence float64
 	var extractedData map[string]interface{}
 
-	if len(matchingPatterns) > 0 {
-		bestPattern = matchingPatterns[0]
-		extractedData, confidence = uc.extractDataFromMessage(bestPattern, message)
+	if len(ranked) > 0 {
+		bestPattern = ranked[0].compiled.Pattern
+		extractedData = ranked[0].extractedData
+		confidence = ranked[0].confidence
 	}
 
 	response := &dto.ProcessedNotificationResponse{
@@ -367,17 +461,114 @@ func (uc *BankNotificationPatternUseCase) ProcessNotification(userID, bankAccoun
 		Processed:     bestPattern != nil,
 		Confidence:    confidence,
 		ExtractedData: extractedData,
+		Candidates:    uc.toCandidateDTOs(ranked),
+		Mode:          mode,
 	}
 
+	// Normalizar monto y fecha con las convenciones de la cuenta y del usuario
+	var movement *notificationMovement
+	var movementErr error
+
 	if bestPattern != nil {
 		response.PatternID = &bestPattern.ID
 		response.PatternName = bestPattern.Name
-		response.RequiresValidation = bestPattern.RequiresValidation || confidence < bestPattern.ConfidenceThreshold
+		response.RequiresValidation = bestPattern.NeedsValidation(confidence)
+
+		format := uc.notificationFormat(userID, bankAccount)
+		movement, movementErr = uc.buildNotificationMovement(bestPattern, extractedData, confidence, format)
+		if movementErr == nil {
+			response.Amount = movement.Amount
+			response.Currency = movement.Currency
+			response.TransactionDate = &movement.Date
+			response.TransactionKind = movement.Kind
+			response.ReportedBalance = movement.Balance
+			if !movement.Kind.IsReversal() {
+				response.TransactionType = movement.Kind.TransactionType()
+			}
+		}
+	}
+
+	// Verificar la configuración de notificaciones de la cuenta: estado, remitente y monto mínimo
+	reason := entity.NotificationReasonAccepted
+	if enforceSettings {
+		if req.SkipSenderCheck {
+			reason = bankAccount.CheckNotificationStatus()
+		} else {
+			reason = bankAccount.CheckNotification(channel, req.Sender)
+		}
+		if reason.Decision() == entity.NotificationDecisionAccepted && movement != nil && !bankAccount.ShouldNotifyAmount(movement.Amount) {
+			reason = entity.NotificationReasonBelowMinAmount
+		}
+	}
+	response.Decision = reason.Decision()
+	response.ReasonCode = reason
+
+	if mode == entity.NotificationIngestionModePreview {
+		// Informar si la notificación repite un evento ya recibido, sin registrarla
+		if movementErr == nil && movement != nil {
+			fingerprint := uc.dedupUC.NewFingerprint(userID, bankAccount, channel, movement, notificationReceivedAt(req))
+			if duplicate, err := uc.dedupUC.FindDuplicate(fingerprint, mode); err == nil && duplicate != nil {
+				applyDuplicate(response, duplicate)
+			}
+		}
+		return response, nil
+	}
+
+	if response.Decision != entity.NotificationDecisionAccepted {
+		return response, nil
+	}
+
+	if bestPattern == nil {
+		return response, errors.New("no matching pattern for notification")
+	}
+	if movementErr != nil {
+		uc.recordPatternMatch(bestPattern.ID, false)
+		return response, movementErr
+	}
+
+	// El mismo evento puede llegar a la vez por varios canales: procesar una
+	// notificación por cuenta bancaria a la vez y enlazar las repetidas
+	unlock := uc.dedupUC.Lock(bankAccountID)
+	defer unlock()
+
+	fingerprint := uc.dedupUC.NewFingerprint(userID, bankAccount, channel, movement, notificationReceivedAt(req))
+	duplicate, err := uc.dedupUC.FindDuplicate(fingerprint, mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check duplicate notification: %w", err)
+	}
+	if duplicate != nil {
+		// No cuenta como nueva coincidencia del patrón: el evento ya se registró
+		fingerprint.DuplicateOfID = duplicate.OriginalID
+		fingerprint.TransactionID = duplicate.TransactionID
+		fingerprint.ExpenseID = duplicate.ExpenseID
+		uc.recordFingerprint(fingerprint, response)
+		applyDuplicate(response, duplicate)
+		return response, nil
 	}
 
+	if err := uc.ingestNotification(userID, bankAccount, bestPattern, req, movement, response); err != nil {
+		uc.recordPatternMatch(bestPattern.ID, false)
+		return response, err
+	}
+
+	fingerprint.TransactionID = response.TransactionID
+	fingerprint.ExpenseID = response.ExpenseID
+	uc.recordFingerprint(fingerprint, response)
+	uc.reconcileBalance(bankAccount, movement, fingerprint, response)
+
+	// Los movimientos auto-aprobados cuentan como éxito; los pendientes se
+	// resuelven cuando el usuario confirma o rechaza el movimiento
+	uc.recordPatternMatch(bestPattern.ID, movement.ValidationStatus == entity.ValidationStatusAuto)
+
 	return response, nil
 }
 
+// recordPatternMatch registra una coincidencia en las estadísticas del patrón.
+// Las vistas previas no se registran para no contar dos veces la misma notificación.
+func (uc *BankNotificationPatternUseCase) recordPatternMatch(patternID uint, success bool) {
+	uc.patternPolicyUC.RecordMatch(patternID, success)
+}
+
 // GetPatternStatistics obtiene estadísticas de patrones de un usuario
 func (uc *BankNotificationPatternUseCase) GetPatternStatistics(userID uint) (*dto.PatternStatisticsResponse, error) {
 	patterns, err := uc.patternRepo.GetByUserID(userID)
@@ -402,6 +593,7 @@ func (uc *BankNotificationPatternUseCase) GetPatternStatistics(userID uint) (*dt
 		}
 		stats.TotalMatches += pattern.MatchCount
 		stats.TotalSuccesses += pattern.SuccessCount
+		stats.TotalFailures += pattern.FailureCount
 	}
 
 	if stats.TotalMatches > 0 {
@@ -411,28 +603,105 @@ func (uc *BankNotificationPatternUseCase) GetPatternStatistics(userID uint) (*dt
 	return stats, nil
 }
 
-// validateRegexPatterns valida los patrones regex
+// buildPattern crea la entidad patrón a partir de la petición, sin guardarla
+func (uc *BankNotificationPatternUseCase) buildPattern(userID uint, req *dto.CreateBankNotificationPatternRequest) (*entity.BankNotificationPattern, error) {
+	pattern := &entity.BankNotificationPattern{
+		UserID:              userID,
+		BankAccountID:       req.BankAccountID,
+		Name:                req.Name,
+		Description:         req.Description,
+		Channel:             req.Channel,
+		Status:              entity.NotificationPatternStatusActive,
+		MessagePattern:      req.MessagePattern,
+		ExampleMessage:      req.ExampleMessage,
+		AmountRegex:         req.AmountRegex,
+		DateRegex:           req.DateRegex,
+		DescriptionRegex:    req.DescriptionRegex,
+		MerchantRegex:       req.MerchantRegex,
+		BalanceRegex:        req.BalanceRegex,
+		TransactionKind:     req.TransactionKind,
+		RequiresValidation:  req.RequiresValidation,
+		ConfidenceThreshold: req.ConfidenceThreshold,
+		AutoApprove:         req.AutoApprove,
+		Priority:            req.Priority,
+		IsDefault:           req.IsDefault,
+	}
+
+	// Establecer palabras clave
+	if len(req.KeywordsTrigger) > 0 {
+		if err := pattern.SetKeywordsTrigger(req.KeywordsTrigger); err != nil {
+			return nil, fmt.Errorf("failed to set trigger keywords: %w", err)
+		}
+	}
+	if len(req.KeywordsExclude) > 0 {
+		if err := pattern.SetKeywordsExclude(req.KeywordsExclude); err != nil {
+			return nil, fmt.Errorf("failed to set exclude keywords: %w", err)
+		}
+	}
+	if len(req.Tags) > 0 {
+		if err := pattern.SetTags(req.Tags); err != nil {
+			return nil, fmt.Errorf("failed to set tags: %w", err)
+		}
+	}
+	if req.Metadata != nil {
+		if err := pattern.SetMetadata(req.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to set metadata: %w", err)
+		}
+	}
+
+	return pattern, nil
+}
+
+// validateRegexPatterns valida los patrones regex y las expresiones de palabras clave
 func (uc *BankNotificationPatternUseCase) validateRegexPatterns(req *dto.CreateBankNotificationPatternRequest) error {
+	if err := validateKeywordExpressions(req.KeywordsTrigger, req.KeywordsExclude); err != nil {
+		return err
+	}
+	if req.MessagePattern != "" {
+		if _, err := extractor.Compile(req.MessagePattern); err != nil {
+			return fmt.Errorf("invalid message pattern: %w", err)
+		}
+	}
 	if req.AmountRegex != "" {
-		if _, err := regexp.Compile(req.AmountRegex); err != nil {
+		if _, err := extractor.CompileField(req.AmountRegex); err != nil {
 			return fmt.Errorf("invalid amount regex: %w", err)
 		}
 	}
 	if req.DateRegex != "" {
-		if _, err := regexp.Compile(req.DateRegex); err != nil {
+		if _, err := extractor.CompileField(req.DateRegex); err != nil {
 			return fmt.Errorf("invalid date regex: %w", err)
 		}
 	}
 	if req.DescriptionRegex != "" {
-		if _, err := regexp.Compile(req.DescriptionRegex); err != nil {
+		if _, err := extractor.CompileField(req.DescriptionRegex); err != nil {
 			return fmt.Errorf("invalid description regex: %w", err)
 		}
 	}
 	if req.MerchantRegex != "" {
-		if _, err := regexp.Compile(req.MerchantRegex); err != nil {
+		if _, err := extractor.CompileField(req.MerchantRegex); err != nil {
 			return fmt.Errorf("invalid merchant regex: %w", err)
 		}
 	}
+	if req.BalanceRegex != "" {
+		if _, err := extractor.CompileField(req.BalanceRegex); err != nil {
+			return fmt.Errorf("invalid balance regex: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateKeywordExpressions verifica que las palabras clave sean expresiones válidas
+func validateKeywordExpressions(lists ...[]string) error {
+	for _, list := range lists {
+		if len(list) > keywords.MaxExpressions {
+			return fmt.Errorf("invalid keyword expressions: more than %d per list", keywords.MaxExpressions)
+		}
+		for _, keyword := range list {
+			if _, err := keywords.Compile(keyword); err != nil {
+				return fmt.Errorf("invalid keyword expression %q: %w", keyword, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -454,65 +723,116 @@ func (uc *BankNotificationPatternUseCase) unsetOtherDefaultPatterns(bankAccountI
 	return nil
 }
 
-// extractDataFromMessage extrae datos de un mensaje usando un patrón
-func (uc *BankNotificationPatternUseCase) extractDataFromMessage(pattern *entity.BankNotificationPattern, message string) (map[string]interface{}, float64) {
+// getPatternSet obtiene los patrones activos y en aprendizaje compilados de una cuenta y canal.
+// En caso de no estar en caché se cargan desde la base de datos y se compilan una sola vez.
+func (uc *BankNotificationPatternUseCase) getPatternSet(bankAccountID uint, channel entity.NotificationChannel) (*patterncache.PatternSet, error) {
+	key := patterncache.Key{BankAccountID: bankAccountID, Channel: channel}
+	if set, found := uc.patternCache.Get(key); found {
+		return set, nil
+	}
+
+	patterns, err := uc.patternRepo.GetCandidatePatterns(bankAccountID, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matching patterns: %w", err)
+	}
+
+	defaultPattern, err := uc.patternRepo.GetDefaultPattern(bankAccountID, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default pattern: %w", err)
+	}
+
+	set := &patterncache.PatternSet{
+		Patterns: make([]*patterncache.CompiledPattern, len(patterns)),
+	}
+	for i, pattern := range patterns {
+		set.Patterns[i] = patterncache.Compile(pattern)
+	}
+	if defaultPattern != nil {
+		set.Default = patterncache.Compile(defaultPattern)
+	}
+
+	uc.patternCache.Put(key, set)
+	return set, nil
+}
+
+// GetPatternCacheStats obtiene las estadísticas de la caché de patrones compilados
+func (uc *BankNotificationPatternUseCase) GetPatternCacheStats() *dto.PatternCacheStatsResponse {
+	stats := uc.patternCache.Stats()
+	return &dto.PatternCacheStatsResponse{
+		Hits:          stats.Hits,
+		Misses:        stats.Misses,
+		Evictions:     stats.Evictions,
+		Invalidations: stats.Invalidations,
+		Entries:       stats.Entries,
+		Capacity:      stats.Capacity,
+		HitRate:       stats.HitRate,
+	}
+}
+
+// Origen de un campo extraído
+const (
+	captureSourceMessagePattern = "message_pattern" // Grupo nombrado del MessagePattern
+	captureSourceFieldRegex     = "field_regex"     // Regex individual del campo
+)
+
+// fieldCapture es el valor extraído de un campo y la regex que lo capturó
+type fieldCapture struct {
+	value  string
+	source string
+}
+
+// extractDataFromMessage extrae datos de un mensaje usando un patrón compilado
+// y calcula la confianza según los campos esperados que se extrajeron
+func (uc *BankNotificationPatternUseCase) extractDataFromMessage(compiled *patterncache.CompiledPattern, message string) (map[string]interface{}, float64) {
+	captures, expectedFields := uc.captureFields(compiled, message)
+
 	extractedData := make(map[string]interface{})
-	confidence := 0.0
-	matches := 0
-	totalAttempts := 0
-
-	// Extraer monto
-	if pattern.AmountRegex != "" {
-		totalAttempts++
-		if re, err := regexp.Compile(pattern.AmountRegex); err == nil {
-			if match := re.FindStringSubmatch(message); len(match) > 1 {
-				extractedData["amount"] = strings.TrimSpace(match[1])
-				matches++
-			}
-		}
+	for field, capture := range captures {
+		extractedData[field] = capture.value
 	}
 
-	// Extraer fecha
-	if pattern.DateRegex != "" {
-		totalAttempts++
-		if re, err := regexp.Compile(pattern.DateRegex); err == nil {
-			if match := re.FindStringSubmatch(message); len(match) > 1 {
-				extractedData["date"] = strings.TrimSpace(match[1])
-				matches++
-			}
-		}
+	return extractedData, captureConfidence(captures, expectedFields)
+}
+
+// captureConfidence calcula la confianza basada en los campos esperados que se extrajeron
+func captureConfidence(captures map[string]fieldCapture, expectedFields map[string]bool) float64 {
+	if len(expectedFields) == 0 {
+		return 0.5 // Confianza base si no hay regex definidos
 	}
+	return float64(len(captures)) / float64(len(expectedFields))
+}
 
-	// Extraer descripción
-	if pattern.DescriptionRegex != "" {
-		totalAttempts++
-		if re, err := regexp.Compile(pattern.DescriptionRegex); err == nil {
-			if match := re.FindStringSubmatch(message); len(match) > 1 {
-				extractedData["description"] = strings.TrimSpace(match[1])
-				matches++
-			}
+// captureFields aplica primero el MessagePattern (regex con grupos nombrados o template)
+// y luego completa los campos faltantes con las regex individuales por campo.
+// Retorna los campos capturados y el conjunto de campos esperados.
+func (uc *BankNotificationPatternUseCase) captureFields(compiled *patterncache.CompiledPattern, message string) (map[string]fieldCapture, map[string]bool) {
+	captures := make(map[string]fieldCapture)
+	expectedFields := make(map[string]bool)
+
+	// Extraer todos los campos del patrón de mensaje en una sola pasada
+	if compiled.Message != nil {
+		for _, name := range extractor.FieldNames(compiled.Message) {
+			expectedFields[name] = true
+		}
+		for name, value := range extractor.Extract(compiled.Message, message) {
+			captures[name] = fieldCapture{value: value, source: captureSourceMessagePattern}
 		}
 	}
 
-	// Extraer comercio
-	if pattern.MerchantRegex != "" {
-		totalAttempts++
-		if re, err := regexp.Compile(pattern.MerchantRegex); err == nil {
-			if match := re.FindStringSubmatch(message); len(match) > 1 {
-				extractedData["merchant"] = strings.TrimSpace(match[1])
-				matches++
+	// Regex individuales por campo (compatibilidad con patrones existentes)
+	for field, re := range compiled.FieldRegexes {
+		expectedFields[field] = true
+		if _, found := captures[field]; found {
+			continue
+		}
+		if match := re.FindStringSubmatch(message); len(match) > 1 {
+			if value := strings.TrimSpace(match[1]); value != "" {
+				captures[field] = fieldCapture{value: value, source: captureSourceFieldRegex}
 			}
 		}
 	}
 
-	// Calcular confianza basada en coincidencias
-	if totalAttempts > 0 {
-		confidence = float64(matches) / float64(totalAttempts)
-	} else {
-		confidence = 0.5 // Confianza base si no hay regex definidos
-	}
-
-	return extractedData, confidence
+	return captures, expectedFields
 }
 
 // toDTO convierte una entidad BankNotificationPattern a DTO de respuesta
@@ -532,17 +852,23 @@ func (uc *BankNotificationPatternUseCase) toDTO(pattern *entity.BankNotification
 		DateRegex:           pattern.DateRegex,
 		DescriptionRegex:    pattern.DescriptionRegex,
 		MerchantRegex:       pattern.MerchantRegex,
+		BalanceRegex:        pattern.BalanceRegex,
+		TransactionKind:     pattern.TransactionKind,
 		RequiresValidation:  pattern.RequiresValidation,
 		ConfidenceThreshold: pattern.ConfidenceThreshold,
 		AutoApprove:         pattern.AutoApprove,
 		MatchCount:          pattern.MatchCount,
 		SuccessCount:        pattern.SuccessCount,
+		FailureCount:        pattern.FailureCount,
 		SuccessRate:         pattern.SuccessRate,
 		LastMatchedAt:       pattern.LastMatchedAt,
 		Priority:            pattern.Priority,
 		IsDefault:           pattern.IsDefault,
 		Tags:                pattern.GetTags(),
 		Metadata:            pattern.GetMetadata(),
+		TemplateID:          pattern.TemplateID,
+		TemplateVersion:     pattern.TemplateVersion,
+		Overrides:           pattern.GetOverrides(),
 		CreatedAt:           pattern.CreatedAt,
 		UpdatedAt:           pattern.UpdatedAt,
 	}
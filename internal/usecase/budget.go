@@ -8,6 +8,7 @@ import (
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
 	"github.com/nick130920/fintech-backend/pkg/apperrors"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // BudgetUseCase contiene la lógica de negocio para presupuestos
@@ -52,7 +53,7 @@ func (uc *BudgetUseCase) CreateBudget(userID uint, req *dto.CreateBudgetRequest)
 	}
 
 	// Validar que la suma de asignaciones no exceda el total
-	totalAllocated := float64(0)
+	totalAllocated := money.Zero
 	for _, allocation := range req.Allocations {
 		totalAllocated += allocation.AllocatedAmount
 	}
@@ -190,7 +191,7 @@ func (uc *BudgetUseCase) UpdateBudget(userID, budgetID uint, req *dto.UpdateBudg
 			// Actualizar campos
 			if allocReq.AllocatedAmount != nil {
 				if *allocReq.AllocatedAmount < allocation.SpentAmount {
-					return nil, fmt.Errorf("%w: for category cannot be less than already spent (%.2f)", apperrors.ErrBudgetAllocationsExceed, allocation.SpentAmount)
+					return nil, fmt.Errorf("%w: for category cannot be less than already spent (%s)", apperrors.ErrBudgetAllocationsExceed, allocation.SpentAmount)
 				}
 				allocation.AllocatedAmount = *allocReq.AllocatedAmount
 				allocation.CalculateRemainingAmount()
@@ -341,7 +342,7 @@ func (uc *BudgetUseCase) UpdateAllocation(userID, allocationID uint, req *dto.Up
 	if req.AllocatedAmount != nil {
 		// Validar que el nuevo monto no sea menor que lo ya gastado
 		if *req.AllocatedAmount < allocation.SpentAmount {
-			return nil, fmt.Errorf("%w: allocated amount cannot be less than already spent (%.2f)", apperrors.ErrBudgetAllocationsExceed, allocation.SpentAmount)
+			return nil, fmt.Errorf("%w: allocated amount cannot be less than already spent (%s)", apperrors.ErrBudgetAllocationsExceed, allocation.SpentAmount)
 		}
 		allocation.AllocatedAmount = *req.AllocatedAmount
 		allocation.CalculateRemainingAmount()
@@ -476,8 +477,8 @@ func (uc *BudgetUseCase) mapExpenseToSummaryResponse(expense *entity.Expense) dt
 	}
 }
 
-func (uc *BudgetUseCase) calculateTotalAmount(expenses []*entity.Expense) float64 {
-	total := float64(0)
+func (uc *BudgetUseCase) calculateTotalAmount(expenses []*entity.Expense) money.Amount {
+	total := money.Zero
 	for _, expense := range expenses {
 		if expense.IsConfirmed() {
 			total += expense.Amount
@@ -497,7 +498,7 @@ func (uc *BudgetUseCase) generateCategoryAlerts(allocations []dto.AllocationSumm
 
 			if allocation.IsOverBudget {
 				alertType = "danger"
-				message = fmt.Sprintf("Te has excedido en %s por $%.2f",
+				message = fmt.Sprintf("Te has excedido en %s por $%s",
 					allocation.Category.Name, allocation.SpentAmount-allocation.AllocatedAmount)
 			} else if allocation.ProgressPercent >= 90 {
 				alertType = "danger"
@@ -524,14 +525,14 @@ func (uc *BudgetUseCase) generateQuickStats(budget *dto.BudgetSummaryResponse, m
 	daysInMonth := time.Date(budget.Year, time.Month(budget.Month+1), 0, 0, 0, 0, 0, time.UTC).Day()
 	daysPassed := time.Now().Day()
 
-	averageDaily := float64(0)
+	averageDaily := money.Zero
 	if daysPassed > 0 {
-		averageDaily = budget.SpentAmount / float64(daysPassed)
+		averageDaily = budget.SpentAmount.Div(int64(daysPassed), money.RoundHalfEven)
 	}
 
-	recommendedDaily := float64(0)
+	recommendedDaily := money.Zero
 	if budget.RemainingDays > 0 {
-		recommendedDaily = budget.RemainingAmount / float64(budget.RemainingDays)
+		recommendedDaily = budget.RemainingAmount.Div(int64(budget.RemainingDays), money.RoundHalfEven)
 	}
 
 	categoriesOnTrack := 0
@@ -0,0 +1,378 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/extractor"
+	"github.com/nick130920/fintech-backend/pkg/inboundmail"
+)
+
+// aliasEncoding genera alias en minúsculas sin caracteres ambiguos para una dirección de correo
+var aliasEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// EmailIngestionUseCase recibe correos de notificaciones bancarias enviados al alias de ingesta
+// de cada usuario, por webhook del proveedor de correo o por el servidor SMTP/LMTP, y los
+// procesa como notificaciones del canal email. El remitente de un correo (cabecera From o
+// MAIL FROM) no está autenticado y, al reenviarlo, suele ser el buzón del propio usuario, por lo
+// que no se compara con el email de notificaciones de la cuenta: el alias es aleatorio y solo lo
+// conoce el usuario, así que recibir el correo en él ya identifica su origen.
+type EmailIngestionUseCase struct {
+	aliasRepo       repo.EmailIngestAliasRepo
+	bankAccountRepo repo.BankAccountRepo
+	accountRepo     repo.AccountRepo
+	categoryRepo    repo.CategoryRepo
+	patternUC       *BankNotificationPatternUseCase
+	domain          string // Dominio de los alias; vacío acepta cualquier dominio
+	token           string // Token del webhook; vacío lo deshabilita
+	maxSize         int64
+}
+
+// NewEmailIngestionUseCase crea una nueva instancia de EmailIngestionUseCase
+func NewEmailIngestionUseCase(
+	aliasRepo repo.EmailIngestAliasRepo,
+	bankAccountRepo repo.BankAccountRepo,
+	accountRepo repo.AccountRepo,
+	categoryRepo repo.CategoryRepo,
+	patternUC *BankNotificationPatternUseCase,
+	domain string,
+	token string,
+	maxSize int64,
+) *EmailIngestionUseCase {
+	if maxSize <= 0 {
+		maxSize = inboundmail.DefaultMaxMessageSize
+	}
+
+	return &EmailIngestionUseCase{
+		aliasRepo:       aliasRepo,
+		bankAccountRepo: bankAccountRepo,
+		accountRepo:     accountRepo,
+		categoryRepo:    categoryRepo,
+		patternUC:       patternUC,
+		domain:          strings.ToLower(strings.TrimSpace(domain)),
+		token:           token,
+		maxSize:         maxSize,
+	}
+}
+
+// WebhookEnabled indica si se configuró el token del webhook de correo entrante
+func (uc *EmailIngestionUseCase) WebhookEnabled() bool {
+	return uc.token != ""
+}
+
+// VerifyToken verifica el token enviado por el proveedor de correo
+func (uc *EmailIngestionUseCase) VerifyToken(token string) bool {
+	if !uc.WebhookEnabled() {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(uc.token)) == 1
+}
+
+// GetAlias obtiene el alias de ingesta del usuario, creándolo si aún no tiene
+func (uc *EmailIngestionUseCase) GetAlias(userID uint) (*dto.EmailIngestAliasResponse, error) {
+	alias, err := uc.getOrCreateAlias(userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.toDTO(alias), nil
+}
+
+// UpdateAlias configura la cuenta bancaria y los parámetros de ingesta de los correos recibidos
+func (uc *EmailIngestionUseCase) UpdateAlias(userID uint, req *dto.UpdateEmailIngestAliasRequest) (*dto.EmailIngestAliasResponse, error) {
+	alias, err := uc.getOrCreateAlias(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BankAccountID != nil {
+		if *req.BankAccountID == 0 {
+			alias.BankAccountID = nil
+		} else {
+			bankAccount, err := uc.bankAccountRepo.GetByID(*req.BankAccountID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get bank account: %w", err)
+			}
+			if bankAccount == nil || bankAccount.UserID != userID {
+				return nil, errors.New("bank account not found")
+			}
+			alias.BankAccountID = &bankAccount.ID
+		}
+	}
+	if req.Mode != "" {
+		alias.Mode = req.Mode
+	}
+	if req.AccountID != nil {
+		if *req.AccountID == 0 {
+			alias.AccountID = nil
+		} else {
+			account, err := uc.accountRepo.GetByID(*req.AccountID)
+			if err != nil || account == nil || account.UserID != userID {
+				return nil, errors.New("account not found")
+			}
+			alias.AccountID = &account.ID
+		}
+	}
+	if req.CategoryID != nil {
+		if *req.CategoryID == 0 {
+			alias.CategoryID = nil
+		} else {
+			// La categoría debe pertenecer al usuario o ser del sistema
+			category, err := uc.categoryRepo.GetByID(*req.CategoryID)
+			if err != nil || category == nil || (!category.IsSystemCategory() && category.UserID != nil && *category.UserID != userID) {
+				return nil, errors.New("category not found")
+			}
+			alias.CategoryID = &category.ID
+		}
+	}
+	if req.IsActive != nil {
+		alias.IsActive = *req.IsActive
+	}
+
+	if err := uc.aliasRepo.Update(alias); err != nil {
+		return nil, err
+	}
+
+	return uc.toDTO(alias), nil
+}
+
+// RotateAlias reemplaza el alias del usuario por uno nuevo; la dirección anterior deja de recibir correos
+func (uc *EmailIngestionUseCase) RotateAlias(userID uint) (*dto.EmailIngestAliasResponse, error) {
+	alias, err := uc.getOrCreateAlias(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := uc.newAliasValue()
+	if err != nil {
+		return nil, err
+	}
+	alias.Alias = value
+
+	if err := uc.aliasRepo.Update(alias); err != nil {
+		return nil, err
+	}
+
+	return uc.toDTO(alias), nil
+}
+
+// ReceiveEmail procesa un correo RFC 822/MIME recibido por el webhook. Si no se indican los
+// destinatarios del sobre, se usan los de las cabeceras del mensaje.
+func (uc *EmailIngestionUseCase) ReceiveEmail(raw []byte, recipients []string) (*dto.InboundEmailResponse, error) {
+	if int64(len(raw)) > uc.maxSize {
+		return nil, errors.New("email exceeds maximum size")
+	}
+
+	message, err := inboundmail.Parse(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+	if len(recipients) == 0 {
+		recipients = message.Recipients
+	}
+
+	response := &dto.InboundEmailResponse{
+		From:    message.From,
+		Subject: message.Subject,
+	}
+
+	matched := false
+	for _, recipient := range recipients {
+		result := dto.InboundEmailRecipientResult{Recipient: recipient}
+
+		alias, err := uc.resolveAlias(recipient)
+		switch {
+		case err != nil:
+			result.Status = dto.InboundEmailStatusFailed
+			result.Error = err.Error()
+		case alias == nil:
+			result.Status = dto.InboundEmailStatusUnknown
+		default:
+			matched = true
+			processed, err := uc.ingest(alias, message)
+			result.Result = processed
+			result.Status = dto.InboundEmailStatusProcessed
+			if err != nil {
+				result.Status = dto.InboundEmailStatusFailed
+				result.Error = err.Error()
+			}
+		}
+
+		response.Recipients = append(response.Recipients, result)
+	}
+
+	if !matched {
+		return nil, errors.New("no ingest alias among recipients")
+	}
+
+	return response, nil
+}
+
+// AcceptRecipient verifica que la dirección corresponde a un alias de ingesta activo (inboundmail.Backend)
+func (uc *EmailIngestionUseCase) AcceptRecipient(address string) error {
+	alias, err := uc.resolveAlias(address)
+	if err != nil {
+		return err
+	}
+	if alias == nil {
+		return inboundmail.ErrUnknownRecipient
+	}
+	return nil
+}
+
+// Deliver procesa un correo recibido por el servidor SMTP/LMTP para uno de sus destinatarios
+// (inboundmail.Backend). Solo falla si el correo no se puede leer o el destinatario no existe;
+// los errores de procesamiento quedan registrados en la bandeja de notificaciones.
+// El remitente del sobre no está autenticado y solo se registra en el log.
+func (uc *EmailIngestionUseCase) Deliver(from, recipient string, data []byte) error {
+	alias, err := uc.resolveAlias(recipient)
+	if err != nil {
+		return err
+	}
+	if alias == nil {
+		return inboundmail.ErrUnknownRecipient
+	}
+
+	message, err := inboundmail.Parse(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid email: %w", err)
+	}
+
+	// Los correos que no se pudieron procesar o enrutar a una cuenta quedan en la bandeja del usuario
+	if _, err := uc.ingest(alias, message); err != nil {
+		log.Printf("Warning: Inbound email from %s for %s could not be processed: %v", from, recipient, err)
+	}
+	return nil
+}
+
+// ingest procesa el correo como notificación del canal email del dueño del alias. Se omite la
+// verificación del remitente de la cuenta; el resto de su configuración se aplica igual.
+func (uc *EmailIngestionUseCase) ingest(alias *entity.EmailIngestAlias, message *inboundmail.Message) (*dto.ProcessedNotificationResponse, error) {
+	// Los pies de página legales de los bancos no aportan datos del movimiento: se recorta
+	// el texto al máximo aceptado para una notificación
+	text := strings.TrimSpace(message.Subject + "\n" + message.Text)
+	if runes := []rune(text); len(runes) > extractor.MaxMessageLength {
+		text = string(runes[:extractor.MaxMessageLength])
+	}
+
+	receivedAt := time.Now()
+	if !message.Date.IsZero() {
+		receivedAt = message.Date
+	}
+
+	req := &dto.ProcessNotificationRequest{
+		Channel:    entity.NotificationChannelEmail,
+		Message:    text,
+		Mode:       alias.Mode,
+		AccountID:  alias.AccountID,
+		CategoryID: alias.CategoryID,
+		ReceivedAt: &receivedAt,
+		// El alias identifica el origen del correo; el remitente no está autenticado
+		SkipSenderCheck: true,
+	}
+	if alias.BankAccountID != nil {
+		req.BankAccountID = *alias.BankAccountID
+	}
+
+	response, err := uc.patternUC.ProcessNotification(alias.UserID, req)
+
+	alias.RecordReceived()
+	if updateErr := uc.aliasRepo.Update(alias); updateErr != nil {
+		log.Printf("Warning: Failed to update email ingest alias %d: %v", alias.ID, updateErr)
+	}
+
+	return response, err
+}
+
+// resolveAlias obtiene el alias activo de una dirección de destino. Retorna nil si la dirección
+// no pertenece al dominio de ingesta o no corresponde a ningún alias activo.
+func (uc *EmailIngestionUseCase) resolveAlias(address string) (*entity.EmailIngestAlias, error) {
+	local, domain := inboundmail.AddressLocalPart(address)
+	if local == "" || (uc.domain != "" && domain != uc.domain) {
+		return nil, nil
+	}
+
+	alias, err := uc.aliasRepo.GetByAlias(local)
+	if err != nil {
+		return nil, err
+	}
+	if alias == nil || !alias.IsActive {
+		return nil, nil
+	}
+	return alias, nil
+}
+
+// getOrCreateAlias obtiene el alias del usuario o crea uno nuevo en modo preview
+func (uc *EmailIngestionUseCase) getOrCreateAlias(userID uint) (*entity.EmailIngestAlias, error) {
+	alias, err := uc.aliasRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if alias != nil {
+		return alias, nil
+	}
+
+	value, err := uc.newAliasValue()
+	if err != nil {
+		return nil, err
+	}
+
+	alias = &entity.EmailIngestAlias{
+		UserID:   userID,
+		Alias:    value,
+		IsActive: true,
+		Mode:     entity.NotificationIngestionModePreview,
+	}
+	if err := uc.aliasRepo.Create(alias); err != nil {
+		return nil, err
+	}
+
+	return alias, nil
+}
+
+// newAliasValue genera un alias aleatorio que no esté en uso
+func (uc *EmailIngestionUseCase) newAliasValue() (string, error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		random := make([]byte, 10)
+		if _, err := rand.Read(random); err != nil {
+			return "", fmt.Errorf("failed to generate email alias: %w", err)
+		}
+		value := aliasEncoding.EncodeToString(random)
+
+		existing, err := uc.aliasRepo.GetByAlias(value)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return value, nil
+		}
+	}
+	return "", errors.New("failed to generate a unique email alias")
+}
+
+// toDTO convierte un alias de ingesta a DTO de respuesta
+func (uc *EmailIngestionUseCase) toDTO(alias *entity.EmailIngestAlias) *dto.EmailIngestAliasResponse {
+	return &dto.EmailIngestAliasResponse{
+		ID:             alias.ID,
+		Alias:          alias.Alias,
+		Address:        alias.Address(uc.domain),
+		BankAccountID:  alias.BankAccountID,
+		IsActive:       alias.IsActive,
+		Mode:           alias.Mode,
+		AccountID:      alias.AccountID,
+		CategoryID:     alias.CategoryID,
+		ReceivedCount:  alias.ReceivedCount,
+		LastReceivedAt: alias.LastReceivedAt,
+		CreatedAt:      alias.CreatedAt,
+		UpdatedAt:      alias.UpdatedAt,
+	}
+}
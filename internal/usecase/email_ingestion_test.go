@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/inboundmail"
+)
+
+// fakeAliasRepo guarda los alias de ingesta en memoria
+type fakeAliasRepo struct {
+	aliases []*entity.EmailIngestAlias
+}
+
+func (r *fakeAliasRepo) Create(alias *entity.EmailIngestAlias) error {
+	alias.ID = uint(len(r.aliases) + 1)
+	r.aliases = append(r.aliases, alias)
+	return nil
+}
+
+func (r *fakeAliasRepo) Update(alias *entity.EmailIngestAlias) error { return nil }
+
+func (r *fakeAliasRepo) GetByUserID(userID uint) (*entity.EmailIngestAlias, error) {
+	for _, alias := range r.aliases {
+		if alias.UserID == userID {
+			return alias, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAliasRepo) GetByAlias(value string) (*entity.EmailIngestAlias, error) {
+	for _, alias := range r.aliases {
+		if alias.Alias == value {
+			return alias, nil
+		}
+	}
+	return nil, nil
+}
+
+// fakeAccountRepo implementa solo GetByID de repo.AccountRepo
+type fakeAccountRepo struct {
+	repo.AccountRepo
+	accounts map[uint]*entity.Account
+}
+
+func (r *fakeAccountRepo) GetByID(id uint) (*entity.Account, error) {
+	if account, ok := r.accounts[id]; ok {
+		return account, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+// fakeCategoryRepo implementa solo GetByID de repo.CategoryRepo
+type fakeCategoryRepo struct {
+	repo.CategoryRepo
+	categories map[uint]*entity.Category
+}
+
+func (r *fakeCategoryRepo) GetByID(id uint) (*entity.Category, error) {
+	if category, ok := r.categories[id]; ok {
+		return category, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func newTestEmailIngestion() *EmailIngestionUseCase {
+	owner, other := uint(1), uint(2)
+	aliasRepo := &fakeAliasRepo{aliases: []*entity.EmailIngestAlias{
+		{ID: 1, UserID: owner, Alias: "abc", IsActive: true, Mode: entity.NotificationIngestionModePreview},
+		{ID: 2, UserID: other, Alias: "off", IsActive: false, Mode: entity.NotificationIngestionModePreview},
+	}}
+	accountRepo := &fakeAccountRepo{accounts: map[uint]*entity.Account{
+		10: {ID: 10, UserID: owner},
+		20: {ID: 20, UserID: other},
+	}}
+	categoryRepo := &fakeCategoryRepo{categories: map[uint]*entity.Category{
+		100: {ID: 100, UserID: &owner},
+		200: {ID: 200, UserID: &other},
+		300: {ID: 300, IsDefault: true},
+	}}
+
+	return NewEmailIngestionUseCase(aliasRepo, nil, accountRepo, categoryRepo, nil, "Ingest.Example.com", "", 0)
+}
+
+func TestEmailIngestionAcceptRecipient(t *testing.T) {
+	uc := newTestEmailIngestion()
+
+	tests := []struct {
+		address string
+		wantErr error
+	}{
+		{"abc@ingest.example.com", nil},
+		{"<ABC+banco@Ingest.Example.com>", nil},
+		{"abc@otro.example.com", inboundmail.ErrUnknownRecipient},
+		{"off@ingest.example.com", inboundmail.ErrUnknownRecipient},
+		{"nadie@ingest.example.com", inboundmail.ErrUnknownRecipient},
+		{"", inboundmail.ErrUnknownRecipient},
+	}
+
+	for _, tt := range tests {
+		if err := uc.AcceptRecipient(tt.address); !errors.Is(err, tt.wantErr) {
+			t.Errorf("AcceptRecipient(%q) = %v, want %v", tt.address, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEmailIngestionRejectsUnknownRecipients(t *testing.T) {
+	uc := newTestEmailIngestion()
+	raw := []byte("From: alertas@banco.com\r\nTo: off@ingest.example.com\r\nSubject: Compra\r\n\r\nCompra por $10.00\r\n")
+
+	// Sin destinatarios del sobre se usan los de las cabeceras
+	if _, err := uc.ReceiveEmail(raw, nil); err == nil || err.Error() != "no ingest alias among recipients" {
+		t.Fatalf("ReceiveEmail error = %v", err)
+	}
+	if _, err := uc.ReceiveEmail(raw, []string{"abc@otro.example.com"}); err == nil || err.Error() != "no ingest alias among recipients" {
+		t.Fatalf("ReceiveEmail error = %v", err)
+	}
+	if err := uc.Deliver("alertas@banco.com", "nadie@ingest.example.com", raw); !errors.Is(err, inboundmail.ErrUnknownRecipient) {
+		t.Fatalf("Deliver error = %v", err)
+	}
+}
+
+func TestEmailIngestionUpdateAliasOwnership(t *testing.T) {
+	id := func(v uint) *uint { return &v }
+
+	tests := []struct {
+		name    string
+		req     dto.UpdateEmailIngestAliasRequest
+		wantErr string
+	}{
+		{name: "own account and category", req: dto.UpdateEmailIngestAliasRequest{AccountID: id(10), CategoryID: id(100)}},
+		{name: "system category", req: dto.UpdateEmailIngestAliasRequest{CategoryID: id(300)}},
+		{name: "clear account and category", req: dto.UpdateEmailIngestAliasRequest{AccountID: id(0), CategoryID: id(0)}},
+		{name: "other user's account", req: dto.UpdateEmailIngestAliasRequest{AccountID: id(20)}, wantErr: "account not found"},
+		{name: "missing account", req: dto.UpdateEmailIngestAliasRequest{AccountID: id(99)}, wantErr: "account not found"},
+		{name: "other user's category", req: dto.UpdateEmailIngestAliasRequest{CategoryID: id(200)}, wantErr: "category not found"},
+		{name: "missing category", req: dto.UpdateEmailIngestAliasRequest{CategoryID: id(999)}, wantErr: "category not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newTestEmailIngestion()
+			response, err := uc.UpdateAlias(1, &tt.req)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("UpdateAlias error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateAlias error: %v", err)
+			}
+			if tt.req.AccountID != nil && !sameID(response.AccountID, *tt.req.AccountID) {
+				t.Errorf("AccountID = %v, want %d", response.AccountID, *tt.req.AccountID)
+			}
+			if tt.req.CategoryID != nil && !sameID(response.CategoryID, *tt.req.CategoryID) {
+				t.Errorf("CategoryID = %v, want %d", response.CategoryID, *tt.req.CategoryID)
+			}
+		})
+	}
+}
+
+// sameID compara un ID opcional con el solicitado, donde 0 significa sin valor
+func sameID(got *uint, want uint) bool {
+	if want == 0 {
+		return got == nil
+	}
+	return got != nil && *got == want
+}
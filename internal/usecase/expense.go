@@ -9,14 +9,16 @@ import (
 	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // ExpenseUseCase contiene la lógica de negocio para gastos
 type ExpenseUseCase struct {
-	expenseRepo  repo.ExpenseRepo
-	budgetRepo   repo.BudgetRepo
-	categoryRepo repo.CategoryRepo
-	userRepo     repo.UserRepo
+	expenseRepo     repo.ExpenseRepo
+	budgetRepo      repo.BudgetRepo
+	categoryRepo    repo.CategoryRepo
+	userRepo        repo.UserRepo
+	patternPolicyUC *PatternPolicyUseCase
 }
 
 // NewExpenseUseCase crea una nueva instancia de ExpenseUseCase
@@ -25,12 +27,14 @@ func NewExpenseUseCase(
 	budgetRepo repo.BudgetRepo,
 	categoryRepo repo.CategoryRepo,
 	userRepo repo.UserRepo,
+	patternPolicyUC *PatternPolicyUseCase,
 ) *ExpenseUseCase {
 	return &ExpenseUseCase{
-		expenseRepo:  expenseRepo,
-		budgetRepo:   budgetRepo,
-		categoryRepo: categoryRepo,
-		userRepo:     userRepo,
+		expenseRepo:     expenseRepo,
+		budgetRepo:      budgetRepo,
+		categoryRepo:    categoryRepo,
+		userRepo:        userRepo,
+		patternPolicyUC: patternPolicyUC,
 	}
 }
 
@@ -108,7 +112,7 @@ func (uc *ExpenseUseCase) CreateExpense(userID uint, req *dto.CreateExpenseReque
 		budget = currentBudget
 		log.Printf("✅ Usando presupuesto actual: ID=%d, Período=%d/%d", budget.ID, budget.Year, budget.Month)
 	} else {
-		log.Printf("✅ Presupuesto encontrado: ID=%d, Total=%.2f", budget.ID, budget.TotalAmount)
+		log.Printf("✅ Presupuesto encontrado: ID=%d, Total=%s", budget.ID, budget.TotalAmount)
 	}
 
 	// Obtener la asignación de la categoría en el presupuesto
@@ -119,7 +123,7 @@ func (uc *ExpenseUseCase) CreateExpense(userID uint, req *dto.CreateExpenseReque
 		return nil, fmt.Errorf("category not allocated in budget: %v", err)
 	}
 
-	log.Printf("✅ Asignación encontrada: ID=%d, Asignado=%.2f, Gastado=%.2f", allocation.ID, allocation.AllocatedAmount, allocation.SpentAmount)
+	log.Printf("✅ Asignación encontrada: ID=%d, Asignado=%s, Gastado=%s", allocation.ID, allocation.AllocatedAmount, allocation.SpentAmount)
 
 	// Crear el gasto
 	expense := &entity.Expense{
@@ -219,7 +223,7 @@ func (uc *ExpenseUseCase) GetExpensesByCategory(userID uint) (map[string]interfa
 	}
 
 	// Agrupar por categoría
-	categoryTotals := make(map[uint]float64)
+	categoryTotals := make(map[uint]money.Amount)
 	categoryNames := make(map[uint]string)
 	categoryIcons := make(map[uint]string)
 
@@ -393,8 +397,72 @@ func (uc *ExpenseUseCase) DeleteExpense(userID, expenseID uint) error {
 	return nil
 }
 
+// ConfirmExpense confirma un gasto pendiente y registra el éxito del patrón que lo generó
+func (uc *ExpenseUseCase) ConfirmExpense(userID, expenseID uint) (*dto.ExpenseSummaryResponse, error) {
+	expense, err := uc.expenseRepo.GetByID(expenseID)
+	if err != nil || expense.UserID != userID {
+		return nil, errors.New("expense not found")
+	}
+
+	if !expense.IsPending() {
+		return nil, errors.New("expense is not pending")
+	}
+
+	expense.Confirm()
+	if err := uc.expenseRepo.Update(expense); err != nil {
+		return nil, err
+	}
+
+	uc.recordPatternFeedback(expense, true, false)
+	return uc.mapExpenseToSummaryResponse(expense), nil
+}
+
+// RejectExpense cancela un gasto generado desde una notificación, recalcula los
+// montos gastados y registra el fallo del patrón que lo generó
+func (uc *ExpenseUseCase) RejectExpense(userID, expenseID uint) (*dto.ExpenseSummaryResponse, error) {
+	expense, err := uc.expenseRepo.GetByID(expenseID)
+	if err != nil || expense.UserID != userID {
+		return nil, errors.New("expense not found")
+	}
+
+	// Los gastos manuales se cancelan o eliminan; el rechazo califica al patrón que generó el gasto
+	if !expense.IsFromNotification() {
+		return nil, errors.New("expense was not generated from a notification")
+	}
+	if !expense.CanBeCancelled() {
+		return nil, errors.New("expense cannot be cancelled")
+	}
+
+	wasSuccess := expense.IsConfirmed()
+	expense.Cancel()
+	if err := uc.expenseRepo.Update(expense); err != nil {
+		return nil, err
+	}
+
+	// Actualizar montos gastados
+	if err := uc.budgetRepo.UpdateAllocationSpentAmount(expense.AllocationID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.budgetRepo.UpdateBudgetSpentAmount(expense.BudgetID); err != nil {
+		return nil, err
+	}
+
+	uc.recordPatternFeedback(expense, false, wasSuccess)
+	return uc.mapExpenseToSummaryResponse(expense), nil
+}
+
 // Helper methods
 
+// recordPatternFeedback registra la validación del usuario en el patrón que generó el gasto
+func (uc *ExpenseUseCase) recordPatternFeedback(expense *entity.Expense, success, wasSuccess bool) {
+	if expense.PatternID == nil {
+		return
+	}
+
+	uc.patternPolicyUC.RecordFeedback(*expense.PatternID, success, wasSuccess)
+}
+
 func (uc *ExpenseUseCase) mapExpenseToSummaryResponse(expense *entity.Expense) *dto.ExpenseSummaryResponse {
 	return &dto.ExpenseSummaryResponse{
 		ID:              expense.ID,
@@ -434,8 +502,8 @@ func (uc *ExpenseUseCase) mapCategoryToSummaryResponse(category *entity.Category
 	}
 }
 
-func (uc *ExpenseUseCase) calculateTotalAmount(expenses []*entity.Expense) float64 {
-	total := float64(0)
+func (uc *ExpenseUseCase) calculateTotalAmount(expenses []*entity.Expense) money.Amount {
+	total := money.Zero
 	for _, expense := range expenses {
 		if expense.IsConfirmed() {
 			total += expense.Amount
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// fakeExpenseRepo implementa solo la lectura y la actualización de repo.ExpenseRepo
+type fakeExpenseRepo struct {
+	repo.ExpenseRepo
+	expenses map[uint]*entity.Expense
+	updated  int
+}
+
+func (r *fakeExpenseRepo) GetByID(id uint) (*entity.Expense, error) {
+	if expense, ok := r.expenses[id]; ok {
+		return expense, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeExpenseRepo) Update(expense *entity.Expense) error {
+	r.updated++
+	return nil
+}
+
+func TestRejectExpenseRequiresNotification(t *testing.T) {
+	patternID := uint(5)
+	expenseRepo := &fakeExpenseRepo{expenses: map[uint]*entity.Expense{
+		1: {ID: 1, UserID: 1, Amount: money.FromUnits(250), Source: entity.ExpenseSourceManual, Status: entity.ExpenseStatusConfirmed},
+		2: {ID: 2, UserID: 1, Amount: money.FromUnits(250), Source: entity.ExpenseSourceNotification, Status: entity.ExpenseStatusCancelled, PatternID: &patternID},
+	}}
+	uc := NewExpenseUseCase(expenseRepo, nil, nil, nil, nil)
+
+	tests := []struct {
+		name      string
+		userID    uint
+		expenseID uint
+		wantErr   string
+	}{
+		{"manual expense", 1, 1, "expense was not generated from a notification"},
+		{"other user", 2, 1, "expense not found"},
+		{"already cancelled", 1, 2, "expense cannot be cancelled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.RejectExpense(tt.userID, tt.expenseID)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("RejectExpense error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if expenseRepo.updated != 0 || expenseRepo.expenses[1].Status != entity.ExpenseStatusConfirmed {
+		t.Fatalf("manual expense was modified: %+v", expenseRepo.expenses[1])
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // IncomeUseCase contiene la lógica de negocio para ingresos
@@ -269,7 +270,7 @@ func (uc *IncomeUseCase) GetIncomeStats(userID uint, year *int) (*dto.IncomeStat
 	}
 
 	// Total de ingresos
-	totalIncome := float64(0)
+	totalIncome := money.Zero
 	for _, income := range incomes {
 		totalIncome += income.Amount
 	}
@@ -277,7 +278,7 @@ func (uc *IncomeUseCase) GetIncomeStats(userID uint, year *int) (*dto.IncomeStat
 	stats.FormattedTotalIncome = entity.FormatCurrency(totalIncome, user.Currency)
 
 	// Promedio mensual
-	monthlyAvg := totalIncome / 12
+	monthlyAvg := totalIncome.Div(12, money.RoundHalfEven)
 	stats.MonthlyAverage = monthlyAvg
 	stats.FormattedMonthlyAverage = entity.FormatCurrency(monthlyAvg, user.Currency)
 
@@ -314,6 +315,9 @@ func (uc *IncomeUseCase) GetRecentIncomes(userID uint, limit int) ([]*dto.Income
 }
 
 // ProcessRecurringIncomes procesa ingresos recurrentes pendientes
+//
+// Deprecated: usar RecurringTransactionUseCase.Process. Solo procesa los ingresos recurrentes
+// registrados antes de las transacciones recurrentes.
 func (uc *IncomeUseCase) ProcessRecurringIncomes(userID uint) (*dto.RecurringIncomeProcessResponse, error) {
 	pendingIncomes, err := uc.incomeRepo.GetPendingRecurringIncomes(userID)
 	if err != nil {
@@ -438,8 +442,8 @@ func (uc *IncomeUseCase) mapIncomeToSummaryResponse(income *entity.Income) *dto.
 }
 
 func (uc *IncomeUseCase) calculateIncomeBySource(incomes []*entity.Income, currency string) []dto.IncomeBySourceResponse {
-	sourceMap := make(map[entity.IncomeSource]float64)
-	total := float64(0)
+	sourceMap := make(map[entity.IncomeSource]money.Amount)
+	total := money.Zero
 
 	for _, income := range incomes {
 		sourceMap[income.Source] += income.Amount
@@ -450,7 +454,7 @@ func (uc *IncomeUseCase) calculateIncomeBySource(incomes []*entity.Income, curre
 	for source, amount := range sourceMap {
 		percentage := float64(0)
 		if total > 0 {
-			percentage = (amount / total) * 100
+			percentage = money.Ratio(amount, total) * 100
 		}
 
 		result = append(result, dto.IncomeBySourceResponse{
@@ -466,7 +470,7 @@ func (uc *IncomeUseCase) calculateIncomeBySource(incomes []*entity.Income, curre
 }
 
 func (uc *IncomeUseCase) calculateMonthlyIncome(incomes []*entity.Income, year int, currency string) []dto.MonthlyIncomeResponse {
-	monthlyMap := make(map[int]float64)
+	monthlyMap := make(map[int]money.Amount)
 	monthlyCount := make(map[int]int)
 
 	for _, income := range incomes {
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+)
+
+// Paginación por defecto de los asientos del libro contable
+const (
+	defaultLedgerPerPage = 50
+	maxLedgerPerPage     = 200
+)
+
+// LedgerUseCase expone el libro contable de partida doble en el que se registran las transacciones
+// y transferencias, y verifica su integridad
+type LedgerUseCase struct {
+	ledgerRepo  repo.LedgerRepo
+	accountRepo repo.AccountRepo
+}
+
+// NewLedgerUseCase crea una nueva instancia de LedgerUseCase
+func NewLedgerUseCase(ledgerRepo repo.LedgerRepo, accountRepo repo.AccountRepo) *LedgerUseCase {
+	return &LedgerUseCase{
+		ledgerRepo:  ledgerRepo,
+		accountRepo: accountRepo,
+	}
+}
+
+// ListEntries obtiene los asientos del usuario con filtros y paginación
+func (uc *LedgerUseCase) ListEntries(userID uint, filter entity.LedgerEntryFilter, page, perPage int) (*dto.PaginatedLedgerEntryResponse, error) {
+	if filter.AccountID != nil {
+		account, err := uc.accountRepo.GetByID(*filter.AccountID)
+		if err != nil || account.UserID != userID {
+			return nil, errors.New("account not found")
+		}
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = defaultLedgerPerPage
+	}
+	if perPage > maxLedgerPerPage {
+		perPage = maxLedgerPerPage
+	}
+	filter.Limit = perPage
+	filter.Offset = (page - 1) * perPage
+
+	entries, total, err := uc.ledgerRepo.GetEntries(userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger entries: %w", err)
+	}
+
+	data := make([]*dto.LedgerEntryResponse, len(entries))
+	for i, entry := range entries {
+		data[i] = uc.toDTO(entry)
+	}
+
+	return &dto.PaginatedLedgerEntryResponse{
+		Data:       data,
+		Total:      int(total),
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: int((total + int64(perPage) - 1) / int64(perPage)),
+	}, nil
+}
+
+// CheckIntegrity verifica que todos los asientos del usuario sumen cero y que el balance guardado
+// de cada cuenta coincida con la suma de sus líneas en el libro contable
+func (uc *LedgerUseCase) CheckIntegrity(userID uint) (*dto.LedgerIntegrityResponse, error) {
+	entriesChecked, err := uc.ledgerRepo.CountEntries(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unbalanced, err := uc.ledgerRepo.GetUnbalancedEntries(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := uc.ledgerRepo.GetAccountTotals(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := uc.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	mismatched := []dto.LedgerAccountCheck{}
+	for _, account := range accounts {
+		ledgerBalance := account.BalanceFromLedger(totals[account.ID])
+		difference := account.Balance - ledgerBalance
+		if difference.IsZero() {
+			continue
+		}
+
+		mismatched = append(mismatched, dto.LedgerAccountCheck{
+			AccountID:     account.ID,
+			Name:          account.Name,
+			Balance:       account.Balance,
+			LedgerBalance: ledgerBalance,
+			Difference:    difference,
+		})
+	}
+
+	if unbalanced == nil {
+		unbalanced = []entity.LedgerImbalance{}
+	}
+
+	return &dto.LedgerIntegrityResponse{
+		Balanced:           len(unbalanced) == 0,
+		Consistent:         len(mismatched) == 0,
+		EntriesChecked:     entriesChecked,
+		UnbalancedEntries:  unbalanced,
+		AccountsChecked:    len(accounts),
+		MismatchedAccounts: mismatched,
+		CheckedAt:          time.Now(),
+	}, nil
+}
+
+// BackfillOpeningEntries registra el asiento de apertura de las cuentas creadas antes del libro
+// contable, para que su balance actual quede respaldado por el libro
+func (uc *LedgerUseCase) BackfillOpeningEntries() (int, error) {
+	return uc.ledgerRepo.CreateOpeningEntries()
+}
+
+// toDTO convierte un asiento del libro contable a DTO de respuesta
+func (uc *LedgerUseCase) toDTO(entry *entity.LedgerEntry) *dto.LedgerEntryResponse {
+	postings := make([]dto.LedgerPostingResponse, len(entry.Postings))
+	for i, posting := range entry.Postings {
+		postings[i] = dto.LedgerPostingResponse{
+			AccountID: posting.AccountID,
+			Nominal:   posting.Nominal,
+			Amount:    posting.Amount,
+		}
+	}
+
+	return &dto.LedgerEntryResponse{
+		ID:            entry.ID,
+		Kind:          entry.Kind,
+		TransactionID: entry.TransactionID,
+		ExpenseID:     entry.ExpenseID,
+		IncomeID:      entry.IncomeID,
+		ReversalOfID:  entry.ReversalOfID,
+		Description:   entry.Description,
+		EntryDate:     entry.EntryDate,
+		Postings:      postings,
+		CreatedAt:     entry.CreatedAt,
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"sync"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+)
+
+// DefaultBatchWorkers es la cantidad por defecto de notificaciones de un lote procesadas en paralelo
+const DefaultBatchWorkers = 4
+
+// NotificationBatchUseCase procesa lotes de notificaciones, por ejemplo el historial de SMS
+// que sincroniza el cliente móvil al instalarse
+type NotificationBatchUseCase struct {
+	patternUC *BankNotificationPatternUseCase
+	workers   int
+}
+
+// NewNotificationBatchUseCase crea una nueva instancia de NotificationBatchUseCase
+func NewNotificationBatchUseCase(patternUC *BankNotificationPatternUseCase, workers int) *NotificationBatchUseCase {
+	if workers <= 0 {
+		workers = DefaultBatchWorkers
+	}
+
+	return &NotificationBatchUseCase{
+		patternUC: patternUC,
+		workers:   workers,
+	}
+}
+
+// ProcessBatch procesa las notificaciones del lote con un número acotado de workers. Cada
+// notificación se procesa igual que en ProcessNotification (bandeja, deduplicación e ingesta);
+// un error en una de ellas no detiene el resto del lote.
+func (uc *NotificationBatchUseCase) ProcessBatch(userID uint, req *dto.BatchProcessNotificationRequest) *dto.BatchProcessNotificationResponse {
+	results := make([]dto.BatchNotificationResult, len(req.Notifications))
+
+	workers := uc.workers
+	if workers > len(req.Notifications) {
+		workers = len(req.Notifications)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = uc.processItem(userID, i, &req.Notifications[i])
+			}
+		}()
+	}
+
+	for i := range req.Notifications {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	response := &dto.BatchProcessNotificationResponse{
+		Total:   len(results),
+		Results: results,
+	}
+	for _, result := range results {
+		switch result.Status {
+		case dto.BatchNotificationStatusProcessed:
+			response.Processed++
+		case dto.BatchNotificationStatusUnmatched:
+			response.Unmatched++
+		case dto.BatchNotificationStatusDuplicate:
+			response.Duplicates++
+		case dto.BatchNotificationStatusSkipped:
+			response.Skipped++
+		default:
+			response.Failed++
+		}
+	}
+
+	return response
+}
+
+// processItem procesa una notificación del lote y clasifica su resultado
+func (uc *NotificationBatchUseCase) processItem(userID uint, index int, req *dto.ProcessNotificationRequest) dto.BatchNotificationResult {
+	result := dto.BatchNotificationResult{Index: index}
+
+	response, err := uc.patternUC.ProcessNotification(userID, req)
+	if err != nil {
+		result.Status = dto.BatchNotificationStatusFailed
+		switch err.Error() {
+		case "no matching pattern for notification", "bank account could not be resolved from notification":
+			result.Status = dto.BatchNotificationStatusUnmatched
+		}
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Result = response
+	switch {
+	case response.Decision != entity.NotificationDecisionAccepted:
+		result.Status = dto.BatchNotificationStatusSkipped
+	case response.PatternID == nil:
+		result.Status = dto.BatchNotificationStatusUnmatched
+	case response.Duplicate:
+		result.Status = dto.BatchNotificationStatusDuplicate
+	default:
+		result.Status = dto.BatchNotificationStatusProcessed
+	}
+
+	return result
+}
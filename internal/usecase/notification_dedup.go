@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+)
+
+// DefaultDedupWindow es la ventana de tiempo por defecto en la que dos notificaciones iguales se consideran el mismo evento
+const DefaultDedupWindow = 30 * time.Minute
+
+// NotificationDedupUseCase detecta notificaciones del mismo evento bancario recibidas por distintos canales
+type NotificationDedupUseCase struct {
+	fingerprintRepo repo.NotificationFingerprintRepo
+	expenseRepo     repo.ExpenseRepo
+	window          time.Duration
+	locks           sync.Map // bankAccountID -> *sync.Mutex
+}
+
+// notificationDuplicate describe el movimiento existente al que se enlaza una notificación repetida
+type notificationDuplicate struct {
+	OriginalID    *uint // Huella de la notificación original (nil si el gasto no provino de una notificación registrada)
+	TransactionID *uint
+	ExpenseID     *uint
+}
+
+// NewNotificationDedupUseCase crea una nueva instancia de NotificationDedupUseCase
+func NewNotificationDedupUseCase(fingerprintRepo repo.NotificationFingerprintRepo, expenseRepo repo.ExpenseRepo, window time.Duration) *NotificationDedupUseCase {
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+
+	return &NotificationDedupUseCase{
+		fingerprintRepo: fingerprintRepo,
+		expenseRepo:     expenseRepo,
+		window:          window,
+	}
+}
+
+// NewFingerprint calcula la huella de una notificación a partir de sus datos normalizados
+func (uc *NotificationDedupUseCase) NewFingerprint(
+	userID uint,
+	bankAccount *entity.BankAccount,
+	channel entity.NotificationChannel,
+	movement *notificationMovement,
+	receivedAt time.Time,
+) *entity.NotificationFingerprint {
+	return entity.NewNotificationFingerprint(
+		userID,
+		bankAccount.ID,
+		channel,
+		movement.Kind,
+		movement.Amount,
+		movementCurrency(movement, bankAccount.Currency),
+		movement.Merchant,
+		movement.Reference,
+		movement.Date,
+		receivedAt,
+		uc.window,
+	)
+}
+
+// Lock serializa el procesamiento de notificaciones de una cuenta bancaria, para que dos canales
+// que reportan el mismo evento al mismo tiempo no creen ambos un movimiento
+func (uc *NotificationDedupUseCase) Lock(bankAccountID uint) func() {
+	value, _ := uc.locks.LoadOrStore(bankAccountID, &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+// FindDuplicate busca una notificación anterior del mismo evento. En modo expense también se
+// consideran los gastos del usuario (incluso manuales) con el mismo monto y comercio dentro de la ventana.
+// Retorna nil si la notificación es nueva.
+func (uc *NotificationDedupUseCase) FindDuplicate(
+	fingerprint *entity.NotificationFingerprint,
+	mode entity.NotificationIngestionMode,
+) (*notificationDuplicate, error) {
+	original, err := uc.fingerprintRepo.GetByFingerprint(fingerprint.BankAccountID, fingerprint.Fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if original != nil && !fingerprint.Matches(original, uc.window) {
+		// Misma huella por el mismo canal y sin referencias: es otro movimiento
+		original = nil
+	}
+
+	if original == nil {
+		candidates, err := uc.fingerprintRepo.GetCandidates(
+			fingerprint.BankAccountID,
+			fingerprint.Amount,
+			fingerprint.EventAt.Add(-uc.window),
+			fingerprint.EventAt.Add(uc.window),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range candidates {
+			if fingerprint.Matches(candidate, uc.window) {
+				original = candidate
+				break
+			}
+		}
+	}
+
+	if original != nil {
+		return &notificationDuplicate{
+			OriginalID:    &original.ID,
+			TransactionID: original.TransactionID,
+			ExpenseID:     original.ExpenseID,
+		}, nil
+	}
+
+	// Sin comercio no hay forma de distinguir dos gastos del mismo monto
+	if mode == entity.NotificationIngestionModeExpense && fingerprint.Kind.IsSpending() && fingerprint.Merchant != "" {
+		expenses, err := uc.expenseRepo.GetDuplicateCandidates(fingerprint.UserID, fingerprint.Amount, fingerprint.Merchant, fingerprint.EventAt, uc.window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get duplicate expenses: %w", err)
+		}
+		if len(expenses) > 0 {
+			return &notificationDuplicate{ExpenseID: &expenses[0].ID}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Record guarda la huella de una notificación procesada
+func (uc *NotificationDedupUseCase) Record(fingerprint *entity.NotificationFingerprint) error {
+	return uc.fingerprintRepo.Create(fingerprint)
+}
@@ -0,0 +1,389 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+)
+
+// Paginación por defecto de la bandeja de notificaciones
+const (
+	defaultInboxPerPage = 20
+	maxInboxPerPage     = 100
+)
+
+// NotificationInboxUseCase contiene la lógica de revisión de la bandeja de notificaciones recibidas
+type NotificationInboxUseCase struct {
+	inboxRepo       repo.NotificationInboxRepo
+	bankAccountRepo repo.BankAccountRepo
+	patternUC       *BankNotificationPatternUseCase
+	transactionUC   *TransactionUseCase
+	expenseUC       *ExpenseUseCase
+}
+
+// NewNotificationInboxUseCase crea una nueva instancia de NotificationInboxUseCase
+func NewNotificationInboxUseCase(
+	inboxRepo repo.NotificationInboxRepo,
+	bankAccountRepo repo.BankAccountRepo,
+	patternUC *BankNotificationPatternUseCase,
+	transactionUC *TransactionUseCase,
+	expenseUC *ExpenseUseCase,
+) *NotificationInboxUseCase {
+	return &NotificationInboxUseCase{
+		inboxRepo:       inboxRepo,
+		bankAccountRepo: bankAccountRepo,
+		patternUC:       patternUC,
+		transactionUC:   transactionUC,
+		expenseUC:       expenseUC,
+	}
+}
+
+// ListItems obtiene las notificaciones de la bandeja del usuario con filtros y paginación
+func (uc *NotificationInboxUseCase) ListItems(userID uint, filter entity.NotificationInboxFilter, page, perPage int) (*dto.PaginatedNotificationInboxResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = defaultInboxPerPage
+	}
+	if perPage > maxInboxPerPage {
+		perPage = maxInboxPerPage
+	}
+	filter.Limit = perPage
+	filter.Offset = (page - 1) * perPage
+
+	items, total, err := uc.inboxRepo.GetWithFilters(userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification inbox: %w", err)
+	}
+
+	data := make([]*dto.NotificationInboxItemResponse, len(items))
+	for i, item := range items {
+		data[i] = uc.toDTO(item)
+	}
+
+	return &dto.PaginatedNotificationInboxResponse{
+		Data:       data,
+		Total:      int(total),
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: int((total + int64(perPage) - 1) / int64(perPage)),
+	}, nil
+}
+
+// GetItem obtiene una notificación de la bandeja
+func (uc *NotificationInboxUseCase) GetItem(userID, itemID uint) (*dto.NotificationInboxItemResponse, error) {
+	item, err := uc.getItem(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.toDTO(item), nil
+}
+
+// RetryItem vuelve a procesar una notificación con los patrones actuales. Los parámetros de
+// ingesta de la petición reemplazan a los guardados.
+func (uc *NotificationInboxUseCase) RetryItem(userID, itemID uint, req *dto.ReviewNotificationInboxRequest) (*dto.NotificationInboxItemResponse, error) {
+	item, err := uc.getItem(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if !item.CanReprocess() {
+		return nil, errors.New("notification was already converted")
+	}
+
+	applyReviewParams(item, req)
+	if err := uc.reprocess(userID, item, true); err != nil {
+		return nil, err
+	}
+
+	return uc.toDTO(item), nil
+}
+
+// ApproveItem convierte una notificación en transacción o gasto. Si ya generó un movimiento
+// pendiente de revisión, lo aprueba; si solo se extrajeron datos o quedó en espera por la
+// configuración de la cuenta, crea el movimiento aprobado.
+func (uc *NotificationInboxUseCase) ApproveItem(userID, itemID uint, req *dto.ReviewNotificationInboxRequest) (*dto.NotificationInboxItemResponse, error) {
+	item, err := uc.getItem(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch item.Status {
+	case entity.NotificationInboxStatusPendingReview:
+		// El movimiento ya existe: aprobarlo
+	case entity.NotificationInboxStatusParsed, entity.NotificationInboxStatusParked, entity.NotificationInboxStatusUnrouted:
+		applyReviewParams(item, req)
+		if item.Mode == entity.NotificationIngestionModePreview {
+			return nil, errors.New("mode is required to approve a notification")
+		}
+		// La aprobación del usuario prevalece sobre la configuración de notificaciones de la cuenta
+		if err := uc.reprocess(userID, item, false); err != nil {
+			return nil, err
+		}
+		if item.Status != entity.NotificationInboxStatusPendingReview && item.Status != entity.NotificationInboxStatusConverted {
+			return nil, fmt.Errorf("notification could not be converted: %s", item.StatusReason)
+		}
+	case entity.NotificationInboxStatusUnmatched:
+		return nil, errors.New("notification has no extracted data")
+	default:
+		return nil, errors.New("notification cannot be approved")
+	}
+
+	// Un movimiento recién creado puede quedar pendiente según la confianza del patrón;
+	// la aprobación del usuario lo confirma
+	if item.Status == entity.NotificationInboxStatusPendingReview {
+		if err := uc.approveMovement(userID, item); err != nil {
+			return nil, err
+		}
+		item.Status = entity.NotificationInboxStatusConverted
+		item.StatusReason = "approved by user"
+	}
+
+	if err := uc.inboxRepo.Update(item); err != nil {
+		return nil, err
+	}
+
+	return uc.toDTO(item), nil
+}
+
+// RejectItem descarta una notificación. Si generó un movimiento pendiente de revisión, lo rechaza.
+func (uc *NotificationInboxUseCase) RejectItem(userID, itemID uint) (*dto.NotificationInboxItemResponse, error) {
+	item, err := uc.getItem(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch item.Status {
+	case entity.NotificationInboxStatusPendingReview:
+		if err := uc.rejectMovement(userID, item); err != nil {
+			return nil, err
+		}
+	case entity.NotificationInboxStatusUnmatched, entity.NotificationInboxStatusParsed, entity.NotificationInboxStatusParked,
+		entity.NotificationInboxStatusUnrouted:
+	default:
+		return nil, errors.New("notification cannot be rejected")
+	}
+
+	item.Status = entity.NotificationInboxStatusIgnored
+	item.StatusReason = "rejected by user"
+	if err := uc.inboxRepo.Update(item); err != nil {
+		return nil, err
+	}
+
+	return uc.toDTO(item), nil
+}
+
+// reprocess aplica nuevamente los patrones al mensaje guardado y actualiza la notificación.
+// enforceSettings indica si se aplica la configuración de notificaciones de la cuenta.
+func (uc *NotificationInboxUseCase) reprocess(userID uint, item *entity.NotificationInboxItem, enforceSettings bool) error {
+	if !item.IsRouted() {
+		return errors.New("bank account is required to process the notification")
+	}
+
+	bankAccount, err := uc.bankAccountRepo.GetByID(*item.BankAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get bank account: %w", err)
+	}
+	if bankAccount == nil || bankAccount.UserID != userID {
+		return errors.New("unauthorized access to bank account")
+	}
+
+	req := &dto.ProcessNotificationRequest{
+		BankAccountID:   *item.BankAccountID,
+		Channel:         item.Channel,
+		Message:         item.Message,
+		Mode:            item.Mode,
+		AccountID:       item.AccountID,
+		CategoryID:      item.CategoryID,
+		ReceivedAt:      &item.ReceivedAt,
+		Sender:          item.Sender,
+		SkipSenderCheck: item.SkipSenderCheck,
+	}
+
+	response, processErr := uc.patternUC.processNotification(userID, bankAccount, req, enforceSettings)
+	applyInboxResult(item, response, processErr)
+
+	return uc.inboxRepo.Update(item)
+}
+
+// approveMovement aprueba la transacción o el gasto pendiente generado por la notificación
+func (uc *NotificationInboxUseCase) approveMovement(userID uint, item *entity.NotificationInboxItem) error {
+	if item.TransactionID != nil {
+		_, err := uc.transactionUC.Approve(userID, *item.TransactionID)
+		return err
+	}
+	if item.ExpenseID != nil {
+		_, err := uc.expenseUC.ConfirmExpense(userID, *item.ExpenseID)
+		return err
+	}
+	return nil
+}
+
+// rejectMovement rechaza la transacción o el gasto pendiente generado por la notificación
+func (uc *NotificationInboxUseCase) rejectMovement(userID uint, item *entity.NotificationInboxItem) error {
+	if item.TransactionID != nil {
+		_, err := uc.transactionUC.Reject(userID, *item.TransactionID)
+		return err
+	}
+	if item.ExpenseID != nil {
+		_, err := uc.expenseUC.RejectExpense(userID, *item.ExpenseID)
+		return err
+	}
+	return nil
+}
+
+// getItem obtiene una notificación de la bandeja verificando que pertenece al usuario
+func (uc *NotificationInboxUseCase) getItem(userID, itemID uint) (*entity.NotificationInboxItem, error) {
+	item, err := uc.inboxRepo.GetByID(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil || item.UserID != userID {
+		return nil, errors.New("notification not found")
+	}
+	return item, nil
+}
+
+// toDTO convierte una notificación de la bandeja a DTO de respuesta
+func (uc *NotificationInboxUseCase) toDTO(item *entity.NotificationInboxItem) *dto.NotificationInboxItemResponse {
+	return &dto.NotificationInboxItemResponse{
+		ID:              item.ID,
+		BankAccountID:   item.BankAccountID,
+		Channel:         item.Channel,
+		Message:         item.Message,
+		Sender:          item.Sender,
+		ReceivedAt:      item.ReceivedAt,
+		Mode:            item.Mode,
+		AccountID:       item.AccountID,
+		CategoryID:      item.CategoryID,
+		Status:          item.Status,
+		StatusReason:    item.StatusReason,
+		ReasonCode:      item.ReasonCode,
+		PatternID:       item.PatternID,
+		Confidence:      item.Confidence,
+		ExtractedData:   item.GetExtractedData(),
+		Amount:          item.Amount,
+		Currency:        item.Currency,
+		TransactionDate: item.TransactionDate,
+		Attempts:        item.Attempts,
+		LastProcessedAt: item.LastProcessedAt,
+		TransactionID:   item.TransactionID,
+		ExpenseID:       item.ExpenseID,
+		UpdatedAt:       item.UpdatedAt,
+	}
+}
+
+// newInboxItem crea la entrada de bandeja de una notificación recién recibida
+func newInboxItem(userID uint, req *dto.ProcessNotificationRequest) *entity.NotificationInboxItem {
+	mode := req.Mode
+	if mode == "" {
+		mode = entity.NotificationIngestionModePreview
+	}
+
+	item := &entity.NotificationInboxItem{
+		UserID:          userID,
+		Channel:         req.Channel,
+		Message:         req.Message,
+		Sender:          req.Sender,
+		ReceivedAt:      notificationReceivedAt(req),
+		SkipSenderCheck: req.SkipSenderCheck,
+		Mode:            mode,
+		AccountID:       req.AccountID,
+		CategoryID:      req.CategoryID,
+		Status:          entity.NotificationInboxStatusUnmatched,
+	}
+	if req.BankAccountID != 0 {
+		bankAccountID := req.BankAccountID
+		item.BankAccountID = &bankAccountID
+	}
+	return item
+}
+
+// notificationReceivedAt retorna la hora de recepción indicada por el cliente o la hora actual
+func notificationReceivedAt(req *dto.ProcessNotificationRequest) time.Time {
+	if req.ReceivedAt != nil && !req.ReceivedAt.IsZero() {
+		return *req.ReceivedAt
+	}
+	return time.Now()
+}
+
+// applyReviewParams reemplaza los parámetros de ingesta guardados por los indicados en la revisión
+func applyReviewParams(item *entity.NotificationInboxItem, req *dto.ReviewNotificationInboxRequest) {
+	if req == nil {
+		return
+	}
+	if req.BankAccountID != nil {
+		item.BankAccountID = req.BankAccountID
+	}
+	if req.Mode != "" {
+		item.Mode = req.Mode
+	}
+	if req.AccountID != nil {
+		item.AccountID = req.AccountID
+	}
+	if req.CategoryID != nil {
+		item.CategoryID = req.CategoryID
+	}
+}
+
+// applyRoutingError marca la notificación como no enrutada para que el usuario asigne la cuenta desde la bandeja
+func applyRoutingError(item *entity.NotificationInboxItem, routingErr error) {
+	now := time.Now()
+	item.Attempts++
+	item.LastProcessedAt = &now
+	item.Status = entity.NotificationInboxStatusUnrouted
+	item.StatusReason = routingErr.Error()
+}
+
+// applyInboxResult guarda en la notificación el resultado de procesarla y calcula su estado
+func applyInboxResult(item *entity.NotificationInboxItem, response *dto.ProcessedNotificationResponse, processErr error) {
+	now := time.Now()
+	item.Attempts++
+	item.LastProcessedAt = &now
+	item.StatusReason = ""
+	if processErr != nil {
+		item.StatusReason = processErr.Error()
+	}
+
+	if response == nil {
+		return
+	}
+
+	item.PatternID = response.PatternID
+	item.Confidence = response.Confidence
+	item.Amount = response.Amount
+	item.Currency = response.Currency
+	item.TransactionDate = response.TransactionDate
+	item.TransactionID = response.TransactionID
+	item.ExpenseID = response.ExpenseID
+	item.NotificationID = response.NotificationID
+	item.ReasonCode = response.ReasonCode
+	_ = item.SetExtractedData(response.ExtractedData)
+
+	switch {
+	case response.Decision == entity.NotificationDecisionIgnored:
+		item.Status = entity.NotificationInboxStatusIgnored
+		item.StatusReason = string(response.ReasonCode)
+	case response.Decision == entity.NotificationDecisionParked:
+		item.Status = entity.NotificationInboxStatusParked
+		item.StatusReason = string(response.ReasonCode)
+	case response.PatternID == nil:
+		item.Status = entity.NotificationInboxStatusUnmatched
+	case response.Duplicate:
+		// El evento ya generó un movimiento por otro canal
+		item.Status = entity.NotificationInboxStatusIgnored
+		item.StatusReason = "duplicate notification"
+		item.TransactionID = nil
+		item.ExpenseID = nil
+	case item.HasMovement() && response.ValidationStatus == entity.ValidationStatusPending:
+		item.Status = entity.NotificationInboxStatusPendingReview
+	case item.HasMovement():
+		item.Status = entity.NotificationInboxStatusConverted
+	default:
+		item.Status = entity.NotificationInboxStatusParsed
+	}
+}
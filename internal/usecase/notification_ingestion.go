@@ -0,0 +1,506 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/keywords"
+	"github.com/nick130920/fintech-backend/pkg/money"
+	"github.com/nick130920/fintech-backend/pkg/normalizer"
+)
+
+// reversalLookback es la antigüedad máxima de la transacción que puede anular un reverso bancario
+const reversalLookback = 90 * 24 * time.Hour
+
+// notificationMovement contiene los datos normalizados de una notificación listos para persistir
+type notificationMovement struct {
+	Amount           money.Amount
+	Currency         string
+	Date             time.Time
+	Description      string
+	Merchant         string
+	Reference        string
+	Kind             entity.TransactionKind
+	Balance          *money.Amount // Saldo disponible reportado por el banco (nil si no se extrajo)
+	BalanceCurrency  string        // Moneda del saldo reportado
+	Confidence       float64
+	ValidationStatus entity.ValidationStatus
+}
+
+// ingestNotification convierte los datos extraídos en una transacción o gasto según el modo solicitado
+func (uc *BankNotificationPatternUseCase) ingestNotification(
+	userID uint,
+	bankAccount *entity.BankAccount,
+	pattern *entity.BankNotificationPattern,
+	req *dto.ProcessNotificationRequest,
+	movement *notificationMovement,
+	response *dto.ProcessedNotificationResponse,
+) error {
+	switch response.Mode {
+	case entity.NotificationIngestionModeTransaction:
+		if movement.Kind.IsReversal() {
+			transaction, original, err := uc.ingestReversal(userID, bankAccount, pattern, req, movement)
+			if err != nil {
+				return err
+			}
+			response.TransactionID = &transaction.ID
+			response.TransactionType = transaction.Type
+			response.ReversedTransactionID = &original.ID
+			break
+		}
+
+		transaction, err := uc.ingestAsTransaction(userID, bankAccount, pattern, req, movement)
+		if err != nil {
+			return err
+		}
+		response.TransactionID = &transaction.ID
+	case entity.NotificationIngestionModeExpense:
+		// Los reversos y devoluciones cancelan el gasto que anulan
+		if movement.Kind.IsReversal() || movement.Kind == entity.TransactionKindRefund {
+			original, err := uc.ingestExpenseReversal(userID, bankAccount, movement)
+			if err != nil {
+				return err
+			}
+			response.ReversedExpenseID = &original.ID
+			break
+		}
+
+		// Solo compras y retiros consumen presupuesto; el resto de movimientos requiere modo transaction
+		if !movement.Kind.IsSpending() {
+			return fmt.Errorf("notification kind %s cannot be recorded as an expense", movement.Kind)
+		}
+		expense, err := uc.ingestAsExpense(userID, bankAccount, pattern, req, movement)
+		if err != nil {
+			return err
+		}
+		response.ExpenseID = &expense.ID
+	default:
+		return fmt.Errorf("unsupported ingestion mode: %s", response.Mode)
+	}
+
+	response.ValidationStatus = movement.ValidationStatus
+	response.RequiresValidation = movement.ValidationStatus == entity.ValidationStatusPending
+
+	return nil
+}
+
+// notificationFormat construye las convenciones de normalización a partir de
+// la moneda de la cuenta bancaria y el locale/zona horaria del usuario
+func (uc *BankNotificationPatternUseCase) notificationFormat(userID uint, bankAccount *entity.BankAccount) normalizer.Format {
+	locale, timezone := "", ""
+	if user, err := uc.userRepo.GetByID(userID); err == nil {
+		locale, timezone = user.Locale, user.Timezone
+	}
+
+	return normalizer.NewFormat(bankAccount.Currency, locale, timezone)
+}
+
+// buildNotificationMovement normaliza los datos extraídos por un patrón
+func (uc *BankNotificationPatternUseCase) buildNotificationMovement(
+	pattern *entity.BankNotificationPattern,
+	extractedData map[string]interface{},
+	confidence float64,
+	format normalizer.Format,
+) (*notificationMovement, error) {
+	rawAmount, _ := extractedData["amount"].(string)
+	if rawAmount == "" {
+		return nil, errors.New("amount could not be extracted from notification")
+	}
+
+	amount, err := normalizer.ParseAmount(rawAmount, format)
+	if err != nil || !amount.Amount.IsPositive() {
+		return nil, fmt.Errorf("invalid extracted amount: %s", rawAmount)
+	}
+
+	// Si la fecha no se pudo extraer o interpretar, usar la fecha de recepción
+	now := time.Now().In(format.Location)
+	date := now
+	if rawDate, ok := extractedData["date"].(string); ok && rawDate != "" {
+		if parsedDate, err := normalizer.ParseDate(rawDate, format, now); err == nil {
+			date = parsedDate
+		}
+	}
+
+	merchant, _ := extractedData["merchant"].(string)
+	reference, _ := extractedData["reference"].(string)
+	kindText, _ := extractedData["type"].(string)
+
+	// El saldo es opcional: si no se puede interpretar, la notificación se procesa sin conciliar
+	var balance *money.Amount
+	var balanceCurrency string
+	if rawBalance, ok := extractedData["balance"].(string); ok && rawBalance != "" {
+		if parsedBalance, err := normalizer.ParseAmount(rawBalance, format); err == nil {
+			balance = &parsedBalance.Amount
+			balanceCurrency = parsedBalance.Currency
+		}
+	}
+	description, _ := extractedData["description"].(string)
+	if description == "" {
+		description = merchant
+	}
+	if description == "" {
+		description = pattern.GetDisplayName()
+	}
+
+	return &notificationMovement{
+		Amount:           amount.Amount,
+		Currency:         amount.Currency,
+		Date:             date,
+		Description:      description,
+		Merchant:         merchant,
+		Reference:        reference,
+		Kind:             pattern.ResolveTransactionKind(kindText),
+		Balance:          balance,
+		BalanceCurrency:  balanceCurrency,
+		Confidence:       confidence,
+		ValidationStatus: pattern.GetValidationStatus(confidence),
+	}, nil
+}
+
+// ingestAsTransaction crea una transacción a partir de una notificación y actualiza el balance de la cuenta
+func (uc *BankNotificationPatternUseCase) ingestAsTransaction(
+	userID uint,
+	bankAccount *entity.BankAccount,
+	pattern *entity.BankNotificationPattern,
+	req *dto.ProcessNotificationRequest,
+	movement *notificationMovement,
+) (*entity.Transaction, error) {
+	account, err := uc.notificationAccount(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := &entity.Transaction{
+		UserID:           userID,
+		AccountID:        account.ID,
+		BankAccountID:    &bankAccount.ID,
+		Type:             movement.Kind.TransactionType(),
+		Status:           movementTransactionStatus(movement),
+		Amount:           movement.Amount,
+		Description:      movement.Description,
+		CategoryID:       req.CategoryID,
+		TransactionDate:  movement.Date,
+		Location:         movement.Merchant,
+		Reference:        movement.Reference,
+		Currency:         movementCurrency(movement, account.Currency),
+		ExchangeRate:     1.0,
+		Source:           entity.TransactionSourceNotification,
+		ValidationStatus: movement.ValidationStatus,
+		RawNotification:  req.Message,
+		AIConfidence:     movement.Confidence,
+		PatternID:        &pattern.ID,
+	}
+
+	// Las autorizaciones de tarjeta retienen fondos hasta que el banco las confirma o vencen
+	if movement.Kind.IsAuthorization() {
+		transaction.PlaceHold()
+	}
+
+	// Guardar la transacción y actualizar balances en una sola transacción de DB
+	if err := uc.transactionRepo.CreateWithBalanceUpdate(transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction from notification: %w", err)
+	}
+
+	return transaction, nil
+}
+
+// ingestReversal anula el movimiento original de un reverso bancario. Si la transacción original
+// sigue pendiente se cancela; si ya se completó se registra una transacción que la compensa.
+// Retorna la transacción resultante y la original.
+func (uc *BankNotificationPatternUseCase) ingestReversal(
+	userID uint,
+	bankAccount *entity.BankAccount,
+	pattern *entity.BankNotificationPattern,
+	req *dto.ProcessNotificationRequest,
+	movement *notificationMovement,
+) (*entity.Transaction, *entity.Transaction, error) {
+	candidates, err := uc.transactionRepo.GetReversalCandidates(bankAccount.ID, movement.Amount, movement.Date.Add(-reversalLookback))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get reversal candidates: %w", err)
+	}
+
+	var owned []*entity.Transaction
+	for _, candidate := range candidates {
+		if candidate.UserID == userID {
+			owned = append(owned, candidate)
+		}
+	}
+
+	references := make([]string, len(owned))
+	places := make([]string, len(owned))
+	for i, candidate := range owned {
+		references[i], places[i] = candidate.Reference, candidate.Location
+	}
+	match := findReversedMovement(references, places, movement)
+	if match < 0 {
+		return nil, nil, errors.New("original transaction for reversal not found")
+	}
+	original := owned[match]
+
+	if original.CanBeCancelled() {
+		if err := uc.transactionUC.Cancel(userID, original.ID); err != nil {
+			return nil, nil, fmt.Errorf("failed to cancel reversed transaction: %w", err)
+		}
+		original.Cancel()
+		return original, original, nil
+	}
+
+	offset := &entity.Transaction{
+		UserID:           userID,
+		AccountID:        original.AccountID,
+		BankAccountID:    &bankAccount.ID,
+		Type:             entity.TransactionTypeIncome,
+		Status:           movementTransactionStatus(movement),
+		Amount:           original.Amount,
+		Description:      "Reverso: " + original.Description,
+		CategoryID:       original.CategoryID,
+		TransactionDate:  movement.Date,
+		Location:         original.Location,
+		Reference:        movement.Reference,
+		Currency:         original.Currency,
+		ExchangeRate:     original.ExchangeRate,
+		Source:           entity.TransactionSourceNotification,
+		ValidationStatus: movement.ValidationStatus,
+		RawNotification:  req.Message,
+		AIConfidence:     movement.Confidence,
+		PatternID:        &pattern.ID,
+		ReversalOfID:     &original.ID,
+	}
+
+	// La compensación mueve el dinero en sentido contrario al original
+	switch original.Type {
+	case entity.TransactionTypeIncome:
+		offset.Type = entity.TransactionTypeExpense
+	case entity.TransactionTypeTransfer:
+		if original.ToAccountID != nil {
+			offset.Type = entity.TransactionTypeTransfer
+			offset.AccountID = *original.ToAccountID
+			offset.ToAccountID = &original.AccountID
+		}
+	}
+
+	if err := uc.transactionRepo.CreateWithBalanceUpdate(offset); err != nil {
+		return nil, nil, fmt.Errorf("failed to create reversal transaction: %w", err)
+	}
+
+	return offset, original, nil
+}
+
+// ingestExpenseReversal cancela el gasto que anula un reverso o devolución bancaria y recalcula
+// los montos gastados de su presupuesto. Retorna el gasto cancelado.
+func (uc *BankNotificationPatternUseCase) ingestExpenseReversal(
+	userID uint,
+	bankAccount *entity.BankAccount,
+	movement *notificationMovement,
+) (*entity.Expense, error) {
+	candidates, err := uc.expenseRepo.GetReversalCandidates(bankAccount.ID, movement.Amount, movement.Date.Add(-reversalLookback))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reversal candidates: %w", err)
+	}
+
+	var owned []*entity.Expense
+	for _, candidate := range candidates {
+		if candidate.UserID == userID {
+			owned = append(owned, candidate)
+		}
+	}
+
+	references := make([]string, len(owned))
+	places := make([]string, len(owned))
+	for i, candidate := range owned {
+		references[i], places[i] = candidate.Reference, candidate.Merchant
+	}
+	match := findReversedMovement(references, places, movement)
+	if match < 0 {
+		return nil, errors.New("original expense for reversal not found")
+	}
+	original := owned[match]
+
+	original.Cancel()
+	if err := uc.expenseRepo.CancelWithBudgetUpdate(original); err != nil {
+		return nil, fmt.Errorf("failed to cancel reversed expense: %w", err)
+	}
+
+	return original, nil
+}
+
+// findReversedMovement elige el movimiento que anula un reverso entre los candidatos del mismo monto,
+// dados la referencia y el comercio de cada uno: primero por referencia, luego por comercio y, si el
+// reverso no indica comercio, el más reciente. Retorna -1 si ninguno corresponde.
+func findReversedMovement(references, places []string, movement *notificationMovement) int {
+	if reference := keywords.Normalize(movement.Reference); reference != "" {
+		for i, candidate := range references {
+			if keywords.Normalize(candidate) == reference {
+				return i
+			}
+		}
+	}
+
+	merchant := keywords.Normalize(movement.Merchant)
+	if merchant != "" {
+		for i, candidate := range places {
+			place := keywords.Normalize(candidate)
+			if place != "" && (strings.Contains(place, merchant) || strings.Contains(merchant, place)) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	if len(places) == 0 {
+		return -1
+	}
+	return 0
+}
+
+// notificationAccount obtiene la cuenta en la que se registra una transacción desde notificación
+func (uc *BankNotificationPatternUseCase) notificationAccount(userID uint, req *dto.ProcessNotificationRequest) (*entity.Account, error) {
+	if req.AccountID == nil {
+		return nil, errors.New("account_id is required to create a transaction")
+	}
+
+	// Verificar que la cuenta existe, pertenece al usuario y está activa
+	account, err := uc.accountRepo.GetByID(*req.AccountID)
+	if err != nil || account.UserID != userID {
+		return nil, errors.New("account not found")
+	}
+	if !account.IsActive {
+		return nil, errors.New("origin account is not active")
+	}
+
+	return account, nil
+}
+
+// movementTransactionStatus retorna el estado inicial de la transacción según la validación del movimiento.
+// Un movimiento pendiente de revisión no es una autorización: el banco ya lo aplicó y solo falta que el
+// usuario confirme la extracción, por lo que se registra en el libro y su rechazo lo revierte.
+func movementTransactionStatus(movement *notificationMovement) entity.TransactionStatus {
+	if movement.ValidationStatus == entity.ValidationStatusPending {
+		return entity.TransactionStatusPending
+	}
+	return entity.TransactionStatusCompleted
+}
+
+// ingestAsExpense crea un gasto contra la asignación de presupuesto vigente de la categoría
+func (uc *BankNotificationPatternUseCase) ingestAsExpense(
+	userID uint,
+	bankAccount *entity.BankAccount,
+	pattern *entity.BankNotificationPattern,
+	req *dto.ProcessNotificationRequest,
+	movement *notificationMovement,
+) (*entity.Expense, error) {
+	if req.CategoryID == nil {
+		return nil, errors.New("category_id is required to create an expense")
+	}
+
+	// Verificar que la categoría pertenece al usuario o es del sistema
+	category, err := uc.categoryRepo.GetByID(*req.CategoryID)
+	if err != nil {
+		return nil, errors.New("category not found")
+	}
+	if !category.IsSystemCategory() && category.UserID != nil && *category.UserID != userID {
+		return nil, errors.New("category not found")
+	}
+
+	// Usar el presupuesto del mes del movimiento o, en su defecto, el actual
+	budget, err := uc.budgetRepo.GetByUserAndMonth(userID, movement.Date.Year(), int(movement.Date.Month()))
+	if err != nil {
+		budget, err = uc.budgetRepo.GetCurrentBudget(userID)
+		if err != nil {
+			return nil, errors.New("no budget found for notification date")
+		}
+	}
+
+	allocation, err := uc.budgetRepo.GetAllocationByBudgetAndCategory(budget.ID, category.ID)
+	if err != nil {
+		return nil, fmt.Errorf("category not allocated in budget: %w", err)
+	}
+
+	status := entity.ExpenseStatusConfirmed
+	if movement.ValidationStatus == entity.ValidationStatusPending {
+		status = entity.ExpenseStatusPending
+	}
+
+	source := entity.ExpenseSourceNotification
+	if req.Channel == entity.NotificationChannelSMS {
+		source = entity.ExpenseSourceSMS
+	}
+
+	expense := &entity.Expense{
+		UserID:       userID,
+		BudgetID:     budget.ID,
+		CategoryID:   category.ID,
+		AllocationID: allocation.ID,
+		Amount:       movement.Amount,
+		Description:  movement.Description,
+		Date:         movement.Date,
+		Source:       source,
+		Status:       status,
+		Merchant:     movement.Merchant,
+		Reference:    movement.Reference,
+		RawData:      req.Message,
+		Confidence:   movement.Confidence,
+		PatternID:    &pattern.ID,
+		Currency:     movementCurrency(movement, bankAccount.Currency),
+		ExchangeRate: 1.0,
+	}
+
+	// Guardar el gasto y recalcular montos gastados en una sola transacción de DB
+	if err := uc.expenseRepo.CreateWithBudgetUpdate(expense); err != nil {
+		return nil, fmt.Errorf("failed to create expense from notification: %w", err)
+	}
+
+	return expense, nil
+}
+
+// recordFingerprint guarda la huella de la notificación. Un error no revierte el movimiento ya
+// creado, pero impide detectar las repeticiones de este evento por otros canales.
+func (uc *BankNotificationPatternUseCase) recordFingerprint(fingerprint *entity.NotificationFingerprint, response *dto.ProcessedNotificationResponse) {
+	if err := uc.dedupUC.Record(fingerprint); err != nil {
+		log.Printf("Warning: Failed to record notification fingerprint: %v", err)
+		return
+	}
+	response.NotificationID = &fingerprint.ID
+}
+
+// reconcileBalance concilia el saldo reportado por la notificación con los movimientos registrados.
+// Un error no revierte el movimiento ya creado; la próxima notificación con saldo vuelve a conciliar.
+func (uc *BankNotificationPatternUseCase) reconcileBalance(
+	bankAccount *entity.BankAccount,
+	movement *notificationMovement,
+	fingerprint *entity.NotificationFingerprint,
+	response *dto.ProcessedNotificationResponse,
+) {
+	if movement.Balance == nil {
+		return
+	}
+
+	discrepancy, err := uc.reconciliationUC.Reconcile(bankAccount, *movement.Balance, movement.BalanceCurrency, fingerprint)
+	if discrepancy != nil {
+		response.BalanceDiscrepancyID = &discrepancy.ID
+	}
+	if err != nil {
+		log.Printf("Warning: Failed to reconcile balance of bank account %d: %v", bankAccount.ID, err)
+	}
+}
+
+// applyDuplicate indica en la respuesta el movimiento existente al que se enlazó la notificación
+func applyDuplicate(response *dto.ProcessedNotificationResponse, duplicate *notificationDuplicate) {
+	response.Duplicate = true
+	response.DuplicateOfID = duplicate.OriginalID
+	response.TransactionID = duplicate.TransactionID
+	response.ExpenseID = duplicate.ExpenseID
+}
+
+// movementCurrency retorna la moneda detectada en la notificación o la de la cuenta si no se indicó
+func movementCurrency(movement *notificationMovement, fallback string) string {
+	if movement.Currency != "" {
+		return movement.Currency
+	}
+	return fallback
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+func TestFindReversedMovement(t *testing.T) {
+	references := []string{"", "AUT-123", ""}
+	places := []string{"OXXO Centro", "Farmacia", "Walmart Express"}
+
+	tests := []struct {
+		name      string
+		reference string
+		merchant  string
+		want      int
+	}{
+		{"by reference", "aut 123", "OXXO", 1},
+		{"unknown reference falls back to merchant", "AUT-999", "walmart", 2},
+		{"truncated merchant", "", "OXXO CENTRO SUC 12", 0},
+		{"unknown merchant", "", "Soriana", -1},
+		{"most recent without data", "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movement := &notificationMovement{Reference: tt.reference, Merchant: tt.merchant}
+			if got := findReversedMovement(references, places, movement); got != tt.want {
+				t.Fatalf("findReversedMovement = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if got := findReversedMovement(nil, nil, &notificationMovement{}); got != -1 {
+		t.Fatalf("findReversedMovement without candidates = %d, want -1", got)
+	}
+}
+
+func TestIngestAsTransactionPlacesHolds(t *testing.T) {
+	accountID := uint(3)
+	uc := &BankNotificationPatternUseCase{
+		accountRepo:     &fakeAccountRepo{accounts: map[uint]*entity.Account{3: {ID: 3, UserID: 1, IsActive: true, Currency: "MXN"}}},
+		transactionRepo: &fakeTransactionRepo{transactions: map[uint]*entity.Transaction{}},
+	}
+	bankAccount := &entity.BankAccount{ID: 10, UserID: 1}
+	pattern := &entity.BankNotificationPattern{ID: 5}
+	req := &dto.ProcessNotificationRequest{AccountID: &accountID}
+
+	tests := []struct {
+		name       string
+		kind       entity.TransactionKind
+		validation entity.ValidationStatus
+		wantStatus entity.TransactionStatus
+		wantHold   bool
+	}{
+		{"authorization", entity.TransactionKindAuthorization, entity.ValidationStatusAuto, entity.TransactionStatusPending, true},
+		{"authorization pending review", entity.TransactionKindAuthorization, entity.ValidationStatusPending, entity.TransactionStatusPending, true},
+		{"purchase", entity.TransactionKindPurchase, entity.ValidationStatusAuto, entity.TransactionStatusCompleted, false},
+		// El banco ya aplicó el movimiento: solo falta confirmar la extracción
+		{"purchase pending review", entity.TransactionKindPurchase, entity.ValidationStatusPending, entity.TransactionStatusPending, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movement := &notificationMovement{
+				Amount: money.FromUnits(250), Date: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+				Kind: tt.kind, ValidationStatus: tt.validation,
+			}
+			transaction, err := uc.ingestAsTransaction(1, bankAccount, pattern, req, movement)
+			if err != nil {
+				t.Fatalf("ingestAsTransaction error: %v", err)
+			}
+			if transaction.Status != tt.wantStatus || transaction.IsHold() != tt.wantHold || transaction.Type != entity.TransactionTypeExpense {
+				t.Fatalf("transaction = %+v", transaction)
+			}
+			if tt.wantHold && transaction.HoldAmount != movement.Amount {
+				t.Fatalf("hold amount = %s, want %s", transaction.HoldAmount, movement.Amount)
+			}
+		})
+	}
+
+	if kind, ok := entity.ClassifyTransactionKind("Preautorización de compra"); !ok || kind != entity.TransactionKindAuthorization {
+		t.Fatalf("ClassifyTransactionKind = %q, %v", kind, ok)
+	}
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/extractor"
+)
+
+// Forma en que se identificó la cuenta bancaria de una notificación sin bank_account_id
+const (
+	notificationRouteCardDigits = "card_digits" // Últimos dígitos de tarjeta o cuenta en el mensaje
+	notificationRoutePattern    = "pattern"     // Única cuenta con un patrón que reconoce el mensaje
+)
+
+// routeNotification identifica la cuenta bancaria de una notificación recibida sin bank_account_id.
+// Primero busca los últimos dígitos de tarjeta o cuenta mencionados en el mensaje; si no hay o no
+// corresponden a ninguna cuenta, evalúa los patrones de cada cuenta con notificaciones habilitadas.
+func (uc *BankNotificationPatternUseCase) routeNotification(userID uint, req *dto.ProcessNotificationRequest) (*entity.BankAccount, string, error) {
+	accounts, err := uc.bankAccountRepo.GetNotificationEnabledAccounts(userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get bank accounts: %w", err)
+	}
+
+	for _, lastFour := range extractor.FindCardLast4(req.Message) {
+		account, err := uc.findAccountByLastFour(userID, lastFour, accounts)
+		if err != nil {
+			return nil, "", err
+		}
+		if account != nil {
+			return account, notificationRouteCardDigits, nil
+		}
+	}
+
+	var matched []*entity.BankAccount
+	for _, account := range accounts {
+		patternSet, err := uc.getPatternSet(account.ID, req.Channel)
+		if err != nil {
+			return nil, "", err
+		}
+
+		lastFour, ok := routingMatch(uc.rankPatterns(patternSet, req.Message))
+		if !ok {
+			continue
+		}
+		if lastFour != "" {
+			// El patrón de la cuenta extrajo los dígitos: solo vale si son los de esta cuenta
+			if account.MatchesLastFour(lastFour) {
+				return account, notificationRouteCardDigits, nil
+			}
+			continue
+		}
+		matched = append(matched, account)
+	}
+
+	switch len(matched) {
+	case 0:
+		return nil, "", errors.New("bank account could not be resolved from notification")
+	case 1:
+		return matched[0], notificationRoutePattern, nil
+	default:
+		return nil, "", errors.New("notification matches several bank accounts")
+	}
+}
+
+// findAccountByLastFour busca la cuenta con notificaciones habilitadas que termina en los dígitos indicados.
+// Se intenta la máscara exacta y, si los usuarios guardaron otro formato, se comparan los últimos dígitos.
+// Si varias cuentas terminan en los mismos dígitos no se puede decidir por ellos y se retorna un error.
+func (uc *BankNotificationPatternUseCase) findAccountByLastFour(userID uint, lastFour string, accounts []*entity.BankAccount) (*entity.BankAccount, error) {
+	masked, err := uc.bankAccountRepo.GetAllByAccountNumberMask(userID, "****"+lastFour, lastFour)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*entity.BankAccount
+	for _, account := range masked {
+		if account.CanReceiveNotifications() {
+			candidates = append(candidates, account)
+		}
+	}
+	if len(candidates) == 0 {
+		for _, account := range accounts {
+			if account.MatchesLastFour(lastFour) {
+				candidates = append(candidates, account)
+			}
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, nil
+	case 1:
+		return candidates[0], nil
+	default:
+		return nil, errors.New("notification matches several bank accounts")
+	}
+}
+
+// routingMatch indica si los patrones de una cuenta reconocen el mensaje. Solo cuentan los patrones
+// específicos que extrajeron el monto; los patrones por defecto aceptan cualquier mensaje.
+// Retorna además los últimos dígitos extraídos por el patrón, si los hay.
+func routingMatch(ranked []*rankedPattern) (string, bool) {
+	for _, candidate := range ranked {
+		if candidate.isDefault {
+			continue
+		}
+		if amount, _ := candidate.extractedData[extractor.FieldAmount].(string); amount == "" {
+			continue
+		}
+		lastFour, _ := candidate.extractedData[extractor.FieldCardLast4].(string)
+		return lastFour, true
+	}
+	return "", false
+}
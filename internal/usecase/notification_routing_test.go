@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+)
+
+// fakeBankAccountRepo implementa solo las búsquedas de cuentas de repo.BankAccountRepo
+type fakeBankAccountRepo struct {
+	repo.BankAccountRepo
+	accounts []*entity.BankAccount
+}
+
+func (r *fakeBankAccountRepo) GetAllByAccountNumberMask(userID uint, masks ...string) ([]*entity.BankAccount, error) {
+	var found []*entity.BankAccount
+	for _, account := range r.accounts {
+		for _, mask := range masks {
+			if account.UserID == userID && account.AccountNumberMask == mask {
+				found = append(found, account)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeBankAccountRepo) GetNotificationEnabledAccounts(userID uint) ([]*entity.BankAccount, error) {
+	var found []*entity.BankAccount
+	for _, account := range r.accounts {
+		if account.UserID == userID && account.CanReceiveNotifications() {
+			found = append(found, account)
+		}
+	}
+	return found, nil
+}
+
+// fakeInboxRepo guarda las notificaciones de la bandeja en memoria
+type fakeInboxRepo struct {
+	repo.NotificationInboxRepo
+	items map[uint]*entity.NotificationInboxItem
+}
+
+func (r *fakeInboxRepo) Create(item *entity.NotificationInboxItem) error {
+	item.ID = uint(len(r.items) + 1)
+	r.items[item.ID] = item
+	return nil
+}
+
+func (r *fakeInboxRepo) Update(item *entity.NotificationInboxItem) error {
+	r.items[item.ID] = item
+	return nil
+}
+
+func (r *fakeInboxRepo) GetByID(id uint) (*entity.NotificationInboxItem, error) {
+	return r.items[id], nil
+}
+
+func TestProcessNotificationKeepsUnroutedNotifications(t *testing.T) {
+	accounts := []*entity.BankAccount{
+		{ID: 1, UserID: 1, AccountNumberMask: "****1234", IsActive: true, IsNotificationEnabled: true},
+		{ID: 2, UserID: 1, AccountNumberMask: "1234", IsActive: true, IsNotificationEnabled: true},
+	}
+	inboxRepo := &fakeInboxRepo{items: map[uint]*entity.NotificationInboxItem{}}
+	uc := &BankNotificationPatternUseCase{bankAccountRepo: &fakeBankAccountRepo{accounts: accounts}, inboxRepo: inboxRepo}
+
+	_, err := uc.ProcessNotification(1, &dto.ProcessNotificationRequest{
+		Channel: entity.NotificationChannelSMS,
+		Message: "Compra por $250.00 en OXXO con tu tarjeta ****1234",
+	})
+	if err == nil || err.Error() != "notification matches several bank accounts" {
+		t.Fatalf("ProcessNotification error = %v", err)
+	}
+
+	item := inboxRepo.items[1]
+	if item == nil || item.IsRouted() || item.Status != entity.NotificationInboxStatusUnrouted || item.StatusReason != err.Error() || item.Attempts != 1 {
+		t.Fatalf("inbox item = %+v", item)
+	}
+
+	// Reprocesarla sin asignar la cuenta no tiene con qué patrones evaluarla
+	inboxUC := NewNotificationInboxUseCase(inboxRepo, uc.bankAccountRepo, uc, nil, nil)
+	if _, err := inboxUC.RetryItem(1, item.ID, nil); err == nil || err.Error() != "bank account is required to process the notification" {
+		t.Fatalf("RetryItem error = %v", err)
+	}
+}
+
+func TestFindAccountByLastFour(t *testing.T) {
+	enabled := func(id uint, mask string) *entity.BankAccount {
+		return &entity.BankAccount{ID: id, UserID: 1, AccountNumberMask: mask, IsActive: true, IsNotificationEnabled: true}
+	}
+	disabled := enabled(3, "****5678")
+	disabled.IsNotificationEnabled = false
+	accounts := []*entity.BankAccount{
+		enabled(1, "****1234"),
+		enabled(2, "1234"),
+		disabled,
+		enabled(4, "****5678"),
+		enabled(5, "**** 9012"),
+		enabled(6, "xx9012"),
+		enabled(7, "**** 3456"),
+	}
+	uc := &BankNotificationPatternUseCase{bankAccountRepo: &fakeBankAccountRepo{accounts: accounts}}
+
+	tests := []struct {
+		name     string
+		lastFour string
+		wantID   uint
+		wantErr  bool
+	}{
+		{name: "same digits in both mask formats", lastFour: "1234", wantErr: true},
+		{name: "skips accounts without notifications", lastFour: "5678", wantID: 4},
+		{name: "other mask format", lastFour: "3456", wantID: 7},
+		{name: "same digits in other formats", lastFour: "9012", wantErr: true},
+		{name: "unknown digits", lastFour: "0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, err := uc.findAccountByLastFour(1, tt.lastFour, accounts)
+			if tt.wantErr {
+				if err == nil || err.Error() != "notification matches several bank accounts" {
+					t.Fatalf("findAccountByLastFour error = %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findAccountByLastFour error: %v", err)
+			}
+			if (account == nil && tt.wantID != 0) || (account != nil && account.ID != tt.wantID) {
+				t.Fatalf("findAccountByLastFour = %+v, want account %d", account, tt.wantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/patterncache"
+)
+
+// TestPattern prueba un borrador de patrón contra mensajes de ejemplo sin guardarlo
+func (uc *BankNotificationPatternUseCase) TestPattern(userID uint, req *dto.TestPatternRequest) (*dto.PatternTestResponse, error) {
+	if req.Pattern == nil {
+		return nil, errors.New("pattern is required")
+	}
+
+	// Verificar que la cuenta bancaria existe y pertenece al usuario
+	bankAccount, err := uc.bankAccountRepo.GetByID(req.Pattern.BankAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank account: %w", err)
+	}
+	if bankAccount == nil {
+		return nil, errors.New("bank account not found")
+	}
+	if bankAccount.UserID != userID {
+		return nil, errors.New("unauthorized access to bank account")
+	}
+
+	if err := uc.validateRegexPatterns(req.Pattern); err != nil {
+		return nil, fmt.Errorf("invalid regex patterns: %w", err)
+	}
+
+	pattern, err := uc.buildPattern(userID, req.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.runPatternTest(pattern, req.Messages), nil
+}
+
+// TestSavedPattern prueba un patrón guardado contra mensajes de ejemplo.
+// No registra coincidencias en las estadísticas ni crea movimientos.
+func (uc *BankNotificationPatternUseCase) TestSavedPattern(userID, patternID uint, req *dto.TestPatternRequest) (*dto.PatternTestResponse, error) {
+	pattern, err := uc.patternRepo.GetByID(patternID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pattern: %w", err)
+	}
+	if pattern == nil {
+		return nil, errors.New("pattern not found")
+	}
+	if pattern.UserID != userID {
+		return nil, errors.New("unauthorized access to pattern")
+	}
+
+	response := uc.runPatternTest(pattern, req.Messages)
+	response.PatternID = &pattern.ID
+	return response, nil
+}
+
+// runPatternTest aplica el patrón a cada mensaje con el mismo proceso usado al procesar notificaciones
+func (uc *BankNotificationPatternUseCase) runPatternTest(pattern *entity.BankNotificationPattern, messages []string) *dto.PatternTestResponse {
+	compiled := patterncache.Compile(pattern)
+
+	response := &dto.PatternTestResponse{
+		TotalMessages: len(messages),
+		Results:       make([]dto.PatternTestResult, len(messages)),
+	}
+
+	for i, message := range messages {
+		triggered, excluded := pattern.MatchedKeywords(message)
+		captures, expectedFields := uc.captureFields(compiled, message)
+		confidence := captureConfidence(captures, expectedFields)
+
+		result := dto.PatternTestResult{
+			Index:              i,
+			Message:            message,
+			KeywordsMatched:    pattern.MatchesKeywords(message),
+			TriggeredKeywords:  triggered,
+			ExcludedKeywords:   excluded,
+			Captures:           make([]dto.FieldCaptureResponse, 0, len(captures)),
+			ExtractedData:      make(map[string]interface{}),
+			Confidence:         confidence,
+			RequiresValidation: pattern.NeedsValidation(confidence),
+			ValidationStatus:   pattern.GetValidationStatus(confidence),
+		}
+
+		for field := range expectedFields {
+			capture, found := captures[field]
+			if !found {
+				result.MissingFields = append(result.MissingFields, field)
+				continue
+			}
+			result.ExtractedData[field] = capture.value
+			result.Captures = append(result.Captures, dto.FieldCaptureResponse{
+				Field:  field,
+				Value:  capture.value,
+				Source: capture.source,
+			})
+		}
+		sort.Slice(result.Captures, func(a, b int) bool { return result.Captures[a].Field < result.Captures[b].Field })
+		sort.Strings(result.MissingFields)
+
+		if result.KeywordsMatched && len(result.Captures) > 0 {
+			response.MatchedMessages++
+		}
+		response.Results[i] = result
+	}
+
+	return response
+}
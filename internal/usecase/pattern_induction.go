@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/pkg/extractor"
+	"github.com/nick130920/fintech-backend/pkg/patterncache"
+)
+
+// Valores de la propuesta de patrón inducido
+const (
+	inducedPatternPriority            = 100
+	inducedPatternConfidenceThreshold = 0.8
+)
+
+// InducePattern propone un patrón a partir de mensajes de ejemplo en los que el usuario marcó
+// los campos, y lo evalúa contra esos mismos ejemplos. La propuesta no se guarda.
+func (uc *BankNotificationPatternUseCase) InducePattern(userID uint, req *dto.InducePatternRequest) (*dto.InducedPatternResponse, error) {
+	// Verificar que la cuenta bancaria existe y pertenece al usuario
+	bankAccount, err := uc.bankAccountRepo.GetByID(req.BankAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank account: %w", err)
+	}
+	if bankAccount == nil {
+		return nil, errors.New("bank account not found")
+	}
+	if bankAccount.UserID != userID {
+		return nil, errors.New("unauthorized access to bank account")
+	}
+
+	examples := make([]extractor.Example, len(req.Examples))
+	for i, example := range req.Examples {
+		spans := make([]extractor.Span, len(example.Spans))
+		for j, span := range example.Spans {
+			spans[j] = extractor.Span{Field: span.Field, Value: span.Value}
+		}
+		examples[i] = extractor.Example{Message: example.Message, Spans: spans}
+	}
+
+	induction, err := extractor.Induce(examples)
+	if err != nil {
+		return nil, fmt.Errorf("invalid examples: %w", err)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = fmt.Sprintf("%s (%s)", bankAccount.BankName, req.Channel)
+	}
+
+	proposal := dto.CreateBankNotificationPatternRequest{
+		BankAccountID:       req.BankAccountID,
+		Name:                name,
+		Description:         fmt.Sprintf("Patrón generado a partir de %d ejemplos", len(req.Examples)),
+		Channel:             req.Channel,
+		MessagePattern:      induction.MessagePattern,
+		ExampleMessage:      req.Examples[0].Message,
+		KeywordsTrigger:     induction.Keywords,
+		AmountRegex:         induction.FieldRegexes[extractor.FieldAmount],
+		DateRegex:           induction.FieldRegexes[extractor.FieldDate],
+		DescriptionRegex:    induction.FieldRegexes[extractor.FieldDescription],
+		MerchantRegex:       induction.FieldRegexes[extractor.FieldMerchant],
+		BalanceRegex:        induction.FieldRegexes[extractor.FieldBalance],
+		RequiresValidation:  true,
+		ConfidenceThreshold: inducedPatternConfidenceThreshold,
+		Priority:            inducedPatternPriority,
+	}
+
+	score, results, err := uc.scoreInducedPattern(userID, &proposal, req.Examples)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.InducedPatternResponse{
+		Pattern:  proposal,
+		Score:    score,
+		Examples: results,
+	}, nil
+}
+
+// scoreInducedPattern aplica la propuesta a los ejemplos con el mismo proceso de extracción
+// usado al procesar notificaciones y compara el resultado con los campos marcados
+func (uc *BankNotificationPatternUseCase) scoreInducedPattern(
+	userID uint,
+	proposal *dto.CreateBankNotificationPatternRequest,
+	examples []dto.NotificationExample,
+) (dto.InductionScoreResponse, []dto.InductionExampleResult, error) {
+	pattern, err := uc.buildPattern(userID, proposal)
+	if err != nil {
+		return dto.InductionScoreResponse{}, nil, err
+	}
+	compiled := patterncache.Compile(pattern)
+
+	score := dto.InductionScoreResponse{TotalExamples: len(examples)}
+	results := make([]dto.InductionExampleResult, len(examples))
+	for i, example := range examples {
+		extractedData, confidence := uc.extractDataFromMessage(compiled, example.Message)
+		result := dto.InductionExampleResult{
+			Index:           i,
+			KeywordsMatched: pattern.MatchesKeywords(example.Message),
+			Confidence:      confidence,
+			ExtractedData:   extractedData,
+		}
+
+		for _, span := range example.Spans {
+			score.TotalFields++
+			extracted, _ := extractedData[span.Field].(string)
+			if normalizeInducedValue(extracted) == normalizeInducedValue(span.Value) {
+				score.CorrectFields++
+			} else {
+				result.MismatchedFields = append(result.MismatchedFields, span.Field)
+			}
+		}
+
+		result.Matched = result.KeywordsMatched && len(result.MismatchedFields) == 0
+		if result.Matched {
+			score.MatchedExamples++
+		}
+		results[i] = result
+	}
+
+	if score.TotalFields > 0 {
+		score.Score = float64(score.CorrectFields) / float64(score.TotalFields)
+	}
+
+	return score, results, nil
+}
+
+// normalizeInducedValue normaliza un valor para compararlo como lo hace el extractor
+func normalizeInducedValue(value string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(value), ".,;"))
+}
@@ -0,0 +1,399 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/patterncache"
+)
+
+// PatternLibraryUseCase contiene la lógica de la biblioteca de plantillas de patrones por banco
+type PatternLibraryUseCase struct {
+	templateRepo    repo.PatternTemplateRepo
+	patternRepo     repo.BankNotificationPatternRepo
+	bankAccountRepo repo.BankAccountRepo
+	userRepo        repo.UserRepo
+	patternCache    *patterncache.Cache
+	patternUC       *BankNotificationPatternUseCase
+	maintainers     map[string]bool // Emails autorizados a importar plantillas
+}
+
+// NewPatternLibraryUseCase crea una nueva instancia de PatternLibraryUseCase
+func NewPatternLibraryUseCase(
+	templateRepo repo.PatternTemplateRepo,
+	patternRepo repo.BankNotificationPatternRepo,
+	bankAccountRepo repo.BankAccountRepo,
+	userRepo repo.UserRepo,
+	patternCache *patterncache.Cache,
+	patternUC *BankNotificationPatternUseCase,
+	maintainers []string,
+) *PatternLibraryUseCase {
+	maintainerSet := make(map[string]bool)
+	for _, email := range maintainers {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			maintainerSet[email] = true
+		}
+	}
+
+	return &PatternLibraryUseCase{
+		templateRepo:    templateRepo,
+		patternRepo:     patternRepo,
+		bankAccountRepo: bankAccountRepo,
+		userRepo:        userRepo,
+		patternCache:    patternCache,
+		patternUC:       patternUC,
+		maintainers:     maintainerSet,
+	}
+}
+
+// ListTemplates obtiene las plantillas del sistema filtradas por banco y canal
+func (uc *PatternLibraryUseCase) ListTemplates(filter entity.PatternTemplateFilter) ([]*dto.PatternTemplateResponse, error) {
+	templates, err := uc.templateRepo.GetSystemTemplates(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pattern templates: %w", err)
+	}
+
+	responses := make([]*dto.PatternTemplateResponse, len(templates))
+	for i, template := range templates {
+		responses[i] = uc.toDTO(template)
+	}
+
+	return responses, nil
+}
+
+// GetTemplate obtiene una plantilla del sistema por ID
+func (uc *PatternLibraryUseCase) GetTemplate(templateID uint) (*dto.PatternTemplateResponse, error) {
+	template, err := uc.getSystemTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.toDTO(template), nil
+}
+
+// Subscribe suscribe una cuenta bancaria a una plantilla: crea una copia del patrón para la cuenta
+// que se actualiza con cada nueva versión de la plantilla, salvo en los campos ajustados por el usuario
+func (uc *PatternLibraryUseCase) Subscribe(userID, templateID uint, req *dto.SubscribePatternTemplateRequest) (*dto.BankNotificationPatternResponse, error) {
+	template, err := uc.getSystemTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	bankAccount, err := uc.bankAccountRepo.GetByID(req.BankAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank account: %w", err)
+	}
+	if bankAccount == nil || bankAccount.UserID != userID {
+		return nil, errors.New("unauthorized access to bank account")
+	}
+	if bankAccount.BankCode != "" && bankAccount.BankCode != template.BankCode {
+		return nil, errors.New("template does not match bank account bank")
+	}
+
+	subscribed, err := uc.patternRepo.GetByTemplateID(template.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscribed patterns: %w", err)
+	}
+	for _, pattern := range subscribed {
+		if pattern.BankAccountID == bankAccount.ID {
+			return nil, errors.New("bank account is already subscribed to template")
+		}
+	}
+
+	pattern := &entity.BankNotificationPattern{
+		UserID:        userID,
+		BankAccountID: bankAccount.ID,
+		Channel:       template.Channel,
+		Status:        entity.NotificationPatternStatusActive,
+	}
+	template.ApplyTo(pattern)
+
+	if err := uc.patternRepo.CreateFromTemplate(pattern); err != nil {
+		return nil, fmt.Errorf("failed to create pattern: %w", err)
+	}
+	uc.patternCache.InvalidateBankAccount(pattern.BankAccountID)
+
+	return uc.patternUC.toDTO(pattern), nil
+}
+
+// Unsubscribe desvincula un patrón de su plantilla. El patrón se conserva como propio
+// y deja de recibir nuevas versiones.
+func (uc *PatternLibraryUseCase) Unsubscribe(userID, patternID uint) (*dto.BankNotificationPatternResponse, error) {
+	pattern, err := uc.patternRepo.GetByID(patternID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pattern: %w", err)
+	}
+	if pattern == nil {
+		return nil, errors.New("pattern not found")
+	}
+	if pattern.UserID != userID {
+		return nil, errors.New("unauthorized access to pattern")
+	}
+	if !pattern.IsSubscribed() {
+		return nil, errors.New("pattern is not subscribed to a template")
+	}
+
+	pattern.TemplateID = nil
+	pattern.TemplateVersion = 0
+	pattern.Overrides = ""
+
+	if err := uc.patternRepo.Update(pattern); err != nil {
+		return nil, fmt.Errorf("failed to update pattern: %w", err)
+	}
+
+	return uc.patternUC.toDTO(pattern), nil
+}
+
+// Export obtiene las plantillas del sistema en el formato de intercambio
+func (uc *PatternLibraryUseCase) Export(filter entity.PatternTemplateFilter) (*dto.PatternLibraryDocument, error) {
+	templates, err := uc.templateRepo.GetSystemTemplates(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pattern templates: %w", err)
+	}
+
+	document := &dto.PatternLibraryDocument{
+		Templates: make([]dto.PatternTemplateSpec, len(templates)),
+	}
+	for i, template := range templates {
+		document.Templates[i] = templateToSpec(template)
+	}
+
+	return document, nil
+}
+
+// Import crea o actualiza plantillas del sistema a partir de un documento exportado. Solo los
+// mantenedores de la biblioteca pueden importar. Las plantillas se identifican por slug: si el
+// contenido cambió, la versión aumenta y los patrones suscritos se actualizan.
+func (uc *PatternLibraryUseCase) Import(userID uint, document *dto.PatternLibraryDocument) (*dto.ImportPatternLibraryResponse, error) {
+	if err := uc.checkMaintainer(userID); err != nil {
+		return nil, err
+	}
+
+	// Validar todo el documento antes de modificar la biblioteca
+	imported := make([]*entity.PatternTemplate, len(document.Templates))
+	slugs := make(map[string]bool)
+	for i := range document.Templates {
+		spec := &document.Templates[i]
+		if slugs[spec.Slug] {
+			return nil, fmt.Errorf("invalid template %s: duplicate slug", spec.Slug)
+		}
+		slugs[spec.Slug] = true
+
+		if err := uc.patternUC.validateRegexPatterns(specToPatternRequest(spec)); err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", spec.Slug, err)
+		}
+
+		template, err := templateFromSpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", spec.Slug, err)
+		}
+		imported[i] = template
+	}
+
+	response := &dto.ImportPatternLibraryResponse{
+		Skipped: []dto.SkippedTemplateResponse{},
+	}
+
+	for _, template := range imported {
+		existing, err := uc.templateRepo.GetBySlug(template.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pattern template: %w", err)
+		}
+
+		if existing == nil {
+			if template.Version == 0 {
+				template.Version = 1
+			}
+			if err := uc.templateRepo.Create(template); err != nil {
+				return nil, fmt.Errorf("failed to create pattern template: %w", err)
+			}
+			response.Created++
+			continue
+		}
+
+		if !existing.IsSystem() {
+			response.Skipped = append(response.Skipped, dto.SkippedTemplateResponse{
+				Slug:   template.Slug,
+				Reason: "slug belongs to a user template",
+			})
+			continue
+		}
+
+		sameContent := existing.SameContent(template) &&
+			existing.BankCode == template.BankCode &&
+			existing.BankName == template.BankName &&
+			existing.Channel == template.Channel
+		switch {
+		case sameContent && (template.Version == 0 || template.Version == existing.Version):
+			response.Unchanged++
+			continue
+		case template.Version == 0:
+			template.Version = existing.Version + 1
+		case template.Version <= existing.Version:
+			response.Skipped = append(response.Skipped, dto.SkippedTemplateResponse{
+				Slug:   template.Slug,
+				Reason: fmt.Sprintf("version %d is not newer than current version %d", template.Version, existing.Version),
+			})
+			continue
+		}
+
+		template.ID = existing.ID
+		template.CreatedAt = existing.CreatedAt
+		if err := uc.templateRepo.Update(template); err != nil {
+			return nil, fmt.Errorf("failed to update pattern template: %w", err)
+		}
+		response.Updated++
+
+		synced, err := uc.syncSubscribers(template)
+		if err != nil {
+			return nil, err
+		}
+		response.SyncedPatterns += synced
+	}
+
+	return response, nil
+}
+
+// syncSubscribers aplica la nueva versión de la plantilla a los patrones suscritos,
+// conservando los campos que cada usuario ajustó
+func (uc *PatternLibraryUseCase) syncSubscribers(template *entity.PatternTemplate) (int, error) {
+	patterns, err := uc.patternRepo.GetByTemplateID(template.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get subscribed patterns: %w", err)
+	}
+
+	synced := 0
+	for _, pattern := range patterns {
+		if pattern.TemplateVersion >= template.Version {
+			continue
+		}
+
+		template.ApplyTo(pattern)
+		if err := uc.patternRepo.Update(pattern); err != nil {
+			return synced, fmt.Errorf("failed to update subscribed pattern %d: %w", pattern.ID, err)
+		}
+		uc.patternCache.InvalidateBankAccount(pattern.BankAccountID)
+		synced++
+	}
+
+	return synced, nil
+}
+
+// checkMaintainer verifica que el usuario pueda modificar la biblioteca del sistema
+func (uc *PatternLibraryUseCase) checkMaintainer(userID uint) error {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || !uc.maintainers[strings.ToLower(user.Email)] {
+		return errors.New("only pattern library maintainers can import templates")
+	}
+	return nil
+}
+
+// getSystemTemplate obtiene una plantilla verificando que pertenece a la biblioteca del sistema
+func (uc *PatternLibraryUseCase) getSystemTemplate(templateID uint) (*entity.PatternTemplate, error) {
+	template, err := uc.templateRepo.GetByID(templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pattern template: %w", err)
+	}
+	if template == nil || !template.IsSystem() {
+		return nil, errors.New("template not found")
+	}
+	return template, nil
+}
+
+// toDTO convierte una plantilla a DTO de respuesta
+func (uc *PatternLibraryUseCase) toDTO(template *entity.PatternTemplate) *dto.PatternTemplateResponse {
+	return &dto.PatternTemplateResponse{
+		ID:                  template.ID,
+		PatternTemplateSpec: templateToSpec(template),
+		UpdatedAt:           template.UpdatedAt,
+	}
+}
+
+// templateToSpec convierte una plantilla al formato de intercambio
+func templateToSpec(template *entity.PatternTemplate) dto.PatternTemplateSpec {
+	return dto.PatternTemplateSpec{
+		Slug:                template.Slug,
+		BankCode:            template.BankCode,
+		BankName:            template.BankName,
+		Channel:             template.Channel,
+		Version:             template.Version,
+		Name:                template.Name,
+		Description:         template.Description,
+		MessagePattern:      template.MessagePattern,
+		ExampleMessage:      template.ExampleMessage,
+		KeywordsTrigger:     template.GetKeywordsTrigger(),
+		KeywordsExclude:     template.GetKeywordsExclude(),
+		AmountRegex:         template.AmountRegex,
+		DateRegex:           template.DateRegex,
+		DescriptionRegex:    template.DescriptionRegex,
+		MerchantRegex:       template.MerchantRegex,
+		BalanceRegex:        template.BalanceRegex,
+		TransactionKind:     template.TransactionKind,
+		RequiresValidation:  template.RequiresValidation,
+		ConfidenceThreshold: template.ConfidenceThreshold,
+		AutoApprove:         template.AutoApprove,
+		Priority:            template.Priority,
+		Tags:                template.GetTags(),
+	}
+}
+
+// templateFromSpec crea una plantilla del sistema a partir del formato de intercambio
+func templateFromSpec(spec *dto.PatternTemplateSpec) (*entity.PatternTemplate, error) {
+	template := &entity.PatternTemplate{
+		Slug:                spec.Slug,
+		BankCode:            spec.BankCode,
+		BankName:            spec.BankName,
+		Channel:             spec.Channel,
+		Version:             spec.Version,
+		Name:                spec.Name,
+		Description:         spec.Description,
+		MessagePattern:      spec.MessagePattern,
+		ExampleMessage:      spec.ExampleMessage,
+		AmountRegex:         spec.AmountRegex,
+		DateRegex:           spec.DateRegex,
+		DescriptionRegex:    spec.DescriptionRegex,
+		MerchantRegex:       spec.MerchantRegex,
+		BalanceRegex:        spec.BalanceRegex,
+		TransactionKind:     spec.TransactionKind,
+		RequiresValidation:  spec.RequiresValidation,
+		ConfidenceThreshold: spec.ConfidenceThreshold,
+		AutoApprove:         spec.AutoApprove,
+		Priority:            spec.Priority,
+	}
+	// Mismos valores por defecto que un patrón nuevo
+	if template.Priority == 0 {
+		template.Priority = 100
+	}
+	if template.ConfidenceThreshold == 0 {
+		template.ConfidenceThreshold = 0.8
+	}
+	if template.TransactionKind == "" {
+		template.TransactionKind = entity.TransactionKindPurchase
+	}
+
+	if err := template.SetLists(spec.KeywordsTrigger, spec.KeywordsExclude, spec.Tags); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// specToPatternRequest adapta una plantilla a la petición de creación de patrón para reutilizar sus validaciones
+func specToPatternRequest(spec *dto.PatternTemplateSpec) *dto.CreateBankNotificationPatternRequest {
+	return &dto.CreateBankNotificationPatternRequest{
+		Name:             spec.Name,
+		Channel:          spec.Channel,
+		MessagePattern:   spec.MessagePattern,
+		AmountRegex:      spec.AmountRegex,
+		DateRegex:        spec.DateRegex,
+		DescriptionRegex: spec.DescriptionRegex,
+		MerchantRegex:    spec.MerchantRegex,
+		BalanceRegex:     spec.BalanceRegex,
+	}
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/patterncache"
+)
+
+// PatternPolicyUseCase contiene la lógica de ciclo de vida de los patrones de notificación:
+// registra sus resultados y los promueve o degrada según la política del usuario
+type PatternPolicyUseCase struct {
+	patternRepo  repo.BankNotificationPatternRepo
+	policyRepo   repo.PatternPolicyRepo
+	historyRepo  repo.PatternHistoryRepo
+	patternCache *patterncache.Cache
+}
+
+// NewPatternPolicyUseCase crea una nueva instancia de PatternPolicyUseCase
+func NewPatternPolicyUseCase(
+	patternRepo repo.BankNotificationPatternRepo,
+	policyRepo repo.PatternPolicyRepo,
+	historyRepo repo.PatternHistoryRepo,
+	patternCache *patterncache.Cache,
+) *PatternPolicyUseCase {
+	return &PatternPolicyUseCase{
+		patternRepo:  patternRepo,
+		policyRepo:   policyRepo,
+		historyRepo:  historyRepo,
+		patternCache: patternCache,
+	}
+}
+
+// GetPolicy obtiene la política de patrones del usuario (o la política por defecto)
+func (uc *PatternPolicyUseCase) GetPolicy(userID uint) (*dto.PatternPolicyResponse, error) {
+	policy, isDefault, err := uc.getPolicy(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.toPolicyDTO(policy, isDefault), nil
+}
+
+// UpdatePolicy actualiza los umbrales de la política de patrones del usuario
+func (uc *PatternPolicyUseCase) UpdatePolicy(userID uint, req *dto.UpdatePatternPolicyRequest) (*dto.PatternPolicyResponse, error) {
+	policy, _, err := uc.getPolicy(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled != nil {
+		policy.Enabled = *req.Enabled
+	}
+	if req.PromoteMinConfirmed != nil {
+		policy.PromoteMinConfirmed = *req.PromoteMinConfirmed
+	}
+	if req.PromoteMinSuccessRate != nil {
+		policy.PromoteMinSuccessRate = *req.PromoteMinSuccessRate
+	}
+	if req.DemoteMinSamples != nil {
+		policy.DemoteMinSamples = *req.DemoteMinSamples
+	}
+	if req.DemoteBelowRate != nil {
+		policy.DemoteBelowRate = *req.DemoteBelowRate
+	}
+	if req.DisableBelowRate != nil {
+		policy.DisableBelowRate = *req.DisableBelowRate
+	}
+
+	// Un patrón debe degradarse antes de desactivarse, y no puede degradarse
+	// inmediatamente después de ser promovido
+	if policy.DisableBelowRate > policy.DemoteBelowRate {
+		return nil, errors.New("disable_below_rate cannot be greater than demote_below_rate")
+	}
+	if policy.DemoteBelowRate > policy.PromoteMinSuccessRate {
+		return nil, errors.New("demote_below_rate cannot be greater than promote_min_success_rate")
+	}
+
+	if err := uc.policyRepo.Save(policy); err != nil {
+		return nil, fmt.Errorf("failed to update pattern policy: %w", err)
+	}
+
+	return uc.toPolicyDTO(policy, false), nil
+}
+
+// GetPatternHistory obtiene el historial de cambios de estado de un patrón
+func (uc *PatternPolicyUseCase) GetPatternHistory(userID, patternID uint) ([]*dto.PatternHistoryResponse, error) {
+	pattern, err := uc.patternRepo.GetByID(patternID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pattern: %w", err)
+	}
+	if pattern == nil {
+		return nil, errors.New("pattern not found")
+	}
+	if pattern.UserID != userID {
+		return nil, errors.New("unauthorized access to pattern")
+	}
+
+	history, err := uc.historyRepo.GetByPatternID(patternID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pattern history: %w", err)
+	}
+
+	responses := make([]*dto.PatternHistoryResponse, len(history))
+	for i, entry := range history {
+		responses[i] = &dto.PatternHistoryResponse{
+			ID:           entry.ID,
+			PatternID:    entry.PatternID,
+			FromStatus:   entry.FromStatus,
+			ToStatus:     entry.ToStatus,
+			Reason:       entry.Reason,
+			Details:      entry.Details,
+			MatchCount:   entry.MatchCount,
+			SuccessCount: entry.SuccessCount,
+			FailureCount: entry.FailureCount,
+			SuccessRate:  entry.SuccessRate,
+			CreatedAt:    entry.CreatedAt,
+		}
+	}
+
+	return responses, nil
+}
+
+// RecordMatch registra una coincidencia de un patrón y evalúa si debe cambiar de estado.
+// Un error al actualizar estadísticas no debe impedir el procesamiento de la notificación.
+func (uc *PatternPolicyUseCase) RecordMatch(patternID uint, success bool) {
+	if err := uc.patternRepo.RecordMatch(patternID, success); err != nil {
+		log.Printf("Warning: Failed to record match for pattern %d: %v", patternID, err)
+		return
+	}
+
+	uc.evaluate(patternID)
+}
+
+// RecordFeedback registra la validación del usuario sobre un movimiento generado
+// por un patrón y evalúa si el patrón debe cambiar de estado
+func (uc *PatternPolicyUseCase) RecordFeedback(patternID uint, success, wasSuccess bool) {
+	if err := uc.patternRepo.RecordFeedback(patternID, success, wasSuccess); err != nil {
+		log.Printf("Warning: Failed to record feedback for pattern %d: %v", patternID, err)
+		return
+	}
+
+	uc.evaluate(patternID)
+}
+
+// RecordManualTransition cambia el estado de un patrón por decisión del usuario y lo registra en el historial
+func (uc *PatternPolicyUseCase) RecordManualTransition(pattern *entity.BankNotificationPattern, status entity.NotificationPatternStatus) error {
+	if pattern.Status == status {
+		return nil
+	}
+
+	history := entity.NewPatternHistory(pattern, status, entity.PatternTransitionReasonManual, "status changed by user")
+	if err := uc.historyRepo.CreateWithStatusChange(history); err != nil {
+		return err
+	}
+
+	uc.patternCache.InvalidateBankAccount(pattern.BankAccountID)
+	return nil
+}
+
+// evaluate aplica la política del usuario a las estadísticas actuales del patrón. Las estadísticas
+// acaban de cambiar, por lo que se copian al patrón en caché para que el ranking use el historial
+// vigente sin recompilar los patrones de la cuenta.
+func (uc *PatternPolicyUseCase) evaluate(patternID uint) {
+	pattern, err := uc.patternRepo.GetByID(patternID)
+	if err != nil || pattern == nil {
+		return
+	}
+	uc.patternCache.UpdatePatternStats(pattern)
+
+	policy, _, err := uc.getPolicy(pattern.UserID)
+	if err != nil {
+		log.Printf("Warning: Failed to get pattern policy for user %d: %v", pattern.UserID, err)
+		return
+	}
+
+	status, reason, ok := policy.Evaluate(pattern)
+	if !ok {
+		return
+	}
+
+	history := entity.NewPatternHistory(pattern, status, reason, uc.describeTransition(policy, pattern, reason))
+	if err := uc.historyRepo.CreateWithStatusChange(history); err != nil {
+		log.Printf("Warning: Failed to change status of pattern %d: %v", patternID, err)
+		return
+	}
+
+	// El cambio de estado modifica el conjunto de patrones de la cuenta
+	uc.patternCache.InvalidateBankAccount(pattern.BankAccountID)
+}
+
+// describeTransition genera la explicación de un cambio de estado automático
+func (uc *PatternPolicyUseCase) describeTransition(policy *entity.PatternPolicy, pattern *entity.BankNotificationPattern, reason entity.PatternTransitionReason) string {
+	rate := pattern.ResolvedSuccessRate()
+
+	switch reason {
+	case entity.PatternTransitionReasonPromoted:
+		return fmt.Sprintf("%d confirmed matches with %.1f%% success rate (requires %d and %.1f%%)",
+			pattern.SuccessCount, rate, policy.PromoteMinConfirmed, policy.PromoteMinSuccessRate)
+	case entity.PatternTransitionReasonDemoted:
+		return fmt.Sprintf("success rate %.1f%% fell below %.1f%% after %d validations",
+			rate, policy.DemoteBelowRate, pattern.SuccessCount+pattern.FailureCount)
+	case entity.PatternTransitionReasonDisabled:
+		return fmt.Sprintf("success rate %.1f%% fell below %.1f%% after %d validations",
+			rate, policy.DisableBelowRate, pattern.SuccessCount+pattern.FailureCount)
+	}
+
+	return ""
+}
+
+// getPolicy obtiene la política del usuario. El segundo valor indica si es la política por defecto.
+func (uc *PatternPolicyUseCase) getPolicy(userID uint) (*entity.PatternPolicy, bool, error) {
+	policy, err := uc.policyRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get pattern policy: %w", err)
+	}
+	if policy == nil {
+		return entity.NewDefaultPatternPolicy(userID), true, nil
+	}
+
+	return policy, false, nil
+}
+
+// toPolicyDTO convierte una política a DTO de respuesta
+func (uc *PatternPolicyUseCase) toPolicyDTO(policy *entity.PatternPolicy, isDefault bool) *dto.PatternPolicyResponse {
+	return &dto.PatternPolicyResponse{
+		Enabled:               policy.Enabled,
+		PromoteMinConfirmed:   policy.PromoteMinConfirmed,
+		PromoteMinSuccessRate: policy.PromoteMinSuccessRate,
+		DemoteMinSamples:      policy.DemoteMinSamples,
+		DemoteBelowRate:       policy.DemoteBelowRate,
+		DisableBelowRate:      policy.DisableBelowRate,
+		IsDefault:             isDefault,
+	}
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/patterncache"
+)
+
+// fakePatternRepo implementa solo las estadísticas de repo.BankNotificationPatternRepo
+type fakePatternRepo struct {
+	repo.BankNotificationPatternRepo
+	pattern *entity.BankNotificationPattern
+}
+
+func (r *fakePatternRepo) GetByID(id uint) (*entity.BankNotificationPattern, error) {
+	return r.pattern, nil
+}
+
+func (r *fakePatternRepo) RecordMatch(id uint, success bool) error {
+	r.pattern.MatchCount++
+	return nil
+}
+
+func (r *fakePatternRepo) RecordFeedback(id uint, success, wasSuccess bool) error {
+	if success {
+		r.pattern.SuccessCount++
+	} else {
+		r.pattern.FailureCount++
+	}
+	return nil
+}
+
+// fakePolicyRepo retorna siempre la misma política
+type fakePolicyRepo struct {
+	repo.PatternPolicyRepo
+	policy *entity.PatternPolicy
+}
+
+func (r *fakePolicyRepo) GetByUserID(userID uint) (*entity.PatternPolicy, error) {
+	return r.policy, nil
+}
+
+func TestPatternStatsUpdateCache(t *testing.T) {
+	pattern := &entity.BankNotificationPattern{ID: 1, UserID: 1, BankAccountID: 10, Status: entity.NotificationPatternStatusActive}
+	cache := patterncache.New(patterncache.DefaultCapacity)
+	policy := &entity.PatternPolicy{UserID: 1, Enabled: false}
+	uc := NewPatternPolicyUseCase(&fakePatternRepo{pattern: pattern}, &fakePolicyRepo{policy: policy}, nil, cache)
+
+	key := patterncache.Key{BankAccountID: 10, Channel: entity.NotificationChannelSMS}
+	cached := patterncache.Compile(&entity.BankNotificationPattern{ID: 1, UserID: 1, BankAccountID: 10, Status: entity.NotificationPatternStatusActive})
+	other := patterncache.Compile(&entity.BankNotificationPattern{ID: 2, UserID: 1, BankAccountID: 10})
+	previous := &patterncache.PatternSet{Patterns: []*patterncache.CompiledPattern{other, cached}, Default: cached}
+	cache.Put(key, previous)
+
+	uc.RecordMatch(1, true)
+	uc.RecordFeedback(1, false, true)
+
+	// Sin cambio de estado el conjunto sigue en caché con las estadísticas vigentes
+	set, ok := cache.Get(key)
+	if !ok {
+		t.Fatal("expected the bank account patterns to stay in the cache")
+	}
+	updated := set.Patterns[1].Pattern
+	if updated.MatchCount != 1 || updated.FailureCount != 1 || set.Default.Pattern != updated || set.Patterns[0] != other {
+		t.Fatalf("cached patterns = %+v", set)
+	}
+	if cached.Pattern.MatchCount != 0 || previous.Patterns[1] != cached {
+		t.Fatal("expected the previous pattern set to be left untouched")
+	}
+	if stats := cache.Stats(); stats.Invalidations != 0 {
+		t.Fatalf("invalidations = %d, want 0", stats.Invalidations)
+	}
+}
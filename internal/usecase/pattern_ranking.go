@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/pkg/patterncache"
+)
+
+// Pesos de cada componente en el puntaje de un patrón candidato (suman 1)
+const (
+	rankWeightExtraction = 0.5 // Proporción de campos esperados que se extrajeron
+	rankWeightKeywords   = 0.2 // Palabras clave de activación presentes en el mensaje
+	rankWeightHistory    = 0.2 // Tasa de éxito histórica del patrón
+	rankWeightPriority   = 0.1 // Prioridad configurada por el usuario
+)
+
+// neutralRankScore se usa cuando un componente no aporta información (sin keywords o sin historial)
+const neutralRankScore = 0.5
+
+// rankedPattern es un patrón candidato evaluado contra un mensaje
+type rankedPattern struct {
+	compiled          *patterncache.CompiledPattern
+	extractedData     map[string]interface{}
+	confidence        float64
+	triggeredKeywords []string
+	keywordScore      float64
+	historyScore      float64
+	priorityScore     float64
+	score             float64
+	isDefault         bool
+}
+
+// rankPatterns evalúa todos los patrones candidatos contra el mensaje y los ordena por puntaje.
+// Se descartan los patrones con palabras de exclusión o sin ninguna palabra de activación;
+// el patrón por defecto siempre se evalúa, aunque sus palabras clave no coincidan.
+// Si se agota el tiempo de evaluación, los patrones restantes no se evalúan.
+func (uc *BankNotificationPatternUseCase) rankPatterns(patternSet *patterncache.PatternSet, message string) []*rankedPattern {
+	var ranked []*rankedPattern
+	evaluated := make(map[uint]bool)
+	deadline := time.Now().Add(uc.evaluationBudget)
+
+	for i, compiled := range patternSet.Patterns {
+		if uc.evaluationBudget > 0 && time.Now().After(deadline) {
+			log.Printf("Warning: Pattern evaluation budget of %s exceeded; %d of %d patterns were skipped",
+				uc.evaluationBudget, len(patternSet.Patterns)-i, len(patternSet.Patterns))
+			break
+		}
+
+		if !compiled.MatchesKeywords(message) {
+			continue
+		}
+		evaluated[compiled.Pattern.ID] = true
+		ranked = append(ranked, uc.scorePattern(compiled, message, compiled.Pattern.IsDefault))
+	}
+
+	if patternSet.Default != nil && !evaluated[patternSet.Default.Pattern.ID] {
+		ranked = append(ranked, uc.scorePattern(patternSet.Default, message, true))
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].compiled.Pattern.Priority < ranked[j].compiled.Pattern.Priority
+	})
+
+	return ranked
+}
+
+// scorePattern calcula el puntaje de un patrón combinando extracción, palabras clave, historial y prioridad
+func (uc *BankNotificationPatternUseCase) scorePattern(compiled *patterncache.CompiledPattern, message string, isDefault bool) *rankedPattern {
+	pattern := compiled.Pattern
+	extractedData, confidence := uc.extractDataFromMessage(compiled, message)
+	triggered, _ := compiled.MatchedKeywords(message)
+
+	candidate := &rankedPattern{
+		compiled:          compiled,
+		extractedData:     extractedData,
+		confidence:        confidence,
+		triggeredKeywords: triggered,
+		keywordScore:      neutralRankScore,
+		historyScore:      neutralRankScore,
+		isDefault:         isDefault,
+	}
+
+	// Más palabras de activación presentes indican un patrón más específico para el mensaje
+	if triggers := pattern.GetKeywordsTrigger(); len(triggers) > 0 {
+		candidate.keywordScore = 0
+		if len(triggered) > 0 {
+			candidate.keywordScore = neutralRankScore + neutralRankScore*float64(len(triggered))/float64(len(triggers))
+		}
+	}
+
+	// El historial solo cuenta cuando el usuario ya validó movimientos del patrón
+	if pattern.SuccessCount+pattern.FailureCount > 0 {
+		candidate.historyScore = pattern.ResolvedSuccessRate() / 100
+	}
+
+	// Menor número = mayor prioridad; la prioridad por defecto (100) vale 0.5
+	priority := pattern.Priority
+	if priority < 0 {
+		priority = 0
+	}
+	candidate.priorityScore = 100 / float64(100+priority)
+
+	candidate.score = rankWeightExtraction*candidate.confidence +
+		rankWeightKeywords*candidate.keywordScore +
+		rankWeightHistory*candidate.historyScore +
+		rankWeightPriority*candidate.priorityScore
+
+	return candidate
+}
+
+// toCandidateDTOs convierte la lista de candidatos evaluados a DTO de respuesta
+func (uc *BankNotificationPatternUseCase) toCandidateDTOs(ranked []*rankedPattern) []dto.PatternCandidateResponse {
+	candidates := make([]dto.PatternCandidateResponse, len(ranked))
+	for i, candidate := range ranked {
+		pattern := candidate.compiled.Pattern
+		candidates[i] = dto.PatternCandidateResponse{
+			Rank:              i + 1,
+			PatternID:         pattern.ID,
+			PatternName:       pattern.Name,
+			Status:            pattern.Status,
+			Score:             candidate.score,
+			Confidence:        candidate.confidence,
+			KeywordScore:      candidate.keywordScore,
+			HistoryScore:      candidate.historyScore,
+			PriorityScore:     candidate.priorityScore,
+			TriggeredKeywords: candidate.triggeredKeywords,
+			IsDefault:         candidate.isDefault,
+			Selected:          i == 0,
+		}
+	}
+	return candidates
+}
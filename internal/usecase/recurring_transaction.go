@@ -0,0 +1,497 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+)
+
+// Límites de la generación de ocurrencias
+const (
+	defaultRecurrencePreview  = 12
+	maxRecurrencePreview      = 100
+	maxOccurrencesPerRun      = 366 // Por recurrencia, para acotar el relleno de una fecha de inicio antigua
+	recurringProcessBatchSize = 500 // Recurrencias de todos los usuarios leídas por página
+	recurrenceDateLayout      = "2006-01-02"
+)
+
+// RecurringTransactionUseCase programa gastos, ingresos y transferencias que se repiten (renta,
+// suscripciones, salario) y genera sus ocurrencias como transacciones
+type RecurringTransactionUseCase struct {
+	recurringRepo   repo.RecurringTransactionRepo
+	transactionRepo repo.TransactionRepo
+	categoryRepo    repo.CategoryRepo
+	userRepo        repo.UserRepo
+	transactionUC   *TransactionUseCase
+}
+
+// NewRecurringTransactionUseCase crea una nueva instancia de RecurringTransactionUseCase
+func NewRecurringTransactionUseCase(
+	recurringRepo repo.RecurringTransactionRepo,
+	transactionRepo repo.TransactionRepo,
+	categoryRepo repo.CategoryRepo,
+	userRepo repo.UserRepo,
+	transactionUC *TransactionUseCase,
+) *RecurringTransactionUseCase {
+	return &RecurringTransactionUseCase{
+		recurringRepo:   recurringRepo,
+		transactionRepo: transactionRepo,
+		categoryRepo:    categoryRepo,
+		userRepo:        userRepo,
+		transactionUC:   transactionUC,
+	}
+}
+
+// Create programa una transacción recurrente y genera las ocurrencias que ya vencieron (ej: una
+// renta que empezó el mes pasado)
+func (uc *RecurringTransactionUseCase) Create(userID uint, req *dto.CreateRecurringTransactionRequest) (*entity.RecurringTransaction, error) {
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.IsAccountActive() {
+		return nil, errors.New("user account is not active")
+	}
+
+	account, toAccount, err := uc.transactionUC.resolveAccounts(userID, req.Type, req.AccountID, req.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if toAccount != nil && toAccount.Currency != account.Currency {
+		return nil, errors.New("currency mismatch between accounts")
+	}
+
+	startDate, err := time.Parse(recurrenceDateLayout, req.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid recurrence: start_date must use the YYYY-MM-DD format")
+	}
+
+	recurring := &entity.RecurringTransaction{
+		UserID:         userID,
+		AccountID:      req.AccountID,
+		Type:           req.Type,
+		Amount:         req.Amount,
+		Currency:       account.Currency,
+		Description:    req.Description,
+		Notes:          req.Notes,
+		Frequency:      req.Frequency,
+		Interval:       req.Interval,
+		DayOfMonth:     req.DayOfMonth,
+		BusinessDay:    req.BusinessDay,
+		StartDate:      entity.DateOf(startDate),
+		MaxOccurrences: req.MaxOccurrences,
+		Status:         entity.RecurringTransactionStatusActive,
+	}
+
+	if req.Type == entity.TransactionTypeTransfer {
+		recurring.ToAccountID = req.ToAccountID
+	} else if req.CategoryID != nil {
+		if err := uc.setCategory(userID, recurring, *req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.EndDate != "" {
+		endDate, err := time.Parse(recurrenceDateLayout, req.EndDate)
+		if err != nil {
+			return nil, errors.New("invalid recurrence: end_date must use the YYYY-MM-DD format")
+		}
+		recurring.EndDate = &endDate
+	}
+
+	if err := uc.validateSchedule(recurring); err != nil {
+		return nil, err
+	}
+
+	recurring.ScheduleNext()
+	if err := uc.recurringRepo.Create(recurring); err != nil {
+		return nil, err
+	}
+
+	// Las ocurrencias que no se puedan generar ahora (ej: fondos insuficientes) se reintentan
+	// en la siguiente ejecución del proceso
+	uc.materialize(recurring, entity.DateOf(time.Now()), &dto.RecurringProcessResponse{})
+
+	return recurring, nil
+}
+
+// List obtiene las transacciones recurrentes del usuario, opcionalmente por estado
+func (uc *RecurringTransactionUseCase) List(userID uint, status *entity.RecurringTransactionStatus) ([]*entity.RecurringTransaction, error) {
+	recurrings, err := uc.recurringRepo.GetByUserID(userID, status)
+	if err != nil {
+		return nil, err
+	}
+	if recurrings == nil {
+		recurrings = []*entity.RecurringTransaction{}
+	}
+	return recurrings, nil
+}
+
+// Get obtiene una transacción recurrente del usuario
+func (uc *RecurringTransactionUseCase) Get(userID, recurringID uint) (*entity.RecurringTransaction, error) {
+	recurring, err := uc.recurringRepo.GetByID(recurringID)
+	if err != nil || recurring.UserID != userID {
+		return nil, errors.New("recurring transaction not found")
+	}
+	return recurring, nil
+}
+
+// Update actualiza la plantilla, las condiciones de fin o el estado de una transacción recurrente.
+// Al reanudarla, las ocurrencias que cayeron mientras estuvo pausada no se generan.
+func (uc *RecurringTransactionUseCase) Update(userID, recurringID uint, req *dto.UpdateRecurringTransactionRequest) (*entity.RecurringTransaction, error) {
+	recurring, err := uc.Get(userID, recurringID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Amount != nil {
+		recurring.Amount = *req.Amount
+	}
+	if req.Description != "" {
+		recurring.Description = req.Description
+	}
+	if req.Notes != "" {
+		recurring.Notes = req.Notes
+	}
+	if req.CategoryID != nil {
+		if recurring.Type == entity.TransactionTypeTransfer {
+			return nil, errors.New("invalid recurrence: transfers have no category")
+		}
+		if err := uc.setCategory(userID, recurring, *req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Cambiar las condiciones de fin puede terminar la recurrencia o reactivar una terminada
+	if req.EndDate != "" || req.MaxOccurrences != nil {
+		if req.EndDate != "" {
+			endDate, err := time.Parse(recurrenceDateLayout, req.EndDate)
+			if err != nil {
+				return nil, errors.New("invalid recurrence: end_date must use the YYYY-MM-DD format")
+			}
+			recurring.EndDate = &endDate
+		}
+		if req.MaxOccurrences != nil {
+			recurring.MaxOccurrences = req.MaxOccurrences
+		}
+		if err := uc.validateSchedule(recurring); err != nil {
+			return nil, err
+		}
+		recurring.ScheduleNext()
+	}
+
+	switch req.Status {
+	case entity.RecurringTransactionStatusPaused:
+		if recurring.Status == entity.RecurringTransactionStatusEnded {
+			return nil, errors.New("recurring transaction has ended")
+		}
+		recurring.Status = entity.RecurringTransactionStatusPaused
+	case entity.RecurringTransactionStatusActive:
+		if recurring.IsPaused() {
+			recurring.Status = entity.RecurringTransactionStatusActive
+			recurring.ScheduleNext()
+			recurring.SkipUntil(time.Now())
+		} else if recurring.Status == entity.RecurringTransactionStatusEnded {
+			return nil, errors.New("recurring transaction has ended")
+		}
+	}
+
+	if err := uc.recurringRepo.Update(recurring); err != nil {
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+// Delete elimina una transacción recurrente. Las transacciones ya generadas se conservan.
+func (uc *RecurringTransactionUseCase) Delete(userID, recurringID uint) error {
+	if _, err := uc.Get(userID, recurringID); err != nil {
+		return err
+	}
+	return uc.recurringRepo.Delete(recurringID)
+}
+
+// Occurrences obtiene las próximas ocurrencias por generar con sus excepciones aplicadas
+func (uc *RecurringTransactionUseCase) Occurrences(userID, recurringID uint, count int) ([]entity.RecurrenceOccurrence, error) {
+	recurring, err := uc.Get(userID, recurringID)
+	if err != nil {
+		return nil, err
+	}
+
+	if count <= 0 {
+		count = defaultRecurrencePreview
+	}
+	if count > maxRecurrencePreview {
+		count = maxRecurrencePreview
+	}
+
+	occurrences := []entity.RecurrenceOccurrence{}
+	if recurring.NextOccurrence == nil {
+		return occurrences, nil
+	}
+	for n := recurring.NextIndex; len(occurrences) < count && recurring.HasOccurrence(n); n++ {
+		occurrences = append(occurrences, recurring.Occurrence(n))
+	}
+
+	return occurrences, nil
+}
+
+// SetException omite o modifica una ocurrencia que aún no se generó. Una ocurrencia movida de
+// fecha debe quedar entre la anterior y la siguiente.
+func (uc *RecurringTransactionUseCase) SetException(userID, recurringID uint, occurrenceDate string, req *dto.SetRecurrenceExceptionRequest) (*entity.RecurrenceOccurrence, error) {
+	recurring, err := uc.Get(userID, recurringID)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := uc.findPendingOccurrence(recurring, occurrenceDate)
+	if err != nil {
+		return nil, err
+	}
+
+	exception := entity.RecurrenceException{
+		RecurringID:    recurring.ID,
+		OccurrenceDate: recurring.OccurrenceDate(index),
+		Action:         req.Action,
+	}
+
+	if req.Action == entity.RecurrenceExceptionModify {
+		if req.Amount == nil && req.Description == "" && req.Notes == "" && req.TransactionDate == "" {
+			return nil, errors.New("invalid recurrence: a modified occurrence must change its amount, description, notes or date")
+		}
+
+		exception.Amount = req.Amount
+		exception.Description = req.Description
+		exception.Notes = req.Notes
+
+		if req.TransactionDate != "" {
+			transactionDate, err := time.Parse(recurrenceDateLayout, req.TransactionDate)
+			if err != nil {
+				return nil, errors.New("invalid recurrence: transaction_date must use the YYYY-MM-DD format")
+			}
+
+			moved := entity.DateOf(transactionDate)
+			if (index > 0 && !moved.After(recurring.OccurrenceDate(index-1))) ||
+				(recurring.HasOccurrence(index+1) && !moved.Before(recurring.OccurrenceDate(index+1))) {
+				return nil, errors.New("invalid recurrence: a moved occurrence must stay between the previous and next occurrences")
+			}
+			exception.TransactionDate = &moved
+		}
+	}
+
+	if err := uc.recurringRepo.SaveException(&exception); err != nil {
+		return nil, err
+	}
+
+	if existing := recurring.ExceptionFor(exception.OccurrenceDate); existing != nil {
+		*existing = exception
+	} else {
+		recurring.Exceptions = append(recurring.Exceptions, exception)
+	}
+
+	occurrence := recurring.Occurrence(index)
+	return &occurrence, nil
+}
+
+// DeleteException devuelve una ocurrencia omitida o modificada a su programación
+func (uc *RecurringTransactionUseCase) DeleteException(userID, recurringID uint, occurrenceDate string) error {
+	recurring, err := uc.Get(userID, recurringID)
+	if err != nil {
+		return err
+	}
+
+	date, err := time.Parse(recurrenceDateLayout, occurrenceDate)
+	if err != nil {
+		return errors.New("invalid recurrence: occurrence date must use the YYYY-MM-DD format")
+	}
+
+	exception := recurring.ExceptionFor(date)
+	if exception == nil {
+		return errors.New("recurrence exception not found")
+	}
+
+	return uc.recurringRepo.DeleteException(recurring.ID, exception.OccurrenceDate)
+}
+
+// Process genera las ocurrencias vencidas de las transacciones recurrentes del usuario
+func (uc *RecurringTransactionUseCase) Process(userID uint) (*dto.RecurringProcessResponse, error) {
+	today := entity.DateOf(time.Now())
+	recurrings, err := uc.recurringRepo.GetDueByUserID(userID, today)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newRecurringProcessResponse()
+	for _, recurring := range recurrings {
+		uc.materialize(recurring, today, result)
+	}
+
+	return result, nil
+}
+
+// ProcessDue genera las ocurrencias vencidas de las transacciones recurrentes de todos los
+// usuarios, recorriéndolas por páginas. Se ejecuta periódicamente; es idempotente, por lo que
+// puede coincidir con Process.
+func (uc *RecurringTransactionUseCase) ProcessDue(now time.Time) (*dto.RecurringProcessResponse, error) {
+	today := entity.DateOf(now)
+	result := newRecurringProcessResponse()
+
+	afterID := uint(0)
+	for {
+		recurrings, err := uc.recurringRepo.GetDue(today, afterID, recurringProcessBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, recurring := range recurrings {
+			uc.materialize(recurring, today, result)
+		}
+
+		if len(recurrings) < recurringProcessBatchSize {
+			return result, nil
+		}
+		afterID = recurrings[len(recurrings)-1].ID
+	}
+}
+
+// materialize genera las ocurrencias de una recurrencia hasta la fecha indicada. Se detiene en la
+// primera que falla (ej: fondos insuficientes) para reintentarla en la siguiente ejecución.
+func (uc *RecurringTransactionUseCase) materialize(recurring *entity.RecurringTransaction, until time.Time, result *dto.RecurringProcessResponse) {
+	result.RecurringChecked++
+
+	for processed := 0; processed < maxOccurrencesPerRun; processed++ {
+		if !recurring.IsActive() || recurring.NextOccurrence == nil || recurring.NextOccurrence.After(until) {
+			return
+		}
+
+		occurrence := recurring.Occurrence(recurring.NextIndex)
+		if occurrence.Status == entity.RecurrenceOccurrenceSkipped {
+			result.Skipped++
+			recurring.Advance(false)
+		} else {
+			// Una ocurrencia movida a una fecha posterior espera hasta esa fecha
+			if occurrence.TransactionDate.After(until) {
+				return
+			}
+
+			transactionID, err := uc.generateOccurrence(recurring, occurrence)
+			if err != nil {
+				result.Failures = append(result.Failures, dto.RecurringProcessFailure{
+					RecurringID:    recurring.ID,
+					OccurrenceDate: occurrence.Date,
+					Error:          err.Error(),
+				})
+				return
+			}
+			if transactionID != 0 {
+				result.Generated++
+				result.TransactionIDs = append(result.TransactionIDs, transactionID)
+			}
+			recurring.Advance(true)
+		}
+
+		if err := uc.recurringRepo.Update(recurring); err != nil {
+			result.Failures = append(result.Failures, dto.RecurringProcessFailure{
+				RecurringID:    recurring.ID,
+				OccurrenceDate: occurrence.Date,
+				Error:          err.Error(),
+			})
+			return
+		}
+	}
+}
+
+// generateOccurrence crea la transacción de una ocurrencia si no existe. Retorna 0 si ya se había
+// generado (ej: en una ejecución interrumpida o simultánea).
+func (uc *RecurringTransactionUseCase) generateOccurrence(recurring *entity.RecurringTransaction, occurrence entity.RecurrenceOccurrence) (uint, error) {
+	exists, err := uc.transactionRepo.ExistsRecurringOccurrence(recurring.ID, occurrence.Date)
+	if err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, nil
+	}
+
+	transaction := recurring.NewTransaction(occurrence)
+	if err := uc.transactionUC.CreateScheduled(transaction); err != nil {
+		// Otra ejecución pudo generar la ocurrencia al mismo tiempo (índice único)
+		if exists, checkErr := uc.transactionRepo.ExistsRecurringOccurrence(recurring.ID, occurrence.Date); checkErr == nil && exists {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to generate occurrence: %w", err)
+	}
+
+	return transaction.ID, nil
+}
+
+// findPendingOccurrence busca una ocurrencia por fecha y verifica que aún no se generó
+func (uc *RecurringTransactionUseCase) findPendingOccurrence(recurring *entity.RecurringTransaction, occurrenceDate string) (int, error) {
+	date, err := time.Parse(recurrenceDateLayout, occurrenceDate)
+	if err != nil {
+		return 0, errors.New("invalid recurrence: occurrence date must use the YYYY-MM-DD format")
+	}
+
+	index, ok := recurring.OccurrenceIndex(date)
+	if !ok {
+		return 0, errors.New("recurring occurrence not found")
+	}
+	if index < recurring.NextIndex {
+		return 0, errors.New("recurring occurrence already generated")
+	}
+
+	return index, nil
+}
+
+// setCategory asigna la categoría de las transacciones generadas, verificando que el usuario puede usarla
+func (uc *RecurringTransactionUseCase) setCategory(userID uint, recurring *entity.RecurringTransaction, categoryID uint) error {
+	category, err := uc.categoryRepo.GetByID(categoryID)
+	if err != nil {
+		return errors.New("category not found")
+	}
+	if !category.IsSystemCategory() && category.UserID != nil && *category.UserID != userID {
+		return errors.New("category not found")
+	}
+
+	recurring.CategoryID = &categoryID
+	recurring.CategoryName = category.Name
+	return nil
+}
+
+// validateSchedule completa los valores por defecto de la programación y verifica que sea coherente
+func (uc *RecurringTransactionUseCase) validateSchedule(recurring *entity.RecurringTransaction) error {
+	if recurring.Interval == 0 {
+		recurring.Interval = 1
+	}
+	if recurring.BusinessDay == "" {
+		recurring.BusinessDay = entity.BusinessDayNone
+	}
+
+	daily := recurring.Frequency == entity.RecurrenceFrequencyDaily
+	if recurring.DayOfMonth != 0 && (daily || recurring.Frequency == entity.RecurrenceFrequencyWeekly) {
+		return errors.New("invalid recurrence: day_of_month only applies to monthly and yearly schedules")
+	}
+	if daily && recurring.BusinessDay != entity.BusinessDayNone {
+		return errors.New("invalid recurrence: business day adjustment does not apply to daily schedules")
+	}
+	if recurring.EndDate != nil && entity.DateOf(*recurring.EndDate).Before(recurring.StartDate) {
+		return errors.New("invalid recurrence: end_date cannot be before start_date")
+	}
+	if !recurring.HasOccurrence(0) {
+		return errors.New("invalid recurrence: the schedule has no occurrences before its end")
+	}
+
+	return nil
+}
+
+// newRecurringProcessResponse crea el resultado vacío de una ejecución del proceso
+func newRecurringProcessResponse() *dto.RecurringProcessResponse {
+	return &dto.RecurringProcessResponse{
+		TransactionIDs: []uint{},
+		Failures:       []dto.RecurringProcessFailure{},
+		ProcessedAt:    time.Now(),
+	}
+}
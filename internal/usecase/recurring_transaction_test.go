@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+)
+
+// fakeRecurringRepo pagina por ID las recurrencias vencidas guardadas en memoria
+type fakeRecurringRepo struct {
+	repo.RecurringTransactionRepo
+	recurrings []*entity.RecurringTransaction
+	pages      int
+}
+
+func (r *fakeRecurringRepo) GetDue(until time.Time, afterID uint, limit int) ([]*entity.RecurringTransaction, error) {
+	r.pages++
+	var page []*entity.RecurringTransaction
+	for _, recurring := range r.recurrings {
+		if recurring.ID > afterID && len(page) < limit {
+			page = append(page, recurring)
+		}
+	}
+	return page, nil
+}
+
+func TestProcessDueReadsEveryPage(t *testing.T) {
+	recurringRepo := &fakeRecurringRepo{}
+	for id := uint(1); id <= 2*recurringProcessBatchSize+1; id++ {
+		// Pausadas: se revisan sin generar ocurrencias
+		recurringRepo.recurrings = append(recurringRepo.recurrings, &entity.RecurringTransaction{ID: id, Status: entity.RecurringTransactionStatusPaused})
+	}
+	uc := NewRecurringTransactionUseCase(recurringRepo, nil, nil, nil, nil)
+
+	result, err := uc.ProcessDue(time.Now())
+	if err != nil {
+		t.Fatalf("ProcessDue error: %v", err)
+	}
+	if result.RecurringChecked != len(recurringRepo.recurrings) || recurringRepo.pages != 3 {
+		t.Fatalf("checked %d recurrings in %d pages, want %d in 3", result.RecurringChecked, recurringRepo.pages, len(recurringRepo.recurrings))
+	}
+}
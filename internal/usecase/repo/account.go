@@ -1,6 +1,9 @@
 package repo
 
-import "github.com/nick130920/fintech-backend/internal/entity"
+import (
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
 
 // AccountRepo define la interfaz para operaciones de cuenta en la base de datos
 type AccountRepo interface {
@@ -13,14 +16,11 @@ type AccountRepo interface {
 
 	// Operaciones específicas de cuentas
 	GetByUserIDAndType(userID uint, accountType entity.AccountType) ([]*entity.Account, error)
-	UpdateBalance(id uint, newBalance float64) error
-	AddToBalance(id uint, amount float64) error
-	SubtractFromBalance(id uint, amount float64) error
 
 	// Validaciones y consultas
 	HasTransactions(id uint) (bool, error)
 	SetActive(id uint, active bool) error
 	GetActiveAccounts(userID uint) ([]*entity.Account, error)
-	GetTotalBalance(userID uint) (float64, error)
+	GetTotalBalance(userID uint) (money.Amount, error)
 	GetAccountsByTypeAndUser(userID uint, accountType entity.AccountType, activeOnly bool) ([]*entity.Account, error)
 }
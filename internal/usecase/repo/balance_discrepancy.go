@@ -0,0 +1,11 @@
+package repo
+
+import "github.com/nick130920/fintech-backend/internal/entity"
+
+// BalanceDiscrepancyRepo define la interfaz para las diferencias de conciliación de saldos
+type BalanceDiscrepancyRepo interface {
+	Create(discrepancy *entity.BalanceDiscrepancy) error
+	GetByID(id uint) (*entity.BalanceDiscrepancy, error)
+	Update(discrepancy *entity.BalanceDiscrepancy) error
+	GetWithFilters(userID uint, filter entity.BalanceDiscrepancyFilter) ([]*entity.BalanceDiscrepancy, int64, error)
+}
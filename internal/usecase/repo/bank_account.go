@@ -1,6 +1,9 @@
 package repo
 
-import "github.com/nick130920/fintech-backend/internal/entity"
+import (
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
 
 // BankAccountRepo define la interfaz para operaciones de cuenta bancaria en la base de datos
 type BankAccountRepo interface {
@@ -18,13 +21,14 @@ type BankAccountRepo interface {
 	// Operaciones de búsqueda
 	GetByBankName(userID uint, bankName string) ([]*entity.BankAccount, error)
 	GetByAccountNumberMask(userID uint, mask string) (*entity.BankAccount, error)
+	GetAllByAccountNumberMask(userID uint, masks ...string) ([]*entity.BankAccount, error)
 	SearchByAlias(userID uint, alias string) ([]*entity.BankAccount, error)
 	GetWithFilters(userID uint, filter entity.BankAccountFilter) ([]*entity.BankAccount, int64, error)
 
 	// Operaciones de estado
 	SetActive(id uint, active bool) error
 	SetNotificationEnabled(id uint, enabled bool) error
-	UpdateBalance(id uint, balance float64) error
+	UpdateBalance(id uint, balance money.Amount) error
 
 	// Estadísticas y consultas especiales
 	CountByUserID(userID uint) (int64, error)
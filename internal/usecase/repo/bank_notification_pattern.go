@@ -6,6 +6,7 @@ import "github.com/nick130920/fintech-backend/internal/entity"
 type BankNotificationPatternRepo interface {
 	// Operaciones básicas CRUD
 	Create(pattern *entity.BankNotificationPattern) error
+	CreateFromTemplate(pattern *entity.BankNotificationPattern) error
 	GetByID(id uint) (*entity.BankNotificationPattern, error)
 	Update(pattern *entity.BankNotificationPattern) error
 	Delete(id uint) error
@@ -24,11 +25,17 @@ type BankNotificationPatternRepo interface {
 	GetMatchingPatterns(bankAccountID uint, channel entity.NotificationChannel, message string) ([]*entity.BankNotificationPattern, error)
 	GetDefaultPattern(bankAccountID uint, channel entity.NotificationChannel) (*entity.BankNotificationPattern, error)
 	GetByPriority(bankAccountID uint, channel entity.NotificationChannel) ([]*entity.BankNotificationPattern, error)
+	GetCandidatePatterns(bankAccountID uint, channel entity.NotificationChannel) ([]*entity.BankNotificationPattern, error)
+
+	// Operaciones de la biblioteca de plantillas
+	GetByTemplateID(templateID uint) ([]*entity.BankNotificationPattern, error)
 
 	// Operaciones de estado y configuración
 	SetStatus(id uint, status entity.NotificationPatternStatus) error
 	SetDefault(id uint, isDefault bool) error
-	UpdateStatistics(id uint, matchCount, successCount int, successRate float64) error
+	UpdateStatistics(pattern *entity.BankNotificationPattern) error
+	RecordMatch(id uint, success bool) error
+	RecordFeedback(id uint, success, wasSuccess bool) error
 
 	// Estadísticas y consultas especiales
 	CountByUserID(userID uint) (int64, error)
@@ -1,6 +1,9 @@
 package repo
 
-import "github.com/nick130920/fintech-backend/internal/entity"
+import (
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
 
 // CategoryRepo define la interfaz para operaciones de categoría en la base de datos
 type CategoryRepo interface {
@@ -26,7 +29,7 @@ type CategoryRepo interface {
 
 	// Verificaciones
 	HasExpenses(categoryID uint) (bool, error)
-	GetCategoryUsageStats(categoryID uint) (int64, float64, error) // count, total amount
+	GetCategoryUsageStats(categoryID uint) (int64, money.Amount, error) // count, total amount
 
 	// Inicialización del sistema
 	CreateDefaultCategories() error
@@ -0,0 +1,11 @@
+package repo
+
+import "github.com/nick130920/fintech-backend/internal/entity"
+
+// EmailIngestAliasRepo define la interfaz para los alias de ingesta de correos
+type EmailIngestAliasRepo interface {
+	Create(alias *entity.EmailIngestAlias) error
+	Update(alias *entity.EmailIngestAlias) error
+	GetByUserID(userID uint) (*entity.EmailIngestAlias, error)
+	GetByAlias(alias string) (*entity.EmailIngestAlias, error)
+}
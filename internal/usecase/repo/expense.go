@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // ExpenseRepo define la interfaz para operaciones de gasto en la base de datos
@@ -15,6 +16,10 @@ type ExpenseRepo interface {
 	Update(expense *entity.Expense) error
 	Delete(id uint) error
 
+	// Operaciones con actualización de presupuesto
+	CreateWithBudgetUpdate(expense *entity.Expense) error
+	CancelWithBudgetUpdate(expense *entity.Expense) error
+
 	// Operaciones con filtros avanzados
 	GetByUserIDWithFilter(userID uint, filter *entity.ExpenseFilter) ([]*entity.ExpenseSummary, error)
 	GetByUserAndDateRange(userID uint, fromDate, toDate *time.Time) ([]*entity.Expense, error)
@@ -39,20 +44,22 @@ type ExpenseRepo interface {
 	GetExpensesByCategories(userID uint, categoryIDs []uint, fromDate, toDate *time.Time) ([]*entity.Expense, error)
 
 	// Operaciones de cálculo
-	CalculateTotalByUser(userID uint, fromDate, toDate *time.Time) (float64, error)
-	CalculateTotalByCategory(userID, categoryID uint, fromDate, toDate *time.Time) (float64, error)
-	CalculateTotalByAllocation(allocationID uint) (float64, error)
-	CalculateDailyAverage(userID uint, fromDate, toDate *time.Time) (float64, error)
+	CalculateTotalByUser(userID uint, fromDate, toDate *time.Time) (money.Amount, error)
+	CalculateTotalByCategory(userID, categoryID uint, fromDate, toDate *time.Time) (money.Amount, error)
+	CalculateTotalByAllocation(allocationID uint) (money.Amount, error)
+	CalculateDailyAverage(userID uint, fromDate, toDate *time.Time) (money.Amount, error)
 
 	// Estadísticas y reportes
-	GetExpensesBySource(userID uint, fromDate, toDate *time.Time) (map[entity.ExpenseSource]float64, error)
+	GetExpensesBySource(userID uint, fromDate, toDate *time.Time) (map[entity.ExpenseSource]money.Amount, error)
 	GetTopMerchants(userID uint, limit int, fromDate, toDate *time.Time) ([]map[string]interface{}, error)
-	GetCategoryTotals(userID uint, fromDate, toDate *time.Time) (map[uint]float64, error)
-	GetDailyTotals(userID uint, fromDate, toDate *time.Time) (map[string]float64, error)
+	GetCategoryTotals(userID uint, fromDate, toDate *time.Time) (map[uint]money.Amount, error)
+	GetDailyTotals(userID uint, fromDate, toDate *time.Time) (map[string]money.Amount, error)
 
 	// Operaciones para procesamiento automático
 	CreateFromSMS(smsData map[string]interface{}) (*entity.Expense, error)
-	GetDuplicateCandidates(amount float64, description string, date time.Time, tolerance time.Duration) ([]*entity.Expense, error)
+	GetDuplicateCandidates(userID uint, amount money.Amount, merchant string, date time.Time, tolerance time.Duration) ([]*entity.Expense, error)
+	GetReversalCandidates(bankAccountID uint, amount money.Amount, since time.Time) ([]*entity.Expense, error)
+	CalculateNotificationTotalByBankAccount(bankAccountID uint, since time.Time) (money.Amount, error)
 
 	// Búsquedas avanzadas
 	SearchByDescription(userID uint, searchTerm string, limit int) ([]*entity.Expense, error)
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // IncomeRepo define la interfaz para el repositorio de ingresos
@@ -22,7 +23,7 @@ type IncomeRepo interface {
 	GetPendingRecurringIncomes(userID uint) ([]*entity.Income, error)
 
 	// Estadísticas y resúmenes
-	GetTotalIncomeByUser(userID uint, startDate, endDate *time.Time) (float64, error)
+	GetTotalIncomeByUser(userID uint, startDate, endDate *time.Time) (money.Amount, error)
 	GetIncomeByMonth(userID uint, year, month int) ([]*entity.Income, error)
 	GetIncomeByYear(userID uint, year int) ([]*entity.Income, error)
 
@@ -0,0 +1,23 @@
+package repo
+
+import (
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// LedgerRepo define la interfaz para el libro contable de partida doble
+type LedgerRepo interface {
+	// Post registra un asiento balanceado y actualiza el balance de sus cuentas en una transacción de DB
+	Post(entry *entity.LedgerEntry) error
+	// PostTransfer registra un traspaso verificando con la cuenta origen bloqueada que tenga fondos disponibles
+	PostTransfer(entry *entity.LedgerEntry, fromAccountID, toAccountID uint, amount money.Amount) error
+	GetEntries(userID uint, filter entity.LedgerEntryFilter) ([]*entity.LedgerEntry, int64, error)
+
+	// Verificación de integridad
+	CountEntries(userID uint) (int64, error)
+	GetUnbalancedEntries(userID uint) ([]entity.LedgerImbalance, error)
+	GetAccountTotals(userID uint) (map[uint]money.Amount, error)
+
+	// CreateOpeningEntries registra el saldo inicial de las cuentas que aún no tienen asientos
+	CreateOpeningEntries() (int, error)
+}
@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// NotificationFingerprintRepo define la interfaz para las huellas de notificaciones procesadas
+type NotificationFingerprintRepo interface {
+	Create(fingerprint *entity.NotificationFingerprint) error
+
+	// Consultas para detectar duplicados (solo notificaciones originales, no enlazadas)
+	GetByFingerprint(bankAccountID uint, fingerprint string) (*entity.NotificationFingerprint, error)
+	GetCandidates(bankAccountID uint, amount money.Amount, from, to time.Time) ([]*entity.NotificationFingerprint, error)
+}
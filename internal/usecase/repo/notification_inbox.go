@@ -0,0 +1,11 @@
+package repo
+
+import "github.com/nick130920/fintech-backend/internal/entity"
+
+// NotificationInboxRepo define la interfaz para la bandeja de notificaciones recibidas
+type NotificationInboxRepo interface {
+	Create(item *entity.NotificationInboxItem) error
+	GetByID(id uint) (*entity.NotificationInboxItem, error)
+	Update(item *entity.NotificationInboxItem) error
+	GetWithFilters(userID uint, filter entity.NotificationInboxFilter) ([]*entity.NotificationInboxItem, int64, error)
+}
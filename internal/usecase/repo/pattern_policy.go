@@ -0,0 +1,16 @@
+package repo
+
+import "github.com/nick130920/fintech-backend/internal/entity"
+
+// PatternPolicyRepo define la interfaz para las políticas de ciclo de vida de patrones
+type PatternPolicyRepo interface {
+	GetByUserID(userID uint) (*entity.PatternPolicy, error)
+	Save(policy *entity.PatternPolicy) error
+}
+
+// PatternHistoryRepo define la interfaz para el historial de cambios de estado de patrones
+type PatternHistoryRepo interface {
+	Create(history *entity.PatternHistory) error
+	CreateWithStatusChange(history *entity.PatternHistory) error
+	GetByPatternID(patternID uint) ([]*entity.PatternHistory, error)
+}
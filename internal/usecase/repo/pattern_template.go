@@ -0,0 +1,12 @@
+package repo
+
+import "github.com/nick130920/fintech-backend/internal/entity"
+
+// PatternTemplateRepo define la interfaz para operaciones de la biblioteca de plantillas de patrones
+type PatternTemplateRepo interface {
+	Create(template *entity.PatternTemplate) error
+	GetByID(id uint) (*entity.PatternTemplate, error)
+	GetBySlug(slug string) (*entity.PatternTemplate, error)
+	Update(template *entity.PatternTemplate) error
+	GetSystemTemplates(filter entity.PatternTemplateFilter) ([]*entity.PatternTemplate, error)
+}
@@ -0,0 +1,24 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+)
+
+// RecurringTransactionRepo define la interfaz para las transacciones recurrentes y sus excepciones
+type RecurringTransactionRepo interface {
+	Create(recurring *entity.RecurringTransaction) error
+	Update(recurring *entity.RecurringTransaction) error
+	Delete(id uint) error
+	GetByID(id uint) (*entity.RecurringTransaction, error)
+	GetByUserID(userID uint, status *entity.RecurringTransactionStatus) ([]*entity.RecurringTransaction, error)
+
+	// Recurrencias activas con ocurrencias por generar hasta una fecha
+	GetDue(until time.Time, afterID uint, limit int) ([]*entity.RecurringTransaction, error)
+	GetDueByUserID(userID uint, until time.Time) ([]*entity.RecurringTransaction, error)
+
+	// Excepciones de ocurrencias puntuales
+	SaveException(exception *entity.RecurrenceException) error
+	DeleteException(recurringID uint, occurrenceDate time.Time) error
+}
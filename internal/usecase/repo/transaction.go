@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // TransactionRepo define la interfaz para operaciones de transacción en la base de datos
@@ -18,9 +19,24 @@ type TransactionRepo interface {
 	// Operaciones con manejo de balance
 	CreateWithBalanceUpdate(transaction *entity.Transaction) error
 	DeleteWithBalanceUpdate(id uint) error
+	CancelWithBalanceUpdate(transaction *entity.Transaction) error
+	ClearWithBalanceUpdate(transaction *entity.Transaction) error
+
+	// Autorizaciones pendientes
+	GetExpiredHolds(before time.Time, limit int) ([]*entity.Transaction, error)
+	ExpireHold(id uint, at time.Time) (*entity.Transaction, error)
+
+	// Líneas de transacciones divididas
+	ReplaceSplits(transaction *entity.Transaction) error
 
 	// Consultas específicas
 	GetByUserIDWithFilter(userID uint, filter *entity.TransactionFilter) ([]*entity.TransactionSummary, error)
-	CalculateAccountBalance(accountID uint) (float64, error)
-	GetTotalsByType(userID uint, fromDate, toDate *time.Time) (map[entity.TransactionType]float64, error)
+	CalculateAccountBalance(accountID uint) (money.Amount, error)
+	GetTotalsByType(userID uint, fromDate, toDate *time.Time) (map[entity.TransactionType]money.Amount, error)
+	GetReversalCandidates(bankAccountID uint, amount money.Amount, since time.Time) ([]*entity.Transaction, error)
+	GetNetAmountByBankAccount(bankAccountID uint, since time.Time) (money.Amount, error)
+	GetCategoryTotals(userID uint, fromDate, toDate *time.Time) ([]entity.TransactionCategoryTotal, error)
+
+	// Ocurrencias de transacciones recurrentes
+	ExistsRecurringOccurrence(recurringID uint, occurrenceDate time.Time) (bool, error)
 }
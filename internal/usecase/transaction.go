@@ -2,11 +2,13 @@ package usecase
 
 import (
 	"errors"
+	"log"
 	"time"
 
 	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // TransactionUseCase contiene la lógica de negocio para transacciones
@@ -14,18 +16,31 @@ type TransactionUseCase struct {
 	transactionRepo repo.TransactionRepo
 	accountRepo     repo.AccountRepo
 	userRepo        repo.UserRepo
+	budgetRepo      repo.BudgetRepo
+	categoryRepo    repo.CategoryRepo
+	patternPolicyUC *PatternPolicyUseCase
+	holdExpiry      time.Duration
 }
 
-// NewTransactionUseCase crea una nueva instancia de TransactionUseCase
+// NewTransactionUseCase crea una nueva instancia de TransactionUseCase. holdExpiry es el tiempo tras
+// el cual vencen las transacciones pendientes sin confirmar; 0 deshabilita el vencimiento.
 func NewTransactionUseCase(
 	transactionRepo repo.TransactionRepo,
 	accountRepo repo.AccountRepo,
 	userRepo repo.UserRepo,
+	budgetRepo repo.BudgetRepo,
+	categoryRepo repo.CategoryRepo,
+	patternPolicyUC *PatternPolicyUseCase,
+	holdExpiry time.Duration,
 ) *TransactionUseCase {
 	return &TransactionUseCase{
 		transactionRepo: transactionRepo,
 		accountRepo:     accountRepo,
 		userRepo:        userRepo,
+		budgetRepo:      budgetRepo,
+		categoryRepo:    categoryRepo,
+		patternPolicyUC: patternPolicyUC,
+		holdExpiry:      holdExpiry,
 	}
 }
 
@@ -40,27 +55,10 @@ func (uc *TransactionUseCase) Create(userID uint, req *dto.CreateTransactionRequ
 		return nil, errors.New("user account is not active")
 	}
 
-	// Verificar que la cuenta existe y pertenece al usuario
-	account, err := uc.accountRepo.GetByID(req.AccountID)
-	if err != nil || account.UserID != userID {
-		return nil, errors.New("account not found")
-	}
-
-	// Para transferencias, verificar cuenta destino
-	var toAccount *entity.Account
-	if req.Type == entity.TransactionTypeTransfer {
-		if req.ToAccountID == nil {
-			return nil, errors.New("to_account_id is required for transfers")
-		}
-
-		toAccount, err = uc.accountRepo.GetByID(*req.ToAccountID)
-		if err != nil || toAccount.UserID != userID {
-			return nil, errors.New("destination account not found")
-		}
-
-		if req.AccountID == *req.ToAccountID {
-			return nil, errors.New("cannot transfer to the same account")
-		}
+	// Verificar que las cuentas existen y pertenecen al usuario
+	account, toAccount, err := uc.resolveAccounts(userID, req.Type, req.AccountID, req.ToAccountID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Validar fondos suficientes para gastos y transferencias
@@ -111,14 +109,63 @@ func (uc *TransactionUseCase) Create(userID uint, req *dto.CreateTransactionRequ
 		return nil, err
 	}
 
+	// Las autorizaciones retienen el monto hasta que se confirman
+	if req.Hold {
+		newTransaction.PlaceHold()
+	}
+
+	// Dividir la transacción en líneas por categoría si se especifican
+	if len(req.Splits) > 0 {
+		splits, err := uc.buildSplits(userID, newTransaction, req.Splits)
+		if err != nil {
+			return nil, err
+		}
+		newTransaction.Splits = splits
+		newTransaction.CategoryID = nil
+	}
+
 	// Guardar la transacción usando transacción de base de datos
 	if err := uc.transactionRepo.CreateWithBalanceUpdate(newTransaction); err != nil {
 		return nil, err
 	}
 
+	if err := uc.refreshBudgets(userID, nil, newTransaction); err != nil {
+		return nil, err
+	}
+
 	return newTransaction, nil
 }
 
+// CreateScheduled registra una transacción generada por el sistema (ej: la ocurrencia de una
+// transacción recurrente) con las mismas validaciones de cuentas y fondos que Create
+func (uc *TransactionUseCase) CreateScheduled(transaction *entity.Transaction) error {
+	account, toAccount, err := uc.resolveAccounts(transaction.UserID, transaction.Type, transaction.AccountID, transaction.ToAccountID)
+	if err != nil {
+		return err
+	}
+
+	if transaction.IsExpense() || transaction.IsTransfer() {
+		if !account.CanDebit(transaction.Amount) {
+			return errors.New("insufficient funds")
+		}
+	}
+
+	if err := uc.validateTransactionRules(transaction, account, toAccount); err != nil {
+		return err
+	}
+
+	if err := uc.transactionRepo.CreateWithBalanceUpdate(transaction); err != nil {
+		return err
+	}
+
+	// La transacción ya existe: un fallo al recalcular el presupuesto no debe reportar la ocurrencia como no generada
+	if err := uc.refreshBudgets(transaction.UserID, nil, transaction); err != nil {
+		log.Printf("Warning: Failed to refresh budgets for scheduled transaction %d: %v", transaction.ID, err)
+	}
+
+	return nil
+}
+
 // GetByUserID obtiene transacciones de un usuario con filtros
 func (uc *TransactionUseCase) GetByUserID(userID uint, filter *entity.TransactionFilter) ([]*entity.TransactionSummary, error) {
 	// Verificar que el usuario existe
@@ -164,11 +211,20 @@ func (uc *TransactionUseCase) Update(userID, transactionID uint, req *dto.Update
 		return nil, errors.New("transaction cannot be modified")
 	}
 
+	// La categoría y la fecha determinan el presupuesto al que suma el gasto
+	previousAllocations, err := uc.budgetAllocationIDs(userID, transaction)
+	if err != nil {
+		return nil, err
+	}
+
 	// Actualizar campos si se proporcionan
 	if req.Description != "" {
 		transaction.Description = req.Description
 	}
 	if req.CategoryID != nil {
+		if transaction.IsSplit() {
+			return nil, errors.New("invalid split: split transactions are categorized by their lines")
+		}
 		transaction.CategoryID = req.CategoryID
 	}
 	if req.Location != "" {
@@ -180,9 +236,6 @@ func (uc *TransactionUseCase) Update(userID, transactionID uint, req *dto.Update
 	if req.Notes != "" {
 		transaction.Notes = req.Notes
 	}
-	if req.Status != "" {
-		transaction.Status = req.Status
-	}
 
 	// Parsear fecha de transacción si se proporciona
 	if req.TransactionDate != "" {
@@ -198,7 +251,25 @@ func (uc *TransactionUseCase) Update(userID, transactionID uint, req *dto.Update
 		}
 	}
 
-	if err := uc.transactionRepo.Update(transaction); err != nil {
+	// Los cambios de estado de una transacción pendiente la confirman o la cancelan
+	switch {
+	case req.Status == "" || req.Status == transaction.Status:
+		if err := uc.transactionRepo.Update(transaction); err != nil {
+			return nil, err
+		}
+	case transaction.IsPending() && req.Status == entity.TransactionStatusCompleted:
+		if err := uc.clearPending(transaction, transaction.Amount); err != nil {
+			return nil, err
+		}
+	case transaction.IsPending() && req.Status == entity.TransactionStatusCancelled:
+		if err := uc.cancelPending(transaction); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("invalid status transition")
+	}
+
+	if err := uc.refreshBudgets(userID, previousAllocations, transaction); err != nil {
 		return nil, err
 	}
 
@@ -218,10 +289,15 @@ func (uc *TransactionUseCase) Delete(userID, transactionID uint) error {
 	}
 
 	// Eliminar la transacción y revertir el balance
-	return uc.transactionRepo.DeleteWithBalanceUpdate(transactionID)
+	if err := uc.transactionRepo.DeleteWithBalanceUpdate(transactionID); err != nil {
+		return err
+	}
+
+	return uc.refreshBudgets(userID, nil, transaction)
 }
 
-// Cancel cancela una transacción pendiente
+// Cancel cancela una transacción pendiente: revierte su asiento o, si es una autorización,
+// libera los fondos que retenía
 func (uc *TransactionUseCase) Cancel(userID, transactionID uint) error {
 	transaction, err := uc.GetByID(userID, transactionID)
 	if err != nil {
@@ -232,12 +308,82 @@ func (uc *TransactionUseCase) Cancel(userID, transactionID uint) error {
 		return errors.New("transaction cannot be cancelled")
 	}
 
-	transaction.Cancel()
-	return uc.transactionRepo.Update(transaction)
+	return uc.cancelPending(transaction)
+}
+
+// Approve confirma una transacción generada desde una notificación y registra
+// el éxito en las estadísticas del patrón que la procesó
+func (uc *TransactionUseCase) Approve(userID, transactionID uint) (*entity.Transaction, error) {
+	transaction, err := uc.GetByID(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.IsRejected() || transaction.IsCancelled() {
+		return nil, errors.New("transaction cannot be approved")
+	}
+	if transaction.IsManuallyValidated() {
+		return transaction, nil
+	}
+
+	wasSuccess := transaction.IsAutoValidated()
+
+	// Aprobar una transacción pendiente la confirma con su monto actual
+	if transaction.IsPending() {
+		transaction.Clear(transaction.Amount, time.Now())
+		transaction.Approve()
+		err = uc.transactionRepo.ClearWithBalanceUpdate(transaction)
+	} else {
+		transaction.Approve()
+		err = uc.transactionRepo.Update(transaction)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	uc.recordPatternFeedback(transaction, true, wasSuccess)
+	return transaction, nil
+}
+
+// Reject rechaza una transacción generada desde una notificación, revierte su
+// efecto en el balance (o libera los fondos que retenía si estaba pendiente) y
+// registra el fallo del patrón que la procesó
+func (uc *TransactionUseCase) Reject(userID, transactionID uint) (*entity.Transaction, error) {
+	transaction, err := uc.GetByID(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.IsRejected() || transaction.IsCancelled() {
+		return nil, errors.New("transaction cannot be rejected")
+	}
+
+	wasSuccess := transaction.IsAutoValidated() || transaction.IsManuallyValidated()
+	transaction.Reject()
+
+	if err := uc.transactionRepo.CancelWithBalanceUpdate(transaction); err != nil {
+		return nil, err
+	}
+
+	if err := uc.refreshBudgets(userID, nil, transaction); err != nil {
+		return nil, err
+	}
+
+	uc.recordPatternFeedback(transaction, false, wasSuccess)
+	return transaction, nil
+}
+
+// recordPatternFeedback registra la validación del usuario en el patrón que generó la transacción.
+func (uc *TransactionUseCase) recordPatternFeedback(transaction *entity.Transaction, success, wasSuccess bool) {
+	if transaction.PatternID == nil {
+		return
+	}
+
+	uc.patternPolicyUC.RecordFeedback(*transaction.PatternID, success, wasSuccess)
 }
 
 // GetAccountBalance calcula el balance actual de una cuenta basado en transacciones
-func (uc *TransactionUseCase) GetAccountBalance(userID, accountID uint) (float64, error) {
+func (uc *TransactionUseCase) GetAccountBalance(userID, accountID uint) (money.Amount, error) {
 	// Verificar que la cuenta pertenece al usuario
 	account, err := uc.accountRepo.GetByID(accountID)
 	if err != nil || account.UserID != userID {
@@ -248,7 +394,7 @@ func (uc *TransactionUseCase) GetAccountBalance(userID, accountID uint) (float64
 }
 
 // GetUserTotalsByType obtiene totales por tipo de transacción para un usuario
-func (uc *TransactionUseCase) GetUserTotalsByType(userID uint, fromDate, toDate *time.Time) (map[entity.TransactionType]float64, error) {
+func (uc *TransactionUseCase) GetUserTotalsByType(userID uint, fromDate, toDate *time.Time) (map[entity.TransactionType]money.Amount, error) {
 	// Verificar que el usuario existe
 	if _, err := uc.userRepo.GetByID(userID); err != nil {
 		return nil, errors.New("user not found")
@@ -273,6 +419,39 @@ func (uc *TransactionUseCase) GetRecentTransactions(userID uint, limit int) ([]*
 	return uc.GetByUserID(userID, filter)
 }
 
+// resolveAccounts obtiene la cuenta origen y, para transferencias, la cuenta destino, verificando
+// que pertenecen al usuario
+func (uc *TransactionUseCase) resolveAccounts(
+	userID uint,
+	transactionType entity.TransactionType,
+	accountID uint,
+	toAccountID *uint,
+) (*entity.Account, *entity.Account, error) {
+	account, err := uc.accountRepo.GetByID(accountID)
+	if err != nil || account.UserID != userID {
+		return nil, nil, errors.New("account not found")
+	}
+
+	if transactionType != entity.TransactionTypeTransfer {
+		return account, nil, nil
+	}
+
+	if toAccountID == nil {
+		return nil, nil, errors.New("to_account_id is required for transfers")
+	}
+
+	toAccount, err := uc.accountRepo.GetByID(*toAccountID)
+	if err != nil || toAccount.UserID != userID {
+		return nil, nil, errors.New("destination account not found")
+	}
+
+	if accountID == *toAccountID {
+		return nil, nil, errors.New("cannot transfer to the same account")
+	}
+
+	return account, toAccount, nil
+}
+
 // validateTransactionRules valida las reglas de negocio para transacciones
 func (uc *TransactionUseCase) validateTransactionRules(
 	transaction *entity.Transaction,
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// holdExpiryBatchSize limita las autorizaciones vencidas que se procesan por ejecución
+const holdExpiryBatchSize = 500
+
+// Clear confirma una transacción pendiente. El monto final puede diferir del autorizado (ej: la
+// propina de un restaurante o el total de una retención de combustible); no se valida contra el
+// balance disponible porque el banco ya registró el movimiento.
+func (uc *TransactionUseCase) Clear(userID, transactionID uint, req *dto.ClearTransactionRequest) (*entity.Transaction, error) {
+	transaction, err := uc.GetByID(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !transaction.IsPending() {
+		return nil, errors.New("transaction is not pending")
+	}
+
+	amount := transaction.Amount
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+
+	if err := uc.clearPending(transaction, amount); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// ExpirePending cancela las autorizaciones de todos los usuarios que no se confirmaron dentro de la
+// ventana configurada y libera sus fondos. Las transacciones pendientes que no son autorizaciones
+// (ej: en espera de revisión) nunca vencen. Retorna cuántas vencieron; las que fallan se reintentan
+// en la siguiente ejecución.
+func (uc *TransactionUseCase) ExpirePending(now time.Time) (int, error) {
+	if uc.holdExpiry <= 0 {
+		return 0, nil
+	}
+
+	holds, err := uc.transactionRepo.GetExpiredHolds(now.Add(-uc.holdExpiry), holdExpiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var errs []error
+	for _, hold := range holds {
+		// La autorización pudo confirmarse o cancelarse después de consultarla: el repositorio lo
+		// verifica bajo bloqueo y la deja intacta
+		expiredHold, err := uc.transactionRepo.ExpireHold(hold.ID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to expire transaction %d: %w", hold.ID, err))
+			continue
+		}
+		if err := uc.refreshBudgets(expiredHold.UserID, nil, expiredHold); err != nil {
+			errs = append(errs, err)
+		}
+		expired++
+	}
+
+	return expired, errors.Join(errs...)
+}
+
+// clearPending confirma una transacción pendiente con su monto final y registra su asiento
+func (uc *TransactionUseCase) clearPending(transaction *entity.Transaction, amount money.Amount) error {
+	if !amount.IsPositive() {
+		return errors.New("transaction amount must be positive")
+	}
+	if transaction.IsSplit() && amount != transaction.Amount {
+		return errors.New("invalid split: split transactions clear at the amount of their lines")
+	}
+
+	transaction.Clear(amount, time.Now())
+	if err := uc.transactionRepo.ClearWithBalanceUpdate(transaction); err != nil {
+		return err
+	}
+
+	// El monto final puede diferir del autorizado
+	return uc.refreshBudgets(transaction.UserID, nil, transaction)
+}
+
+// cancelPending cancela una transacción pendiente, libera sus fondos y recalcula los presupuestos
+// a los que sumaba
+func (uc *TransactionUseCase) cancelPending(transaction *entity.Transaction) error {
+	transaction.Cancel()
+	if err := uc.transactionRepo.CancelWithBalanceUpdate(transaction); err != nil {
+		return err
+	}
+
+	return uc.refreshBudgets(transaction.UserID, nil, transaction)
+}
@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// maxTransactionSplits limita las líneas en que se puede dividir una transacción
+const maxTransactionSplits = 20
+
+// UpdateSplits reemplaza las líneas por categoría de una transacción. Una lista vacía deja la
+// transacción sin dividir en la categoría indicada. Los presupuestos anteriores y nuevos se recalculan.
+func (uc *TransactionUseCase) UpdateSplits(userID, transactionID uint, req *dto.UpdateTransactionSplitsRequest) (*entity.Transaction, error) {
+	transaction, err := uc.GetByID(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !transaction.CanBeModified() {
+		return nil, errors.New("transaction cannot be modified")
+	}
+
+	previousAllocations, err := uc.budgetAllocationIDs(userID, transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	splits, err := uc.buildSplits(userID, transaction, req.Splits)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(splits) > 0:
+		if req.CategoryID != nil {
+			return nil, errors.New("invalid split: category_id only applies when removing the split")
+		}
+		transaction.CategoryID = nil
+		transaction.CategoryName = ""
+	case req.CategoryID != nil:
+		category, err := uc.splitCategory(userID, *req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		transaction.CategoryID = &category.ID
+		transaction.CategoryName = category.Name
+	case transaction.IsSplit():
+		// Las líneas guardan la categoría: sin ellas la transacción necesita una
+		return nil, errors.New("invalid split: category_id is required to remove the split")
+	}
+	transaction.Splits = splits
+
+	if err := uc.transactionRepo.ReplaceSplits(transaction); err != nil {
+		return nil, fmt.Errorf("failed to update transaction splits: %w", err)
+	}
+
+	if err := uc.refreshBudgets(userID, previousAllocations, transaction); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// GetCategoryTotals obtiene los totales por categoría de un usuario. Las transacciones divididas
+// suman a la categoría de cada una de sus líneas.
+func (uc *TransactionUseCase) GetCategoryTotals(userID uint, fromDate, toDate *time.Time) ([]entity.TransactionCategoryTotal, error) {
+	// Verificar que el usuario existe
+	if _, err := uc.userRepo.GetByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	totals, err := uc.transactionRepo.GetCategoryTotals(userID, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	if totals == nil {
+		totals = []entity.TransactionCategoryTotal{}
+	}
+
+	return totals, nil
+}
+
+// buildSplits valida las líneas solicitadas y las convierte en líneas de la transacción
+func (uc *TransactionUseCase) buildSplits(userID uint, transaction *entity.Transaction, lines []dto.TransactionSplitRequest) ([]entity.TransactionSplit, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	if transaction.IsTransfer() {
+		return nil, errors.New("invalid split: transfers cannot be split")
+	}
+	if len(lines) < 2 {
+		return nil, errors.New("invalid split: at least two lines are required")
+	}
+	if len(lines) > maxTransactionSplits {
+		return nil, fmt.Errorf("invalid split: at most %d lines are allowed", maxTransactionSplits)
+	}
+
+	splits := make([]entity.TransactionSplit, len(lines))
+	total := money.Zero
+	for i, line := range lines {
+		if !line.Amount.IsPositive() {
+			return nil, errors.New("invalid split: line amounts must be positive")
+		}
+
+		split := entity.TransactionSplit{
+			CategoryID:   line.CategoryID,
+			AllocationID: line.AllocationID,
+			Amount:       line.Amount,
+			Notes:        line.Notes,
+		}
+
+		if split.CategoryID != nil {
+			category, err := uc.splitCategory(userID, *split.CategoryID)
+			if err != nil {
+				return nil, err
+			}
+			split.CategoryName = category.Name
+		}
+
+		if err := uc.resolveSplitAllocation(userID, transaction, &split); err != nil {
+			return nil, err
+		}
+
+		splits[i] = split
+		total += line.Amount
+	}
+
+	if total != transaction.Amount {
+		return nil, fmt.Errorf("invalid split: lines sum %s but the transaction amount is %s", total, transaction.Amount)
+	}
+
+	return splits, nil
+}
+
+// splitCategory obtiene una categoría del usuario o del sistema para una línea de la transacción
+func (uc *TransactionUseCase) splitCategory(userID, categoryID uint) (*entity.Category, error) {
+	category, err := uc.categoryRepo.GetByID(categoryID)
+	if err != nil {
+		return nil, errors.New("invalid split: category not found")
+	}
+	if !category.IsSystemCategory() && category.UserID != nil && *category.UserID != userID {
+		return nil, errors.New("invalid split: category not found")
+	}
+	return category, nil
+}
+
+// resolveSplitAllocation asocia la línea a la asignación de presupuesto a la que suma. Si no se
+// indica, los gastos usan la asignación de su categoría en el presupuesto del mes, si existe.
+func (uc *TransactionUseCase) resolveSplitAllocation(userID uint, transaction *entity.Transaction, split *entity.TransactionSplit) error {
+	if split.AllocationID == nil {
+		if !transaction.IsExpense() || split.CategoryID == nil {
+			return nil
+		}
+
+		allocation, err := uc.monthAllocation(userID, transaction.TransactionDate, *split.CategoryID)
+		if err != nil {
+			return err
+		}
+		if allocation != nil {
+			split.AllocationID = &allocation.ID
+		}
+		return nil
+	}
+
+	if !transaction.IsExpense() {
+		return errors.New("invalid split: budget allocations only apply to expenses")
+	}
+
+	allocation, err := uc.budgetRepo.GetAllocationByID(*split.AllocationID)
+	if err != nil {
+		return errors.New("invalid split: budget allocation not found")
+	}
+	budget, err := uc.budgetRepo.GetByID(allocation.BudgetID)
+	if err != nil || budget.UserID != userID {
+		return errors.New("invalid split: budget allocation not found")
+	}
+
+	// La línea hereda la categoría de la asignación o debe coincidir con ella
+	if split.CategoryID == nil {
+		categoryID := allocation.CategoryID
+		split.CategoryID = &categoryID
+		split.CategoryName = allocation.Category.Name
+	} else if *split.CategoryID != allocation.CategoryID {
+		return errors.New("invalid split: budget allocation belongs to another category")
+	}
+
+	return nil
+}
+
+// monthAllocation obtiene la asignación de la categoría en el presupuesto del mes de la fecha.
+// Retorna nil si el mes no tiene presupuesto o la categoría no está asignada en él.
+func (uc *TransactionUseCase) monthAllocation(userID uint, date time.Time, categoryID uint) (*entity.BudgetAllocation, error) {
+	budget, err := uc.budgetRepo.GetByUserAndMonth(userID, date.Year(), int(date.Month()))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+
+	allocation, err := uc.budgetRepo.GetAllocationByBudgetAndCategory(budget.ID, categoryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget allocation: %w", err)
+	}
+	return allocation, nil
+}
+
+// budgetAllocationIDs retorna las asignaciones de presupuesto a las que suma un gasto: las de sus
+// líneas si está dividido o, si no, la de su categoría en el presupuesto del mes
+func (uc *TransactionUseCase) budgetAllocationIDs(userID uint, transaction *entity.Transaction) ([]uint, error) {
+	if !transaction.IsExpense() {
+		return nil, nil
+	}
+	if transaction.IsSplit() {
+		return transaction.SplitAllocationIDs(), nil
+	}
+	if transaction.CategoryID == nil {
+		return nil, nil
+	}
+
+	allocation, err := uc.monthAllocation(userID, transaction.TransactionDate, *transaction.CategoryID)
+	if err != nil || allocation == nil {
+		return nil, err
+	}
+	return []uint{allocation.ID}, nil
+}
+
+// refreshBudgets recalcula los presupuestos a los que suma la transacción, además de las
+// asignaciones indicadas (ej: a las que sumaba antes de modificarse)
+func (uc *TransactionUseCase) refreshBudgets(userID uint, previousAllocations []uint, transaction *entity.Transaction) error {
+	allocationIDs, err := uc.budgetAllocationIDs(userID, transaction)
+	if err != nil {
+		return err
+	}
+	return uc.refreshAllocations(append(previousAllocations, allocationIDs...))
+}
+
+// refreshAllocations recalcula el gasto de las asignaciones indicadas y de sus presupuestos
+func (uc *TransactionUseCase) refreshAllocations(allocationIDs []uint) error {
+	refreshed := make(map[uint]bool)
+	budgets := make(map[uint]bool)
+	for _, allocationID := range allocationIDs {
+		if refreshed[allocationID] {
+			continue
+		}
+		refreshed[allocationID] = true
+
+		if err := uc.budgetRepo.UpdateAllocationSpentAmount(allocationID); err != nil {
+			return fmt.Errorf("failed to update allocation spent amount: %w", err)
+		}
+
+		allocation, err := uc.budgetRepo.GetAllocationByID(allocationID)
+		if err != nil {
+			return fmt.Errorf("failed to get budget allocation: %w", err)
+		}
+		budgets[allocation.BudgetID] = true
+	}
+
+	for budgetID := range budgets {
+		if err := uc.budgetRepo.UpdateBudgetSpentAmount(budgetID); err != nil {
+			return fmt.Errorf("failed to update budget spent amount: %w", err)
+		}
+	}
+
+	return nil
+}
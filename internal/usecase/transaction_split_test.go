@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/nick130920/fintech-backend/internal/controller/http/v1/dto"
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// fakeTransactionRepo implementa solo la lectura, la creación y el reemplazo de líneas de repo.TransactionRepo
+type fakeTransactionRepo struct {
+	repo.TransactionRepo
+	transactions map[uint]*entity.Transaction
+}
+
+func (r *fakeTransactionRepo) GetByID(id uint) (*entity.Transaction, error) {
+	if transaction, ok := r.transactions[id]; ok {
+		return transaction, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeTransactionRepo) ReplaceSplits(transaction *entity.Transaction) error { return nil }
+
+func (r *fakeTransactionRepo) CreateWithBalanceUpdate(transaction *entity.Transaction) error {
+	transaction.ID = uint(len(r.transactions) + 1)
+	r.transactions[transaction.ID] = transaction
+	return nil
+}
+
+// fakeBudgetRepo guarda un presupuesto en memoria y registra las asignaciones recalculadas
+type fakeBudgetRepo struct {
+	repo.BudgetRepo
+	budget    *entity.Budget
+	budgetErr error
+	refreshed []uint
+}
+
+func (r *fakeBudgetRepo) GetByUserAndMonth(userID uint, year, month int) (*entity.Budget, error) {
+	if r.budgetErr != nil {
+		return nil, r.budgetErr
+	}
+	if r.budget == nil || r.budget.UserID != userID || r.budget.Year != year || r.budget.Month != month {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.budget, nil
+}
+
+func (r *fakeBudgetRepo) GetAllocationByBudgetAndCategory(budgetID, categoryID uint) (*entity.BudgetAllocation, error) {
+	for i := range r.budget.Allocations {
+		if r.budget.Allocations[i].CategoryID == categoryID {
+			return &r.budget.Allocations[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeBudgetRepo) GetAllocationByID(id uint) (*entity.BudgetAllocation, error) {
+	for i := range r.budget.Allocations {
+		if r.budget.Allocations[i].ID == id {
+			return &r.budget.Allocations[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeBudgetRepo) UpdateAllocationSpentAmount(allocationID uint) error {
+	r.refreshed = append(r.refreshed, allocationID)
+	return nil
+}
+
+func (r *fakeBudgetRepo) UpdateBudgetSpentAmount(budgetID uint) error { return nil }
+
+func newTestSplits(transaction *entity.Transaction, budgetErr error) (*TransactionUseCase, *fakeBudgetRepo) {
+	owner := uint(1)
+	budgetRepo := &fakeBudgetRepo{
+		budget: &entity.Budget{ID: 1, UserID: owner, Year: 2026, Month: 3, Allocations: []entity.BudgetAllocation{
+			{ID: 11, BudgetID: 1, CategoryID: 100},
+			{ID: 12, BudgetID: 1, CategoryID: 200},
+		}},
+		budgetErr: budgetErr,
+	}
+	categoryRepo := &fakeCategoryRepo{categories: map[uint]*entity.Category{
+		100: {ID: 100, UserID: &owner, Name: "Súper"},
+		200: {ID: 200, UserID: &owner, Name: "Farmacia"},
+	}}
+	transactionRepo := &fakeTransactionRepo{transactions: map[uint]*entity.Transaction{transaction.ID: transaction}}
+
+	return NewTransactionUseCase(transactionRepo, nil, nil, budgetRepo, categoryRepo, nil, 0), budgetRepo
+}
+
+func TestUpdateSplitsRemovingTheSplit(t *testing.T) {
+	id := func(v uint) *uint { return &v }
+	split := func() *entity.Transaction {
+		return &entity.Transaction{
+			ID: 1, UserID: 1, Type: entity.TransactionTypeExpense, Status: entity.TransactionStatusCompleted,
+			Amount: money.FromUnits(100), TransactionDate: time.Date(2026, 3, 5, 0, 0, 0, 0, time.Local),
+			Splits: []entity.TransactionSplit{
+				{CategoryID: id(100), AllocationID: id(11), Amount: money.FromUnits(60)},
+				{CategoryID: id(200), AllocationID: id(12), Amount: money.FromUnits(40)},
+			},
+		}
+	}
+
+	t.Run("requires a category", func(t *testing.T) {
+		uc, _ := newTestSplits(split(), nil)
+		_, err := uc.UpdateSplits(1, 1, &dto.UpdateTransactionSplitsRequest{})
+		if err == nil || err.Error() != "invalid split: category_id is required to remove the split" {
+			t.Fatalf("UpdateSplits error = %v", err)
+		}
+	})
+
+	t.Run("restores the category", func(t *testing.T) {
+		uc, budgetRepo := newTestSplits(split(), nil)
+		transaction, err := uc.UpdateSplits(1, 1, &dto.UpdateTransactionSplitsRequest{CategoryID: id(200)})
+		if err != nil {
+			t.Fatalf("UpdateSplits error: %v", err)
+		}
+		if transaction.IsSplit() || transaction.CategoryID == nil || *transaction.CategoryID != 200 || transaction.CategoryName != "Farmacia" {
+			t.Fatalf("transaction = %+v", transaction)
+		}
+		// Se recalculan las asignaciones de las líneas anteriores y la de la categoría
+		if got := budgetRepo.refreshed; len(got) != 2 || got[0] != 11 || got[1] != 12 {
+			t.Fatalf("refreshed allocations = %v, want [11 12]", got)
+		}
+	})
+
+	t.Run("category only when removing", func(t *testing.T) {
+		uc, _ := newTestSplits(split(), nil)
+		_, err := uc.UpdateSplits(1, 1, &dto.UpdateTransactionSplitsRequest{
+			CategoryID: id(100),
+			Splits: []dto.TransactionSplitRequest{
+				{CategoryID: id(100), Amount: money.FromUnits(50)},
+				{CategoryID: id(200), Amount: money.FromUnits(50)},
+			},
+		})
+		if err == nil || err.Error() != "invalid split: category_id only applies when removing the split" {
+			t.Fatalf("UpdateSplits error = %v", err)
+		}
+	})
+}
+
+func TestUpdateSplitsBudgetLookup(t *testing.T) {
+	id := func(v uint) *uint { return &v }
+	unsplit := func() *entity.Transaction {
+		return &entity.Transaction{
+			ID: 1, UserID: 1, Type: entity.TransactionTypeExpense, Status: entity.TransactionStatusCompleted,
+			CategoryID: id(100), Amount: money.FromUnits(100), TransactionDate: time.Date(2026, 3, 5, 0, 0, 0, 0, time.Local),
+		}
+	}
+	req := &dto.UpdateTransactionSplitsRequest{Splits: []dto.TransactionSplitRequest{
+		{CategoryID: id(100), Amount: money.FromUnits(70)},
+		{CategoryID: id(200), Amount: money.FromUnits(30)},
+	}}
+
+	t.Run("assigns the month allocations", func(t *testing.T) {
+		uc, budgetRepo := newTestSplits(unsplit(), nil)
+		transaction, err := uc.UpdateSplits(1, 1, req)
+		if err != nil {
+			t.Fatalf("UpdateSplits error: %v", err)
+		}
+		if transaction.CategoryID != nil || *transaction.Splits[0].AllocationID != 11 || *transaction.Splits[1].AllocationID != 12 {
+			t.Fatalf("transaction = %+v", transaction)
+		}
+		if got := budgetRepo.refreshed; len(got) != 2 || got[0] != 11 || got[1] != 12 {
+			t.Fatalf("refreshed allocations = %v, want [11 12]", got)
+		}
+	})
+
+	t.Run("surfaces lookup errors", func(t *testing.T) {
+		uc, _ := newTestSplits(unsplit(), errors.New("connection refused"))
+		_, err := uc.UpdateSplits(1, 1, req)
+		if err == nil || !strings.Contains(err.Error(), "failed to get budget") {
+			t.Fatalf("UpdateSplits error = %v", err)
+		}
+	})
+}
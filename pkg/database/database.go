@@ -11,6 +11,7 @@ import (
 
 	"github.com/nick130920/fintech-backend/configs"
 	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // Database representa una conexión a la base de datos
@@ -83,9 +84,21 @@ func runMigrations(db *gorm.DB) error {
 		// Opcional: mantener Account y Transaction para compatibilidad
 		&entity.Account{},
 		&entity.Transaction{},
+		&entity.TransactionSplit{},
+		&entity.RecurringTransaction{},
+		&entity.RecurrenceException{},
+		&entity.LedgerEntry{},
+		&entity.LedgerPosting{},
 		// Nuevas entidades para notificaciones bancarias
 		&entity.BankAccount{},
 		&entity.BankNotificationPattern{},
+		&entity.PatternPolicy{},
+		&entity.PatternHistory{},
+		&entity.NotificationFingerprint{},
+		&entity.NotificationInboxItem{},
+		&entity.PatternTemplate{},
+		&entity.BalanceDiscrepancy{},
+		&entity.EmailIngestAlias{},
 	)
 }
 
@@ -104,6 +117,18 @@ func CreateTables(db *gorm.DB) error {
 func DropTables(db *gorm.DB) error {
 	return db.Migrator().DropTable(
 		// Eliminar en orden inverso por dependencias
+		&entity.LedgerPosting{},
+		&entity.LedgerEntry{},
+		&entity.TransactionSplit{},
+		&entity.RecurrenceException{},
+		&entity.RecurringTransaction{},
+		&entity.EmailIngestAlias{},
+		&entity.BalanceDiscrepancy{},
+		&entity.PatternTemplate{},
+		&entity.NotificationInboxItem{},
+		&entity.NotificationFingerprint{},
+		&entity.PatternHistory{},
+		&entity.PatternPolicy{},
 		&entity.BankNotificationPattern{}, // Depende de BankAccount
 		&entity.Expense{},
 		&entity.BudgetAllocation{},
@@ -148,8 +173,8 @@ func Seed(db *gorm.DB) error {
 				UserID:         testUser.ID,
 				Name:           "Cuenta Principal",
 				Type:           entity.AccountTypeChecking,
-				InitialBalance: 10000.00,
-				Balance:        10000.00,
+				InitialBalance: money.FromUnits(10000),
+				Balance:        money.FromUnits(10000),
 				Currency:       "MXN",
 				Color:          "#007bff",
 				IsActive:       true,
@@ -0,0 +1,46 @@
+package extractor
+
+import (
+	"regexp"
+	"sort"
+)
+
+// cardDigitsRegexes reconocen los últimos cuatro dígitos de una tarjeta o cuenta en un mensaje:
+// enmascarados ("****1234", "XX1234", "•••• 1234") o precedidos de una palabra que los
+// identifica ("terminación 1234", "tarjeta 1234", "ending in 1234").
+var cardDigitsRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`(?:[*xX•·]{2,}|\.{3,})[\s-]?(\d{4})\b`),
+	regexp.MustCompile(`(?i)\b(?:terminaci[oó]n|terminad[ao] en|termina en|con final|ending(?: in)?|tarjeta|cuenta|card|account|tdc|tdd)\s*(?:no\.?|n[uú]m(?:ero)?\.?|#)?\s*[:.]?\s*(\d{4})\b`),
+}
+
+// FindCardLast4 retorna los posibles últimos cuatro dígitos de tarjeta o cuenta mencionados en el
+// mensaje, sin repetir y en orden de aparición. Se usa para identificar la cuenta bancaria de una
+// notificación antes de aplicar sus patrones.
+func FindCardLast4(message string) []string {
+	type occurrence struct {
+		start  int
+		digits string
+	}
+
+	var occurrences []occurrence
+	for _, re := range cardDigitsRegexes {
+		for _, match := range re.FindAllStringSubmatchIndex(message, -1) {
+			occurrences = append(occurrences, occurrence{start: match[2], digits: message[match[2]:match[3]]})
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].start < occurrences[j].start
+	})
+
+	seen := make(map[string]bool)
+	var digits []string
+	for _, occ := range occurrences {
+		if !seen[occ.digits] {
+			seen[occ.digits] = true
+			digits = append(digits, occ.digits)
+		}
+	}
+
+	return digits
+}
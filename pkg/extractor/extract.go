@@ -0,0 +1,40 @@
+package extractor
+
+import (
+	"regexp"
+	"strings"
+)
+
+// FieldNames retorna los nombres de los grupos nombrados de una regex compilada
+func FieldNames(re *regexp.Regexp) []string {
+	var names []string
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Extract aplica la regex compilada al mensaje y retorna el valor de cada grupo
+// nombrado que capturó texto. Retorna nil si el mensaje no coincide.
+func Extract(re *regexp.Regexp, message string) map[string]string {
+	match := re.FindStringSubmatch(message)
+	if match == nil {
+		return nil
+	}
+
+	fields := make(map[string]string)
+	for i, name := range re.SubexpNames() {
+		if name == "" || i >= len(match) {
+			continue
+		}
+
+		value := strings.TrimRight(strings.TrimSpace(match[i]), ".,;")
+		if value != "" {
+			fields[name] = value
+		}
+	}
+
+	return fields
+}
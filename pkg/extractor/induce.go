@@ -0,0 +1,462 @@
+package extractor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxAnchorWords es la cantidad de palabras de contexto usadas en las regex individuales por campo
+const maxAnchorWords = 3
+
+// maxInducedKeywords es la cantidad máxima de palabras clave propuestas
+const maxInducedKeywords = 5
+
+// fieldNameRegex valida los nombres de campo marcados por el usuario
+var fieldNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// keywordStopwords son palabras frecuentes que no sirven como disparadores
+var keywordStopwords = map[string]bool{
+	"para": true, "desde": true, "este": true, "esta": true, "usted": true, "como": true,
+	"from": true, "your": true, "with": true, "this": true, "that": true, "have": true,
+}
+
+// Span marca el valor de un campo dentro de un mensaje de ejemplo
+type Span struct {
+	Field string // Nombre del campo (ej: amount, merchant, date)
+	Value string // Texto exacto del campo tal como aparece en el mensaje
+}
+
+// Example es un mensaje de ejemplo con los campos marcados por el usuario
+type Example struct {
+	Message string
+	Spans   []Span
+}
+
+// Induction es la propuesta de patrón inducida a partir de ejemplos
+type Induction struct {
+	MessagePattern string            // Regex con grupos nombrados
+	FieldRegexes   map[string]string // Regex individuales por campo (un grupo de captura)
+	Keywords       []string          // Palabras presentes en todos los ejemplos
+}
+
+// locatedSpan es un campo ubicado dentro del mensaje
+type locatedSpan struct {
+	field      string
+	start, end int
+}
+
+// segment es el texto fijo entre dos campos consecutivos, común a todos los ejemplos.
+// Cuando los ejemplos difieren, prefix y suffix son las partes comunes y variable es true.
+type segment struct {
+	prefix   string
+	suffix   string
+	variable bool
+}
+
+// Induce genera una regex con grupos nombrados a partir de mensajes del mismo banco en los
+// que el usuario marcó el valor de cada campo. Todos los ejemplos deben marcar los mismos
+// campos y en el mismo orden; el texto entre campos que no coincide se reemplaza por un comodín.
+func Induce(examples []Example) (*Induction, error) {
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("at least one example is required")
+	}
+
+	located := make([][]locatedSpan, len(examples))
+	for i, example := range examples {
+		spans, err := locateSpans(example)
+		if err != nil {
+			return nil, fmt.Errorf("example %d: %w", i+1, err)
+		}
+		if i > 0 && !sameFieldOrder(spans, located[0]) {
+			return nil, fmt.Errorf("example %d: fields must be marked in the same order as example 1", i+1)
+		}
+		located[i] = spans
+	}
+
+	fields := make([]string, len(located[0]))
+	for i, span := range located[0] {
+		fields[i] = span.field
+	}
+
+	// gaps[g][e] es el texto del ejemplo e antes del campo g (el último es el texto final)
+	gaps := make([][]string, len(fields)+1)
+	for g := range gaps {
+		gaps[g] = make([]string, len(examples))
+	}
+	for e, spans := range located {
+		message := examples[e].Message
+		last := 0
+		for g, span := range spans {
+			gaps[g][e] = message[last:span.start]
+			last = span.end
+		}
+		gaps[len(fields)][e] = message[last:]
+	}
+
+	segments := make([]segment, len(gaps))
+	for g, texts := range gaps {
+		segments[g] = buildSegment(texts, g == 0, g == len(gaps)-1)
+	}
+
+	expressions := make([]string, len(fields))
+	for i, field := range fields {
+		values := make([]string, len(examples))
+		for e, spans := range located {
+			values[e] = examples[e].Message[spans[i].start:spans[i].end]
+		}
+		expressions[i] = inducedExpression(field, values, i == len(fields)-1 && isEmptySegment(segments[i+1]))
+	}
+
+	induction := &Induction{
+		MessagePattern: buildMessagePattern(fields, expressions, segments),
+		FieldRegexes:   make(map[string]string),
+		Keywords:       induceKeywords(gaps),
+	}
+
+	for i, field := range fields {
+		switch field {
+		case FieldAmount, FieldDate, FieldDescription, FieldMerchant, FieldBalance:
+			next := ""
+			if i+1 < len(fields) {
+				next = expressions[i+1]
+			}
+			induction.FieldRegexes[field] = buildFieldRegex(expressions[i], next, segments[i], segments[i+1])
+		}
+	}
+
+	if _, err := Compile(induction.MessagePattern); err != nil {
+		return nil, fmt.Errorf("generated pattern is invalid: %w", err)
+	}
+
+	return induction, nil
+}
+
+// locateSpans ubica los campos marcados dentro del mensaje y los ordena por posición
+func locateSpans(example Example) ([]locatedSpan, error) {
+	if len(example.Spans) == 0 {
+		return nil, fmt.Errorf("no fields marked")
+	}
+
+	seen := make(map[string]bool)
+	spans := make([]locatedSpan, 0, len(example.Spans))
+	for _, span := range example.Spans {
+		if !fieldNameRegex.MatchString(span.Field) {
+			return nil, fmt.Errorf("invalid field name %q", span.Field)
+		}
+		if seen[span.Field] {
+			return nil, fmt.Errorf("field %s is marked more than once", span.Field)
+		}
+		seen[span.Field] = true
+
+		value := strings.TrimSpace(span.Value)
+		if value == "" {
+			return nil, fmt.Errorf("field %s has an empty value", span.Field)
+		}
+
+		start := findFreeOccurrence(example.Message, value, spans)
+		if start < 0 {
+			return nil, fmt.Errorf("value %q of field %s not found in message", value, span.Field)
+		}
+		spans = append(spans, locatedSpan{field: span.Field, start: start, end: start + len(value)})
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	return spans, nil
+}
+
+// findFreeOccurrence busca la primera aparición de value que no se superponga con campos ya ubicados
+func findFreeOccurrence(message, value string, taken []locatedSpan) int {
+	offset := 0
+	for {
+		index := strings.Index(message[offset:], value)
+		if index < 0 {
+			return -1
+		}
+		start := offset + index
+		end := start + len(value)
+
+		overlaps := false
+		for _, span := range taken {
+			if start < span.end && span.start < end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			return start
+		}
+		offset = start + 1
+	}
+}
+
+// sameFieldOrder indica si dos ejemplos marcan los mismos campos en el mismo orden
+func sameFieldOrder(a, b []locatedSpan) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].field != b[i].field {
+			return false
+		}
+	}
+	return true
+}
+
+// buildSegment calcula el texto fijo de un hueco entre campos. Antes del primer campo solo
+// importa el final del texto y después del último campo solo su inicio.
+func buildSegment(texts []string, first, last bool) segment {
+	if allEqualFold(texts) {
+		return segment{prefix: texts[0]}
+	}
+
+	switch {
+	case first:
+		return segment{suffix: commonSuffix(texts)}
+	case last:
+		return segment{prefix: commonPrefix(texts)}
+	}
+
+	prefix := commonPrefix(texts)
+	suffix := commonSuffix(texts)
+
+	// Evitar que prefijo y sufijo se solapen en el texto más corto
+	shortest := len(texts[0])
+	for _, text := range texts[1:] {
+		if len(text) < shortest {
+			shortest = len(text)
+		}
+	}
+	if len(prefix)+len(suffix) > shortest {
+		suffix = ""
+	}
+
+	return segment{prefix: prefix, suffix: suffix, variable: true}
+}
+
+// buildMessagePattern arma la regex con grupos nombrados a partir de los campos y el texto fijo entre ellos
+func buildMessagePattern(fields, expressions []string, segments []segment) string {
+	var builder strings.Builder
+	builder.WriteString("(?i)")
+
+	builder.WriteString(segmentToRegex(segments[0]))
+	for i, field := range fields {
+		builder.WriteString("(?P<" + field + ">" + expressions[i] + ")")
+		builder.WriteString(segmentToRegex(segments[i+1]))
+	}
+
+	return builder.String()
+}
+
+// buildFieldRegex arma una regex individual para un campo usando algunas palabras del contexto.
+// El texto libre se delimita además con la expresión del campo siguiente, si existe.
+func buildFieldRegex(expression, next string, before, after segment) string {
+	leading := before.suffix
+	if !before.variable && before.suffix == "" {
+		leading = before.prefix
+	}
+
+	var builder strings.Builder
+	builder.WriteString("(?i)")
+	builder.WriteString(literalToRegex(lastWords(leading, maxAnchorWords)))
+	builder.WriteString("(" + expression + ")")
+
+	if isFreeText(expression) {
+		builder.WriteString(segmentToRegex(after))
+		if next != "" {
+			builder.WriteString("(?:" + next + ")")
+		}
+	} else {
+		builder.WriteString(literalToRegex(firstWords(after.prefix, maxAnchorWords)))
+	}
+
+	return builder.String()
+}
+
+// inducedExpression elige la expresión más específica del campo que acepta todos los valores
+// marcados. Si ninguna los acepta, el campo se trata como texto libre.
+func inducedExpression(field string, values []string, last bool) string {
+	candidates := []string{}
+	if field == FieldDate {
+		candidates = dateExpressions
+	} else if expression, known := fieldExpressions[field]; known {
+		candidates = []string{expression}
+	}
+
+	for _, candidate := range candidates {
+		re := regexp.MustCompile(`(?i)^(?:` + candidate + `)$`)
+		matchesAll := true
+		for _, value := range values {
+			if !re.MatchString(value) {
+				matchesAll = false
+				break
+			}
+		}
+		if matchesAll {
+			return candidate
+		}
+	}
+
+	// Texto libre: perezoso salvo que sea el último campo y no haya texto fijo después
+	if last {
+		return `.+`
+	}
+	return `.+?`
+}
+
+// isFreeText indica si la expresión corresponde a un campo de texto libre
+func isFreeText(expression string) bool {
+	return expression == `.+` || expression == `.+?`
+}
+
+// isEmptySegment indica si el hueco no tiene texto fijo
+func isEmptySegment(seg segment) bool {
+	return !seg.variable && strings.TrimSpace(seg.prefix+seg.suffix) == ""
+}
+
+// segmentToRegex convierte el texto fijo de un hueco en regex, con un comodín si los ejemplos difieren
+func segmentToRegex(seg segment) string {
+	if !seg.variable {
+		return literalToRegex(seg.prefix + seg.suffix)
+	}
+	return literalToRegex(seg.prefix) + `.*?` + literalToRegex(seg.suffix)
+}
+
+// induceKeywords retorna palabras del texto fijo presentes en todos los ejemplos,
+// en el orden en que aparecen en el primero
+func induceKeywords(gaps [][]string) []string {
+	examples := len(gaps[0])
+	words := make([]map[string]bool, examples)
+	var ordered []string
+	for e := 0; e < examples; e++ {
+		words[e] = make(map[string]bool)
+		for _, texts := range gaps {
+			for _, word := range strings.FieldsFunc(strings.ToLower(texts[e]), isNotLetter) {
+				if len([]rune(word)) < 4 || keywordStopwords[word] {
+					continue
+				}
+				if e == 0 && !words[0][word] {
+					ordered = append(ordered, word)
+				}
+				words[e][word] = true
+			}
+		}
+	}
+
+	var keywords []string
+	for _, word := range ordered {
+		common := true
+		for e := 1; e < examples; e++ {
+			if !words[e][word] {
+				common = false
+				break
+			}
+		}
+		if common {
+			keywords = append(keywords, word)
+			if len(keywords) == maxInducedKeywords {
+				break
+			}
+		}
+	}
+
+	return keywords
+}
+
+// allEqualFold indica si todos los textos son iguales sin distinguir mayúsculas
+func allEqualFold(texts []string) bool {
+	for _, text := range texts[1:] {
+		if !strings.EqualFold(text, texts[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// commonPrefix retorna el prefijo común (sin distinguir mayúsculas) recortado a un límite de palabra
+func commonPrefix(texts []string) string {
+	reference := []rune(texts[0])
+	length := len(reference)
+	for _, text := range texts[1:] {
+		runes := []rune(text)
+		n := 0
+		for n < length && n < len(runes) && unicode.ToLower(runes[n]) == unicode.ToLower(reference[n]) {
+			n++
+		}
+		length = n
+	}
+
+	// Si el prefijo termina a mitad de una palabra, retroceder hasta el último separador
+	if length > 0 && length < len(reference) && isWordRune(reference[length-1]) && isWordRune(reference[length]) {
+		for length > 0 && isWordRune(reference[length-1]) {
+			length--
+		}
+	}
+
+	return string(reference[:length])
+}
+
+// commonSuffix retorna el sufijo común (sin distinguir mayúsculas) recortado a un límite de palabra
+func commonSuffix(texts []string) string {
+	reference := []rune(texts[0])
+	length := len(reference)
+	for _, text := range texts[1:] {
+		runes := []rune(text)
+		n := 0
+		for n < length && n < len(runes) &&
+			unicode.ToLower(runes[len(runes)-1-n]) == unicode.ToLower(reference[len(reference)-1-n]) {
+			n++
+		}
+		length = n
+	}
+
+	start := len(reference) - length
+	if start > 0 && start < len(reference) && isWordRune(reference[start]) && isWordRune(reference[start-1]) {
+		for start < len(reference) && isWordRune(reference[start]) {
+			start++
+		}
+	}
+
+	return string(reference[start:])
+}
+
+// lastWords retorna las últimas n palabras de un texto conservando el espacio final
+func lastWords(text string, n int) string {
+	trimmed := strings.TrimRightFunc(text, unicode.IsSpace)
+	words := strings.Fields(trimmed)
+	if len(words) <= n {
+		return text
+	}
+	index := strings.LastIndex(trimmed, strings.Join(words[len(words)-n:], " "))
+	if index < 0 {
+		return text
+	}
+	return text[index:]
+}
+
+// firstWords retorna las primeras n palabras de un texto conservando el espacio inicial
+func firstWords(text string, n int) string {
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	words := strings.Fields(trimmed)
+	if len(words) <= n {
+		return text
+	}
+	head := strings.Join(words[:n], " ")
+	index := strings.Index(text, head)
+	if index < 0 {
+		return text
+	}
+	return text[:index+len(head)]
+}
+
+// isWordRune indica si el carácter forma parte de una palabra
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isNotLetter se usa para separar palabras al buscar palabras clave
+func isNotLetter(r rune) bool {
+	return !unicode.IsLetter(r)
+}
@@ -0,0 +1,161 @@
+package extractor
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestInduce(t *testing.T) {
+	examples := []Example{
+		{
+			Message: "BBVA: Compra aprobada por $1,250.00 en OXXO CENTRO con tu tarjeta *1234 el 05/03/2026",
+			Spans: []Span{
+				{Field: FieldAmount, Value: "$1,250.00"},
+				{Field: FieldMerchant, Value: "OXXO CENTRO"},
+				{Field: FieldCardLast4, Value: "1234"},
+				{Field: FieldDate, Value: "05/03/2026"},
+			},
+		},
+		{
+			// Los campos pueden marcarse en cualquier orden; se ordenan por posición
+			Message: "BBVA: Compra aprobada por $89.90 en FARMACIA GUADALAJARA con tu tarjeta *1234 el 12/03/2026",
+			Spans: []Span{
+				{Field: FieldMerchant, Value: "FARMACIA GUADALAJARA"},
+				{Field: FieldAmount, Value: "$89.90"},
+				{Field: FieldDate, Value: "12/03/2026"},
+				{Field: FieldCardLast4, Value: "1234"},
+			},
+		},
+	}
+
+	induction, err := Induce(examples)
+	if err != nil {
+		t.Fatalf("Induce error: %v", err)
+	}
+
+	message := "BBVA: Compra aprobada por $3,499.00 en LIVERPOOL PERISUR con tu tarjeta *5678 el 20/03/2026"
+	fields := Extract(regexp.MustCompile(induction.MessagePattern), message)
+	want := map[string]string{
+		FieldAmount:    "$3,499.00",
+		FieldMerchant:  "LIVERPOOL PERISUR",
+		FieldCardLast4: "5678",
+		FieldDate:      "20/03/2026",
+	}
+	for field, value := range want {
+		if fields[field] != value {
+			t.Errorf("message pattern field %s = %q, want %q", field, fields[field], value)
+		}
+	}
+
+	// Solo los campos que se extraen con regex individual
+	if _, ok := induction.FieldRegexes[FieldCardLast4]; ok || len(induction.FieldRegexes) != 3 {
+		t.Fatalf("FieldRegexes = %q", induction.FieldRegexes)
+	}
+	for field, expression := range induction.FieldRegexes {
+		match := regexp.MustCompile(expression).FindStringSubmatch(message)
+		if len(match) != 2 || match[1] != want[field] {
+			t.Errorf("field regex %s = %q, want %q", field, match, want[field])
+		}
+	}
+
+	if got := strings.Join(induction.Keywords, ","); got != "bbva,compra,aprobada,tarjeta" {
+		t.Errorf("Keywords = %q", induction.Keywords)
+	}
+}
+
+func TestInduceVariableText(t *testing.T) {
+	examples := []Example{
+		{
+			Message: "Transferencia enviada de $100.00 a JUAN PEREZ concepto RENTA MARZO",
+			Spans: []Span{
+				{Field: FieldAmount, Value: "$100.00"},
+				{Field: FieldMerchant, Value: "JUAN PEREZ"},
+				{Field: FieldDescription, Value: "RENTA MARZO"},
+			},
+		},
+		{
+			Message: "Transferencia recibida de $2,000.00 a ANA LOPEZ concepto PAGO",
+			Spans: []Span{
+				{Field: FieldAmount, Value: "$2,000.00"},
+				{Field: FieldMerchant, Value: "ANA LOPEZ"},
+				{Field: FieldDescription, Value: "PAGO"},
+			},
+		},
+	}
+
+	induction, err := Induce(examples)
+	if err != nil {
+		t.Fatalf("Induce error: %v", err)
+	}
+
+	// El texto distinto antes del primer campo no forma parte del patrón y el último campo
+	// de texto libre llega hasta el final del mensaje
+	fields := Extract(regexp.MustCompile(induction.MessagePattern), "Transferencia programada de $50.00 a LUIS concepto LUZ Y AGUA")
+	if fields[FieldAmount] != "$50.00" || fields[FieldMerchant] != "LUIS" || fields[FieldDescription] != "LUZ Y AGUA" {
+		t.Fatalf("fields = %q (pattern %q)", fields, induction.MessagePattern)
+	}
+
+	if got := strings.Join(induction.Keywords, ","); got != "transferencia,concepto" {
+		t.Errorf("Keywords = %q", induction.Keywords)
+	}
+}
+
+func TestInduceErrors(t *testing.T) {
+	message := "Compra por $250.00 en OXXO"
+	example := func(spans ...Span) Example {
+		return Example{Message: message, Spans: spans}
+	}
+	amount := Span{Field: FieldAmount, Value: "$250.00"}
+	merchant := Span{Field: FieldMerchant, Value: "OXXO"}
+
+	tests := []struct {
+		name     string
+		examples []Example
+		wantErr  string
+	}{
+		{"no examples", nil, "at least one example is required"},
+		{"no fields", []Example{example()}, "example 1: no fields marked"},
+		{"invalid field name", []Example{example(Span{Field: "Monto", Value: "$250.00"})}, `example 1: invalid field name "Monto"`},
+		{"field marked twice", []Example{example(amount, amount)}, "example 1: field amount is marked more than once"},
+		{"empty value", []Example{example(Span{Field: FieldAmount, Value: "  "})}, "example 1: field amount has an empty value"},
+		{"value not in message", []Example{example(Span{Field: FieldAmount, Value: "$5.00"})}, `example 1: value "$5.00" of field amount not found in message`},
+		{"different fields", []Example{example(amount, merchant), example(amount)}, "example 2: fields must be marked in the same order as example 1"},
+		{
+			"different order",
+			[]Example{example(amount, merchant), {Message: "Compra en OXXO por $250.00", Spans: []Span{amount, merchant}}},
+			"example 2: fields must be marked in the same order as example 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Induce(tt.examples)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Induce error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommonAffixes(t *testing.T) {
+	tests := []struct {
+		texts      []string
+		wantPrefix string
+		wantSuffix string
+	}{
+		{[]string{"Compra en ", "COMPRA en "}, "Compra en ", "Compra en "},
+		{[]string{"Compra aprobada en ", "Compra rechazada en "}, "Compra ", " en "},
+		{[]string{"tarjeta de crédito ", "tarjeta de débito "}, "tarjeta de ", " "}, // Sin cortar palabras
+		{[]string{"abc", "xyz"}, "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := commonPrefix(tt.texts); got != tt.wantPrefix {
+			t.Errorf("commonPrefix(%q) = %q, want %q", tt.texts, got, tt.wantPrefix)
+		}
+		if got := commonSuffix(tt.texts); got != tt.wantSuffix {
+			t.Errorf("commonSuffix(%q) = %q, want %q", tt.texts, got, tt.wantSuffix)
+		}
+	}
+}
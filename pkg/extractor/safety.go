@@ -0,0 +1,84 @@
+package extractor
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"unicode/utf8"
+)
+
+// Límites de seguridad de las expresiones escritas por los usuarios. El motor de regex de Go
+// evalúa en tiempo lineal, pero una expresión muy grande o con repeticiones anidadas genera
+// un programa enorme que se ejecuta contra cada notificación de cada lote.
+const (
+	MaxMessagePatternLength = 2000 // Caracteres del MessagePattern (regex o template)
+	MaxFieldRegexLength     = 500  // Caracteres de una regex de campo (monto, fecha...)
+	MaxRepeatCount          = 100  // Máximo de un contador de repetición {n,m}
+	MaxRepetitionNesting    = 2    // Cuantificadores sin límite anidados: "(?:\d+,)+" tiene 2
+	MaxAlternationBranches  = 32   // Alternativas de un mismo grupo (a|b|c...)
+	MaxProgramSize          = 5000 // Instrucciones del programa compilado
+	MaxMessageLength        = 8000 // Caracteres de una notificación a procesar
+)
+
+// CompileField valida los límites de seguridad de una regex de campo y la compila
+func CompileField(expression string) (*regexp.Regexp, error) {
+	if utf8.RuneCountInString(expression) > MaxFieldRegexLength {
+		return nil, fmt.Errorf("regex exceeds %d characters", MaxFieldRegexLength)
+	}
+	if err := CheckComplexity(expression); err != nil {
+		return nil, err
+	}
+	return regexp.Compile(expression)
+}
+
+// CheckComplexity verifica que una regex no supere los límites de repetición, anidamiento,
+// alternativas y tamaño del programa compilado
+func CheckComplexity(expression string) error {
+	parsed, err := syntax.Parse(expression, syntax.Perl)
+	if err != nil {
+		// La sintaxis inválida la reporta regexp.Compile con su propio mensaje
+		return nil
+	}
+
+	if err := checkNode(parsed, 0); err != nil {
+		return err
+	}
+
+	program, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil
+	}
+	if len(program.Inst) > MaxProgramSize {
+		return fmt.Errorf("regex is too complex (%d instructions, maximum %d)", len(program.Inst), MaxProgramSize)
+	}
+
+	return nil
+}
+
+// checkNode recorre el árbol de la regex contando los cuantificadores sin límite anidados. Las
+// repeticiones acotadas ("?", "{1,3}") no cuentan: su costo lo limita el tamaño del programa.
+func checkNode(node *syntax.Regexp, nesting int) error {
+	switch node.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpRepeat:
+		if node.Op == syntax.OpRepeat && (node.Max > MaxRepeatCount || node.Min > MaxRepeatCount) {
+			return fmt.Errorf("regex repetition count exceeds %d", MaxRepeatCount)
+		}
+		if node.Op != syntax.OpRepeat || node.Max == -1 {
+			nesting++
+		}
+		if nesting > MaxRepetitionNesting {
+			return fmt.Errorf("regex nests more than %d repetitions", MaxRepetitionNesting)
+		}
+	case syntax.OpAlternate:
+		if len(node.Sub) > MaxAlternationBranches {
+			return fmt.Errorf("regex alternation has more than %d branches", MaxAlternationBranches)
+		}
+	}
+
+	for _, sub := range node.Sub {
+		if err := checkNode(sub, nesting); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package extractor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileBuiltInPlaceholders(t *testing.T) {
+	for field := range fieldExpressions {
+		template := "Movimiento {" + field + "} en {merchant}"
+		if _, err := Compile(template); err != nil {
+			t.Errorf("Compile(%q) error: %v", template, err)
+		}
+	}
+
+	template := "Compra por {amount} en {merchant} el {date} a las {time} tarjeta {card_last4} ref {reference} saldo {balance}"
+	re, err := Compile(template)
+	if err != nil {
+		t.Fatalf("Compile(%q) error: %v", template, err)
+	}
+
+	fields := Extract(re, "Compra por $1,250.00 en OXXO CENTRO el 5 de marzo a las 14:32 tarjeta 1234 ref AB-99 saldo $10,000.00")
+	want := map[string]string{
+		FieldAmount:    "$1,250.00",
+		FieldMerchant:  "OXXO CENTRO",
+		FieldDate:      "5 de marzo",
+		FieldTime:      "14:32",
+		FieldCardLast4: "1234",
+		FieldReference: "AB-99",
+		FieldBalance:   "$10,000.00",
+	}
+	for field, value := range want {
+		if fields[field] != value {
+			t.Errorf("field %s = %q, want %q", field, fields[field], value)
+		}
+	}
+}
+
+func TestCheckComplexity(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{name: "simple", expression: `\d+`},
+		{name: "two unbounded levels", expression: `(?:\d+,)+`},
+		{name: "optional groups do not nest", expression: `a(?:b(?:c(?:d)?)?)?`},
+		{name: "bounded repeats do not nest", expression: `(?:(?:\d{1,2}){1,3}){1,2}`},
+		{name: "three unbounded levels", expression: `(?:(?:\d+)+)+`, wantErr: "nests more than"},
+		{name: "unbounded counter nests", expression: `(?:(?:a{2,})*)+`, wantErr: "nests more than"},
+		{name: "repeat count", expression: `a{101}`, wantErr: "repetition count exceeds"},
+		{name: "alternation", expression: alternation(MaxAlternationBranches + 1), wantErr: "alternation has more than"},
+		{name: "alternation at limit", expression: alternation(MaxAlternationBranches)},
+		{name: "program size", expression: strings.Repeat(`\p{L}{50}`, 100), wantErr: "too complex"},
+		{name: "invalid syntax is left to regexp", expression: `(`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckComplexity(tt.expression)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckComplexity(%q) error: %v", tt.expression, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("CheckComplexity(%q) error = %v, want %q", tt.expression, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// alternation construye un grupo con n alternativas sin prefijo común, que el parser no factoriza
+func alternation(n int) string {
+	branches := make([]string, n)
+	for i := range branches {
+		branches[i] = string(rune('α'+i)) + "z"
+	}
+	return "(?:" + strings.Join(branches, "|") + ")"
+}
+
+func TestCompileFieldLength(t *testing.T) {
+	if _, err := CompileField(strings.Repeat("a", MaxFieldRegexLength+1)); err == nil {
+		t.Fatal("expected error for a field regex over the length limit")
+	}
+	if _, err := CompileField(`\d{4}`); err != nil {
+		t.Fatalf("CompileField error: %v", err)
+	}
+}
+
+func TestCompileMessagePatternLength(t *testing.T) {
+	if _, err := Compile("{amount}" + strings.Repeat("a", MaxMessagePatternLength)); err == nil {
+		t.Fatal("expected error for a message pattern over the length limit")
+	}
+}
@@ -0,0 +1,133 @@
+package extractor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Campos conocidos que un patrón puede extraer de una notificación
+const (
+	FieldAmount      = "amount"      // Monto del movimiento
+	FieldDate        = "date"        // Fecha del movimiento
+	FieldTime        = "time"        // Hora del movimiento
+	FieldDescription = "description" // Descripción libre
+	FieldMerchant    = "merchant"    // Comercio o destinatario
+	FieldCardLast4   = "card_last4"  // Últimos cuatro dígitos de la tarjeta
+	FieldBalance     = "balance"     // Saldo disponible reportado por el banco
+	FieldType        = "type"        // Tipo de movimiento (compra, retiro, abono...)
+	FieldReference   = "reference"   // Número de referencia o autorización
+)
+
+// fieldExpressions define la expresión usada por cada campo conocido dentro de un template.
+// Los campos no listados capturan texto libre.
+var fieldExpressions = map[string]string{
+	FieldAmount:    `[^\s\d]{0,4}\s?-?\d(?:[\d.,']*\d)?`,
+	FieldBalance:   `[^\s\d]{0,4}\s?-?\d(?:[\d.,']*\d)?`,
+	FieldCardLast4: `\d{4}`,
+	FieldDate:      strings.Join(dateExpressions, "|"),
+	FieldTime:      `\d{1,2}:\d{2}(?::\d{2})?(?:\s?[ap]\.?\s?m\.?)?`,
+	FieldReference: `[A-Za-z0-9-]+`,
+}
+
+// dateExpressions son las formas de fecha aceptadas: numérica, con nombre de mes y relativa (hoy, ayer)
+var dateExpressions = []string{
+	`\d{1,4}[-/.]\d{1,2}(?:[-/.]\d{2,4})?`,
+	`\d{1,2}(?:\s+de)?[\s\-/]*\p{L}{3,10}\.?(?:(?:\s+del?)?[\s\-/]*\d{2,4})?`,
+	`\p{L}+`,
+}
+
+var (
+	placeholderRegex = regexp.MustCompile(`\{([a-z][a-z0-9_]*)\}`)
+	namedGroupRegex  = regexp.MustCompile(`\(\?P?<[A-Za-z_][A-Za-z0-9_]*>`)
+	whitespaceRegex  = regexp.MustCompile(`\s+`)
+)
+
+// IsTemplate indica si el patrón usa la sintaxis de placeholders
+// ("Compra por {amount} en {merchant}") en lugar de una regex con grupos nombrados
+func IsTemplate(pattern string) bool {
+	return !namedGroupRegex.MatchString(pattern) && placeholderRegex.MatchString(pattern)
+}
+
+// Compile compila el patrón de mensaje de un patrón de notificación. Acepta una
+// regex con grupos nombrados (ej: "(?P<amount>...)") o un template con placeholders.
+func Compile(pattern string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, fmt.Errorf("empty message pattern")
+	}
+	if utf8.RuneCountInString(pattern) > MaxMessagePatternLength {
+		return nil, fmt.Errorf("message pattern exceeds %d characters", MaxMessagePatternLength)
+	}
+
+	expression := pattern
+	if IsTemplate(pattern) {
+		var err error
+		if expression, err = TemplateToRegex(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := CheckComplexity(expression); err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(FieldNames(re)) == 0 {
+		return nil, fmt.Errorf("message pattern must define at least one named group or placeholder")
+	}
+
+	return re, nil
+}
+
+// TemplateToRegex convierte un template con placeholders en una regex con grupos nombrados.
+// El texto literal se compara sin distinguir mayúsculas y con espacios flexibles.
+func TemplateToRegex(template string) (string, error) {
+	locations := placeholderRegex.FindAllStringSubmatchIndex(template, -1)
+	if len(locations) == 0 {
+		return "", fmt.Errorf("template has no placeholders")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("(?i)")
+
+	seen := make(map[string]bool)
+	last := 0
+	for i, location := range locations {
+		builder.WriteString(literalToRegex(template[last:location[0]]))
+
+		name := template[location[2]:location[3]]
+		if seen[name] {
+			return "", fmt.Errorf("placeholder {%s} is used more than once", name)
+		}
+		seen[name] = true
+
+		expression, known := fieldExpressions[name]
+		if !known {
+			// Texto libre: el último placeholder toma el resto de la línea
+			expression = `.+?`
+			if i == len(locations)-1 && strings.TrimSpace(template[location[1]:]) == "" {
+				expression = `.+`
+			}
+		}
+
+		builder.WriteString("(?P<" + name + ">" + expression + ")")
+		last = location[1]
+	}
+	builder.WriteString(literalToRegex(template[last:]))
+
+	return builder.String(), nil
+}
+
+// literalToRegex escapa un fragmento literal del template permitiendo espacios flexibles
+func literalToRegex(literal string) string {
+	parts := whitespaceRegex.Split(literal, -1)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return strings.Join(parts, `\s+`)
+}
@@ -0,0 +1,251 @@
+// Package inboundmail recibe correos de notificaciones bancarias: interpreta mensajes
+// RFC 822/MIME y expone un servidor SMTP/LMTP mínimo que los entrega a un Backend.
+package inboundmail
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// maxMultipartDepth limita el anidamiento de partes MIME que se recorre
+const maxMultipartDepth = 5
+
+// Message representa un correo interpretado
+type Message struct {
+	From       string    // Dirección del remitente (sin nombre)
+	Recipients []string  // Destinatarios de las cabeceras To, Cc, Delivered-To y X-Original-To
+	Subject    string    // Asunto decodificado
+	Date       time.Time // Fecha del encabezado Date; cero si falta o es inválida
+	Text       string    // Cuerpo en texto plano; si solo hay HTML, su texto sin etiquetas
+}
+
+// wordDecoder decodifica cabeceras con palabras codificadas (=?UTF-8?Q?...?=)
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// Parse interpreta un mensaje RFC 822/MIME y extrae el cuerpo text/plain o, en su defecto, el HTML
+func Parse(r io.Reader) (*Message, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email: %w", err)
+	}
+
+	parsed := &Message{}
+
+	if from, err := msg.Header.AddressList("From"); err == nil && len(from) > 0 {
+		parsed.From = strings.ToLower(from[0].Address)
+	}
+	if subject, err := wordDecoder.DecodeHeader(msg.Header.Get("Subject")); err == nil {
+		parsed.Subject = strings.TrimSpace(subject)
+	} else {
+		parsed.Subject = strings.TrimSpace(msg.Header.Get("Subject"))
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		parsed.Date = date
+	}
+	parsed.Recipients = headerRecipients(msg.Header)
+
+	plain, htmlBody, err := readBody(msg.Header, msg.Body, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.TrimSpace(plain) != "":
+		parsed.Text = normalizeText(plain)
+	case strings.TrimSpace(htmlBody) != "":
+		parsed.Text = HTMLToText(htmlBody)
+	default:
+		return nil, errors.New("email has no text body")
+	}
+
+	return parsed, nil
+}
+
+// AddressLocalPart separa una dirección de correo en parte local y dominio, en minúsculas.
+// Ignora el sufijo "+etiqueta" de la parte local.
+func AddressLocalPart(address string) (local, domain string) {
+	address = strings.ToLower(strings.TrimSpace(address))
+	address = strings.Trim(address, "<>")
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return address, ""
+	}
+	local, domain = address[:at], address[at+1:]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	return local, domain
+}
+
+// headerRecipients retorna los destinatarios de las cabeceras, sin repetir
+func headerRecipients(header mail.Header) []string {
+	seen := make(map[string]bool)
+	var recipients []string
+	for _, key := range []string{"Delivered-To", "X-Original-To", "To", "Cc"} {
+		for _, value := range header[key] {
+			addresses, err := mail.ParseAddressList(value)
+			if err != nil {
+				continue
+			}
+			for _, address := range addresses {
+				normalized := strings.ToLower(address.Address)
+				if !seen[normalized] {
+					seen[normalized] = true
+					recipients = append(recipients, normalized)
+				}
+			}
+		}
+	}
+	return recipients
+}
+
+// partHeader es el subconjunto de cabeceras MIME necesario para leer una parte
+type partHeader interface {
+	Get(key string) string
+}
+
+// readBody recorre la parte MIME y retorna el primer cuerpo text/plain y el primer text/html
+func readBody(header partHeader, body io.Reader, depth int) (plain, htmlBody string, err error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		// Sin Content-Type (o inválido) el cuerpo es texto plano
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= maxMultipartDepth {
+			return "", "", errors.New("email has too many nested parts")
+		}
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", "", fmt.Errorf("failed to read email part: %w", err)
+			}
+			// Los adjuntos no forman parte del texto de la notificación
+			if strings.HasPrefix(strings.ToLower(part.Header.Get("Content-Disposition")), "attachment") {
+				continue
+			}
+			p, h, err := readBody(part.Header, part, depth+1)
+			if err != nil {
+				return "", "", err
+			}
+			if plain == "" {
+				plain = p
+			}
+			if htmlBody == "" {
+				htmlBody = h
+			}
+		}
+		return plain, htmlBody, nil
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", nil
+	}
+
+	content, err := decodePart(header.Get("Content-Transfer-Encoding"), params["charset"], body)
+	if err != nil {
+		return "", "", err
+	}
+	if mediaType == "text/html" {
+		return "", content, nil
+	}
+	return content, "", nil
+}
+
+// decodePart decodifica la codificación de transferencia y el juego de caracteres de una parte.
+// En las partes de un multipart, multipart.Reader ya decodifica quoted-printable y quita la cabecera.
+func decodePart(transferEncoding, charset string, body io.Reader) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, newlineStripper{body})
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	if charset != "" && !strings.EqualFold(charset, "utf-8") && !strings.EqualFold(charset, "us-ascii") {
+		decoded, err := charsetReader(charset, body)
+		if err == nil {
+			body = decoded
+		}
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode email body: %w", err)
+	}
+	return string(content), nil
+}
+
+// charsetReader convierte un juego de caracteres a UTF-8 (ISO-8859-1, Windows-1252...)
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %s", charset)
+	}
+	return encoding.NewDecoder().Reader(input), nil
+}
+
+// newlineStripper elimina los saltos de línea del contenido base64
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n newlineStripper) Read(p []byte) (int, error) {
+	count, err := n.r.Read(p)
+	out := p[:0]
+	for _, b := range p[:count] {
+		if b != '\r' && b != '\n' && b != ' ' && b != '\t' {
+			out = append(out, b)
+		}
+	}
+	return len(out), err
+}
+
+var (
+	htmlHiddenRegex = regexp.MustCompile(`(?is)<(style|script|head)\b.*?</(style|script|head)>`)
+	htmlBreakRegex  = regexp.MustCompile(`(?i)<\s*(br|/p|/div|/tr|/li|/h[1-6])\b[^>]*>`)
+	htmlCellRegex   = regexp.MustCompile(`(?i)<\s*/t[dh]\s*>`)
+	htmlTagRegex    = regexp.MustCompile(`(?s)<[^>]*>`)
+	spacesRegex     = regexp.MustCompile(`[ \t\x{00A0}]+`)
+)
+
+// HTMLToText convierte un cuerpo HTML en texto: quita estilos, scripts y etiquetas,
+// conserva los saltos de línea de bloques y celdas y decodifica las entidades
+func HTMLToText(body string) string {
+	body = htmlHiddenRegex.ReplaceAllString(body, " ")
+	body = htmlBreakRegex.ReplaceAllString(body, "\n")
+	body = htmlCellRegex.ReplaceAllString(body, " ")
+	body = htmlTagRegex.ReplaceAllString(body, " ")
+	return normalizeText(html.UnescapeString(body))
+}
+
+// normalizeText une los espacios repetidos y descarta las líneas vacías
+func normalizeText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(spacesRegex.ReplaceAllString(line, " "))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,184 @@
+package inboundmail
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// crlf convierte los saltos de línea de un mensaje de prueba a CRLF
+func crlf(message string) string {
+	return strings.ReplaceAll(message, "\n", "\r\n")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		from    string
+		subject string
+		text    string
+	}{
+		{
+			name: "plain text",
+			raw: `From: Banco <Alertas@Banco.com>
+To: abc@ingest.example.com
+Subject: Compra aprobada
+Date: Mon, 2 Mar 2026 14:32:00 -0600
+
+Compra por $1,250.00   en OXXO
+
+Tarjeta 1234
+`,
+			from:    "alertas@banco.com",
+			subject: "Compra aprobada",
+			text:    "Compra por $1,250.00 en OXXO\nTarjeta 1234",
+		},
+		{
+			name: "encoded subject and latin-1 quoted-printable body",
+			raw: `From: alertas@banco.com
+Subject: =?UTF-8?Q?Notificaci=C3=B3n_de_compra?=
+Content-Type: text/plain; charset=ISO-8859-1
+Content-Transfer-Encoding: quoted-printable
+
+Compra en caf=E9 por $50.00
+`,
+			from:    "alertas@banco.com",
+			subject: "Notificación de compra",
+			text:    "Compra en café por $50.00",
+		},
+		{
+			name: "multipart alternative prefers plain text",
+			raw: `From: alertas@banco.com
+Subject: Retiro
+MIME-Version: 1.0
+Content-Type: multipart/alternative; boundary="b1"
+
+--b1
+Content-Type: text/html; charset=UTF-8
+
+<p>Retiro <b>HTML</b></p>
+--b1
+Content-Type: text/plain; charset=UTF-8
+
+Retiro por $300.00
+--b1--
+`,
+			from:    "alertas@banco.com",
+			subject: "Retiro",
+			text:    "Retiro por $300.00",
+		},
+		{
+			name: "html only with base64 and attachment",
+			raw: `From: alertas@banco.com
+Subject: Transferencia
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="b1"
+
+--b1
+Content-Type: text/plain
+Content-Disposition: attachment; filename="estado.txt"
+
+No es parte de la notificación
+--b1
+Content-Type: text/html; charset=UTF-8
+Content-Transfer-Encoding: base64
+
+PGh0bWw+PGhlYWQ+PHN0eWxlPnB7Y29sb3I6cmVkfTwvc3R5bGU+PC9oZWFkPjxib2R5
+PjxwPlRyYW5zZmVyZW5jaWEmbmJzcDtwb3IgJDIwMC4wMDwvcD48dGFibGU+PHRyPjx0
+ZD5SZWY8L3RkPjx0ZD5BQjk5PC90ZD48L3RyPjwvdGFibGU+PC9ib2R5PjwvaHRtbD4=
+--b1--
+`,
+			from:    "alertas@banco.com",
+			subject: "Transferencia",
+			text:    "Transferencia por $200.00\nRef AB99",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := Parse(strings.NewReader(crlf(tt.raw)))
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			if message.From != tt.from {
+				t.Errorf("From = %q, want %q", message.From, tt.from)
+			}
+			if message.Subject != tt.subject {
+				t.Errorf("Subject = %q, want %q", message.Subject, tt.subject)
+			}
+			if message.Text != tt.text {
+				t.Errorf("Text = %q, want %q", message.Text, tt.text)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	// Cada parte multipart contiene otra, un nivel más allá de maxMultipartDepth
+	nested := "From: a@b.com\nContent-Type: multipart/mixed; boundary=\"b0\"\n\n"
+	closing := ""
+	for depth := 1; depth <= maxMultipartDepth; depth++ {
+		nested += "--b" + strconv.Itoa(depth-1) + "\nContent-Type: multipart/mixed; boundary=\"b" + strconv.Itoa(depth) + "\"\n\n"
+		closing = "--b" + strconv.Itoa(depth-1) + "--\n" + closing
+	}
+	nested += "--b" + strconv.Itoa(maxMultipartDepth) + "\nContent-Type: text/plain\n\nCompra\n--b" + strconv.Itoa(maxMultipartDepth) + "--\n" + closing
+
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr string
+	}{
+		{name: "no body", raw: "From: a@b.com\nSubject: Vacío\n\n\n", wantErr: "no text body"},
+		{name: "only attachments", raw: "From: a@b.com\nContent-Type: application/pdf\n\n%PDF\n", wantErr: "no text body"},
+		{name: "nested parts", raw: nested, wantErr: "too many nested parts"},
+		{name: "not an email", raw: "", wantErr: "failed to read email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(crlf(tt.raw)))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Parse error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseRecipients(t *testing.T) {
+	raw := crlf(`From: alertas@banco.com
+Delivered-To: Abc@Ingest.example.com
+To: Usuario <abc@ingest.example.com>, otro@example.com
+Cc: copia@example.com
+
+Compra por $10.00
+`)
+
+	message, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	want := []string{"abc@ingest.example.com", "otro@example.com", "copia@example.com"}
+	if strings.Join(message.Recipients, ",") != strings.Join(want, ",") {
+		t.Fatalf("Recipients = %v, want %v", message.Recipients, want)
+	}
+}
+
+func TestAddressLocalPart(t *testing.T) {
+	tests := []struct {
+		address, local, domain string
+	}{
+		{"abc@ingest.example.com", "abc", "ingest.example.com"},
+		{" <ABC+banco@Ingest.Example.com> ", "abc", "ingest.example.com"},
+		{"abc", "abc", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		local, domain := AddressLocalPart(tt.address)
+		if local != tt.local || domain != tt.domain {
+			t.Errorf("AddressLocalPart(%q) = (%q, %q), want (%q, %q)", tt.address, local, domain, tt.local, tt.domain)
+		}
+	}
+}
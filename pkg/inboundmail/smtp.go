@@ -0,0 +1,362 @@
+package inboundmail
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Límites por defecto del servidor
+const (
+	DefaultMaxMessageSize = 10 * 1024 * 1024 // 10MB
+	DefaultMaxSessions    = 20
+	defaultMaxRecipients  = 50
+	defaultSessionTimeout = 5 * time.Minute
+	refuseTimeout         = 10 * time.Second
+	maxLineLength         = 1000 // Longitud máxima de una línea de comando con CRLF (RFC 5321)
+)
+
+// ErrUnknownRecipient indica que el destinatario no corresponde a ningún alias de ingesta
+var ErrUnknownRecipient = errors.New("unknown recipient")
+
+// Backend recibe los correos aceptados por el servidor
+type Backend interface {
+	// AcceptRecipient verifica, en RCPT TO, que la dirección pertenece a un alias de ingesta
+	AcceptRecipient(address string) error
+	// Deliver entrega el mensaje completo a uno de sus destinatarios
+	Deliver(from, recipient string, data []byte) error
+}
+
+// Server es un servidor SMTP (o LMTP) mínimo que solo recibe correo para los destinatarios
+// aceptados por el Backend. No hace relay ni autenticación: se expone detrás del proveedor
+// de correo o en la red interna, y el remitente que entrega al Backend no está verificado.
+type Server struct {
+	Addr           string // Dirección de escucha (host:puerto)
+	Domain         string // Nombre anunciado en el saludo
+	LMTP           bool   // Habla LMTP (LHLO y una respuesta por destinatario tras DATA)
+	MaxMessageSize int64
+	MaxSessions    int // Conexiones simultáneas; las demás se rechazan con 421
+	Backend        Backend
+
+	mu       sync.Mutex
+	listener net.Listener
+	closed   bool
+}
+
+// ListenAndServe escucha en Addr y atiende conexiones hasta que se cierre el servidor
+func (s *Server) ListenAndServe() error {
+	listener, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.Addr, err)
+	}
+	return s.Serve(listener)
+}
+
+// Serve atiende las conexiones del listener hasta que se cierre el servidor
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	sessions := make(chan struct{}, s.maxSessions())
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case sessions <- struct{}{}:
+			go func() {
+				defer func() { <-sessions }()
+				s.handleConn(conn)
+			}()
+		default:
+			go s.refuse(conn)
+		}
+	}
+}
+
+// Close deja de aceptar conexiones
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Close()
+}
+
+// refuse rechaza una conexión cuando se alcanzó el máximo de sesiones simultáneas
+func (s *Server) refuse(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetWriteDeadline(time.Now().Add(refuseTimeout))
+	_, _ = fmt.Fprintf(conn, "421 %s Too many connections, try again later\r\n", s.domain())
+}
+
+// session guarda el sobre del mensaje en curso
+type session struct {
+	greeted    bool
+	mail       bool // Se recibió MAIL FROM (el remitente puede ser vacío: <>)
+	from       string
+	recipients []string
+}
+
+func (s *session) reset() {
+	s.mail = false
+	s.from = ""
+	s.recipients = nil
+}
+
+// handleConn atiende una conexión SMTP/LMTP
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	// El búfer de lectura acota la longitud de las líneas de comando
+	reader := bufio.NewReaderSize(conn, maxLineLength)
+	text := textproto.NewReader(reader)
+	writer := textproto.NewWriter(bufio.NewWriter(conn))
+	domain := s.domain()
+	protocol := "ESMTP"
+	if s.LMTP {
+		protocol = "LMTP"
+	}
+
+	reply := func(code int, format string, args ...interface{}) bool {
+		_ = conn.SetWriteDeadline(time.Now().Add(defaultSessionTimeout))
+		return writer.PrintfLine("%d %s", code, fmt.Sprintf(format, args...)) == nil
+	}
+
+	if !reply(220, "%s %s ready", domain, protocol) {
+		return
+	}
+
+	sess := &session{}
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(defaultSessionTimeout))
+		line, err := readLine(reader)
+		if errors.Is(err, errLineTooLong) {
+			reply(500, "Line too long")
+			return
+		}
+		if err != nil {
+			return
+		}
+
+		verb, arg := splitCommand(line)
+		switch verb {
+		case "HELO", "EHLO", "LHLO":
+			if (verb == "LHLO") != s.LMTP {
+				reply(500, "%s not supported", verb)
+				continue
+			}
+			sess.greeted = true
+			sess.reset()
+			if verb == "HELO" {
+				reply(250, "%s", domain)
+				continue
+			}
+			_ = writer.PrintfLine("250-%s", domain)
+			_ = writer.PrintfLine("250-8BITMIME")
+			_ = writer.PrintfLine("250-PIPELINING")
+			reply(250, "SIZE %d", s.maxMessageSize())
+		case "MAIL":
+			if !sess.greeted {
+				reply(503, "Send %s first", s.greeting())
+				continue
+			}
+			from, ok := commandAddress(arg, "FROM:")
+			if !ok {
+				reply(501, "Syntax: MAIL FROM:<address>")
+				continue
+			}
+			sess.reset()
+			sess.from = from
+			sess.mail = true
+			reply(250, "OK")
+		case "RCPT":
+			if !sess.mail {
+				reply(503, "Need MAIL before RCPT")
+				continue
+			}
+			to, ok := commandAddress(arg, "TO:")
+			if !ok || to == "" {
+				reply(501, "Syntax: RCPT TO:<address>")
+				continue
+			}
+			if len(sess.recipients) >= defaultMaxRecipients {
+				reply(452, "Too many recipients")
+				continue
+			}
+			if err := s.Backend.AcceptRecipient(to); err != nil {
+				reply(550, "No such user here")
+				continue
+			}
+			sess.recipients = append(sess.recipients, to)
+			reply(250, "OK")
+		case "DATA":
+			if len(sess.recipients) == 0 {
+				reply(503, "Need RCPT before DATA")
+				continue
+			}
+			if !reply(354, "End data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := readData(text, s.maxMessageSize())
+			if err != nil {
+				// El resto del mensaje no se lee: se cierra la sesión
+				if errors.Is(err, errMessageTooLarge) {
+					reply(552, "Message exceeds maximum size")
+				}
+				return
+			}
+			s.deliver(sess, data, reply)
+			sess.reset()
+		case "RSET":
+			sess.reset()
+			reply(250, "OK")
+		case "NOOP":
+			reply(250, "OK")
+		case "VRFY":
+			reply(252, "Cannot verify user")
+		case "QUIT":
+			reply(221, "Bye")
+			return
+		default:
+			reply(502, "Command not implemented")
+		}
+	}
+}
+
+// deliver entrega el mensaje a cada destinatario. En LMTP responde una vez por destinatario;
+// en SMTP responde una sola vez y acepta el mensaje si llegó al menos a uno.
+func (s *Server) deliver(sess *session, data []byte, reply func(code int, format string, args ...interface{}) bool) {
+	delivered := 0
+	var lastErr error
+	for _, recipient := range sess.recipients {
+		err := s.Backend.Deliver(sess.from, recipient, data)
+		if err != nil {
+			log.Printf("Warning: Failed to deliver inbound email to %s: %v", recipient, err)
+			lastErr = err
+		} else {
+			delivered++
+		}
+
+		if s.LMTP {
+			if err != nil {
+				reply(554, "<%s> delivery failed", recipient)
+			} else {
+				reply(250, "<%s> delivered", recipient)
+			}
+		}
+	}
+
+	if s.LMTP {
+		return
+	}
+	if delivered == 0 && lastErr != nil {
+		reply(554, "Delivery failed")
+		return
+	}
+	reply(250, "OK: message accepted")
+}
+
+func (s *Server) greeting() string {
+	if s.LMTP {
+		return "LHLO"
+	}
+	return "HELO/EHLO"
+}
+
+func (s *Server) domain() string {
+	if s.Domain != "" {
+		return s.Domain
+	}
+	return "localhost"
+}
+
+func (s *Server) maxSessions() int {
+	if s.MaxSessions > 0 {
+		return s.MaxSessions
+	}
+	return DefaultMaxSessions
+}
+
+func (s *Server) maxMessageSize() int64 {
+	if s.MaxMessageSize > 0 {
+		return s.MaxMessageSize
+	}
+	return DefaultMaxMessageSize
+}
+
+var (
+	// errMessageTooLarge indica que el mensaje supera el tamaño máximo
+	errMessageTooLarge = errors.New("message too large")
+	// errLineTooLong indica que una línea de comando supera maxLineLength
+	errLineTooLong = errors.New("line too long")
+)
+
+// readLine lee una línea de comando sin su fin de línea. Las líneas que no caben en el búfer
+// del lector (maxLineLength) retornan errLineTooLong sin leer el resto.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return "", errLineTooLong
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// readData lee el contenido de DATA hasta la línea con un punto, sin superar maxSize.
+// Si el mensaje es demasiado grande retorna errMessageTooLarge sin leer el resto.
+func readData(r *textproto.Reader, maxSize int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r.DotReader(), maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, errMessageTooLarge
+	}
+	return data, nil
+}
+
+// splitCommand separa el verbo (en mayúsculas) de sus argumentos
+func splitCommand(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	if space := strings.IndexByte(line, ' '); space >= 0 {
+		return strings.ToUpper(line[:space]), strings.TrimSpace(line[space+1:])
+	}
+	return strings.ToUpper(line), ""
+}
+
+// commandAddress extrae la dirección de "FROM:<a@b>" o "TO:<a@b>", ignorando parámetros ESMTP
+func commandAddress(arg, prefix string) (string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+	address := strings.TrimSpace(arg[len(prefix):])
+	if space := strings.IndexByte(address, ' '); space >= 0 {
+		address = address[:space]
+	}
+	if !strings.HasPrefix(address, "<") || !strings.HasSuffix(address, ">") {
+		return "", false
+	}
+	return strings.ToLower(strings.Trim(address, "<>")), true
+}
@@ -0,0 +1,251 @@
+package inboundmail
+
+import (
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// delivery es un correo entregado al backend de prueba
+type delivery struct {
+	from, recipient string
+	data            string
+}
+
+// fakeBackend acepta los destinatarios de un dominio y guarda los correos entregados
+type fakeBackend struct {
+	mu         sync.Mutex
+	domain     string
+	failFor    string
+	deliveries []delivery
+}
+
+func (b *fakeBackend) AcceptRecipient(address string) error {
+	if _, domain := AddressLocalPart(address); domain != b.domain {
+		return ErrUnknownRecipient
+	}
+	return nil
+}
+
+func (b *fakeBackend) Deliver(from, recipient string, data []byte) error {
+	if recipient == b.failFor {
+		return ErrUnknownRecipient
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deliveries = append(b.deliveries, delivery{from: from, recipient: recipient, data: string(data)})
+	return nil
+}
+
+func (b *fakeBackend) delivered() []delivery {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]delivery(nil), b.deliveries...)
+}
+
+// startServer atiende el servidor en un puerto local hasta que termine la prueba
+func startServer(t *testing.T, server *Server) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+	return listener.Addr().String()
+}
+
+// dialSession abre una sesión con el servidor en un puerto local y lee el saludo
+func dialSession(t *testing.T, server *Server) *textproto.Conn {
+	t.Helper()
+	text, err := textproto.Dial("tcp", startServer(t, server))
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	t.Cleanup(func() { _ = text.Close() })
+	expectCode(t, text, 220)
+	return text
+}
+
+// command envía una línea y verifica el código de la respuesta
+func command(t *testing.T, text *textproto.Conn, code int, format string, args ...interface{}) string {
+	t.Helper()
+	if err := text.PrintfLine(format, args...); err != nil {
+		t.Fatalf("write %q: %v", format, err)
+	}
+	return expectCode(t, text, code)
+}
+
+func expectCode(t *testing.T, text *textproto.Conn, code int) string {
+	t.Helper()
+	_, message, err := text.ReadResponse(code)
+	if err != nil {
+		t.Fatalf("expected %d: %v", code, err)
+	}
+	return message
+}
+
+func TestServerDeliversToAcceptedRecipients(t *testing.T) {
+	backend := &fakeBackend{domain: "ingest.example.com"}
+	addr := startServer(t, &Server{Domain: "ingest.example.com", Backend: backend})
+
+	client, err := smtp.Dial(addr)
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Hello("banco.com"); err != nil {
+		t.Fatalf("EHLO error: %v", err)
+	}
+	if ok, size := client.Extension("SIZE"); !ok || size != "10485760" {
+		t.Fatalf("SIZE extension = %v %q", ok, size)
+	}
+	if err := client.Mail("Alertas@Banco.com"); err != nil {
+		t.Fatalf("MAIL error: %v", err)
+	}
+	if err := client.Rcpt("abc@ingest.example.com"); err != nil {
+		t.Fatalf("RCPT error: %v", err)
+	}
+	if err := client.Rcpt("alguien@otro.com"); err == nil || !strings.HasPrefix(err.Error(), "550") {
+		t.Fatalf("RCPT for unknown recipient error = %v, want 550", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		t.Fatalf("DATA error: %v", err)
+	}
+	if _, err := writer.Write([]byte("Subject: Compra\r\n\r\nCompra por $10.00\r\n.punto inicial\r\n")); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("end of DATA error: %v", err)
+	}
+	if err := client.Quit(); err != nil {
+		t.Fatalf("QUIT error: %v", err)
+	}
+
+	deliveries := backend.delivered()
+	if len(deliveries) != 1 {
+		t.Fatalf("deliveries = %d, want 1", len(deliveries))
+	}
+	got := deliveries[0]
+	if got.from != "alertas@banco.com" || got.recipient != "abc@ingest.example.com" {
+		t.Fatalf("delivery envelope = %q -> %q", got.from, got.recipient)
+	}
+	// DATA se entrega con fines de línea LF y sin el punto de transparencia
+	if got.data != "Subject: Compra\n\nCompra por $10.00\n.punto inicial\n" {
+		t.Fatalf("delivery data = %q", got.data)
+	}
+}
+
+func TestServerCommandOrder(t *testing.T) {
+	text := dialSession(t, &Server{Backend: &fakeBackend{domain: "ingest.example.com"}})
+
+	command(t, text, 503, "MAIL FROM:<a@b.com>")
+	command(t, text, 250, "HELO banco.com")
+	command(t, text, 503, "RCPT TO:<abc@ingest.example.com>")
+	command(t, text, 501, "MAIL FROM:a@b.com")
+	command(t, text, 250, "MAIL FROM:<>")
+	command(t, text, 503, "DATA")
+	command(t, text, 502, "EXPN lista")
+	command(t, text, 500, "LHLO banco.com")
+	command(t, text, 221, "QUIT")
+}
+
+func TestServerLMTPRepliesPerRecipient(t *testing.T) {
+	backend := &fakeBackend{domain: "ingest.example.com", failFor: "def@ingest.example.com"}
+	text := dialSession(t, &Server{LMTP: true, Backend: backend})
+
+	command(t, text, 500, "EHLO banco.com")
+	command(t, text, 250, "LHLO banco.com")
+	command(t, text, 250, "MAIL FROM:<alertas@banco.com>")
+	command(t, text, 250, "RCPT TO:<abc@ingest.example.com>")
+	command(t, text, 250, "RCPT TO:<def@ingest.example.com>")
+	command(t, text, 354, "DATA")
+	command(t, text, 250, "Subject: Compra\r\n\r\nCompra por $10.00\r\n.")
+	expectCode(t, text, 554)
+
+	if deliveries := backend.delivered(); len(deliveries) != 1 || deliveries[0].recipient != "abc@ingest.example.com" {
+		t.Fatalf("deliveries = %+v", deliveries)
+	}
+}
+
+func TestServerLimits(t *testing.T) {
+	t.Run("line too long", func(t *testing.T) {
+		text := dialSession(t, &Server{Backend: &fakeBackend{}})
+
+		// Una línea que llena el búfer sin terminar: el servidor la rechaza sin leer más
+		if _, err := text.W.WriteString(strings.Repeat("a", maxLineLength)); err != nil || text.W.Flush() != nil {
+			t.Fatalf("write error: %v", err)
+		}
+		expectCode(t, text, 500)
+		if _, err := text.ReadLine(); err == nil {
+			t.Fatal("expected the session to be closed")
+		}
+	})
+
+	t.Run("message too large", func(t *testing.T) {
+		backend := &fakeBackend{domain: "ingest.example.com"}
+		text := dialSession(t, &Server{MaxMessageSize: 16, Backend: backend})
+
+		command(t, text, 250, "HELO banco.com")
+		command(t, text, 250, "MAIL FROM:<alertas@banco.com>")
+		command(t, text, 250, "RCPT TO:<abc@ingest.example.com>")
+		command(t, text, 354, "DATA")
+		command(t, text, 552, "%s\r\n.", strings.Repeat("x", 32))
+		if len(backend.delivered()) != 0 {
+			t.Fatal("expected no deliveries")
+		}
+	})
+
+	t.Run("too many recipients", func(t *testing.T) {
+		text := dialSession(t, &Server{Backend: &fakeBackend{domain: "ingest.example.com"}})
+
+		command(t, text, 250, "HELO banco.com")
+		command(t, text, 250, "MAIL FROM:<alertas@banco.com>")
+		for i := 0; i < defaultMaxRecipients; i++ {
+			command(t, text, 250, "RCPT TO:<abc@ingest.example.com>")
+		}
+		command(t, text, 452, "RCPT TO:<abc@ingest.example.com>")
+	})
+
+	t.Run("too many sessions", func(t *testing.T) {
+		addr := startServer(t, &Server{MaxSessions: 1, Backend: &fakeBackend{}})
+
+		first, err := textproto.Dial("tcp", addr)
+		if err != nil {
+			t.Fatalf("dial error: %v", err)
+		}
+		defer first.Close()
+		expectCode(t, first, 220)
+
+		second, err := textproto.Dial("tcp", addr)
+		if err != nil {
+			t.Fatalf("dial error: %v", err)
+		}
+		defer second.Close()
+		expectCode(t, second, 421)
+
+		// Al cerrar la primera sesión se libera su lugar
+		command(t, first, 221, "QUIT")
+		first.Close()
+		for attempt := 0; ; attempt++ {
+			third, err := textproto.Dial("tcp", addr)
+			if err != nil {
+				t.Fatalf("dial error: %v", err)
+			}
+			code, _, err := third.ReadResponse(220)
+			third.Close()
+			if err == nil {
+				break
+			}
+			if code != 421 || attempt == 50 {
+				t.Fatalf("expected the session to be released: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,542 @@
+// Package keywords evalúa las palabras clave de activación y exclusión de los patrones de
+// notificación. Cada palabra clave es una expresión sobre el texto normalizado del mensaje
+// (sin mayúsculas ni acentos):
+//
+//	compra                 contiene "compra" (también "COMPRA", "cómpra" o "compras")
+//	"compra aprobada"      frase exacta, palabra por palabra
+//	"compra tarjeta"~3     todas las palabras a no más de 3 palabras extra de distancia
+//	aprobada~1             alguna palabra del mensaje a distancia de edición 1 o menos
+//	compra AND NOT retiro  operadores AND, OR y NOT (en mayúsculas) y paréntesis
+//
+// Dos términos seguidos sin operador se combinan con AND. Una palabra clave sin comillas,
+// paréntesis, "~" ni operadores se trata como una frase literal, igual que antes de
+// existir las expresiones.
+package keywords
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Límites de los modificadores "~"
+const (
+	MaxFuzzyDistance = 3  // Distancia de edición máxima por palabra
+	MaxProximity     = 20 // Palabras extra permitidas entre los términos de una frase
+)
+
+// Límites de tamaño de las palabras clave de un patrón
+const (
+	MaxExpressionLength = 200 // Caracteres de una expresión
+	MaxExpressions      = 50  // Expresiones por lista (activación o exclusión)
+)
+
+// Text es un mensaje normalizado, listo para evaluar varias expresiones
+type Text struct {
+	lower      string   // Mensaje original en minúsculas
+	normalized string   // Palabras normalizadas separadas por un espacio
+	words      []string // Palabras normalizadas en orden
+}
+
+// NewText normaliza un mensaje para evaluar expresiones sobre él
+func NewText(message string) *Text {
+	normalized := Normalize(message)
+	return &Text{
+		lower:      strings.ToLower(message),
+		normalized: normalized,
+		words:      strings.Fields(normalized),
+	}
+}
+
+// Normalize pasa el texto a minúsculas, elimina acentos y diéresis y reemplaza todo lo que
+// no sea letra o dígito por un único espacio
+func Normalize(text string) string {
+	stripped, _, err := transform.String(
+		transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC),
+		text,
+	)
+	if err != nil {
+		stripped = text
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(stripped), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// Expression es una palabra clave compilada
+type Expression struct {
+	source string
+	root   node
+}
+
+// Compile compila una expresión de palabras clave
+func Compile(expression string) (*Expression, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, errors.New("empty expression")
+	}
+	if len([]rune(expression)) > MaxExpressionLength {
+		return nil, fmt.Errorf("expression exceeds %d characters", MaxExpressionLength)
+	}
+
+	if !isExpression(expression) {
+		return Literal(expression), nil
+	}
+
+	p := &parser{tokens: tokenize(expression)}
+	if err := p.tokensErr(); err != nil {
+		return nil, err
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q", p.peek().text)
+	}
+
+	return &Expression{source: expression, root: root}, nil
+}
+
+// Literal crea una expresión que busca el texto tal cual (normalizado) dentro del mensaje.
+// Las palabras clave sin letras ni dígitos (por ejemplo "$") se buscan sin normalizar.
+func Literal(keyword string) *Expression {
+	term := &termNode{text: Normalize(keyword)}
+	if term.text == "" {
+		term = &termNode{text: strings.ToLower(keyword), raw: true}
+	}
+	return &Expression{source: keyword, root: term}
+}
+
+// String retorna la expresión original
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Match evalúa la expresión sobre un mensaje normalizado
+func (e *Expression) Match(text *Text) bool {
+	return e.root.match(text)
+}
+
+// MatchAll retorna las expresiones (en su forma original) que coinciden con el mensaje
+func MatchAll(expressions []*Expression, text *Text) []string {
+	var matched []string
+	for _, expression := range expressions {
+		if expression.Match(text) {
+			matched = append(matched, expression.source)
+		}
+	}
+	return matched
+}
+
+// isExpression indica si la palabra clave usa la sintaxis de expresiones
+func isExpression(keyword string) bool {
+	if strings.ContainsAny(keyword, "\"()~") {
+		return true
+	}
+	for _, word := range strings.Fields(keyword) {
+		if word == "AND" || word == "OR" || word == "NOT" {
+			return true
+		}
+	}
+	return false
+}
+
+// node es un nodo del árbol de la expresión
+type node interface {
+	match(text *Text) bool
+}
+
+// termNode busca una palabra o texto sin comillas. Sin distancia, basta con que aparezca
+// dentro del mensaje; con distancia, alguna palabra del mensaje debe estar a esa distancia de edición.
+type termNode struct {
+	text     string
+	distance int
+	raw      bool // Buscar en el mensaje sin normalizar
+}
+
+func (n *termNode) match(text *Text) bool {
+	if n.text == "" {
+		return false
+	}
+	if n.raw {
+		return strings.Contains(text.lower, n.text)
+	}
+	if strings.Contains(text.normalized, n.text) {
+		return true
+	}
+	if n.distance == 0 {
+		return false
+	}
+	for _, word := range text.words {
+		if editDistance(word, n.text, n.distance) <= n.distance {
+			return true
+		}
+	}
+	return false
+}
+
+// phraseNode busca una frase entre comillas. Sin proximidad, las palabras deben aparecer
+// seguidas; con proximidad, todas deben aparecer en una ventana de len(words)+proximity palabras.
+type phraseNode struct {
+	words     []string
+	proximity int
+}
+
+func (n *phraseNode) match(text *Text) bool {
+	if len(n.words) == 0 {
+		return false
+	}
+	if n.proximity == 0 {
+		return strings.Contains(" "+text.normalized+" ", " "+strings.Join(n.words, " ")+" ")
+	}
+
+	window := len(n.words) + n.proximity
+	for start := range text.words {
+		end := start + window
+		if end > len(text.words) {
+			end = len(text.words)
+		}
+		if containsAll(text.words[start:end], n.words) {
+			return true
+		}
+	}
+	return false
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) match(text *Text) bool {
+	return !n.operand.match(text)
+}
+
+type andNode struct {
+	operands []node
+}
+
+func (n *andNode) match(text *Text) bool {
+	for _, operand := range n.operands {
+		if !operand.match(text) {
+			return false
+		}
+	}
+	return true
+}
+
+type orNode struct {
+	operands []node
+}
+
+func (n *orNode) match(text *Text) bool {
+	for _, operand := range n.operands {
+		if operand.match(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Tipos de token
+const (
+	tokenWord = iota
+	tokenPhrase
+	tokenOpen
+	tokenClose
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind     int
+	text     string
+	modifier string // Valor después de "~" (vacío si no tiene)
+	hasTilde bool
+	err      error
+}
+
+// tokenize separa la expresión en tokens; los errores léxicos quedan en el token afectado
+func tokenize(expression string) []token {
+	var tokens []token
+	input := []rune(expression)
+
+	for i := 0; i < len(input); {
+		r := input[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")"})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(input) && input[end] != '"' {
+				end++
+			}
+			if end >= len(input) {
+				tokens = append(tokens, token{kind: tokenPhrase, text: string(input[i:]), err: errors.New("unterminated phrase")})
+				return tokens
+			}
+			tok := token{kind: tokenPhrase, text: string(input[i+1 : end])}
+			i = readModifier(input, end+1, &tok)
+			tokens = append(tokens, tok)
+		default:
+			end := i
+			for end < len(input) && !unicode.IsSpace(input[end]) && !strings.ContainsRune("()\"~", input[end]) {
+				end++
+			}
+			word := string(input[i:end])
+			tok := token{kind: tokenWord, text: word}
+			switch word {
+			case "AND":
+				tok.kind = tokenAnd
+			case "OR":
+				tok.kind = tokenOr
+			case "NOT":
+				tok.kind = tokenNot
+			}
+			if end == i {
+				// "~" sin término previo
+				tok.err = errors.New("unexpected \"~\"")
+				end++
+			}
+			i = readModifier(input, end, &tok)
+			tokens = append(tokens, tok)
+		}
+	}
+
+	return tokens
+}
+
+// readModifier lee el sufijo "~N" de un término o frase y retorna la posición siguiente
+func readModifier(input []rune, i int, tok *token) int {
+	if i >= len(input) || input[i] != '~' {
+		return i
+	}
+	tok.hasTilde = true
+	end := i + 1
+	for end < len(input) && unicode.IsDigit(input[end]) {
+		end++
+	}
+	tok.modifier = string(input[i+1 : end])
+	return end
+}
+
+// parser es un parser descendente recursivo de expresiones de palabras clave
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// tokensErr retorna el primer error léxico de la expresión
+func (p *parser) tokensErr() error {
+	for _, tok := range p.tokens {
+		if tok.err != nil {
+			return tok.err
+		}
+		if tok.hasTilde && tok.kind != tokenWord && tok.kind != tokenPhrase {
+			return fmt.Errorf("unexpected \"~\" after %q", tok.text)
+		}
+	}
+	return nil
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// parseOr: and ("OR" and)*
+func (p *parser) parseOr() (node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []node{first}
+	for !p.done() && p.peek().kind == tokenOr {
+		p.pos++
+		operand, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &orNode{operands: operands}, nil
+}
+
+// parseAnd: unary (["AND"] unary)*
+func (p *parser) parseAnd() (node, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []node{first}
+	for !p.done() {
+		tok := p.peek()
+		if tok.kind == tokenOr || tok.kind == tokenClose {
+			break
+		}
+		if tok.kind == tokenAnd {
+			p.pos++
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &andNode{operands: operands}, nil
+}
+
+// parseUnary: "NOT" unary | primary
+func (p *parser) parseUnary() (node, error) {
+	if !p.done() && p.peek().kind == tokenNot {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary: "(" or ")" | frase | término
+func (p *parser) parsePrimary() (node, error) {
+	if p.done() {
+		return nil, errors.New("unexpected end of expression")
+	}
+
+	tok := p.peek()
+	p.pos++
+
+	switch tok.kind {
+	case tokenOpen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != tokenClose {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+
+	case tokenPhrase:
+		proximity, err := parseModifier(tok, MaxProximity)
+		if err != nil {
+			return nil, err
+		}
+		words := strings.Fields(Normalize(tok.text))
+		if len(words) == 0 {
+			return nil, errors.New("empty phrase")
+		}
+		return &phraseNode{words: words, proximity: proximity}, nil
+
+	case tokenWord:
+		distance, err := parseModifier(tok, MaxFuzzyDistance)
+		if err != nil {
+			return nil, err
+		}
+		text := Normalize(tok.text)
+		if text == "" {
+			return nil, fmt.Errorf("term %q has no letters or digits", tok.text)
+		}
+		return &termNode{text: text, distance: distance}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
+
+// parseModifier interpreta el valor de "~N" (por defecto 1) y verifica su límite
+func parseModifier(tok token, max int) (int, error) {
+	if !tok.hasTilde {
+		return 0, nil
+	}
+	if tok.modifier == "" {
+		return 1, nil
+	}
+
+	value, err := strconv.Atoi(tok.modifier)
+	if err != nil || value < 0 || value > max {
+		return 0, fmt.Errorf("invalid distance %q for %q (max %d)", tok.modifier, tok.text, max)
+	}
+	return value, nil
+}
+
+// containsAll verifica que todas las palabras buscadas estén en la ventana
+func containsAll(window, words []string) bool {
+	for _, word := range words {
+		found := false
+		for _, candidate := range window {
+			if candidate == word {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// editDistance calcula la distancia de Levenshtein entre a y b. Deja de calcular en cuanto
+// la distancia supera limit y en ese caso retorna limit+1.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
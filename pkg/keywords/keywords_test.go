@@ -0,0 +1,152 @@
+package keywords
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"¡Compra APROBADA!", "compra aprobada"},
+		{"Pingüino   café", "pinguino cafe"},
+		{"$1,250.00 MXN", "1 250 00 mxn"},
+		{"Tarjeta *1234", "tarjeta 1234"},
+		{"  \t ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.text); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestExpressionMatch(t *testing.T) {
+	message := "COMPRA APROBADA por $1,250.00 en OXXO Centro con tu tarjeta débito *1234"
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		// Literales, como antes de existir las expresiones
+		{"compra", true},
+		{"compras", false},
+		{"cómpra aprobada", true},
+		{"DÉBITO", true},
+		{"$", true},
+		{"€", false},
+
+		// Frases y proximidad
+		{`"compra aprobada"`, true},
+		{`"aprobada compra"`, false},
+		{`"oxxo tarjeta"~3`, true},
+		{`"tarjeta oxxo"~3`, true},
+		{`"oxxo tarjeta"~2`, false},
+
+		// Distancia de edición
+		{"aprovada", false},
+		{"aprovada~1", true},
+		{"aprovada~", true},
+		{"aprovado~1", false},
+		{"aprovado~2", true},
+		{"oxxo~1", true},
+
+		// Operadores
+		{"compra AND NOT retiro", true},
+		{"compra AND NOT oxxo", false},
+		{"retiro OR transferencia", false},
+		{"retiro OR oxxo", true},
+		{"compra (retiro OR tarjeta)", true},
+		{"compra retiro", false},
+		{"NOT (retiro OR deposito)", true},
+		{"NOT NOT compra", true},
+		{"retiro OR compra AND NOT oxxo", false},
+		{`tarjeta~2 AND NOT "compra rechazada"`, true},
+	}
+
+	text := NewText(message)
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			expression, err := Compile(tt.expression)
+			if err != nil {
+				t.Fatalf("Compile error: %v", err)
+			}
+			if got := expression.Match(text); got != tt.want {
+				t.Fatalf("Match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{"", "empty expression"},
+		{"   ", "empty expression"},
+		{strings.Repeat("a", MaxExpressionLength+1), "expression exceeds 200 characters"},
+		{`"compra`, "unterminated phrase"},
+		{`""`, "empty phrase"},
+		{"(compra", "missing closing parenthesis"},
+		{"compra)", `unexpected ")"`},
+		{"compra AND", "unexpected end of expression"},
+		{"AND compra", `unexpected "AND"`},
+		{"~2 compra", `unexpected "~"`},
+		{"(compra)~2 tarjeta", `unexpected "~"`},
+		{"compra AND~2 tarjeta", `unexpected "~" after "AND"`},
+		{"compra~4", `invalid distance "4" for "compra" (max 3)`},
+		{`"compra tarjeta"~21`, `invalid distance "21" for "compra tarjeta" (max 20)`},
+		{"$ AND compra", `term "$" has no letters or digits`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantErr, func(t *testing.T) {
+			_, err := Compile(tt.expression)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Compile(%q) error = %v, want %q", tt.expression, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchAll(t *testing.T) {
+	var expressions []*Expression
+	for _, source := range []string{"retiro", "Compra Aprobada", `"tarjeta debito"`, "NOT oxxo"} {
+		expression, err := Compile(source)
+		if err != nil {
+			t.Fatalf("Compile(%q) error: %v", source, err)
+		}
+		expressions = append(expressions, expression)
+	}
+
+	got := MatchAll(expressions, NewText("Compra aprobada con tarjeta de débito en OXXO"))
+	if len(got) != 1 || got[0] != "Compra Aprobada" {
+		t.Fatalf("MatchAll = %q, want [\"Compra Aprobada\"]", got)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"", "", 0, 0},
+		{"compra", "compra", 1, 0},
+		{"kitten", "sitting", 3, 3},
+		{"kitten", "sitting", 2, 3}, // Supera el límite
+		{"abc", "abcdef", 1, 2},     // La diferencia de longitud ya supera el límite
+		{"ñandu", "nandu", 1, 1},
+		{"aprobada", "aprovado", 3, 2},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package money
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrCurrencyMismatch se retorna al operar montos de monedas distintas
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// zeroDecimalCurrencies son las monedas ISO 4217 sin centavos de uso común en la región
+var zeroDecimalCurrencies = map[string]bool{
+	"CLP": true,
+	"PYG": true,
+	"JPY": true,
+	"KRW": true,
+}
+
+// MinorUnits retorna los decimales que usa una moneda (2 salvo las monedas sin centavos)
+func MinorUnits(currency string) int {
+	if zeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return 0
+	}
+	return Scale
+}
+
+// Money es un monto exacto en una moneda ISO 4217
+type Money struct {
+	Amount   Amount `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// New crea un Money con el código de moneda en mayúsculas
+func New(amount Amount, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(strings.TrimSpace(currency))}
+}
+
+// IsZero verifica si el monto es cero
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// SameCurrency verifica si dos montos están en la misma moneda
+func (m Money) SameCurrency(other Money) bool {
+	return strings.EqualFold(m.Currency, other.Currency)
+}
+
+// Add suma dos montos de la misma moneda
+func (m Money) Add(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub resta dos montos de la misma moneda
+func (m Money) Sub(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Cmp compara dos montos de la misma moneda: -1 si es menor, 0 si es igual y 1 si es mayor
+func (m Money) Cmp(other Money) (int, error) {
+	if !m.SameCurrency(other) {
+		return 0, ErrCurrencyMismatch
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Round redondea el monto a los decimales de su moneda (ej: los pesos chilenos no tienen centavos)
+func (m Money) Round(mode RoundingMode) Money {
+	if MinorUnits(m.Currency) == Scale {
+		return m
+	}
+	return Money{Amount: m.Amount.MulRat(1, unit, mode).MulRat(unit, 1, mode), Currency: m.Currency}
+}
+
+// Split reparte el monto en n partes iguales que suman exactamente el monto, respetando los
+// decimales de la moneda
+func (m Money) Split(n int) []Money {
+	if n <= 0 {
+		return nil
+	}
+
+	step := Amount(1)
+	if MinorUnits(m.Currency) == 0 {
+		step = unit
+	}
+
+	// Se reparte en la unidad mínima de la moneda; el resto que no alcanza para una unidad
+	// queda en la primera parte
+	parts := (m.Amount / step).Split(n)
+	result := make([]Money, n)
+	for i, part := range parts {
+		result[i] = Money{Amount: part * step, Currency: m.Currency}
+	}
+	result[0].Amount += m.Amount % step
+	return result
+}
+
+// String retorna el monto con su moneda ("1234.50 MXN")
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Amount.String()
+	}
+	return m.Amount.String() + " " + m.Currency
+}
@@ -0,0 +1,475 @@
+// Package money representa montos de dinero exactos. Los montos se guardan como un entero de
+// centésimos (las columnas de la base de datos son decimal(15,2)), por lo que sumas, restas y
+// comparaciones no acumulan el error de float64. Las operaciones que pueden producir fracciones
+// de centavo reciben un modo de redondeo explícito.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale es la cantidad de decimales de un Amount
+const Scale = 2
+
+// unit es la cantidad de centésimos de una unidad monetaria
+const unit = 100
+
+// ErrInvalidAmount se retorna cuando un texto no es un monto decimal válido
+var ErrInvalidAmount = errors.New("invalid money amount")
+
+// ErrOverflow se retorna cuando un monto no cabe en un Amount
+var ErrOverflow = errors.New("money amount out of range")
+
+// RoundingMode define cómo se redondean las fracciones de centavo
+type RoundingMode int
+
+const (
+	RoundHalfUp   RoundingMode = iota // La mitad se aleja de cero (2.345 → 2.35); el de PostgreSQL para numeric
+	RoundHalfEven                     // Redondeo bancario: la mitad va al par más cercano (2.345 → 2.34)
+	RoundDown                         // Trunca hacia cero
+	RoundUp                           // Se aleja de cero si hay fracción
+)
+
+// Amount es un monto exacto expresado en centésimos de la unidad monetaria
+type Amount int64
+
+// Zero es el monto cero
+const Zero Amount = 0
+
+// FromMinor crea un monto a partir de centésimos
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// FromUnits crea un monto a partir de unidades enteras
+func FromUnits(units int64) Amount {
+	return Amount(units * unit)
+}
+
+// FromFloat convierte un float64 al monto más cercano. Usa la representación decimal más corta
+// del número (0.1 es "0.1", no 0.1000000000000000055...) y redondea la mitad alejándose de cero.
+func FromFloat(value float64) Amount {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return Zero
+	}
+	amount, err := ParseRound(strconv.FormatFloat(value, 'f', -1, 64), RoundHalfUp)
+	if err != nil {
+		return Zero
+	}
+	return amount
+}
+
+// Parse interpreta un monto decimal ("1234.5", "-0.05"). Los decimales más allá del centavo
+// se redondean alejándose de cero, como al guardar en una columna decimal(15,2).
+func Parse(text string) (Amount, error) {
+	return ParseRound(text, RoundHalfUp)
+}
+
+// MustParse es como Parse pero falla con panic; para constantes del código
+func MustParse(text string) Amount {
+	amount, err := Parse(text)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// ParseRound interpreta un monto decimal redondeando los decimales más allá del centavo con el modo indicado
+func ParseRound(text string, mode RoundingMode) (Amount, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Zero, ErrInvalidAmount
+	}
+
+	negative := false
+	switch text[0] {
+	case '-':
+		negative = true
+		text = text[1:]
+	case '+':
+		text = text[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(text, ".")
+	if whole == "" && fraction == "" {
+		return Zero, ErrInvalidAmount
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || !isDigits(fraction) {
+		return Zero, ErrInvalidAmount
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/unit {
+		return Zero, ErrOverflow
+	}
+
+	// Centésimos exactos y resto para decidir el redondeo
+	cents := fraction
+	rest := ""
+	if len(cents) > Scale {
+		cents, rest = cents[:Scale], cents[Scale:]
+	}
+	cents += strings.Repeat("0", Scale-len(cents))
+	minor, _ := strconv.ParseInt(cents, 10, 64)
+
+	total := units*unit + minor
+	if roundsAway(total, rest, mode) {
+		total++
+	}
+
+	if negative {
+		total = -total
+	}
+	return Amount(total), nil
+}
+
+// roundsAway decide si un monto truncado debe subir un centavo según los dígitos descartados
+func roundsAway(truncated int64, rest string, mode RoundingMode) bool {
+	rest = strings.TrimRight(rest, "0")
+	if rest == "" {
+		return false
+	}
+
+	switch mode {
+	case RoundDown:
+		return false
+	case RoundUp:
+		return true
+	}
+
+	switch {
+	case rest[0] > '5':
+		return true
+	case rest[0] < '5':
+		return false
+	case len(rest) > 1:
+		// Más que la mitad exacta
+		return true
+	}
+
+	// Exactamente la mitad
+	if mode == RoundHalfEven {
+		return truncated%2 != 0
+	}
+	return true
+}
+
+// isDigits verifica que el texto solo tenga dígitos
+func isDigits(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] < '0' || text[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Minor retorna el monto en centésimos
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Float64 retorna el monto como float64. Solo para razones y porcentajes, nunca para seguir
+// operando con dinero.
+func (a Amount) Float64() float64 {
+	return float64(a) / unit
+}
+
+// String retorna el monto con dos decimales ("1234.50", "-0.05")
+func (a Amount) String() string {
+	minor := int64(a)
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/unit, minor%unit)
+}
+
+// IsZero verifica si el monto es cero
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+// IsPositive verifica si el monto es mayor que cero
+func (a Amount) IsPositive() bool {
+	return a > 0
+}
+
+// IsNegative verifica si el monto es menor que cero
+func (a Amount) IsNegative() bool {
+	return a < 0
+}
+
+// Abs retorna el valor absoluto del monto
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Neg retorna el monto con signo contrario
+func (a Amount) Neg() Amount {
+	return -a
+}
+
+// MulRat multiplica el monto por la fracción num/den redondeando con el modo indicado
+func (a Amount) MulRat(num, den int64, mode RoundingMode) Amount {
+	if den == 0 {
+		return Zero
+	}
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(a)), big.NewRat(num, den))
+	return roundRat(product, mode)
+}
+
+// Mul multiplica el monto por un factor (porcentaje, tipo de cambio, proporción) redondeando con
+// el modo indicado. El factor se toma por su representación decimal más corta.
+func (a Amount) Mul(factor float64, mode RoundingMode) Amount {
+	if math.IsNaN(factor) || math.IsInf(factor, 0) {
+		return Zero
+	}
+	rat, ok := new(big.Rat).SetString(strconv.FormatFloat(factor, 'f', -1, 64))
+	if !ok {
+		return Zero
+	}
+	return roundRat(rat.Mul(rat, new(big.Rat).SetInt64(int64(a))), mode)
+}
+
+// Div divide el monto entre n redondeando con el modo indicado. Para repartir un monto sin
+// perder centavos se usa Split.
+func (a Amount) Div(n int64, mode RoundingMode) Amount {
+	return a.MulRat(1, n, mode)
+}
+
+// Split reparte el monto en n partes iguales que suman exactamente el monto. Ver Allocate.
+func (a Amount) Split(n int) []Amount {
+	if n <= 0 {
+		return nil
+	}
+	weights := make([]int64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return a.Allocate(weights...)
+}
+
+// Allocate reparte el monto en proporción a los pesos. Cada parte se redondea con redondeo
+// bancario y los centavos que faltan o sobran se asignan a las partes con mayor fracción
+// descartada (en empate, a la primera), de modo que las partes suman exactamente el monto.
+func (a Amount) Allocate(weights ...int64) []Amount {
+	parts := make([]Amount, len(weights))
+	var total int64
+	for _, weight := range weights {
+		if weight > 0 {
+			total += weight
+		}
+	}
+	if total == 0 {
+		return parts
+	}
+
+	remainders := make([]*big.Rat, len(weights))
+	var allocated Amount
+	for i, weight := range weights {
+		if weight <= 0 {
+			remainders[i] = new(big.Rat)
+			continue
+		}
+		exact := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(a)), big.NewRat(weight, total))
+		parts[i] = roundRat(exact, RoundHalfEven)
+		remainders[i] = exact.Sub(exact, new(big.Rat).SetInt64(int64(parts[i])))
+		allocated += parts[i]
+	}
+
+	// Ajustar centavo a centavo la diferencia que dejó el redondeo
+	for diff := a - allocated; diff != 0; {
+		step := Amount(1)
+		if diff < 0 {
+			step = -1
+		}
+
+		best := -1
+		for i, remainder := range remainders {
+			if weights[i] <= 0 {
+				continue
+			}
+			if best < 0 || (step > 0 && remainder.Cmp(remainders[best]) > 0) || (step < 0 && remainder.Cmp(remainders[best]) < 0) {
+				best = i
+			}
+		}
+
+		parts[best] += step
+		remainders[best].Sub(remainders[best], new(big.Rat).SetInt64(int64(step)))
+		diff -= step
+	}
+
+	return parts
+}
+
+// roundRat redondea una cantidad racional de centésimos a un Amount con el modo indicado
+func roundRat(value *big.Rat, mode RoundingMode) Amount {
+	num := new(big.Int).Set(value.Num())
+	den := value.Denom()
+
+	negative := num.Sign() < 0
+	num.Abs(num)
+
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	if remainder.Sign() != 0 {
+		twice := new(big.Int).Mul(remainder, big.NewInt(2))
+		cmp := twice.Cmp(den)
+
+		up := false
+		switch mode {
+		case RoundUp:
+			up = true
+		case RoundDown:
+			up = false
+		case RoundHalfEven:
+			up = cmp > 0 || (cmp == 0 && quotient.Bit(0) == 1)
+		default:
+			up = cmp >= 0
+		}
+		if up {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		if negative {
+			return Amount(math.MinInt64)
+		}
+		return Amount(math.MaxInt64)
+	}
+	result := quotient.Int64()
+	if negative {
+		result = -result
+	}
+	return Amount(result)
+}
+
+// Ratio retorna a/b como float64 para porcentajes y proporciones. Retorna 0 si b es cero.
+func Ratio(a, b Amount) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// Sum suma los montos
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total
+}
+
+// Min retorna el menor de dos montos
+func Min(a, b Amount) Amount {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Max retorna el mayor de dos montos
+func Max(a, b Amount) Amount {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// MarshalJSON codifica el monto como número JSON con dos decimales exactos (12.50)
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON acepta un número JSON o un texto con el monto. El número se interpreta por su
+// texto, sin pasar por float64.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	// Notación exponencial (1e3): se acepta pasando por float64
+	if strings.ContainsAny(text, "eE") {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return ErrInvalidAmount
+		}
+		*a = FromFloat(value)
+		return nil
+	}
+
+	amount, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// Value guarda el monto como texto decimal exacto (driver.Valuer)
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan lee el monto de una columna numeric o de un agregado (sql.Scanner)
+func (a *Amount) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*a = Zero
+	case string:
+		return a.scanText(value)
+	case []byte:
+		return a.scanText(string(value))
+	case int64:
+		*a = FromUnits(value)
+	case float64:
+		*a = FromFloat(value)
+	default:
+		return fmt.Errorf("cannot scan %T into money.Amount", src)
+	}
+	return nil
+}
+
+// scanText interpreta el texto de una columna numeric; los agregados como AVG pueden tener
+// más de dos decimales o venir en notación exponencial
+func (a *Amount) scanText(text string) error {
+	if strings.ContainsAny(text, "eE") {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return ErrInvalidAmount
+		}
+		*a = FromFloat(value)
+		return nil
+	}
+
+	amount, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// GormDataType indica a GORM el tipo de columna de los montos
+func (Amount) GormDataType() string {
+	return "decimal(15,2)"
+}
@@ -0,0 +1,272 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Amount
+		wantErr error
+	}{
+		{"0", Zero, nil},
+		{"12", FromUnits(12), nil},
+		{"12.5", FromMinor(1250), nil},
+		{"12.50", FromMinor(1250), nil},
+		{"-0.01", FromMinor(-1), nil},
+		{"+3.10", FromMinor(310), nil},
+		{".75", FromMinor(75), nil},
+		{"  1.00 ", FromUnits(1), nil},
+		{"1.005", FromMinor(101), nil}, // La mitad se aleja de cero
+		{"-1.005", FromMinor(-101), nil},
+		{"1,000.00", Zero, ErrInvalidAmount},
+		{"abc", Zero, ErrInvalidAmount},
+		{"", Zero, ErrInvalidAmount},
+		{"1.2.3", Zero, ErrInvalidAmount},
+		{"99999999999999999999", Zero, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.text)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Parse(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseRound(t *testing.T) {
+	tests := []struct {
+		text string
+		mode RoundingMode
+		want Amount
+	}{
+		{"2.345", RoundHalfUp, FromMinor(235)},
+		{"2.345", RoundHalfEven, FromMinor(234)},
+		{"2.355", RoundHalfEven, FromMinor(236)},
+		{"2.3451", RoundHalfEven, FromMinor(235)},
+		{"2.349", RoundDown, FromMinor(234)},
+		{"2.341", RoundUp, FromMinor(235)},
+		{"-2.345", RoundHalfUp, FromMinor(-235)},
+		{"-2.341", RoundUp, FromMinor(-235)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRound(tt.text, tt.mode)
+		if err != nil {
+			t.Errorf("ParseRound(%q) error: %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRound(%q, %d) = %s, want %s", tt.text, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{Zero, "0.00"},
+		{FromMinor(5), "0.05"},
+		{FromMinor(-5), "-0.05"},
+		{FromMinor(123456), "1234.56"},
+		{FromUnits(-10), "-10.00"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("String(%d) = %q, want %q", int64(tt.amount), got, tt.want)
+		}
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  Amount
+	}{
+		{0.1 + 0.2, FromMinor(30)},
+		{1.005, FromMinor(101)},
+		{-2.675, FromMinor(-268)},
+		{math.NaN(), Zero},
+		{math.Inf(1), Zero},
+	}
+
+	for _, tt := range tests {
+		if got := FromFloat(tt.value); got != tt.want {
+			t.Errorf("FromFloat(%v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Amount
+		want Amount
+	}{
+		{"div rounds half even", FromMinor(1005).Div(2, RoundHalfEven), FromMinor(502)},
+		{"div rounds half up", FromMinor(1005).Div(2, RoundHalfUp), FromMinor(503)},
+		{"div by zero", FromUnits(10).Div(0, RoundHalfEven), Zero},
+		{"mulrat exact", FromUnits(100).MulRat(1, 3, RoundDown), FromMinor(3333)},
+		{"mulrat negative", FromUnits(-100).MulRat(2, 3, RoundHalfUp), FromMinor(-6667)},
+		{"mul uses the shortest decimal", FromUnits(1000).Mul(0.07, RoundHalfEven), FromUnits(70)},
+		{"mul rounds", FromMinor(999).Mul(0.5, RoundHalfEven), FromMinor(500)},
+		{"mul by NaN", FromUnits(1).Mul(math.NaN(), RoundHalfEven), Zero},
+		{"sum", Sum(FromMinor(10), FromMinor(20), FromMinor(-5)), FromMinor(25)},
+		{"min", Min(FromMinor(-1), FromMinor(1)), FromMinor(-1)},
+		{"max", Max(FromMinor(-1), FromMinor(1)), FromMinor(1)},
+		{"abs", FromMinor(-7).Abs(), FromMinor(7)},
+		{"overflow saturates", Amount(math.MaxInt64).MulRat(2, 1, RoundHalfEven), Amount(math.MaxInt64)},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Amount
+		weights []int64
+		want    []Amount
+	}{
+		{"even split", FromUnits(100), []int64{1, 1, 1}, []Amount{3334, 3333, 3333}},
+		{"weighted", FromUnits(10), []int64{1, 2, 2}, []Amount{200, 400, 400}},
+		{"cent goes to the largest fraction", FromMinor(100), []int64{1, 2}, []Amount{33, 67}},
+		{"negative amount", FromMinor(-100), []int64{1, 1, 1}, []Amount{-34, -33, -33}},
+		{"ignores non-positive weights", FromMinor(100), []int64{0, 1, -1}, []Amount{0, 100, 0}},
+		{"no weights", FromMinor(100), []int64{0}, []Amount{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.amount.Allocate(tt.weights...)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Allocate = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Allocate = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if parts := FromMinor(1001).Split(4); Sum(parts...) != FromMinor(1001) {
+		t.Fatalf("Split parts %v do not add up to 10.01", parts)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Amount Amount `json:"amount"`
+	}{FromMinor(1250)})
+	if err != nil || string(data) != `{"amount":12.50}` {
+		t.Fatalf("Marshal = %s, %v", data, err)
+	}
+
+	tests := []struct {
+		json    string
+		want    Amount
+		wantErr bool
+	}{
+		{`12.5`, FromMinor(1250), false},
+		{`"12.50"`, FromMinor(1250), false},
+		{`0.1`, FromMinor(10), false},
+		{`1e3`, FromUnits(1000), false},
+		{`null`, Zero, false},
+		{`"doce"`, Zero, true},
+	}
+
+	for _, tt := range tests {
+		var got Amount
+		err := json.Unmarshal([]byte(tt.json), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v", tt.json, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %s, want %s", tt.json, got, tt.want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		src     interface{}
+		want    Amount
+		wantErr bool
+	}{
+		{"1234.56", FromMinor(123456), false},
+		{[]byte("0.50"), FromMinor(50), false},
+		{"33.3333333333333333", FromMinor(3333), false}, // AVG de numeric
+		{"1.5e2", FromUnits(150), false},
+		{int64(7), FromUnits(7), false},
+		{float64(2.5), FromMinor(250), false},
+		{nil, Zero, false},
+		{true, Zero, true},
+	}
+
+	for _, tt := range tests {
+		var got Amount
+		err := got.Scan(tt.src)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Scan(%v) error = %v", tt.src, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Scan(%v) = %s, want %s", tt.src, got, tt.want)
+		}
+	}
+
+	value, err := FromMinor(-1).Value()
+	if err != nil || value != "-0.01" {
+		t.Fatalf("Value = %v, %v", value, err)
+	}
+}
+
+func TestMoney(t *testing.T) {
+	mxn := New(FromUnits(10), " mxn ")
+	if mxn.Currency != "MXN" || mxn.String() != "10.00 MXN" {
+		t.Fatalf("New = %+v", mxn)
+	}
+
+	if sum, err := mxn.Add(New(FromMinor(50), "MXN")); err != nil || sum.Amount != FromMinor(1050) {
+		t.Fatalf("Add = %v, %v", sum, err)
+	}
+	if _, err := mxn.Sub(New(FromUnits(1), "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("Sub across currencies error = %v", err)
+	}
+	if cmp, err := mxn.Cmp(New(FromUnits(11), "mxn")); err != nil || cmp != -1 {
+		t.Fatalf("Cmp = %d, %v", cmp, err)
+	}
+
+	clp := New(FromMinor(100050), "CLP")
+	if got := clp.Round(RoundHalfEven); got.Amount != FromUnits(1000) {
+		t.Fatalf("Round CLP = %s", got)
+	}
+	if got := mxn.Round(RoundHalfEven); got != mxn {
+		t.Fatalf("Round MXN = %s", got)
+	}
+
+	parts := New(FromUnits(100), "CLP").Split(3)
+	if parts[0].Amount != FromUnits(34) || parts[1].Amount != FromUnits(33) || parts[2].Amount != FromUnits(33) {
+		t.Fatalf("Split CLP = %v", parts)
+	}
+	if MinorUnits("jpy") != 0 || MinorUnits("USD") != Scale {
+		t.Fatal("unexpected minor units")
+	}
+}
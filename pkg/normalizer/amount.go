@@ -0,0 +1,126 @@
+package normalizer
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+// ErrInvalidAmount se retorna cuando el texto no contiene un monto interpretable
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// currencySymbols asocia símbolos con su código ISO. El orden importa: los
+// símbolos compuestos deben evaluarse antes que "$".
+var currencySymbols = []struct {
+	Symbol   string
+	Currency string
+}{
+	{"US$", "USD"},
+	{"U$S", "USD"},
+	{"USD$", "USD"},
+	{"R$", "BRL"},
+	{"S/", "PEN"},
+	{"€", "EUR"},
+	{"£", "GBP"},
+}
+
+var (
+	isoCodeRegex = regexp.MustCompile(`(?i)\b(MXN|USD|COP|EUR|ARS|CLP|PEN|BRL|UYU|GBP|CAD|DOP|GTQ|VES)\b`)
+	numberRegex  = regexp.MustCompile(`-?\d[\d.,']*\d|-?\d`)
+)
+
+// ParseAmount interpreta un monto capturado (ej: "$1,234.56", "1.234,56 COP")
+// aplicando las convenciones de separadores del formato y detectando la moneda. El monto se
+// interpreta en decimal exacto y se redondea con la mitad hacia arriba a los decimales de la
+// moneda (centavos, o unidades en monedas sin centavos como el peso chileno).
+func ParseAmount(raw string, format Format) (money.Money, error) {
+	number := numberRegex.FindString(raw)
+	if number == "" {
+		return money.Money{}, ErrInvalidAmount
+	}
+
+	normalized := normalizeNumber(number, format)
+	value, err := money.ParseRound(normalized, money.RoundHalfUp)
+	if err != nil {
+		return money.Money{}, ErrInvalidAmount
+	}
+
+	return money.New(value, DetectCurrency(raw, format.Currency)).Round(money.RoundHalfUp), nil
+}
+
+// DetectCurrency detecta la moneda indicada en el texto por código ISO o
+// símbolo. Si solo aparece "$" o no hay indicación, retorna la moneda por defecto.
+func DetectCurrency(raw, defaultCurrency string) string {
+	if match := isoCodeRegex.FindString(raw); match != "" {
+		return strings.ToUpper(match)
+	}
+
+	for _, symbol := range currencySymbols {
+		if strings.Contains(raw, symbol.Symbol) {
+			return symbol.Currency
+		}
+	}
+
+	return defaultCurrency
+}
+
+// normalizeNumber convierte un número con separadores locales al formato de strconv
+func normalizeNumber(number string, format Format) string {
+	// Los apóstrofes solo se usan como separadores de miles
+	number = strings.ReplaceAll(number, "'", "")
+
+	lastDot := strings.LastIndexByte(number, '.')
+	lastComma := strings.LastIndexByte(number, ',')
+
+	var decimal byte
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		// Ambos separadores presentes: el último es el decimal
+		if lastDot > lastComma {
+			decimal = '.'
+		} else {
+			decimal = ','
+		}
+	case lastDot >= 0:
+		decimal = resolveSingleSeparator(number, '.', format)
+	case lastComma >= 0:
+		decimal = resolveSingleSeparator(number, ',', format)
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(number); i++ {
+		char := number[i]
+		switch {
+		case char == decimal:
+			builder.WriteByte('.')
+		case char == '.' || char == ',':
+			// Separador de miles: se descarta
+		default:
+			builder.WriteByte(char)
+		}
+	}
+
+	return builder.String()
+}
+
+// resolveSingleSeparator decide si un único tipo de separador es decimal o de
+// miles. Retorna el separador si es decimal, o 0 si es de miles.
+func resolveSingleSeparator(number string, separator byte, format Format) byte {
+	if strings.Count(number, string(separator)) > 1 {
+		return 0
+	}
+
+	digitsAfter := len(number) - strings.LastIndexByte(number, separator) - 1
+	if digitsAfter != 3 {
+		return separator
+	}
+
+	// Con exactamente tres dígitos el texto es ambiguo ("1,234" / "1.234"):
+	// se resuelve con las convenciones del país del banco
+	if separator == format.ThousandsSeparator {
+		return 0
+	}
+	return separator
+}
@@ -0,0 +1,168 @@
+package normalizer
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDate se retorna cuando el texto no contiene una fecha interpretable
+var ErrInvalidDate = errors.New("invalid date")
+
+// monthNames asocia los prefijos de tres letras de meses en español e inglés con su número
+var monthNames = map[string]time.Month{
+	"ene": time.January, "jan": time.January,
+	"feb": time.February,
+	"mar": time.March,
+	"abr": time.April, "apr": time.April,
+	"may": time.May,
+	"jun": time.June,
+	"jul": time.July,
+	"ago": time.August, "aug": time.August,
+	"sep": time.September, "set": time.September,
+	"oct": time.October,
+	"nov": time.November,
+	"dic": time.December, "dec": time.December,
+}
+
+// relativeDays asocia expresiones relativas con el desfase en días respecto a hoy
+var relativeDays = map[string]int{
+	"hoy":       0,
+	"today":     0,
+	"ayer":      -1,
+	"yesterday": -1,
+	"anteayer":  -2,
+	"antier":    -2,
+}
+
+var (
+	isoDateRegex     = regexp.MustCompile(`\b(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})\b`)
+	numericDateRegex = regexp.MustCompile(`\b(\d{1,2})[-/.](\d{1,2})(?:[-/.](\d{2,4}))?\b`)
+	namedDateRegex   = regexp.MustCompile(`\b(\d{1,2})(?:\s+de)?[\s\-/]*([a-z]{3,10})\.?(?:(?:\s+del?)?[\s\-/]*(\d{4}|\d{2}))?\b`)
+	timeRegex        = regexp.MustCompile(`\b(\d{1,2}):(\d{2})(?::(\d{2}))?\s*(am|pm|a\.\s?m\.|p\.\s?m\.)?`)
+	relativeRegex    = regexp.MustCompile(`\b(hoy|today|ayer|yesterday|anteayer|antier)\b`)
+)
+
+// accentReplacer elimina los acentos más comunes en español
+var accentReplacer = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u")
+
+// ParseDate interpreta una fecha capturada en formatos dd/mm, mm/dd, ISO, con
+// nombres de mes en español ("15 OCT", "15 de octubre") o relativos ("hoy").
+// Si el texto no indica el año se asume el más reciente que no quede en el
+// futuro respecto a now. La hora se toma del texto si aparece.
+func ParseDate(raw string, format Format, now time.Time) (time.Time, error) {
+	location := format.Location
+	if location == nil {
+		location = time.Local
+	}
+	now = now.In(location)
+	text := accentReplacer.Replace(strings.ToLower(strings.TrimSpace(raw)))
+
+	year, month, day, found := 0, time.Month(0), 0, false
+
+	if match := isoDateRegex.FindStringSubmatch(text); match != nil {
+		year, _ = strconv.Atoi(match[1])
+		monthNumber, _ := strconv.Atoi(match[2])
+		month = time.Month(monthNumber)
+		day, _ = strconv.Atoi(match[3])
+		found = true
+	} else if match := numericDateRegex.FindStringSubmatch(text); match != nil {
+		first, _ := strconv.Atoi(match[1])
+		second, _ := strconv.Atoi(match[2])
+		day, month = first, time.Month(second)
+		if !format.DayFirst {
+			day, month = second, time.Month(first)
+		}
+		// Si el orden del formato produce un mes inválido, probar el orden inverso
+		if month > 12 && day <= 12 {
+			day, month = int(month), time.Month(day)
+		}
+		year = parseYear(match[3])
+		found = true
+	} else if match := findNamedDate(text); match != nil {
+		day, _ = strconv.Atoi(match[1])
+		month = monthNames[match[2][:3]]
+		year = parseYear(match[3])
+		found = true
+	} else if match := relativeRegex.FindStringSubmatch(text); match != nil {
+		date := now.AddDate(0, 0, relativeDays[match[1]])
+		year, month, day = date.Year(), date.Month(), date.Day()
+		found = true
+	}
+
+	if !found || month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, ErrInvalidDate
+	}
+
+	hour, minute, second := parseTime(text)
+	inferYear := year == 0
+	if inferYear {
+		year = now.Year()
+	}
+
+	date := time.Date(year, month, day, hour, minute, second, 0, location)
+	if date.Day() != day {
+		// time.Date normaliza fechas como 31/02; se consideran inválidas
+		return time.Time{}, ErrInvalidDate
+	}
+
+	// Sin año explícito, una fecha futura corresponde al año anterior (ej: "28 DIC" recibido en enero)
+	if inferYear && date.After(now.AddDate(0, 0, 1)) {
+		date = date.AddDate(-1, 0, 0)
+	}
+
+	return date, nil
+}
+
+// findNamedDate busca una fecha con nombre de mes conocido
+func findNamedDate(text string) []string {
+	for _, match := range namedDateRegex.FindAllStringSubmatch(text, -1) {
+		if _, ok := monthNames[match[2][:3]]; ok {
+			return match
+		}
+	}
+	return nil
+}
+
+// parseYear interpreta años de dos o cuatro dígitos. Retorna 0 si no hay año.
+func parseYear(raw string) int {
+	if raw == "" {
+		return 0
+	}
+	year, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0
+	}
+	if year < 100 {
+		year += 2000
+	}
+	return year
+}
+
+// parseTime extrae la hora del texto (ej: "14:32", "2:32 pm"). Retorna cero si no hay hora.
+func parseTime(text string) (int, int, int) {
+	match := timeRegex.FindStringSubmatch(text)
+	if match == nil {
+		return 0, 0, 0
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	second, _ := strconv.Atoi(match[3])
+
+	meridiem := strings.NewReplacer(".", "", " ", "").Replace(match[4])
+	switch {
+	case meridiem == "pm" && hour < 12:
+		hour += 12
+	case meridiem == "am" && hour == 12:
+		hour = 0
+	}
+
+	if hour > 23 || minute > 59 || second > 59 {
+		return 0, 0, 0
+	}
+
+	return hour, minute, second
+}
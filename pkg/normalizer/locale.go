@@ -0,0 +1,103 @@
+package normalizer
+
+import (
+	"strings"
+	"time"
+)
+
+// Format describe las convenciones numéricas y de fecha usadas por un banco/usuario
+type Format struct {
+	DecimalSeparator   byte           // Separador decimal (ej: '.' en MXN, ',' en COP)
+	ThousandsSeparator byte           // Separador de miles
+	DayFirst           bool           // true para dd/mm, false para mm/dd
+	Currency           string         // Moneda por defecto cuando el texto no la indica
+	Location           *time.Location // Zona horaria para interpretar fechas
+}
+
+// commaDecimalCurrencies indica, para cada moneda soportada, si sus bancos usan coma como
+// separador decimal. Toda moneda reconocida en los montos debe estar aquí: las que no están
+// toman el separador del locale del usuario.
+var commaDecimalCurrencies = map[string]bool{
+	"MXN": false,
+	"USD": false,
+	"CAD": false,
+	"GBP": false,
+	"PEN": false,
+	"DOP": false,
+	"GTQ": false,
+	"COP": true,
+	"ARS": true,
+	"CLP": true,
+	"UYU": true,
+	"VES": true,
+	"BRL": true,
+	"EUR": true,
+}
+
+// commaDecimalRegions son las regiones de locale que usan coma como separador decimal
+var commaDecimalRegions = map[string]bool{
+	"CO": true,
+	"AR": true,
+	"CL": true,
+	"UY": true,
+	"VE": true,
+	"BR": true,
+	"ES": true,
+}
+
+// monthFirstRegions son las regiones que escriben las fechas como mm/dd
+var monthFirstRegions = map[string]bool{
+	"US": true,
+}
+
+// NewFormat construye el formato a partir de la moneda de la cuenta bancaria,
+// el locale del usuario (ej: "es", "es-CO", "en_US") y su zona horaria
+func NewFormat(currency, locale, timezone string) Format {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	language, region := splitLocale(locale)
+
+	format := Format{
+		DecimalSeparator:   '.',
+		ThousandsSeparator: ',',
+		DayFirst:           true,
+		Currency:           currency,
+		Location:           time.Local,
+	}
+
+	// La moneda de la cuenta define las convenciones del país del banco;
+	// el locale solo se usa cuando la moneda no es conocida
+	commaDecimal, known := commaDecimalCurrencies[currency]
+	if !known {
+		commaDecimal = commaDecimalRegions[region]
+	}
+	if commaDecimal {
+		format.DecimalSeparator = ','
+		format.ThousandsSeparator = '.'
+	}
+
+	if monthFirstRegions[region] || (language == "en" && region == "") {
+		format.DayFirst = false
+	}
+
+	if timezone != "" {
+		if location, err := time.LoadLocation(timezone); err == nil {
+			format.Location = location
+		}
+	}
+
+	return format
+}
+
+// splitLocale separa un locale en idioma y región en mayúsculas
+func splitLocale(locale string) (string, string) {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	parts := strings.SplitN(locale, "-", 2)
+
+	language := strings.ToLower(parts[0])
+	region := ""
+	if len(parts) == 2 {
+		region = strings.ToUpper(parts[1])
+	}
+
+	return language, region
+}
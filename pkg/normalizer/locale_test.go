@@ -0,0 +1,46 @@
+package normalizer
+
+import "testing"
+
+func TestNewFormatSeparators(t *testing.T) {
+	tests := []struct {
+		currency, locale string
+		decimal          byte
+		dayFirst         bool
+	}{
+		{"MXN", "es-MX", '.', true},
+		{"MXN", "es-CO", '.', true}, // La moneda de la cuenta prevalece sobre el locale
+		{"USD", "es-AR", '.', true},
+		{"USD", "en-US", '.', false},
+		{"cop", "en", ',', false},
+		{"EUR", "es_ES", ',', true},
+		{"", "es-CL", ',', true},
+		{"XYZ", "pt-BR", ',', true},
+		{"XYZ", "", '.', true},
+	}
+
+	for _, tt := range tests {
+		format := NewFormat(tt.currency, tt.locale, "")
+		if format.DecimalSeparator != tt.decimal || format.DayFirst != tt.dayFirst {
+			t.Errorf("NewFormat(%q, %q) = decimal %q dayFirst %v, want %q %v",
+				tt.currency, tt.locale, format.DecimalSeparator, format.DayFirst, tt.decimal, tt.dayFirst)
+		}
+	}
+}
+
+func TestNewFormatCoversDetectedCurrencies(t *testing.T) {
+	// Toda moneda que ParseAmount puede detectar necesita su separador decimal
+	for _, currency := range []string{"MXN", "USD", "COP", "EUR", "ARS", "CLP", "PEN", "BRL", "UYU", "GBP", "CAD", "DOP", "GTQ", "VES"} {
+		if !isoCodeRegex.MatchString(currency) {
+			t.Errorf("%s is not detected in amounts", currency)
+		}
+		if _, ok := commaDecimalCurrencies[currency]; !ok {
+			t.Errorf("%s has no decimal separator", currency)
+		}
+	}
+	for _, symbol := range currencySymbols {
+		if _, ok := commaDecimalCurrencies[symbol.Currency]; !ok {
+			t.Errorf("%s has no decimal separator", symbol.Currency)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package patterncache
+
+import (
+	"container/list"
+	"regexp"
+	"sync"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/pkg/extractor"
+	"github.com/nick130920/fintech-backend/pkg/keywords"
+)
+
+// DefaultCapacity es el número de conjuntos (cuenta bancaria + canal) que se mantienen en memoria por defecto
+const DefaultCapacity = 1000
+
+// Key identifica un conjunto de patrones en caché
+type Key struct {
+	BankAccountID uint
+	Channel       entity.NotificationChannel
+}
+
+// CompiledPattern es un patrón con sus expresiones ya compiladas
+type CompiledPattern struct {
+	Pattern      *entity.BankNotificationPattern
+	Message      *regexp.Regexp            // MessagePattern compilado (nil si no tiene o es inválido)
+	FieldRegexes map[string]*regexp.Regexp // Regex individuales por campo (amount, date, ...)
+	Triggers     []*keywords.Expression    // Palabras clave de activación compiladas
+	Excludes     []*keywords.Expression    // Palabras clave de exclusión compiladas
+}
+
+// MatchesKeywords verifica las palabras clave del patrón usando las expresiones compiladas
+func (cp *CompiledPattern) MatchesKeywords(message string) bool {
+	triggered, excluded := cp.MatchedKeywords(message)
+	return entity.KeywordsAccepted(cp.Triggers, triggered, excluded)
+}
+
+// MatchedKeywords retorna las palabras clave de activación y exclusión que cumple el mensaje
+func (cp *CompiledPattern) MatchedKeywords(message string) (triggered, excluded []string) {
+	return entity.MatchKeywordExpressions(cp.Triggers, cp.Excludes, message)
+}
+
+// PatternSet contiene los patrones activos de una cuenta y canal, ordenados por prioridad
+type PatternSet struct {
+	Patterns []*CompiledPattern
+	Default  *CompiledPattern
+}
+
+// Stats contiene las estadísticas de uso de la caché
+type Stats struct {
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Invalidations uint64
+	Entries       int
+	Capacity      int
+	HitRate       float64 // Porcentaje de aciertos (0-100)
+}
+
+// Cache es una caché LRU acotada de patrones compilados, segura para uso concurrente
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[Key]*list.Element
+	order    *list.List // Frente = usado más recientemente
+
+	hits          uint64
+	misses        uint64
+	evictions     uint64
+	invalidations uint64
+}
+
+type cacheEntry struct {
+	key Key
+	set *PatternSet
+}
+
+// New crea una nueva caché con la capacidad indicada
+func New(capacity int) *Cache {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+
+	return &Cache{
+		capacity: capacity,
+		entries:  make(map[Key]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get obtiene el conjunto de patrones de una cuenta y canal
+func (c *Cache) Get(key Key) (*PatternSet, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		c.misses++
+		return nil, false
+	}
+
+	c.hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*cacheEntry).set, true
+}
+
+// Put guarda el conjunto de patrones de una cuenta y canal, desalojando el menos usado si es necesario
+func (c *Cache) Put(key Key, set *PatternSet) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, found := c.entries[key]; found {
+		element.Value.(*cacheEntry).set = set
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, set: set})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.evictions++
+	}
+}
+
+// InvalidateBankAccount elimina los conjuntos de todos los canales de una cuenta bancaria
+func (c *Cache) InvalidateBankAccount(bankAccountID uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if key.BankAccountID == bankAccountID {
+			c.order.Remove(element)
+			delete(c.entries, key)
+			c.invalidations++
+		}
+	}
+}
+
+// UpdatePatternStats copia las estadísticas de uso del patrón (coincidencias, éxitos, fallos) a
+// los conjuntos en caché que lo contienen, sin recompilarlos. Los conjuntos se reemplazan por
+// copias para no modificar los que otras goroutines estén evaluando.
+func (c *Cache) UpdatePatternStats(pattern *entity.BankNotificationPattern) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if key.BankAccountID != pattern.BankAccountID {
+			continue
+		}
+		entry := element.Value.(*cacheEntry)
+		if set, updated := entry.set.withPatternStats(pattern); updated {
+			entry.set = set
+		}
+	}
+}
+
+// withPatternStats retorna una copia del conjunto con las estadísticas del patrón actualizadas
+func (s *PatternSet) withPatternStats(pattern *entity.BankNotificationPattern) (*PatternSet, bool) {
+	replaced := make(map[*CompiledPattern]*CompiledPattern)
+	replace := func(compiled *CompiledPattern) *CompiledPattern {
+		if compiled == nil || compiled.Pattern.ID != pattern.ID {
+			return compiled
+		}
+		if updated, ok := replaced[compiled]; ok {
+			return updated
+		}
+
+		stats := *compiled.Pattern
+		stats.MatchCount = pattern.MatchCount
+		stats.SuccessCount = pattern.SuccessCount
+		stats.FailureCount = pattern.FailureCount
+		stats.SuccessRate = pattern.SuccessRate
+		stats.LastMatchedAt = pattern.LastMatchedAt
+
+		updated := *compiled
+		updated.Pattern = &stats
+		replaced[compiled] = &updated
+		return &updated
+	}
+
+	set := &PatternSet{
+		Patterns: make([]*CompiledPattern, len(s.Patterns)),
+		Default:  replace(s.Default),
+	}
+	for i, compiled := range s.Patterns {
+		set.Patterns[i] = replace(compiled)
+	}
+
+	return set, len(replaced) > 0
+}
+
+// Stats retorna las estadísticas actuales de la caché
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := Stats{
+		Hits:          c.hits,
+		Misses:        c.misses,
+		Evictions:     c.evictions,
+		Invalidations: c.invalidations,
+		Entries:       len(c.entries),
+		Capacity:      c.capacity,
+	}
+
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRate = float64(c.hits) / float64(total) * 100
+	}
+
+	return stats
+}
+
+// Compile compila las expresiones de un patrón. Las expresiones inválidas o que superan
+// los límites de seguridad se omiten, igual que al extraer sin caché.
+func Compile(pattern *entity.BankNotificationPattern) *CompiledPattern {
+	compiled := &CompiledPattern{
+		Pattern:      pattern,
+		FieldRegexes: make(map[string]*regexp.Regexp),
+		Triggers:     pattern.TriggerExpressions(),
+		Excludes:     pattern.ExcludeExpressions(),
+	}
+
+	if pattern.MessagePattern != "" {
+		if re, err := extractor.Compile(pattern.MessagePattern); err == nil {
+			compiled.Message = re
+		}
+	}
+
+	fieldRegexes := map[string]string{
+		extractor.FieldAmount:      pattern.AmountRegex,
+		extractor.FieldDate:        pattern.DateRegex,
+		extractor.FieldDescription: pattern.DescriptionRegex,
+		extractor.FieldMerchant:    pattern.MerchantRegex,
+		extractor.FieldBalance:     pattern.BalanceRegex,
+	}
+	for field, expression := range fieldRegexes {
+		if expression == "" {
+			continue
+		}
+		if re, err := extractor.CompileField(expression); err == nil {
+			compiled.FieldRegexes[field] = re
+		}
+	}
+
+	return compiled
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // AccountPostgres implementa la interfaz AccountRepo usando PostgreSQL
@@ -17,9 +18,19 @@ func NewAccountPostgres(db *gorm.DB) repo.AccountRepo {
 	return &AccountPostgres{db: db}
 }
 
-// Create crea una nueva cuenta en la base de datos
+// Create crea una nueva cuenta y el asiento de su saldo inicial en una transacción de DB
 func (r *AccountPostgres) Create(account *entity.Account) error {
-	return r.db.Create(account).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(account).Error; err != nil {
+			return err
+		}
+
+		// El balance de la cuenta ya es el saldo inicial: el asiento solo se registra
+		if entry := entity.NewOpeningLedgerEntry(account); entry != nil {
+			return createLedgerEntry(tx, entry)
+		}
+		return nil
+	})
 }
 
 // GetByID obtiene una cuenta por su ID
@@ -46,9 +57,10 @@ func (r *AccountPostgres) GetByUserIDAndType(userID uint, accountType entity.Acc
 	return accounts, err
 }
 
-// Update actualiza una cuenta en la base de datos
+// Update actualiza una cuenta en la base de datos. El balance y los fondos retenidos no se guardan:
+// solo cambian al registrar asientos y autorizaciones de transacciones.
 func (r *AccountPostgres) Update(account *entity.Account) error {
-	return r.db.Save(account).Error
+	return r.db.Omit("balance", "held_amount").Save(account).Error
 }
 
 // Delete elimina una cuenta (soft delete)
@@ -56,21 +68,6 @@ func (r *AccountPostgres) Delete(id uint) error {
 	return r.db.Delete(&entity.Account{}, id).Error
 }
 
-// UpdateBalance actualiza el balance de una cuenta
-func (r *AccountPostgres) UpdateBalance(id uint, newBalance float64) error {
-	return r.db.Model(&entity.Account{}).Where("id = ?", id).Update("balance", newBalance).Error
-}
-
-// AddToBalance suma una cantidad al balance actual
-func (r *AccountPostgres) AddToBalance(id uint, amount float64) error {
-	return r.db.Model(&entity.Account{}).Where("id = ?", id).Update("balance", gorm.Expr("balance + ?", amount)).Error
-}
-
-// SubtractFromBalance resta una cantidad del balance actual
-func (r *AccountPostgres) SubtractFromBalance(id uint, amount float64) error {
-	return r.db.Model(&entity.Account{}).Where("id = ?", id).Update("balance", gorm.Expr("balance - ?", amount)).Error
-}
-
 // HasTransactions verifica si una cuenta tiene transacciones asociadas
 func (r *AccountPostgres) HasTransactions(id uint) (bool, error) {
 	var count int64
@@ -94,8 +91,8 @@ func (r *AccountPostgres) GetActiveAccounts(userID uint) ([]*entity.Account, err
 }
 
 // GetTotalBalance obtiene el balance total de todas las cuentas de un usuario
-func (r *AccountPostgres) GetTotalBalance(userID uint) (float64, error) {
-	var total float64
+func (r *AccountPostgres) GetTotalBalance(userID uint) (money.Amount, error) {
+	var total money.Amount
 	err := r.db.Model(&entity.Account{}).
 		Where("user_id = ? AND is_active = ?", userID, true).
 		Select("COALESCE(SUM(balance), 0)").
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"gorm.io/gorm"
+)
+
+// BalanceDiscrepancyPostgres implementa BalanceDiscrepancyRepo usando PostgreSQL
+type BalanceDiscrepancyPostgres struct {
+	db *gorm.DB
+}
+
+// NewBalanceDiscrepancyPostgres crea una nueva instancia del repositorio de diferencias de conciliación
+func NewBalanceDiscrepancyPostgres(db *gorm.DB) repo.BalanceDiscrepancyRepo {
+	return &BalanceDiscrepancyPostgres{db: db}
+}
+
+// Create guarda una diferencia de conciliación
+func (r *BalanceDiscrepancyPostgres) Create(discrepancy *entity.BalanceDiscrepancy) error {
+	if err := r.db.Create(discrepancy).Error; err != nil {
+		return fmt.Errorf("failed to create balance discrepancy: %w", err)
+	}
+	return nil
+}
+
+// GetByID obtiene una diferencia de conciliación por ID. Retorna nil si no existe.
+func (r *BalanceDiscrepancyPostgres) GetByID(id uint) (*entity.BalanceDiscrepancy, error) {
+	var discrepancy entity.BalanceDiscrepancy
+	if err := r.db.First(&discrepancy, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get balance discrepancy %d: %w", id, err)
+	}
+	return &discrepancy, nil
+}
+
+// Update actualiza una diferencia de conciliación
+func (r *BalanceDiscrepancyPostgres) Update(discrepancy *entity.BalanceDiscrepancy) error {
+	if err := r.db.Save(discrepancy).Error; err != nil {
+		return fmt.Errorf("failed to update balance discrepancy %d: %w", discrepancy.ID, err)
+	}
+	return nil
+}
+
+// GetWithFilters obtiene las diferencias del usuario con filtros, de la más reciente a la más antigua
+func (r *BalanceDiscrepancyPostgres) GetWithFilters(userID uint, filter entity.BalanceDiscrepancyFilter) ([]*entity.BalanceDiscrepancy, int64, error) {
+	query := r.db.Model(&entity.BalanceDiscrepancy{}).Where("user_id = ?", userID)
+
+	if filter.BankAccountID != nil {
+		query = query.Where("bank_account_id = ?", *filter.BankAccountID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count balance discrepancies: %w", err)
+	}
+
+	query = query.Order("created_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var discrepancies []*entity.BalanceDiscrepancy
+	if err := query.Find(&discrepancies).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get balance discrepancies: %w", err)
+	}
+
+	return discrepancies, total, nil
+}
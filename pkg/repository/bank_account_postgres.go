@@ -6,6 +6,7 @@ import (
 
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -103,6 +104,15 @@ func (r *BankAccountPostgres) GetByAccountNumberMask(userID uint, mask string) (
 	return &bankAccount, nil
 }
 
+// GetAllByAccountNumberMask obtiene todas las cuentas bancarias de un usuario con alguna de las máscaras
+func (r *BankAccountPostgres) GetAllByAccountNumberMask(userID uint, masks ...string) ([]*entity.BankAccount, error) {
+	var bankAccounts []*entity.BankAccount
+	if err := r.db.Where("user_id = ? AND account_number_mask IN ?", userID, masks).Order("created_at DESC").Find(&bankAccounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to get bank accounts by mask for user %d: %w", userID, err)
+	}
+	return bankAccounts, nil
+}
+
 // SearchByAlias busca cuentas bancarias por alias
 func (r *BankAccountPostgres) SearchByAlias(userID uint, alias string) ([]*entity.BankAccount, error) {
 	var bankAccounts []*entity.BankAccount
@@ -183,7 +193,7 @@ func (r *BankAccountPostgres) SetNotificationEnabled(id uint, enabled bool) erro
 }
 
 // UpdateBalance actualiza el balance de una cuenta bancaria
-func (r *BankAccountPostgres) UpdateBalance(id uint, balance float64) error {
+func (r *BankAccountPostgres) UpdateBalance(id uint, balance money.Amount) error {
 	updates := map[string]interface{}{
 		"last_balance":        balance,
 		"last_balance_update": time.Now(),
@@ -2,12 +2,11 @@ package repository
 
 import (
 	"fmt"
-	"strings"
-	"time"
 
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // BankNotificationPatternPostgres implementa BankNotificationPatternRepo usando PostgreSQL
@@ -28,6 +27,26 @@ func (r *BankNotificationPatternPostgres) Create(pattern *entity.BankNotificatio
 	return nil
 }
 
+// CreateFromTemplate crea un patrón copiado de una plantilla conservando requires_validation=false,
+// que gorm reemplaza al crear por su valor por defecto, en una transacción de DB
+func (r *BankNotificationPatternPostgres) CreateFromTemplate(pattern *entity.BankNotificationPattern) error {
+	requiresValidation := pattern.RequiresValidation
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(pattern).Error; err != nil {
+			return err
+		}
+		if pattern.RequiresValidation == requiresValidation {
+			return nil
+		}
+		pattern.RequiresValidation = requiresValidation
+		return tx.Model(pattern).Update("requires_validation", requiresValidation).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create bank notification pattern: %w", err)
+	}
+	return nil
+}
+
 // GetByID obtiene un patrón de notificación por ID
 func (r *BankNotificationPatternPostgres) GetByID(id uint) (*entity.BankNotificationPattern, error) {
 	var pattern entity.BankNotificationPattern
@@ -169,33 +188,8 @@ func (r *BankNotificationPatternPostgres) GetMatchingPatterns(bankAccountID uint
 
 	// Filtrar patrones que podrían coincidir basándose en palabras clave
 	var matchingPatterns []*entity.BankNotificationPattern
-	messageLower := strings.ToLower(message)
-
 	for _, pattern := range patterns {
-		// Verificar palabras clave de activación
-		triggerKeywords := pattern.GetKeywordsTrigger()
-		excludeKeywords := pattern.GetKeywordsExclude()
-
-		hasMatch := len(triggerKeywords) == 0 // Si no hay keywords, considera todos
-		hasExclusion := false
-
-		// Verificar palabras de activación
-		for _, keyword := range triggerKeywords {
-			if strings.Contains(messageLower, strings.ToLower(keyword)) {
-				hasMatch = true
-				break
-			}
-		}
-
-		// Verificar palabras de exclusión
-		for _, keyword := range excludeKeywords {
-			if strings.Contains(messageLower, strings.ToLower(keyword)) {
-				hasExclusion = true
-				break
-			}
-		}
-
-		if hasMatch && !hasExclusion {
+		if pattern.MatchesKeywords(message) {
 			matchingPatterns = append(matchingPatterns, pattern)
 		}
 	}
@@ -227,6 +221,19 @@ func (r *BankNotificationPatternPostgres) GetByPriority(bankAccountID uint, chan
 	return patterns, nil
 }
 
+// GetCandidatePatterns obtiene los patrones activos y en aprendizaje de una cuenta y canal.
+// Los patrones en aprendizaje se evalúan después de los activos con la misma prioridad.
+func (r *BankNotificationPatternPostgres) GetCandidatePatterns(bankAccountID uint, channel entity.NotificationChannel) ([]*entity.BankNotificationPattern, error) {
+	var patterns []*entity.BankNotificationPattern
+	if err := r.db.Where("bank_account_id = ? AND channel = ? AND status IN ?",
+		bankAccountID, channel, []entity.NotificationPatternStatus{entity.NotificationPatternStatusActive, entity.NotificationPatternStatusLearning}).
+		Order("priority ASC, CASE WHEN status = 'active' THEN 0 ELSE 1 END, success_rate DESC").
+		Find(&patterns).Error; err != nil {
+		return nil, fmt.Errorf("failed to get candidate patterns: %w", err)
+	}
+	return patterns, nil
+}
+
 // SetStatus cambia el estado de un patrón
 func (r *BankNotificationPatternPostgres) SetStatus(id uint, status entity.NotificationPatternStatus) error {
 	if err := r.db.Model(&entity.BankNotificationPattern{}).Where("id = ?", id).Update("status", status).Error; err != nil {
@@ -259,19 +266,48 @@ func (r *BankNotificationPatternPostgres) SetDefault(id uint, isDefault bool) er
 }
 
 // UpdateStatistics actualiza las estadísticas de un patrón
-func (r *BankNotificationPatternPostgres) UpdateStatistics(id uint, matchCount, successCount int, successRate float64) error {
+func (r *BankNotificationPatternPostgres) UpdateStatistics(pattern *entity.BankNotificationPattern) error {
 	updates := map[string]interface{}{
-		"match_count":     matchCount,
-		"success_count":   successCount,
-		"success_rate":    successRate,
-		"last_matched_at": time.Now(),
+		"match_count":     pattern.MatchCount,
+		"success_count":   pattern.SuccessCount,
+		"failure_count":   pattern.FailureCount,
+		"success_rate":    pattern.SuccessRate,
+		"last_matched_at": pattern.LastMatchedAt,
 	}
-	if err := r.db.Model(&entity.BankNotificationPattern{}).Where("id = ?", id).Updates(updates).Error; err != nil {
-		return fmt.Errorf("failed to update statistics for pattern %d: %w", id, err)
+	if err := r.db.Model(&entity.BankNotificationPattern{}).Where("id = ?", pattern.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update statistics for pattern %d: %w", pattern.ID, err)
 	}
 	return nil
 }
 
+// RecordMatch registra una coincidencia del patrón bloqueando la fila para evitar conteos perdidos
+func (r *BankNotificationPatternPostgres) RecordMatch(id uint, success bool) error {
+	return r.updateStatisticsLocked(id, func(pattern *entity.BankNotificationPattern) {
+		pattern.RecordMatch(success)
+	})
+}
+
+// RecordFeedback registra la validación del usuario sobre un movimiento generado por el patrón
+func (r *BankNotificationPatternPostgres) RecordFeedback(id uint, success, wasSuccess bool) error {
+	return r.updateStatisticsLocked(id, func(pattern *entity.BankNotificationPattern) {
+		pattern.RecordFeedback(success, wasSuccess)
+	})
+}
+
+// updateStatisticsLocked lee el patrón con bloqueo de fila, aplica el cambio y guarda las estadísticas
+func (r *BankNotificationPatternPostgres) updateStatisticsLocked(id uint, apply func(pattern *entity.BankNotificationPattern)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var pattern entity.BankNotificationPattern
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&pattern, id).Error; err != nil {
+			return fmt.Errorf("failed to get pattern %d for statistics: %w", id, err)
+		}
+
+		apply(&pattern)
+
+		return NewBankNotificationPatternPostgres(tx).UpdateStatistics(&pattern)
+	})
+}
+
 // CountByUserID cuenta los patrones de un usuario
 func (r *BankNotificationPatternPostgres) CountByUserID(userID uint) (int64, error) {
 	var count int64
@@ -325,3 +361,12 @@ func (r *BankNotificationPatternPostgres) GetLearningPatterns(userID uint) ([]*e
 	}
 	return patterns, nil
 }
+
+// GetByTemplateID obtiene los patrones suscritos a una plantilla de la biblioteca
+func (r *BankNotificationPatternPostgres) GetByTemplateID(templateID uint) ([]*entity.BankNotificationPattern, error) {
+	var patterns []*entity.BankNotificationPattern
+	if err := r.db.Where("template_id = ?", templateID).Find(&patterns).Error; err != nil {
+		return nil, fmt.Errorf("failed to get bank notification patterns for template %d: %w", templateID, err)
+	}
+	return patterns, nil
+}
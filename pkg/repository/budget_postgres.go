@@ -7,6 +7,7 @@ import (
 
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // BudgetPostgres implementa BudgetRepo usando PostgreSQL
@@ -142,10 +143,11 @@ func (r *BudgetPostgres) DeleteAllocation(id uint) error {
 	return r.db.Delete(&entity.BudgetAllocation{}, id).Error
 }
 
-// UpdateBudgetSpentAmount actualiza el monto gastado de un presupuesto basado en sus gastos
+// UpdateBudgetSpentAmount actualiza el monto gastado de un presupuesto basado en sus gastos y en las
+// transacciones de gasto del mes: por línea las divididas y por categoría asignada las demás
 func (r *BudgetPostgres) UpdateBudgetSpentAmount(budgetID uint) error {
 	// Crear la subconsulta correctamente con GORM
-	var totalSpent float64
+	var totalSpent money.Amount
 
 	// Primero obtenemos el total gastado
 	err := r.db.Model(&entity.Expense{}).
@@ -157,6 +159,22 @@ func (r *BudgetPostgres) UpdateBudgetSpentAmount(budgetID uint) error {
 		return err
 	}
 
+	// Sumar las líneas de transacciones divididas asignadas al presupuesto
+	splitsSpent, err := r.splitsSpentAmount("s.allocation_id IN (?)",
+		r.db.Model(&entity.BudgetAllocation{}).Select("id").Where("budget_id = ?", budgetID))
+	if err != nil {
+		return err
+	}
+	totalSpent += splitsSpent
+
+	// Sumar las transacciones sin dividir de las categorías asignadas
+	transactionsSpent, err := r.transactionsSpentAmount(budgetID, "category_id IN (?)",
+		r.db.Model(&entity.BudgetAllocation{}).Select("category_id").Where("budget_id = ?", budgetID))
+	if err != nil {
+		return err
+	}
+	totalSpent += transactionsSpent
+
 	// Luego actualizamos el presupuesto con el valor calculado
 	return r.db.Model(&entity.Budget{}).
 		Where("id = ?", budgetID).
@@ -166,10 +184,12 @@ func (r *BudgetPostgres) UpdateBudgetSpentAmount(budgetID uint) error {
 		}).Error
 }
 
-// UpdateAllocationSpentAmount actualiza el monto gastado de una asignación basado en sus gastos
+// UpdateAllocationSpentAmount actualiza el monto gastado de una asignación basado en sus gastos, en
+// las líneas de transacciones divididas asignadas a ella y en las transacciones de gasto sin dividir
+// de su categoría en el mes del presupuesto
 func (r *BudgetPostgres) UpdateAllocationSpentAmount(allocationID uint) error {
 	// Crear la subconsulta correctamente con GORM
-	var totalSpent float64
+	var totalSpent money.Amount
 
 	// Primero obtenemos el total gastado
 	err := r.db.Model(&entity.Expense{}).
@@ -181,6 +201,24 @@ func (r *BudgetPostgres) UpdateAllocationSpentAmount(allocationID uint) error {
 		return err
 	}
 
+	// Sumar las líneas de transacciones divididas asignadas a la asignación
+	splitsSpent, err := r.splitsSpentAmount("s.allocation_id = ?", allocationID)
+	if err != nil {
+		return err
+	}
+	totalSpent += splitsSpent
+
+	// Sumar las transacciones sin dividir de la categoría
+	var allocation entity.BudgetAllocation
+	if err := r.db.First(&allocation, allocationID).Error; err != nil {
+		return err
+	}
+	transactionsSpent, err := r.transactionsSpentAmount(allocation.BudgetID, "category_id = ?", allocation.CategoryID)
+	if err != nil {
+		return err
+	}
+	totalSpent += transactionsSpent
+
 	// Luego actualizamos la asignación con el valor calculado
 	return r.db.Model(&entity.BudgetAllocation{}).
 		Where("id = ?", allocationID).
@@ -190,6 +228,38 @@ func (r *BudgetPostgres) UpdateAllocationSpentAmount(allocationID uint) error {
 		}).Error
 }
 
+// splitsSpentAmount suma las líneas de gastos divididos vigentes que cumplen la condición indicada
+func (r *BudgetPostgres) splitsSpentAmount(condition string, args ...interface{}) (money.Amount, error) {
+	var total money.Amount
+	err := r.db.Table("transaction_splits s").
+		Select("COALESCE(SUM(s.amount), 0)").
+		Joins("JOIN transactions t ON t.id = s.transaction_id").
+		Where("t.type = ? AND t.status <> ? AND t.deleted_at IS NULL", entity.TransactionTypeExpense, entity.TransactionStatusCancelled).
+		Where(condition, args...).
+		Row().Scan(&total)
+	return total, err
+}
+
+// transactionsSpentAmount suma las transacciones de gasto vigentes sin dividir del mes del presupuesto
+// cuya categoría cumple la condición indicada. Las divididas suman por línea en splitsSpentAmount.
+func (r *BudgetPostgres) transactionsSpentAmount(budgetID uint, condition string, args ...interface{}) (money.Amount, error) {
+	var budget entity.Budget
+	if err := r.db.First(&budget, budgetID).Error; err != nil {
+		return 0, err
+	}
+	start := time.Date(budget.Year, time.Month(budget.Month), 1, 0, 0, 0, 0, time.Local)
+
+	var total money.Amount
+	err := r.db.Model(&entity.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND type = ? AND status <> ?", budget.UserID, entity.TransactionTypeExpense, entity.TransactionStatusCancelled).
+		Where("transaction_date >= ? AND transaction_date < ?", start, start.AddDate(0, 1, 0)).
+		Where("NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transactions.id)").
+		Where(condition, args...).
+		Row().Scan(&total)
+	return total, err
+}
+
 // GetBudgetSummary obtiene un resumen del presupuesto con estadísticas
 func (r *BudgetPostgres) GetBudgetSummary(userID uint, year, month int) (*entity.Budget, error) {
 	var budget entity.Budget
@@ -5,6 +5,7 @@ import (
 
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // CategoryPostgres implementa CategoryRepo usando PostgreSQL
@@ -207,10 +208,11 @@ func (r *CategoryPostgres) HasExpenses(categoryID uint) (bool, error) {
 	return count > 0, err
 }
 
-// GetCategoryUsageStats obtiene estadísticas de uso de una categoría
-func (r *CategoryPostgres) GetCategoryUsageStats(categoryID uint) (int64, float64, error) {
+// GetCategoryUsageStats obtiene estadísticas de uso de una categoría en gastos y líneas de
+// transacciones divididas
+func (r *CategoryPostgres) GetCategoryUsageStats(categoryID uint) (int64, money.Amount, error) {
 	var count int64
-	var totalAmount float64
+	var totalAmount money.Amount
 
 	err := r.db.Model(&entity.Expense{}).
 		Where("category_id = ? AND status IN (?)", categoryID, []string{"confirmed", "pending"}).
@@ -225,7 +227,23 @@ func (r *CategoryPostgres) GetCategoryUsageStats(categoryID uint) (int64, float6
 		Select("COALESCE(SUM(amount), 0)").
 		Scan(&totalAmount).Error
 
-	return count, totalAmount, err
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Las líneas de transacciones divididas cuentan como uso de su categoría
+	var splits struct {
+		Count int64
+		Total money.Amount
+	}
+	err = r.db.Table("transaction_splits s").
+		Select("COUNT(*) AS count, COALESCE(SUM(s.amount), 0) AS total").
+		Joins("JOIN transactions t ON t.id = s.transaction_id").
+		Where("s.category_id = ? AND t.type = ? AND t.status <> ? AND t.deleted_at IS NULL",
+			categoryID, entity.TransactionTypeExpense, entity.TransactionStatusCancelled).
+		Scan(&splits).Error
+
+	return count + splits.Count, totalAmount + splits.Total, err
 }
 
 // CreateDefaultCategories crea las categorías por defecto del sistema
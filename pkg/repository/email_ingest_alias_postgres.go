@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"gorm.io/gorm"
+)
+
+// EmailIngestAliasPostgres implementa EmailIngestAliasRepo usando PostgreSQL
+type EmailIngestAliasPostgres struct {
+	db *gorm.DB
+}
+
+// NewEmailIngestAliasPostgres crea una nueva instancia del repositorio de alias de ingesta de correos
+func NewEmailIngestAliasPostgres(db *gorm.DB) repo.EmailIngestAliasRepo {
+	return &EmailIngestAliasPostgres{db: db}
+}
+
+// Create guarda un alias de ingesta
+func (r *EmailIngestAliasPostgres) Create(alias *entity.EmailIngestAlias) error {
+	if err := r.db.Create(alias).Error; err != nil {
+		return fmt.Errorf("failed to create email ingest alias: %w", err)
+	}
+	return nil
+}
+
+// Update actualiza un alias de ingesta
+func (r *EmailIngestAliasPostgres) Update(alias *entity.EmailIngestAlias) error {
+	if err := r.db.Save(alias).Error; err != nil {
+		return fmt.Errorf("failed to update email ingest alias %d: %w", alias.ID, err)
+	}
+	return nil
+}
+
+// GetByUserID obtiene el alias de ingesta del usuario. Retorna nil si no tiene.
+func (r *EmailIngestAliasPostgres) GetByUserID(userID uint) (*entity.EmailIngestAlias, error) {
+	var alias entity.EmailIngestAlias
+	if err := r.db.Where("user_id = ?", userID).First(&alias).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get email ingest alias of user %d: %w", userID, err)
+	}
+	return &alias, nil
+}
+
+// GetByAlias obtiene un alias de ingesta por su parte local. Retorna nil si no existe.
+func (r *EmailIngestAliasPostgres) GetByAlias(alias string) (*entity.EmailIngestAlias, error) {
+	var found entity.EmailIngestAlias
+	if err := r.db.Where("alias = ?", alias).First(&found).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get email ingest alias: %w", err)
+	}
+	return &found, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // ExpensePostgres implementa ExpenseRepo usando PostgreSQL
@@ -19,9 +20,56 @@ func NewExpensePostgres(db *gorm.DB) repo.ExpenseRepo {
 	return &ExpensePostgres{db: db}
 }
 
-// Create crea un nuevo gasto
+// Create crea un nuevo gasto y registra su asiento en el libro contable
 func (r *ExpensePostgres) Create(expense *entity.Expense) error {
-	return r.db.Create(expense).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(expense).Error; err != nil {
+			return err
+		}
+		return syncExpenseEntries(tx, expense.ID)
+	})
+}
+
+// CreateWithBudgetUpdate crea un gasto, registra su asiento y recalcula los montos gastados del
+// presupuesto en una transacción de DB
+func (r *ExpensePostgres) CreateWithBudgetUpdate(expense *entity.Expense) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(expense).Error; err != nil {
+			return err
+		}
+		if err := syncExpenseEntries(tx, expense.ID); err != nil {
+			return err
+		}
+
+		// Recalcular montos gastados dentro de la misma transacción
+		budgetRepo := NewBudgetPostgres(tx)
+		if err := budgetRepo.UpdateAllocationSpentAmount(expense.AllocationID); err != nil {
+			return err
+		}
+
+		return budgetRepo.UpdateBudgetSpentAmount(expense.BudgetID)
+	})
+}
+
+// CancelWithBudgetUpdate guarda un gasto cancelado, revierte su asiento y recalcula los montos
+// gastados del presupuesto en una transacción de DB
+func (r *ExpensePostgres) CancelWithBudgetUpdate(expense *entity.Expense) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(expense).Error; err != nil {
+			return err
+		}
+		if err := syncExpenseEntries(tx, expense.ID); err != nil {
+			return err
+		}
+
+		// Recalcular montos gastados dentro de la misma transacción
+		budgetRepo := NewBudgetPostgres(tx)
+		if err := budgetRepo.UpdateAllocationSpentAmount(expense.AllocationID); err != nil {
+			return err
+		}
+
+		return budgetRepo.UpdateBudgetSpentAmount(expense.BudgetID)
+	})
 }
 
 // GetByID obtiene un gasto por ID
@@ -106,14 +154,24 @@ func (r *ExpensePostgres) GetByAllocationID(allocationID uint) ([]*entity.Expens
 	return expenses, err
 }
 
-// Update actualiza un gasto
+// Update actualiza un gasto y su asiento en el libro contable
 func (r *ExpensePostgres) Update(expense *entity.Expense) error {
-	return r.db.Save(expense).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(expense).Error; err != nil {
+			return err
+		}
+		return syncExpenseEntries(tx, expense.ID)
+	})
 }
 
-// Delete elimina un gasto (soft delete)
+// Delete elimina un gasto (soft delete) y revierte su asiento en el libro contable
 func (r *ExpensePostgres) Delete(id uint) error {
-	return r.db.Delete(&entity.Expense{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity.Expense{}, id).Error; err != nil {
+			return err
+		}
+		return syncExpenseEntries(tx, id)
+	})
 }
 
 // GetExpensesByStatus obtiene gastos por estado
@@ -238,21 +296,29 @@ func (r *ExpensePostgres) Search(userID uint, params SearchParams) ([]*entity.Ex
 	return expenses, total, err
 }
 
-// UpdateStatus actualiza el estado de un gasto
+// UpdateStatus actualiza el estado de un gasto; al cancelarlo se revierte su asiento
 func (r *ExpensePostgres) UpdateStatus(id uint, status entity.ExpenseStatus) error {
-	return r.db.Model(&entity.Expense{}).Where("id = ?", id).Update("status", status).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Expense{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+			return err
+		}
+		return syncExpenseEntries(tx, id)
+	})
 }
 
-// BulkCreate crea múltiples gastos en una transacción
+// BulkCreate crea múltiples gastos con sus asientos en una transacción
 func (r *ExpensePostgres) BulkCreate(expenses []*entity.Expense) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		return tx.Create(&expenses).Error
+		if err := tx.Create(&expenses).Error; err != nil {
+			return err
+		}
+		return syncExpenseEntries(tx, expenseIDs(expenses)...)
 	})
 }
 
 // GetTotalSpentByUser obtiene el total gastado por un usuario en un período
-func (r *ExpensePostgres) GetTotalSpentByUser(userID uint, startDate, endDate *time.Time) (float64, error) {
-	var total float64
+func (r *ExpensePostgres) GetTotalSpentByUser(userID uint, startDate, endDate *time.Time) (money.Amount, error) {
+	var total money.Amount
 
 	query := r.db.Model(&entity.Expense{}).
 		Where("user_id = ? AND status IN (?)", userID, []string{"confirmed", "pending"}).
@@ -270,8 +336,8 @@ func (r *ExpensePostgres) GetTotalSpentByUser(userID uint, startDate, endDate *t
 }
 
 // GetTotalSpentByCategory obtiene el total gastado por categoría
-func (r *ExpensePostgres) GetTotalSpentByCategory(userID, categoryID uint, startDate, endDate *time.Time) (float64, error) {
-	var total float64
+func (r *ExpensePostgres) GetTotalSpentByCategory(userID, categoryID uint, startDate, endDate *time.Time) (money.Amount, error) {
+	var total money.Amount
 
 	query := r.db.Model(&entity.Expense{}).
 		Where("user_id = ? AND category_id = ? AND status IN (?)", userID, categoryID, []string{"confirmed", "pending"}).
@@ -292,20 +358,20 @@ func (r *ExpensePostgres) GetTotalSpentByCategory(userID, categoryID uint, start
 
 // ExpenseCategorySummary representa un resumen de gastos por categoría
 type ExpenseCategorySummary struct {
-	CategoryID    uint    `json:"category_id"`
-	CategoryName  string  `json:"category_name"`
-	CategoryIcon  string  `json:"category_icon"`
-	CategoryColor string  `json:"category_color"`
-	Count         int64   `json:"count"`
-	TotalAmount   float64 `json:"total_amount"`
+	CategoryID    uint         `json:"category_id"`
+	CategoryName  string       `json:"category_name"`
+	CategoryIcon  string       `json:"category_icon"`
+	CategoryColor string       `json:"category_color"`
+	Count         int64        `json:"count"`
+	TotalAmount   money.Amount `json:"total_amount"`
 }
 
 // MonthlyExpenseSummary representa un resumen de gastos mensuales
 type MonthlyExpenseSummary struct {
-	Year        int     `json:"year"`
-	Month       int     `json:"month"`
-	Count       int64   `json:"count"`
-	TotalAmount float64 `json:"total_amount"`
+	Year        int          `json:"year"`
+	Month       int          `json:"month"`
+	Count       int64        `json:"count"`
+	TotalAmount money.Amount `json:"total_amount"`
 }
 
 // SearchParams define los parámetros de búsqueda para gastos
@@ -313,8 +379,8 @@ type SearchParams struct {
 	CategoryID *uint                 `json:"category_id"`
 	StartDate  *time.Time            `json:"start_date"`
 	EndDate    *time.Time            `json:"end_date"`
-	MinAmount  *float64              `json:"min_amount"`
-	MaxAmount  *float64              `json:"max_amount"`
+	MinAmount  *money.Amount         `json:"min_amount"`
+	MaxAmount  *money.Amount         `json:"max_amount"`
 	Status     *entity.ExpenseStatus `json:"status"`
 	SearchTerm string                `json:"search_term"`
 	Offset     int                   `json:"offset"`
@@ -347,9 +413,14 @@ func (r *ExpensePostgres) CancelExpense(id uint) error {
 
 // BatchConfirmExpenses confirma múltiples gastos
 func (r *ExpensePostgres) BatchConfirmExpenses(expenseIDs []uint) error {
-	return r.db.Model(&entity.Expense{}).
-		Where("id IN ?", expenseIDs).
-		Update("status", entity.ExpenseStatusConfirmed).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Expense{}).
+			Where("id IN ?", expenseIDs).
+			Update("status", entity.ExpenseStatusConfirmed).Error; err != nil {
+			return err
+		}
+		return syncExpenseEntries(tx, expenseIDs...)
+	})
 }
 
 // GetTodayExpenses obtiene gastos de hoy
@@ -412,18 +483,18 @@ func (r *ExpensePostgres) GetExpensesByCategories(userID uint, categoryIDs []uin
 }
 
 // CalculateTotalByUser calcula el total gastado por usuario
-func (r *ExpensePostgres) CalculateTotalByUser(userID uint, fromDate, toDate *time.Time) (float64, error) {
+func (r *ExpensePostgres) CalculateTotalByUser(userID uint, fromDate, toDate *time.Time) (money.Amount, error) {
 	return r.GetTotalSpentByUser(userID, fromDate, toDate)
 }
 
 // CalculateTotalByCategory calcula el total gastado por categoría
-func (r *ExpensePostgres) CalculateTotalByCategory(userID, categoryID uint, fromDate, toDate *time.Time) (float64, error) {
+func (r *ExpensePostgres) CalculateTotalByCategory(userID, categoryID uint, fromDate, toDate *time.Time) (money.Amount, error) {
 	return r.GetTotalSpentByCategory(userID, categoryID, fromDate, toDate)
 }
 
 // CalculateTotalByAllocation calcula el total gastado por asignación
-func (r *ExpensePostgres) CalculateTotalByAllocation(allocationID uint) (float64, error) {
-	var total float64
+func (r *ExpensePostgres) CalculateTotalByAllocation(allocationID uint) (money.Amount, error) {
+	var total money.Amount
 
 	err := r.db.Model(&entity.Expense{}).
 		Where("allocation_id = ? AND status IN (?)", allocationID, []string{"confirmed", "pending"}).
@@ -434,7 +505,7 @@ func (r *ExpensePostgres) CalculateTotalByAllocation(allocationID uint) (float64
 }
 
 // CalculateDailyAverage calcula el promedio diario de gastos
-func (r *ExpensePostgres) CalculateDailyAverage(userID uint, fromDate, toDate *time.Time) (float64, error) {
+func (r *ExpensePostgres) CalculateDailyAverage(userID uint, fromDate, toDate *time.Time) (money.Amount, error) {
 	total, err := r.GetTotalSpentByUser(userID, fromDate, toDate)
 	if err != nil {
 		return 0, err
@@ -444,38 +515,85 @@ func (r *ExpensePostgres) CalculateDailyAverage(userID uint, fromDate, toDate *t
 		return total, nil
 	}
 
-	days := toDate.Sub(*fromDate).Hours() / 24
-	if days <= 0 {
+	period := toDate.Sub(*fromDate)
+	if period <= 0 {
 		return total, nil
 	}
 
-	return total / days, nil
+	// total * 24h / período, en aritmética exacta
+	return total.MulRat(int64(24*time.Hour), int64(period), money.RoundHalfEven), nil
 }
 
 // Métodos básicos para cumplir con la interfaz (implementaciones mínimas)
 
-func (r *ExpensePostgres) GetExpensesBySource(userID uint, fromDate, toDate *time.Time) (map[entity.ExpenseSource]float64, error) {
-	return make(map[entity.ExpenseSource]float64), nil
+func (r *ExpensePostgres) GetExpensesBySource(userID uint, fromDate, toDate *time.Time) (map[entity.ExpenseSource]money.Amount, error) {
+	return make(map[entity.ExpenseSource]money.Amount), nil
 }
 
 func (r *ExpensePostgres) GetTopMerchants(userID uint, limit int, fromDate, toDate *time.Time) ([]map[string]interface{}, error) {
 	return []map[string]interface{}{}, nil
 }
 
-func (r *ExpensePostgres) GetCategoryTotals(userID uint, fromDate, toDate *time.Time) (map[uint]float64, error) {
-	return make(map[uint]float64), nil
+func (r *ExpensePostgres) GetCategoryTotals(userID uint, fromDate, toDate *time.Time) (map[uint]money.Amount, error) {
+	return make(map[uint]money.Amount), nil
 }
 
-func (r *ExpensePostgres) GetDailyTotals(userID uint, fromDate, toDate *time.Time) (map[string]float64, error) {
-	return make(map[string]float64), nil
+func (r *ExpensePostgres) GetDailyTotals(userID uint, fromDate, toDate *time.Time) (map[string]money.Amount, error) {
+	return make(map[string]money.Amount), nil
 }
 
 func (r *ExpensePostgres) CreateFromSMS(smsData map[string]interface{}) (*entity.Expense, error) {
 	return nil, nil
 }
 
-func (r *ExpensePostgres) GetDuplicateCandidates(amount float64, description string, date time.Time, tolerance time.Duration) ([]*entity.Expense, error) {
-	return []*entity.Expense{}, nil
+// GetDuplicateCandidates obtiene gastos no cancelados del usuario con el mismo monto cuya fecha
+// está dentro de la tolerancia. Si se indica el comercio normalizado, el comercio normalizado del
+// gasto debe contenerlo o estar contenido en él (algunos canales lo truncan).
+func (r *ExpensePostgres) GetDuplicateCandidates(userID uint, amount money.Amount, merchant string, date time.Time, tolerance time.Duration) ([]*entity.Expense, error) {
+	var expenses []*entity.Expense
+
+	query := r.db.Where("user_id = ? AND amount = ? AND date BETWEEN ? AND ? AND status <> ?",
+		userID, amount, date.Add(-tolerance), date.Add(tolerance), entity.ExpenseStatusCancelled)
+
+	if merchant != "" {
+		query = query.Where("merchant_key <> '' AND (strpos(merchant_key, ?) > 0 OR strpos(?, merchant_key) > 0)", merchant, merchant)
+	}
+
+	err := query.Order("date ASC").Find(&expenses).Error
+	return expenses, err
+}
+
+// GetReversalCandidates obtiene los gastos vigentes creados desde notificaciones de una cuenta bancaria
+// (enlazados a ella mediante su huella) con el mismo monto desde una fecha
+func (r *ExpensePostgres) GetReversalCandidates(bankAccountID uint, amount money.Amount, since time.Time) ([]*entity.Expense, error) {
+	var candidates []*entity.Expense
+	err := r.db.Where("amount = ? AND status <> ? AND date >= ?", amount, entity.ExpenseStatusCancelled, since).
+		Where("id IN (?)", r.db.Model(&entity.NotificationFingerprint{}).Select("expense_id").
+			Where("bank_account_id = ? AND expense_id IS NOT NULL AND duplicate_of_id IS NULL", bankAccountID)).
+		Order("date DESC").
+		Find(&candidates).Error
+	return candidates, err
+}
+
+// CalculateNotificationTotalByBankAccount suma los gastos registrados desde una fecha a partir de
+// notificaciones de una cuenta bancaria (los gastos se enlazan a la cuenta mediante su huella), menos
+// los registrados antes que se cancelaron o eliminaron después. Los gastos cancelados no se pueden
+// modificar, por lo que su fecha de actualización es la de la cancelación.
+func (r *ExpensePostgres) CalculateNotificationTotalByBankAccount(bankAccountID uint, since time.Time) (money.Amount, error) {
+	var total money.Amount
+	err := r.db.Table("expenses e").
+		Joins("JOIN notification_fingerprints f ON f.expense_id = e.id AND f.duplicate_of_id IS NULL").
+		Where("f.bank_account_id = ? AND (e.created_at > ? OR e.updated_at > ? OR e.deleted_at > ?)", bankAccountID, since, since, since).
+		Select(`COALESCE(SUM(CASE
+			WHEN e.created_at > @since AND e.status <> @cancelled AND e.deleted_at IS NULL THEN e.amount
+			WHEN e.created_at <= @since AND ((e.status = @cancelled AND e.updated_at > @since) OR e.deleted_at > @since) THEN -e.amount
+			ELSE 0 END), 0)`,
+			map[string]interface{}{
+				"since":     since,
+				"cancelled": entity.ExpenseStatusCancelled,
+			}).
+		Scan(&total).Error
+	return total, err
 }
 
 func (r *ExpensePostgres) SearchByDescription(userID uint, searchTerm string, limit int) ([]*entity.Expense, error) {
@@ -526,10 +644,31 @@ func (r *ExpensePostgres) CleanupCancelledExpenses(olderThan time.Duration) erro
 }
 
 func (r *ExpensePostgres) UpdateCurrency(fromCurrency, toCurrency string, exchangeRate float64) error {
-	return r.db.Model(&entity.Expense{}).
-		Where("currency = ?", fromCurrency).
-		Updates(map[string]interface{}{
-			"currency": toCurrency,
-			"amount":   gorm.Expr("amount * ?", exchangeRate),
-		}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Model(&entity.Expense{}).Where("currency = ?", fromCurrency).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Expense{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"currency": toCurrency,
+				"amount":   gorm.Expr("amount * ?", exchangeRate),
+			}).Error; err != nil {
+			return err
+		}
+
+		// Los montos cambiaron: reemplazar sus asientos
+		return syncExpenseEntries(tx, ids...)
+	})
+}
+
+// expenseIDs retorna los IDs de los gastos
+func expenseIDs(expenses []*entity.Expense) []uint {
+	ids := make([]uint, len(expenses))
+	for i, expense := range expenses {
+		ids[i] = expense.ID
+	}
+	return ids
 }
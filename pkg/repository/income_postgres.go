@@ -8,6 +8,7 @@ import (
 
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
 )
 
 // IncomePostgres implementa IncomeRepo usando PostgreSQL
@@ -20,9 +21,14 @@ func NewIncomePostgres(db *gorm.DB) repo.IncomeRepo {
 	return &IncomePostgres{db: db}
 }
 
-// Create crea un nuevo ingreso
+// Create crea un nuevo ingreso y registra su asiento en el libro contable
 func (r *IncomePostgres) Create(income *entity.Income) error {
-	return r.db.Create(income).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(income).Error; err != nil {
+			return err
+		}
+		return syncIncomeEntry(tx, income.ID)
+	})
 }
 
 // GetByID obtiene un ingreso por su ID
@@ -35,14 +41,24 @@ func (r *IncomePostgres) GetByID(id uint) (*entity.Income, error) {
 	return &income, nil
 }
 
-// Update actualiza un ingreso existente
+// Update actualiza un ingreso existente y su asiento en el libro contable
 func (r *IncomePostgres) Update(income *entity.Income) error {
-	return r.db.Save(income).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(income).Error; err != nil {
+			return err
+		}
+		return syncIncomeEntry(tx, income.ID)
+	})
 }
 
-// Delete elimina un ingreso (soft delete)
+// Delete elimina un ingreso (soft delete) y revierte su asiento en el libro contable
 func (r *IncomePostgres) Delete(id uint) error {
-	return r.db.Delete(&entity.Income{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity.Income{}, id).Error; err != nil {
+			return err
+		}
+		return syncIncomeEntry(tx, id)
+	})
 }
 
 // GetByUserID obtiene ingresos por ID de usuario con paginación
@@ -122,8 +138,8 @@ func (r *IncomePostgres) GetPendingRecurringIncomes(userID uint) ([]*entity.Inco
 }
 
 // GetTotalIncomeByUser obtiene el total de ingresos de un usuario en un rango de fechas
-func (r *IncomePostgres) GetTotalIncomeByUser(userID uint, startDate, endDate *time.Time) (float64, error) {
-	var total float64
+func (r *IncomePostgres) GetTotalIncomeByUser(userID uint, startDate, endDate *time.Time) (money.Amount, error) {
+	var total money.Amount
 	query := r.db.Model(&entity.Income{}).
 		Select("COALESCE(SUM(amount), 0)").
 		Where("user_id = ?", userID)
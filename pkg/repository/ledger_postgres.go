@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// errUnbalancedEntry se retorna al intentar registrar un asiento cuyas líneas no suman cero
+	errUnbalancedEntry = errors.New("unbalanced ledger entry")
+	// errInsufficientFunds se retorna cuando la cuenta origen de un traspaso no tiene fondos disponibles
+	errInsufficientFunds = errors.New("insufficient funds")
+)
+
+// LedgerPostgres implementa LedgerRepo usando PostgreSQL
+type LedgerPostgres struct {
+	db *gorm.DB
+}
+
+// NewLedgerPostgres crea una nueva instancia del repositorio del libro contable
+func NewLedgerPostgres(db *gorm.DB) repo.LedgerRepo {
+	return &LedgerPostgres{db: db}
+}
+
+// Post registra un asiento y actualiza el balance de sus cuentas en una transacción de DB
+func (r *LedgerPostgres) Post(entry *entity.LedgerEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return postLedgerEntry(tx, entry)
+	})
+}
+
+// PostTransfer registra el asiento de un traspaso entre dos cuentas. Bloquea ambas cuentas y verifica,
+// dentro de la misma transacción de DB, que la cuenta origen tenga fondos disponibles.
+func (r *LedgerPostgres) PostTransfer(entry *entity.LedgerEntry, fromAccountID, toAccountID uint, amount money.Amount) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Bloquear en orden de ID para no cruzarse con un traspaso en sentido contrario
+		var accounts []*entity.Account
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []uint{fromAccountID, toAccountID}).
+			Order("id").
+			Find(&accounts).Error; err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			if account.ID == fromAccountID && !account.CanDebit(amount) {
+				return errInsufficientFunds
+			}
+		}
+
+		return postLedgerEntry(tx, entry)
+	})
+}
+
+// GetEntries obtiene los asientos del usuario con sus líneas, del más reciente al más antiguo
+func (r *LedgerPostgres) GetEntries(userID uint, filter entity.LedgerEntryFilter) ([]*entity.LedgerEntry, int64, error) {
+	query := r.db.Model(&entity.LedgerEntry{}).Where("user_id = ?", userID)
+
+	if filter.AccountID != nil {
+		query = query.Where("id IN (?)", r.db.Model(&entity.LedgerPosting{}).Select("entry_id").Where("account_id = ?", *filter.AccountID))
+	}
+	if filter.TransactionID != nil {
+		query = query.Where("transaction_id = ?", *filter.TransactionID)
+	}
+	if filter.Kind != nil {
+		query = query.Where("kind = ?", *filter.Kind)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count ledger entries: %w", err)
+	}
+
+	query = query.Order("entry_date DESC, id DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var entries []*entity.LedgerEntry
+	if err := query.Preload("Postings").Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get ledger entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// CountEntries cuenta los asientos del usuario
+func (r *LedgerPostgres) CountEntries(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&entity.LedgerEntry{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count ledger entries: %w", err)
+	}
+	return count, nil
+}
+
+// GetUnbalancedEntries obtiene los asientos del usuario cuyas líneas no suman cero o que tienen
+// menos de dos líneas. La suma se calcula en la base de datos sobre montos decimales exactos.
+func (r *LedgerPostgres) GetUnbalancedEntries(userID uint) ([]entity.LedgerImbalance, error) {
+	var imbalances []entity.LedgerImbalance
+	err := r.db.Table("ledger_entries e").
+		Select("e.id AS entry_id, e.kind, e.transaction_id, e.expense_id, e.income_id, COUNT(p.id) AS posting_count, COALESCE(SUM(p.amount), 0) AS total").
+		Joins("LEFT JOIN ledger_postings p ON p.entry_id = e.id").
+		Where("e.user_id = ?", userID).
+		Group("e.id, e.kind, e.transaction_id, e.expense_id, e.income_id").
+		Having("COALESCE(SUM(p.amount), 0) <> 0 OR COUNT(p.id) < 2").
+		Order("e.id").
+		Scan(&imbalances).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unbalanced ledger entries: %w", err)
+	}
+	return imbalances, nil
+}
+
+// GetAccountTotals suma las líneas de cada cuenta del usuario en el libro contable
+func (r *LedgerPostgres) GetAccountTotals(userID uint) (map[uint]money.Amount, error) {
+	var rows []struct {
+		AccountID uint
+		Total     money.Amount
+	}
+	err := r.db.Table("ledger_postings p").
+		Select("p.account_id, COALESCE(SUM(p.amount), 0) AS total").
+		Joins("JOIN ledger_entries e ON e.id = p.entry_id").
+		Where("e.user_id = ? AND p.account_id IS NOT NULL", userID).
+		Group("p.account_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger account totals: %w", err)
+	}
+
+	totals := make(map[uint]money.Amount, len(rows))
+	for _, row := range rows {
+		totals[row.AccountID] = row.Total
+	}
+	return totals, nil
+}
+
+// CreateOpeningEntries registra el saldo actual como asiento de apertura de las cuentas con saldo
+// que aún no tienen líneas en el libro contable, creadas antes de que existiera. El balance de la
+// cuenta ya refleja ese saldo, por lo que no se modifica.
+func (r *LedgerPostgres) CreateOpeningEntries() (int, error) {
+	var accounts []*entity.Account
+	err := r.db.Where("balance <> 0").
+		Where("id NOT IN (?)", r.db.Model(&entity.LedgerPosting{}).Select("account_id").Where("account_id IS NOT NULL")).
+		Find(&accounts).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get accounts without ledger entries: %w", err)
+	}
+
+	created := 0
+	for _, account := range accounts {
+		entry := entity.NewOpeningLedgerEntry(account)
+		if entry == nil {
+			continue
+		}
+		if err := createLedgerEntry(r.db, entry); err != nil {
+			return created, fmt.Errorf("failed to create opening entry for account %d: %w", account.ID, err)
+		}
+		created++
+	}
+
+	return created, nil
+}
+
+// postLedgerEntry registra un asiento y aplica sus líneas al balance de las cuentas
+func postLedgerEntry(tx *gorm.DB, entry *entity.LedgerEntry) error {
+	if err := createLedgerEntry(tx, entry); err != nil {
+		return err
+	}
+	return applyLedgerEntry(tx, entry)
+}
+
+// createLedgerEntry guarda un asiento con sus líneas sin modificar el balance de las cuentas
+func createLedgerEntry(tx *gorm.DB, entry *entity.LedgerEntry) error {
+	if !entry.IsBalanced() {
+		return fmt.Errorf("%w: postings sum %s", errUnbalancedEntry, entry.Total())
+	}
+	return tx.Create(entry).Error
+}
+
+// applyLedgerEntry suma las líneas del asiento al balance de sus cuentas. En las tarjetas de
+// crédito el balance es la deuda, por lo que las líneas se aplican con signo contrario.
+func applyLedgerEntry(tx *gorm.DB, entry *entity.LedgerEntry) error {
+	for _, posting := range entry.Postings {
+		if posting.AccountID == nil {
+			continue
+		}
+		if err := tx.Model(&entity.Account{}).
+			Where("id = ?", *posting.AccountID).
+			Update("balance", gorm.Expr("CASE WHEN type = ? THEN balance - ? ELSE balance + ? END",
+				entity.AccountTypeCredit, posting.Amount, posting.Amount)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reverseTransactionEntry registra el reverso del asiento vigente de una transacción. Las transacciones
+// registradas antes del libro contable no tienen asiento y se revierten a partir de sus datos.
+func reverseTransactionEntry(tx *gorm.DB, trans *entity.Transaction, description string) error {
+	original, err := currentEntry(tx, entity.LedgerEntryKindTransaction, "transaction_id", trans.ID)
+	if err != nil {
+		return err
+	}
+	if original == nil {
+		original = entity.NewTransactionLedgerEntry(trans)
+	}
+
+	return postLedgerEntry(tx, original.Reversal(description))
+}
+
+// currentEntry obtiene el último asiento del tipo indicado que no ha sido revertido, del registro
+// cuyo ID guarda la columna. Retorna nil si no tiene.
+func currentEntry(tx *gorm.DB, kind entity.LedgerEntryKind, column string, id uint) (*entity.LedgerEntry, error) {
+	var entry entity.LedgerEntry
+	err := tx.Preload("Postings").
+		Where(column+" = ? AND kind = ?", id, kind).
+		Where("id NOT IN (?)", tx.Model(&entity.LedgerEntry{}).Select("reversal_of_id").Where("reversal_of_id IS NOT NULL")).
+		Order("id DESC").
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// syncRecordEntry deja como asiento vigente de un gasto o ingreso el indicado (nil si no debe
+// tener): si difiere del vigente, revierte el vigente y registra el nuevo. Los registros anteriores
+// al libro contable no tienen asiento y lo reciben la primera vez que cambian.
+func syncRecordEntry(tx *gorm.DB, kind entity.LedgerEntryKind, column string, id uint, want *entity.LedgerEntry, description string) error {
+	current, err := currentEntry(tx, kind, column, id)
+	if err != nil {
+		return err
+	}
+	if current != nil && want != nil && current.Matches(want) {
+		return nil
+	}
+
+	if current != nil {
+		if err := postLedgerEntry(tx, current.Reversal(description)); err != nil {
+			return err
+		}
+	}
+	if want != nil {
+		return postLedgerEntry(tx, want)
+	}
+	return nil
+}
+
+// syncExpenseEntries actualiza el asiento de los gastos indicados: los gastos vigentes quedan
+// registrados con su monto y fecha; los cancelados o eliminados, revertidos
+func syncExpenseEntries(tx *gorm.DB, ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var expenses []*entity.Expense
+	if err := tx.Unscoped().Where("id IN ?", ids).Find(&expenses).Error; err != nil {
+		return err
+	}
+
+	for _, expense := range expenses {
+		var want *entity.LedgerEntry
+		if !expense.DeletedAt.Valid && expense.Status != entity.ExpenseStatusCancelled {
+			want = entity.NewExpenseLedgerEntry(expense)
+		}
+		if err := syncRecordEntry(tx, entity.LedgerEntryKindExpense, "expense_id", expense.ID, want,
+			"Anulación del gasto: "+expense.Description); err != nil {
+			return fmt.Errorf("failed to post ledger entry for expense %d: %w", expense.ID, err)
+		}
+	}
+	return nil
+}
+
+// syncIncomeEntry actualiza el asiento de un ingreso: registrado con su monto y fecha mientras
+// exista, revertido al eliminarse
+func syncIncomeEntry(tx *gorm.DB, id uint) error {
+	var income entity.Income
+	err := tx.Unscoped().First(&income, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var want *entity.LedgerEntry
+	if !income.DeletedAt.Valid {
+		want = entity.NewIncomeLedgerEntry(&income)
+	}
+	if err = syncRecordEntry(tx, entity.LedgerEntryKindIncome, "income_id", income.ID, want,
+		"Anulación del ingreso: "+income.Description); err != nil {
+		return fmt.Errorf("failed to post ledger entry for income %d: %w", income.ID, err)
+	}
+	return nil
+}
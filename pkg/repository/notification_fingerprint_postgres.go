@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
+	"gorm.io/gorm"
+)
+
+// NotificationFingerprintPostgres implementa NotificationFingerprintRepo usando PostgreSQL
+type NotificationFingerprintPostgres struct {
+	db *gorm.DB
+}
+
+// NewNotificationFingerprintPostgres crea una nueva instancia del repositorio de huellas de notificaciones
+func NewNotificationFingerprintPostgres(db *gorm.DB) repo.NotificationFingerprintRepo {
+	return &NotificationFingerprintPostgres{db: db}
+}
+
+// Create registra la huella de una notificación
+func (r *NotificationFingerprintPostgres) Create(fingerprint *entity.NotificationFingerprint) error {
+	if err := r.db.Create(fingerprint).Error; err != nil {
+		return fmt.Errorf("failed to create notification fingerprint: %w", err)
+	}
+	return nil
+}
+
+// GetByFingerprint obtiene la notificación original con la huella exacta. Retorna nil si no existe.
+func (r *NotificationFingerprintPostgres) GetByFingerprint(bankAccountID uint, fingerprint string) (*entity.NotificationFingerprint, error) {
+	var found entity.NotificationFingerprint
+	if err := r.db.Where("bank_account_id = ? AND fingerprint = ? AND duplicate_of_id IS NULL", bankAccountID, fingerprint).
+		Order("event_at ASC").
+		First(&found).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get notification fingerprint: %w", err)
+	}
+	return &found, nil
+}
+
+// GetCandidates obtiene las notificaciones originales de una cuenta con el mismo monto en un rango de tiempo
+func (r *NotificationFingerprintPostgres) GetCandidates(bankAccountID uint, amount money.Amount, from, to time.Time) ([]*entity.NotificationFingerprint, error) {
+	var candidates []*entity.NotificationFingerprint
+	if err := r.db.Where("bank_account_id = ? AND amount = ? AND event_at BETWEEN ? AND ? AND duplicate_of_id IS NULL",
+		bankAccountID, amount, from, to).
+		Order("event_at ASC").
+		Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to get duplicate candidates: %w", err)
+	}
+	return candidates, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"gorm.io/gorm"
+)
+
+// NotificationInboxPostgres implementa NotificationInboxRepo usando PostgreSQL
+type NotificationInboxPostgres struct {
+	db *gorm.DB
+}
+
+// NewNotificationInboxPostgres crea una nueva instancia del repositorio de la bandeja de notificaciones
+func NewNotificationInboxPostgres(db *gorm.DB) repo.NotificationInboxRepo {
+	return &NotificationInboxPostgres{db: db}
+}
+
+// Create guarda una notificación recibida
+func (r *NotificationInboxPostgres) Create(item *entity.NotificationInboxItem) error {
+	if err := r.db.Create(item).Error; err != nil {
+		return fmt.Errorf("failed to create notification inbox item: %w", err)
+	}
+	return nil
+}
+
+// GetByID obtiene una notificación de la bandeja por ID. Retorna nil si no existe.
+func (r *NotificationInboxPostgres) GetByID(id uint) (*entity.NotificationInboxItem, error) {
+	var item entity.NotificationInboxItem
+	if err := r.db.First(&item, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get notification inbox item %d: %w", id, err)
+	}
+	return &item, nil
+}
+
+// Update actualiza una notificación de la bandeja
+func (r *NotificationInboxPostgres) Update(item *entity.NotificationInboxItem) error {
+	if err := r.db.Save(item).Error; err != nil {
+		return fmt.Errorf("failed to update notification inbox item %d: %w", item.ID, err)
+	}
+	return nil
+}
+
+// GetWithFilters obtiene las notificaciones del usuario con filtros, de la más reciente a la más antigua
+func (r *NotificationInboxPostgres) GetWithFilters(userID uint, filter entity.NotificationInboxFilter) ([]*entity.NotificationInboxItem, int64, error) {
+	query := r.db.Model(&entity.NotificationInboxItem{}).Where("user_id = ?", userID)
+
+	if filter.BankAccountID != nil {
+		query = query.Where("bank_account_id = ?", *filter.BankAccountID)
+	}
+	if filter.Channel != nil {
+		query = query.Where("channel = ?", *filter.Channel)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count notification inbox items: %w", err)
+	}
+
+	query = query.Order("received_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var items []*entity.NotificationInboxItem
+	if err := query.Find(&items).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get notification inbox items: %w", err)
+	}
+
+	return items, total, nil
+}
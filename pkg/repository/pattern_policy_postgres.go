@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"gorm.io/gorm"
+)
+
+// PatternPolicyPostgres implementa PatternPolicyRepo usando PostgreSQL
+type PatternPolicyPostgres struct {
+	db *gorm.DB
+}
+
+// NewPatternPolicyPostgres crea una nueva instancia del repositorio de políticas de patrones
+func NewPatternPolicyPostgres(db *gorm.DB) repo.PatternPolicyRepo {
+	return &PatternPolicyPostgres{db: db}
+}
+
+// GetByUserID obtiene la política de un usuario. Retorna nil si el usuario no tiene una propia.
+func (r *PatternPolicyPostgres) GetByUserID(userID uint) (*entity.PatternPolicy, error) {
+	var policy entity.PatternPolicy
+	if err := r.db.Where("user_id = ?", userID).First(&policy).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pattern policy for user %d: %w", userID, err)
+	}
+	return &policy, nil
+}
+
+// Save crea o actualiza la política de un usuario
+func (r *PatternPolicyPostgres) Save(policy *entity.PatternPolicy) error {
+	if err := r.db.Save(policy).Error; err != nil {
+		return fmt.Errorf("failed to save pattern policy: %w", err)
+	}
+	return nil
+}
+
+// PatternHistoryPostgres implementa PatternHistoryRepo usando PostgreSQL
+type PatternHistoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewPatternHistoryPostgres crea una nueva instancia del repositorio de historial de patrones
+func NewPatternHistoryPostgres(db *gorm.DB) repo.PatternHistoryRepo {
+	return &PatternHistoryPostgres{db: db}
+}
+
+// Create registra un cambio de estado de un patrón
+func (r *PatternHistoryPostgres) Create(history *entity.PatternHistory) error {
+	if err := r.db.Create(history).Error; err != nil {
+		return fmt.Errorf("failed to create pattern history: %w", err)
+	}
+	return nil
+}
+
+// CreateWithStatusChange cambia el estado del patrón y registra la transición en una sola transacción de DB
+func (r *PatternHistoryPostgres) CreateWithStatusChange(history *entity.PatternHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := NewBankNotificationPatternPostgres(tx).SetStatus(history.PatternID, history.ToStatus); err != nil {
+			return err
+		}
+
+		return NewPatternHistoryPostgres(tx).Create(history)
+	})
+}
+
+// GetByPatternID obtiene el historial de un patrón, del cambio más reciente al más antiguo
+func (r *PatternHistoryPostgres) GetByPatternID(patternID uint) ([]*entity.PatternHistory, error) {
+	var history []*entity.PatternHistory
+	if err := r.db.Where("pattern_id = ?", patternID).Order("created_at DESC").Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to get history for pattern %d: %w", patternID, err)
+	}
+	return history, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"gorm.io/gorm"
+)
+
+// PatternTemplatePostgres implementa PatternTemplateRepo usando PostgreSQL
+type PatternTemplatePostgres struct {
+	db *gorm.DB
+}
+
+// NewPatternTemplatePostgres crea una nueva instancia del repositorio de plantillas de patrones
+func NewPatternTemplatePostgres(db *gorm.DB) repo.PatternTemplateRepo {
+	return &PatternTemplatePostgres{db: db}
+}
+
+// Create crea una nueva plantilla
+func (r *PatternTemplatePostgres) Create(template *entity.PatternTemplate) error {
+	if err := r.db.Create(template).Error; err != nil {
+		return fmt.Errorf("failed to create pattern template: %w", err)
+	}
+	return nil
+}
+
+// GetByID obtiene una plantilla por ID. Retorna nil si no existe.
+func (r *PatternTemplatePostgres) GetByID(id uint) (*entity.PatternTemplate, error) {
+	var template entity.PatternTemplate
+	if err := r.db.First(&template, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pattern template %d: %w", id, err)
+	}
+	return &template, nil
+}
+
+// GetBySlug obtiene una plantilla por su identificador estable. Retorna nil si no existe.
+func (r *PatternTemplatePostgres) GetBySlug(slug string) (*entity.PatternTemplate, error) {
+	var template entity.PatternTemplate
+	if err := r.db.Where("slug = ?", slug).First(&template).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pattern template %s: %w", slug, err)
+	}
+	return &template, nil
+}
+
+// Update actualiza una plantilla existente
+func (r *PatternTemplatePostgres) Update(template *entity.PatternTemplate) error {
+	if err := r.db.Save(template).Error; err != nil {
+		return fmt.Errorf("failed to update pattern template %d: %w", template.ID, err)
+	}
+	return nil
+}
+
+// GetSystemTemplates obtiene las plantillas del sistema (user_id nulo) con filtros
+func (r *PatternTemplatePostgres) GetSystemTemplates(filter entity.PatternTemplateFilter) ([]*entity.PatternTemplate, error) {
+	query := r.db.Where("user_id IS NULL")
+
+	if filter.BankCode != "" {
+		query = query.Where("bank_code = ?", filter.BankCode)
+	}
+	if filter.Channel != nil {
+		query = query.Where("channel = ?", *filter.Channel)
+	}
+
+	var templates []*entity.PatternTemplate
+	if err := query.Order("bank_code ASC, channel ASC, priority ASC, slug ASC").Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("failed to get pattern templates: %w", err)
+	}
+	return templates, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/nick130920/fintech-backend/internal/entity"
+	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+)
+
+// RecurringTransactionPostgres implementa RecurringTransactionRepo usando PostgreSQL
+type RecurringTransactionPostgres struct {
+	db *gorm.DB
+}
+
+// NewRecurringTransactionPostgres crea una nueva instancia del repositorio de transacciones recurrentes
+func NewRecurringTransactionPostgres(db *gorm.DB) repo.RecurringTransactionRepo {
+	return &RecurringTransactionPostgres{db: db}
+}
+
+// Create guarda una transacción recurrente
+func (r *RecurringTransactionPostgres) Create(recurring *entity.RecurringTransaction) error {
+	if err := r.db.Omit("Exceptions").Create(recurring).Error; err != nil {
+		return fmt.Errorf("failed to create recurring transaction: %w", err)
+	}
+	return nil
+}
+
+// Update actualiza una transacción recurrente. Las excepciones se guardan con SaveException.
+func (r *RecurringTransactionPostgres) Update(recurring *entity.RecurringTransaction) error {
+	if err := r.db.Omit("Exceptions").Save(recurring).Error; err != nil {
+		return fmt.Errorf("failed to update recurring transaction %d: %w", recurring.ID, err)
+	}
+	return nil
+}
+
+// Delete elimina una transacción recurrente (soft delete). Las transacciones ya generadas se conservan.
+func (r *RecurringTransactionPostgres) Delete(id uint) error {
+	if err := r.db.Delete(&entity.RecurringTransaction{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete recurring transaction %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetByID obtiene una transacción recurrente con sus excepciones
+func (r *RecurringTransactionPostgres) GetByID(id uint) (*entity.RecurringTransaction, error) {
+	var recurring entity.RecurringTransaction
+	if err := r.withExceptions().First(&recurring, id).Error; err != nil {
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+// GetByUserID obtiene las transacciones recurrentes de un usuario, opcionalmente por estado
+func (r *RecurringTransactionPostgres) GetByUserID(userID uint, status *entity.RecurringTransactionStatus) ([]*entity.RecurringTransaction, error) {
+	query := r.withExceptions().Where("user_id = ?", userID)
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	var recurrings []*entity.RecurringTransaction
+	if err := query.Order("next_occurrence IS NULL, next_occurrence, id").Find(&recurrings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get recurring transactions of user %d: %w", userID, err)
+	}
+	return recurrings, nil
+}
+
+// GetDue obtiene una página de las recurrencias activas de todos los usuarios con ocurrencias hasta
+// la fecha indicada, ordenadas por ID a partir del indicado. Se pagina por ID y no por fecha para que
+// las recurrencias que fallan, que conservan su fecha vencida, no ocupen siempre la primera página.
+func (r *RecurringTransactionPostgres) GetDue(until time.Time, afterID uint, limit int) ([]*entity.RecurringTransaction, error) {
+	var recurrings []*entity.RecurringTransaction
+	err := r.withExceptions().
+		Where("status = ? AND next_occurrence <= ? AND id > ?", entity.RecurringTransactionStatusActive, until, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&recurrings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due recurring transactions: %w", err)
+	}
+	return recurrings, nil
+}
+
+// GetDueByUserID obtiene las recurrencias activas de un usuario con ocurrencias hasta la fecha indicada
+func (r *RecurringTransactionPostgres) GetDueByUserID(userID uint, until time.Time) ([]*entity.RecurringTransaction, error) {
+	var recurrings []*entity.RecurringTransaction
+	err := r.withExceptions().
+		Where("user_id = ? AND status = ? AND next_occurrence <= ?", userID, entity.RecurringTransactionStatusActive, until).
+		Order("next_occurrence, id").
+		Find(&recurrings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due recurring transactions of user %d: %w", userID, err)
+	}
+	return recurrings, nil
+}
+
+// SaveException crea o reemplaza la excepción de una ocurrencia
+func (r *RecurringTransactionPostgres) SaveException(exception *entity.RecurrenceException) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "recurring_id"}, {Name: "occurrence_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "action", "amount", "description", "notes", "transaction_date"}),
+	}).Create(exception).Error
+	if err != nil {
+		return fmt.Errorf("failed to save recurrence exception: %w", err)
+	}
+	return nil
+}
+
+// DeleteException elimina la excepción de una ocurrencia
+func (r *RecurringTransactionPostgres) DeleteException(recurringID uint, occurrenceDate time.Time) error {
+	err := r.db.Where("recurring_id = ? AND occurrence_date = ?", recurringID, occurrenceDate).
+		Delete(&entity.RecurrenceException{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete recurrence exception: %w", err)
+	}
+	return nil
+}
+
+// withExceptions precarga las excepciones ordenadas por fecha
+func (r *RecurringTransactionPostgres) withExceptions() *gorm.DB {
+	return r.db.Preload("Exceptions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("occurrence_date")
+	})
+}
@@ -1,12 +1,25 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/nick130920/fintech-backend/internal/entity"
 	"github.com/nick130920/fintech-backend/internal/usecase/repo"
+	"github.com/nick130920/fintech-backend/pkg/money"
+)
+
+var (
+	// errTransactionNotPending se retorna al confirmar una transacción que ya no está pendiente
+	errTransactionNotPending = errors.New("transaction is not pending")
+	// errTransactionCancelled se retorna al cancelar una transacción que ya estaba cancelada
+	errTransactionCancelled = errors.New("transaction is already cancelled")
+	// errHeldAmountMismatch se retorna al liberar más fondos de los que la cuenta tiene retenidos
+	errHeldAmountMismatch = errors.New("held amount of account does not cover the hold")
 )
 
 // TransactionPostgres implementa la interfaz TransactionRepo usando PostgreSQL
@@ -24,7 +37,9 @@ func (r *TransactionPostgres) Create(transaction *entity.Transaction) error {
 	return r.db.Create(transaction).Error
 }
 
-// CreateWithBalanceUpdate crea una transacción y actualiza los balances en una transacción de DB
+// CreateWithBalanceUpdate crea una transacción y registra su asiento en el libro contable, que
+// actualiza los balances de sus cuentas, en una transacción de DB. Las autorizaciones retienen su
+// monto en la cuenta origen sin registrar asiento hasta que se confirman.
 func (r *TransactionPostgres) CreateWithBalanceUpdate(trans *entity.Transaction) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		// Crear la transacción
@@ -32,47 +47,53 @@ func (r *TransactionPostgres) CreateWithBalanceUpdate(trans *entity.Transaction)
 			return err
 		}
 
-		// Actualizar balance de la cuenta origen
-		switch trans.Type {
-		case entity.TransactionTypeIncome:
-			// Sumar al balance
-			if err := tx.Model(&struct{ ID uint }{}).Table("accounts").
-				Where("id = ?", trans.AccountID).
-				Update("balance", gorm.Expr("balance + ?", trans.Amount)).Error; err != nil {
-				return err
-			}
-		case entity.TransactionTypeExpense:
-			// Restar del balance
-			if err := tx.Model(&struct{ ID uint }{}).Table("accounts").
-				Where("id = ?", trans.AccountID).
-				Update("balance", gorm.Expr("balance - ?", trans.Amount)).Error; err != nil {
-				return err
-			}
-		case entity.TransactionTypeTransfer:
-			// Restar de cuenta origen
-			if err := tx.Model(&struct{ ID uint }{}).Table("accounts").
-				Where("id = ?", trans.AccountID).
-				Update("balance", gorm.Expr("balance - ?", trans.Amount)).Error; err != nil {
+		if trans.IsHold() {
+			return holdFunds(tx, trans, trans.HoldAmount)
+		}
+		return postLedgerEntry(tx, entity.NewTransactionLedgerEntry(trans))
+	})
+}
+
+// ClearWithBalanceUpdate guarda una transacción pendiente confirmada con su monto final. Si era una
+// autorización libera los fondos retenidos y registra su asiento; las demás pendientes ya tienen
+// asiento, que solo se reemplaza si cambió el monto.
+func (r *TransactionPostgres) ClearWithBalanceUpdate(trans *entity.Transaction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var stored entity.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&stored, trans.ID).Error; err != nil {
+			return err
+		}
+		if !stored.IsPending() {
+			return errTransactionNotPending
+		}
+
+		if err := tx.Omit("Splits").Save(trans).Error; err != nil {
+			return err
+		}
+
+		if stored.IsHold() {
+			if err := holdFunds(tx, &stored, -stored.HoldAmount); err != nil {
 				return err
 			}
-			// Sumar a cuenta destino
-			if trans.ToAccountID != nil {
-				if err := tx.Model(&struct{ ID uint }{}).Table("accounts").
-					Where("id = ?", *trans.ToAccountID).
-					Update("balance", gorm.Expr("balance + ?", trans.Amount)).Error; err != nil {
-					return err
-				}
-			}
+			return postLedgerEntry(tx, entity.NewTransactionLedgerEntry(trans))
 		}
 
-		return nil
+		if stored.Amount == trans.Amount {
+			return nil
+		}
+		if err := reverseTransactionEntry(tx, &stored, "Ajuste: "+stored.Description); err != nil {
+			return err
+		}
+		return postLedgerEntry(tx, entity.NewTransactionLedgerEntry(trans))
 	})
 }
 
-// GetByID obtiene una transacción por su ID
+// GetByID obtiene una transacción por su ID con sus líneas
 func (r *TransactionPostgres) GetByID(id uint) (*entity.Transaction, error) {
 	var transaction entity.Transaction
-	err := r.db.First(&transaction, id).Error
+	err := r.db.Preload("Splits", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&transaction, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -86,12 +107,53 @@ func (r *TransactionPostgres) GetByUserID(userID uint) ([]*entity.Transaction, e
 	return transactions, err
 }
 
+// GetReversalCandidates obtiene las transacciones vigentes creadas desde notificaciones de una cuenta
+// bancaria con el mismo monto desde una fecha, que todavía no fueron compensadas por un reverso
+func (r *TransactionPostgres) GetReversalCandidates(bankAccountID uint, amount money.Amount, since time.Time) ([]*entity.Transaction, error) {
+	var candidates []*entity.Transaction
+	err := r.db.Where("bank_account_id = ? AND amount = ? AND source = ? AND status <> ? AND transaction_date >= ? AND reversal_of_id IS NULL",
+		bankAccountID, amount, entity.TransactionSourceNotification, entity.TransactionStatusCancelled, since).
+		Where("id NOT IN (?)", r.db.Model(&entity.Transaction{}).Select("reversal_of_id").Where("reversal_of_id IS NOT NULL")).
+		Order("transaction_date DESC").
+		Find(&candidates).Error
+	return candidates, err
+}
+
+// GetNetAmountByBankAccount calcula el efecto neto en el saldo de una cuenta bancaria de los movimientos
+// ocurridos desde una fecha: las transacciones vigentes registradas desde entonces, menos las registradas
+// antes que se cancelaron o eliminaron después (su dinero volvió a la cuenta). Las transacciones canceladas
+// no se pueden modificar, por lo que su fecha de actualización es la de la cancelación. Las transferencias
+// restan, salvo las que compensan un reverso.
+func (r *TransactionPostgres) GetNetAmountByBankAccount(bankAccountID uint, since time.Time) (money.Amount, error) {
+	signed := `CASE
+			WHEN type = @income THEN amount
+			WHEN type = @transfer AND reversal_of_id IS NOT NULL THEN amount
+			ELSE -amount END`
+
+	var net money.Amount
+	err := r.db.Unscoped().Model(&entity.Transaction{}).
+		Where("bank_account_id = ? AND (created_at > ? OR updated_at > ? OR deleted_at > ?)", bankAccountID, since, since, since).
+		Select(`COALESCE(SUM(CASE
+			WHEN created_at > @since AND status <> @cancelled AND deleted_at IS NULL THEN `+signed+`
+			WHEN created_at <= @since AND ((status = @cancelled AND updated_at > @since) OR deleted_at > @since) THEN -(`+signed+`)
+			ELSE 0 END), 0)`,
+			map[string]interface{}{
+				"since":     since,
+				"cancelled": entity.TransactionStatusCancelled,
+				"income":    entity.TransactionTypeIncome,
+				"transfer":  entity.TransactionTypeTransfer,
+			}).
+		Scan(&net).Error
+	return net, err
+}
+
 // GetByUserIDWithFilter obtiene transacciones con filtros
 func (r *TransactionPostgres) GetByUserIDWithFilter(userID uint, filter *entity.TransactionFilter) ([]*entity.TransactionSummary, error) {
 	query := r.db.Table("transactions t").
 		Select(`t.id, t.type, t.status, t.amount, t.description, 
 		        t.category_name, t.transaction_date, t.currency, t.created_at,
-		        a.name as account_name, ta.name as to_account_name`).
+		        a.name as account_name, ta.name as to_account_name,
+		        EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id) as is_split`).
 		Joins("LEFT JOIN accounts a ON t.account_id = a.id").
 		Joins("LEFT JOIN accounts ta ON t.to_account_id = ta.id").
 		Where("t.user_id = ?", userID)
@@ -107,7 +169,9 @@ func (r *TransactionPostgres) GetByUserIDWithFilter(userID uint, filter *entity.
 		query = query.Where("t.status = ?", *filter.Status)
 	}
 	if filter.CategoryID != nil {
-		query = query.Where("t.category_id = ?", *filter.CategoryID)
+		// Las transacciones divididas se filtran por la categoría de sus líneas
+		query = query.Where("(t.category_id = ? OR EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id AND s.category_id = ?))",
+			*filter.CategoryID, *filter.CategoryID)
 	}
 	if filter.FromDate != nil {
 		query = query.Where("t.transaction_date >= ?", *filter.FromDate)
@@ -149,9 +213,9 @@ func (r *TransactionPostgres) GetByUserIDWithFilter(userID uint, filter *entity.
 	return summaries, err
 }
 
-// Update actualiza una transacción
+// Update actualiza una transacción. Las líneas se reemplazan con ReplaceSplits.
 func (r *TransactionPostgres) Update(transaction *entity.Transaction) error {
-	return r.db.Save(transaction).Error
+	return r.db.Omit("Splits").Save(transaction).Error
 }
 
 // Delete elimina una transacción (soft delete)
@@ -159,7 +223,8 @@ func (r *TransactionPostgres) Delete(id uint) error {
 	return r.db.Delete(&entity.Transaction{}, id).Error
 }
 
-// DeleteWithBalanceUpdate elimina una transacción y revierte los cambios de balance
+// DeleteWithBalanceUpdate elimina una transacción y registra el reverso de su asiento, o libera los
+// fondos retenidos si era una autorización pendiente
 func (r *TransactionPostgres) DeleteWithBalanceUpdate(id uint) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		// Obtener la transacción primero
@@ -168,37 +233,9 @@ func (r *TransactionPostgres) DeleteWithBalanceUpdate(id uint) error {
 			return err
 		}
 
-		// Revertir cambios de balance
-		switch trans.Type {
-		case entity.TransactionTypeIncome:
-			// Restar del balance (revertir suma)
-			if err := tx.Model(&struct{ ID uint }{}).Table("accounts").
-				Where("id = ?", trans.AccountID).
-				Update("balance", gorm.Expr("balance - ?", trans.Amount)).Error; err != nil {
-				return err
-			}
-		case entity.TransactionTypeExpense:
-			// Sumar al balance (revertir resta)
-			if err := tx.Model(&struct{ ID uint }{}).Table("accounts").
-				Where("id = ?", trans.AccountID).
-				Update("balance", gorm.Expr("balance + ?", trans.Amount)).Error; err != nil {
-				return err
-			}
-		case entity.TransactionTypeTransfer:
-			// Sumar a cuenta origen (revertir resta)
-			if err := tx.Model(&struct{ ID uint }{}).Table("accounts").
-				Where("id = ?", trans.AccountID).
-				Update("balance", gorm.Expr("balance + ?", trans.Amount)).Error; err != nil {
-				return err
-			}
-			// Restar de cuenta destino (revertir suma)
-			if trans.ToAccountID != nil {
-				if err := tx.Model(&struct{ ID uint }{}).Table("accounts").
-					Where("id = ?", *trans.ToAccountID).
-					Update("balance", gorm.Expr("balance - ?", trans.Amount)).Error; err != nil {
-					return err
-				}
-			}
+		// Liberar la autorización o revertir el asiento de la transacción
+		if err := releaseTransaction(tx, &trans, "Eliminación: "+trans.Description); err != nil {
+			return err
 		}
 
 		// Eliminar la transacción
@@ -206,12 +243,132 @@ func (r *TransactionPostgres) DeleteWithBalanceUpdate(id uint) error {
 	})
 }
 
+// CancelWithBalanceUpdate guarda una transacción cancelada y registra el reverso de su asiento, o
+// libera los fondos retenidos si era una autorización pendiente
+func (r *TransactionPostgres) CancelWithBalanceUpdate(trans *entity.Transaction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var stored entity.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&stored, trans.ID).Error; err != nil {
+			return err
+		}
+		if stored.IsCancelled() {
+			return errTransactionCancelled
+		}
+
+		if err := tx.Omit("Splits").Save(trans).Error; err != nil {
+			return err
+		}
+
+		return releaseTransaction(tx, &stored, "Cancelación: "+trans.Description)
+	})
+}
+
+// ExpireHold cancela una autorización vencida y libera sus fondos. Se verifica bajo bloqueo que siga
+// pendiente, para no cancelar una autorización que el usuario confirmó mientras tanto.
+func (r *TransactionPostgres) ExpireHold(id uint, at time.Time) (*entity.Transaction, error) {
+	var stored entity.Transaction
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Splits").First(&stored, id).Error; err != nil {
+			return err
+		}
+		if !stored.IsHold() {
+			return errTransactionNotPending
+		}
+
+		if err := holdFunds(tx, &stored, -stored.HoldAmount); err != nil {
+			return err
+		}
+
+		stored.Expire(at)
+		return tx.Model(&entity.Transaction{}).Where("id = ?", stored.ID).
+			Updates(map[string]interface{}{
+				"status":     stored.Status,
+				"expired_at": stored.ExpiredAt,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// GetExpiredHolds obtiene las autorizaciones pendientes de todos los usuarios registradas hasta la
+// fecha indicada. Las transacciones en espera de revisión nunca vencen.
+func (r *TransactionPostgres) GetExpiredHolds(before time.Time, limit int) ([]*entity.Transaction, error) {
+	var holds []*entity.Transaction
+	err := r.db.
+		Where("status = ? AND is_authorization = ? AND validation_status <> ? AND created_at <= ?",
+			entity.TransactionStatusPending, true, entity.ValidationStatusPending, before).
+		Order("created_at, id").
+		Limit(limit).
+		Find(&holds).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired holds: %w", err)
+	}
+	return holds, nil
+}
+
+// releaseTransaction deshace el efecto en los balances de una transacción tal como está guardada:
+// libera los fondos retenidos de una autorización pendiente o registra el reverso de su asiento
+func releaseTransaction(tx *gorm.DB, stored *entity.Transaction, description string) error {
+	if stored.IsHold() {
+		return holdFunds(tx, stored, -stored.HoldAmount)
+	}
+	return reverseTransactionEntry(tx, stored, description)
+}
+
+// holdFunds suma el monto indicado a los fondos retenidos de la cuenta origen de una autorización;
+// un monto negativo los libera. Liberar más de lo retenido indica que los fondos retenidos de la
+// cuenta no coinciden con sus autorizaciones y se reporta como error.
+func holdFunds(tx *gorm.DB, trans *entity.Transaction, amount money.Amount) error {
+	if !trans.HoldsFunds() {
+		return nil
+	}
+
+	result := tx.Model(&entity.Account{}).
+		Where("id = ? AND held_amount + ? >= 0", trans.AccountID, amount).
+		Update("held_amount", gorm.Expr("held_amount + ?", amount))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: account %d", errHeldAmountMismatch, trans.AccountID)
+	}
+	return nil
+}
+
+// ReplaceSplits reemplaza las líneas de una transacción por las que tiene asignadas y guarda su
+// categoría, en una transacción de DB. Una lista vacía deja la transacción sin dividir.
+func (r *TransactionPostgres) ReplaceSplits(trans *entity.Transaction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transaction_id = ?", trans.ID).Delete(&entity.TransactionSplit{}).Error; err != nil {
+			return err
+		}
+
+		for i := range trans.Splits {
+			trans.Splits[i].ID = 0
+			trans.Splits[i].TransactionID = trans.ID
+		}
+		if len(trans.Splits) > 0 {
+			if err := tx.Create(&trans.Splits).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&entity.Transaction{}).Where("id = ?", trans.ID).
+			Updates(map[string]interface{}{
+				"category_id":   trans.CategoryID,
+				"category_name": trans.CategoryName,
+			}).Error
+	})
+}
+
 // CalculateAccountBalance calcula el balance de una cuenta basado en transacciones
-func (r *TransactionPostgres) CalculateAccountBalance(accountID uint) (float64, error) {
-	var balance float64
+func (r *TransactionPostgres) CalculateAccountBalance(accountID uint) (money.Amount, error) {
+	var balance money.Amount
 
 	// Sumar ingresos
-	var income float64
+	var income money.Amount
 	if err := r.db.Model(&entity.Transaction{}).
 		Where("account_id = ? AND type = ? AND status = ?",
 			accountID, entity.TransactionTypeIncome, entity.TransactionStatusCompleted).
@@ -221,7 +378,7 @@ func (r *TransactionPostgres) CalculateAccountBalance(accountID uint) (float64,
 	}
 
 	// Restar gastos
-	var expenses float64
+	var expenses money.Amount
 	if err := r.db.Model(&entity.Transaction{}).
 		Where("account_id = ? AND type = ? AND status = ?",
 			accountID, entity.TransactionTypeExpense, entity.TransactionStatusCompleted).
@@ -231,7 +388,7 @@ func (r *TransactionPostgres) CalculateAccountBalance(accountID uint) (float64,
 	}
 
 	// Restar transferencias salientes
-	var transfersOut float64
+	var transfersOut money.Amount
 	if err := r.db.Model(&entity.Transaction{}).
 		Where("account_id = ? AND type = ? AND status = ?",
 			accountID, entity.TransactionTypeTransfer, entity.TransactionStatusCompleted).
@@ -241,7 +398,7 @@ func (r *TransactionPostgres) CalculateAccountBalance(accountID uint) (float64,
 	}
 
 	// Sumar transferencias entrantes
-	var transfersIn float64
+	var transfersIn money.Amount
 	if err := r.db.Model(&entity.Transaction{}).
 		Where("to_account_id = ? AND type = ? AND status = ?",
 			accountID, entity.TransactionTypeTransfer, entity.TransactionStatusCompleted).
@@ -255,7 +412,7 @@ func (r *TransactionPostgres) CalculateAccountBalance(accountID uint) (float64,
 }
 
 // GetTotalsByType obtiene totales por tipo de transacción
-func (r *TransactionPostgres) GetTotalsByType(userID uint, fromDate, toDate *time.Time) (map[entity.TransactionType]float64, error) {
+func (r *TransactionPostgres) GetTotalsByType(userID uint, fromDate, toDate *time.Time) (map[entity.TransactionType]money.Amount, error) {
 	query := r.db.Model(&entity.Transaction{}).
 		Select("type, SUM(amount) as total").
 		Where("user_id = ? AND status = ?", userID, entity.TransactionStatusCompleted).
@@ -270,17 +427,65 @@ func (r *TransactionPostgres) GetTotalsByType(userID uint, fromDate, toDate *tim
 
 	var results []struct {
 		Type  entity.TransactionType `json:"type"`
-		Total float64                `json:"total"`
+		Total money.Amount           `json:"total"`
 	}
 
 	if err := query.Find(&results).Error; err != nil {
 		return nil, err
 	}
 
-	totals := make(map[entity.TransactionType]float64)
+	totals := make(map[entity.TransactionType]money.Amount)
 	for _, result := range results {
 		totals[result.Type] = result.Total
 	}
 
 	return totals, nil
 }
+
+// GetCategoryTotals obtiene los totales por categoría y tipo de las transacciones completadas. Las
+// transacciones divididas suman el monto de cada línea a su categoría en lugar de la categoría propia.
+func (r *TransactionPostgres) GetCategoryTotals(userID uint, fromDate, toDate *time.Time) ([]entity.TransactionCategoryTotal, error) {
+	conditions := "t.user_id = ? AND t.status = ? AND t.type <> ? AND t.deleted_at IS NULL"
+	args := []interface{}{userID, entity.TransactionStatusCompleted, entity.TransactionTypeTransfer}
+	if fromDate != nil {
+		conditions += " AND t.transaction_date >= ?"
+		args = append(args, *fromDate)
+	}
+	if toDate != nil {
+		conditions += " AND t.transaction_date <= ?"
+		args = append(args, *toDate)
+	}
+
+	query := `SELECT category_id, category_name, type, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS total
+		FROM (
+			SELECT t.category_id, t.category_name, t.type, t.amount
+			FROM transactions t
+			WHERE ` + conditions + ` AND NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id)
+			UNION ALL
+			SELECT s.category_id, s.category_name, t.type, s.amount
+			FROM transaction_splits s
+			JOIN transactions t ON t.id = s.transaction_id
+			WHERE ` + conditions + `
+		) lines
+		GROUP BY category_id, category_name, type
+		ORDER BY total DESC`
+
+	var totals []entity.TransactionCategoryTotal
+	if err := r.db.Raw(query, append(args, args...)...).Scan(&totals).Error; err != nil {
+		return nil, fmt.Errorf("failed to get category totals: %w", err)
+	}
+	return totals, nil
+}
+
+// ExistsRecurringOccurrence verifica si ya se generó la ocurrencia de una transacción recurrente,
+// incluyendo las transacciones eliminadas para no volver a generarlas
+func (r *TransactionPostgres) ExistsRecurringOccurrence(recurringID uint, occurrenceDate time.Time) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&entity.Transaction{}).
+		Where("recurring_id = ? AND occurrence_date = ?", recurringID, occurrenceDate).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check recurring occurrence: %w", err)
+	}
+	return count > 0, nil
+}